@@ -0,0 +1,319 @@
+import {
+  GameController
+} from './common.js';
+
+import {
+  UserCache
+} from '../utils/cache.js';
+
+import {
+  CardHand,
+} from './card.js';
+
+// Kinds of calls in the auction; these match the server's BridgeCallKind.
+const BridgePass = 0;
+const BridgeBid = 1;
+const BridgeDouble = 2;
+const BridgeRedouble = 3;
+
+// Strains in ascending order of rank; these match the server's BridgeStrain,
+// which orders the suits differently than CardSuit does.
+const BridgeStrains = {
+  1: { label: "♣", name: "Clubs", color: "black" },
+  2: { label: "♦", name: "Diamonds", color: "red" },
+  3: { label: "♥", name: "Hearts", color: "red" },
+  4: { label: "♠", name: "Spades", color: "black" },
+  5: { label: "NT", name: "No Trump", color: "black" },
+};
+
+class BridgeController extends GameController {
+  async assignTeams(team_data) {
+    return await this.wsController.sendAndWait({
+      'message_type': 'assign',
+      ...team_data,
+    });
+  }
+
+  async deal() {
+    return await this.wsController.sendAndWait({
+      'message_type': 'deal',
+    });
+  }
+
+  async call(kind, level, strain) {
+    return await this.wsController.send({
+      'message_type': 'call',
+      'kind': +kind,
+      'level': +level,
+      'strain': +strain,
+    });
+  }
+
+  async play(card) {
+    return await this.wsController.send({
+      'message_type': 'play',
+      'card_id': +card,
+    });
+  }
+}
+
+// Unlike Rush, where we have to duplicate logic on the client and server to
+// move and drop tiles &c, here we can lazily take values from the server and
+// blindly update ours. This is because we only do a single action at a time,
+// and unless there's a network glitch (in which case server wins anyways),
+// the data always aligns after the message is confirmed by the server.
+class BridgeData {
+  constructor(game) {
+    this.game = game;
+  }
+}
+
+class BridgeGame {
+  constructor(game, readonly) {
+    this.game = game;
+
+    if (readonly === undefined || readonly === null || readonly === false) {
+      this.controller = new BridgeController(game);
+      this.controller.onMessage("state", (data) => { this.handleNewState(data) });
+      this.controller.onMessage("game-state", (data) => { this.handleNewState(data) });
+      this.controller.onMessage("synopsis", (data) => { this.handleNewSynopsis(data) });
+    }
+
+    this.data = new BridgeData(game);
+    this.synopsis = {};
+
+    this.started = false;
+    this.dealt = false;
+    this.bid = false;
+    this.finished = false;
+
+    this.onChange = () => {};
+
+    // Partners sit across from each other; the owner picks who sits where.
+    this.hasTeams = true;
+  }
+
+  async handleNewState(message) {
+    // Bridge is a simpler game than Rush. We can always take the hand from
+    // the server as this is a turn-based game. We won't get out of sync like
+    // Rush.
+
+    // Update some metadata about game progress.
+    this.started = message.started;
+    this.dealt = message.dealt;
+    this.bid = message.bid;
+    this.finished = message.finished;
+
+    // Then update the main data object.
+    this.data.hand = message?.hand ? CardHand.deserialize(message.hand) : null;
+    if (this.data.hand != null) {
+      this.data.hand.cardSort(true, true);
+    }
+    this.data.dummy_hand = message?.dummy_hand ? CardHand.deserialize(message.dummy_hand) : null;
+    if (this.data.dummy_hand != null) {
+      this.data.dummy_hand.cardSort(true, true);
+    }
+    this.data.tricks = message?.tricks;
+    this.data.turn = message?.turn;
+    this.data.leader = message?.leader;
+    this.data.dealer = message?.dealer;
+    this.data.declarer = message?.declarer;
+    this.data.dummy = message?.dummy;
+    this.data.auction = message?.auction || [];
+    this.data.contract = message?.contract;
+    this.data.played = message?.played ? CardHand.deserialize(message.played) : null;
+    this.data.who_played = [];
+    if (message?.who_played) {
+      for (let uid of message.who_played) {
+        this.data.who_played.push(await UserCache.FromId(uid));
+      }
+    }
+    this.data.board = message?.board;
+    this.data.vulnerable = message?.vulnerable;
+    this.data.below = message?.below;
+    this.data.games = message?.games;
+    this.data.rubbers = message?.rubbers;
+    this.data.scores = message?.scores;
+    this.data.config = message?.config;
+    if (this.data.config) {
+      this.game.config = this.data.config;
+    }
+
+    this.onChange(this);
+  }
+
+  async handleNewSynopsis(message) {
+    // Bridge is a simpler game than Rush. We can always take the hand from
+    // the server as this is a turn-based game. We won't get out of sync like
+    // Rush.
+    if (message.players) {
+      for (let player of message.players) {
+        player.user = await UserCache.FromId(player.user);
+      }
+    }
+    Object.assign(this.synopsis, message);
+
+    this.onChange(this);
+  }
+
+  // The user sitting at the given player index, as the auction and contract
+  // refer to players by index.
+  player_user(index) {
+    for (let player of this.synopsis?.players || []) {
+      if (+player.player_index === +index) {
+        return player.user;
+      }
+    }
+
+    return null;
+  }
+
+  // Our own player index, or -1 when spectating.
+  my_index() {
+    for (let player of this.synopsis?.players || []) {
+      if (+player.user?.id === +this.game.user.id) {
+        return +player.player_index;
+      }
+    }
+
+    return -1;
+  }
+
+  // Calls the auction currently allows us to make, besides passing.
+  valid_calls() {
+    var last_bid = null;
+    var last_call = null;
+    for (let index = this.data.auction.length - 1; index >= 0; index--) {
+      let call = this.data.auction[index];
+      if (last_call === null && +call.kind !== BridgePass) {
+        last_call = call;
+      }
+
+      if (+call.kind === BridgeBid) {
+        last_bid = call;
+        break;
+      }
+    }
+
+    var my_side = this.my_index() % 2;
+    var result = {
+      last_bid: last_bid,
+      double: last_call !== null && +last_call.kind === BridgeBid && +last_call.player % 2 !== my_side,
+      redouble: last_call !== null && +last_call.kind === BridgeDouble && +last_call.player % 2 !== my_side,
+    };
+
+    return result;
+  }
+
+  // Whether a bid of this level and strain is higher than the last bid.
+  valid_bid(level, strain) {
+    var last_bid = this.valid_calls().last_bid;
+    if (+level < 1 || +level > 7 || !BridgeStrains[+strain]) {
+      return false;
+    }
+
+    if (last_bid === null) {
+      return true;
+    }
+
+    return +level > +last_bid.level || (+level === +last_bid.level && +strain > +last_bid.strain);
+  }
+
+  is_declarer() {
+    return this.data.contract && +this.data.declarer === +this.game.user.id;
+  }
+
+  is_dummy() {
+    return this.data.contract && +this.data.dummy === +this.game.user.id;
+  }
+
+  // The declarer plays the dummy's cards when it is the dummy's turn.
+  playing_dummy() {
+    return this.bid && this.is_declarer() && +this.data.turn === +this.data.dummy;
+  }
+
+  my_turn() {
+    if (this.bid && this.is_dummy()) {
+      return false;
+    }
+
+    return +this.data.turn === +this.game.user.id || this.playing_dummy();
+  }
+
+  my_deal() {
+    return +this.data.dealer === +this.game.user.id;
+  }
+
+  async deal() {
+    return this.controller.deal();
+  }
+
+  async pass() {
+    return this.controller.call(BridgePass, 0, 0);
+  }
+
+  async makeBid(level, strain) {
+    return this.controller.call(BridgeBid, level, strain);
+  }
+
+  async double() {
+    return this.controller.call(BridgeDouble, 0, 0);
+  }
+
+  async redouble() {
+    return this.controller.call(BridgeRedouble, 0, 0);
+  }
+
+  async play(card) {
+    return this.controller.play(card);
+  }
+
+  close() {
+    this.controller.close();
+    this.onChange = (e) => { return true };
+  }
+}
+
+// Short description of a contract, such as "4♠ doubled".
+function contractString(contract) {
+  if (!contract) {
+    return "Passed out";
+  }
+
+  var strain = BridgeStrains[+contract.strain];
+  var result = contract.level + (strain ? strain.label : "?");
+  if (+contract.doubled === 1) {
+    result += " doubled";
+  } else if (+contract.doubled === 2) {
+    result += " redoubled";
+  }
+
+  return result;
+}
+
+// Short description of a call in the auction.
+function callString(call) {
+  if (+call.kind === BridgePass) {
+    return "Pass";
+  } else if (+call.kind === BridgeDouble) {
+    return "Double";
+  } else if (+call.kind === BridgeRedouble) {
+    return "Redouble";
+  }
+
+  var strain = BridgeStrains[+call.strain];
+  return call.level + (strain ? strain.label : "?");
+}
+
+export {
+  BridgeData,
+  BridgeGame,
+  BridgeController,
+  BridgeStrains,
+  BridgePass,
+  BridgeBid,
+  BridgeDouble,
+  BridgeRedouble,
+  contractString,
+  callString,
+};
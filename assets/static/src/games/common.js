@@ -43,6 +43,20 @@ class GameController {
     });
   }
 
+  async addBot(difficulty) {
+    return await this.wsController.sendAndWait({
+      'message_type': 'add-bot',
+      'difficulty': difficulty,
+    });
+  }
+
+  async removeBot(bot) {
+    return await this.wsController.sendAndWait({
+      'message_type': 'remove-bot',
+      'target_id': +bot,
+    });
+  }
+
   async startGame() {
     return await this.wsController.sendAndWait({
       'message_type': 'start',
@@ -0,0 +1,168 @@
+import {
+  GameController
+} from './common.js';
+
+import {
+  UserCache
+} from '../utils/cache.js';
+
+import {
+  CardHand,
+  Card,
+  CardSuit,
+} from './card.js';
+
+class CrazyEightsController extends GameController {
+  async draw() {
+    return await this.wsController.send({
+      'message_type': 'draw',
+    });
+  }
+
+  async pass() {
+    return await this.wsController.send({
+      'message_type': 'pass',
+    });
+  }
+
+  async play(card, suit) {
+    return await this.wsController.send({
+      'message_type': 'play',
+      'card_id': +card,
+      'suit': +suit,
+    });
+  }
+}
+
+// Unlike Rush, where we have to duplicate logic on the client and server to
+// move and drop tiles &c, here we can lazily take values from the server and
+// blindly update ours. This is because we only do a single action at a time,
+// and unless there's a network glitch (in which case server wins anyways),
+// the data always aligns after the message is confirmed by the server.
+class CrazyEightsData {
+  constructor(game) {
+    this.game = game;
+  }
+}
+
+class CrazyEightsGame {
+  constructor(game, readonly) {
+    this.game = game;
+
+    if (readonly === undefined || readonly === null || readonly === false) {
+      this.controller = new CrazyEightsController(game);
+      this.controller.onMessage("state", (data) => { this.handleNewState(data) });
+      this.controller.onMessage("game-state", (data) => { this.handleNewState(data) });
+      this.controller.onMessage("synopsis", (data) => { this.handleNewSynopsis(data) });
+    }
+
+    this.data = new CrazyEightsData(game);
+    this.synopsis = {};
+
+    this.started = false;
+    this.finished = false;
+
+    this.onChange = () => {};
+  }
+
+  async handleNewState(message) {
+    // Crazy Eights is a simpler game than Rush. We can always take the hand
+    // from the server as this is a turn-based game. We won't get out of sync
+    // like Rush.
+
+    // Update some metadata about game progress.
+    this.started = message.started;
+    this.finished = message.finished;
+
+    // Then update the main data object.
+    this.data.hand = message?.hand ? CardHand.deserialize(message.hand) : null;
+    if (this.data.hand != null) {
+      this.data.hand.cardSort(true, true, false);
+    }
+    this.data.drawn = message?.drawn;
+    this.data.turn = message?.turn;
+    this.data.direction = message?.direction;
+    this.data.top = message?.top && message.top.rank ? Card.deserialize(message.top) : null;
+    this.data.suit = message?.suit ? CardSuit.deserialize(message.suit) : null;
+    this.data.draw_pile = message?.draw_pile;
+    this.data.discard_pile = message?.discard_pile;
+    this.data.config = message?.config;
+    if (this.data.config) {
+      this.game.config = this.data.config;
+    }
+
+    this.onChange(this);
+  }
+
+  async handleNewSynopsis(message) {
+    // Crazy Eights is a simpler game than Rush. We can always take the hand
+    // from the server as this is a turn-based game. We won't get out of sync
+    // like Rush.
+    if (message.players) {
+      for (let player of message.players) {
+        player.user = await UserCache.FromId(player.user);
+      }
+    }
+    Object.assign(this.synopsis, message);
+
+    this.onChange(this);
+  }
+
+  // Whether this card names a new suit when played.
+  is_wild(card) {
+    return !!this.data.config?.wild_eights && +card.rank.value === 8;
+  }
+
+  // Whether this card may be played on the discard pile: it must match the
+  // suit to follow or the rank of the top card, unless it is wild.
+  can_play(card) {
+    if (!card || !this.data.top) {
+      return false;
+    }
+
+    return this.is_wild(card) || +card.suit.value === +this.data.suit?.value || +card.rank.value === +this.data.top.rank.value;
+  }
+
+  // Passing is only allowed after drawing, or when there's nothing left to
+  // draw.
+  can_pass() {
+    return !!this.data.drawn || +this.data.draw_pile + +this.data.discard_pile <= 1;
+  }
+
+  suits() {
+    var result = [];
+    for (let suit of [1, 2, 3, 4]) {
+      let card_suit = new CardSuit(suit);
+      result.push({ label: card_suit.toUnicode() + " " + card_suit.toString(), value: "" + suit });
+    }
+
+    return result;
+  }
+
+  my_turn() {
+    return +this.data.turn === +this.game.user.id || +this.data.turn?.id === +this.game.user.id;
+  }
+
+  async draw() {
+    return this.controller.draw();
+  }
+
+  async pass() {
+    return this.controller.pass();
+  }
+
+  async play(card, suit) {
+    return this.controller.play(card, suit);
+  }
+
+  close() {
+    this.controller.close();
+    this.onChange = (e) => { return true };
+  }
+}
+
+export {
+  CrazyEightsData,
+  CrazyEightsGame,
+  CrazyEightsController,
+};
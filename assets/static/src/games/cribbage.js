@@ -0,0 +1,194 @@
+import {
+  GameController
+} from './common.js';
+
+import {
+  UserCache
+} from '../utils/cache.js';
+
+import {
+  CardHand,
+  Card,
+} from './card.js';
+
+class CribbageController extends GameController {
+  async assignTeams(team_data) {
+    return await this.wsController.sendAndWait({
+      'message_type': 'assign',
+      ...team_data,
+    });
+  }
+
+  async deal() {
+    return await this.wsController.sendAndWait({
+      'message_type': 'deal',
+    });
+  }
+
+  async discard(cards) {
+    return await this.wsController.send({
+      'message_type': 'discard',
+      'card_ids': cards.map(card => +card),
+    });
+  }
+
+  async play(card) {
+    return await this.wsController.send({
+      'message_type': 'play',
+      'card_id': +card,
+    });
+  }
+
+  async claim(points) {
+    return await this.wsController.send({
+      'message_type': 'claim',
+      'points': +points,
+    });
+  }
+}
+
+// Unlike Rush, where we have to duplicate logic on the client and server to
+// move and drop tiles &c, here we can lazily take values from the server and
+// blindly update ours. This is because we only do a single action at a time,
+// and unless there's a network glitch (in which case server wins anyways),
+// the data always aligns after the message is confirmed by the server.
+class CribbageData {
+  constructor(game) {
+    this.game = game;
+  }
+}
+
+class CribbageGame {
+  constructor(game, readonly) {
+    this.game = game;
+
+    if (readonly === undefined || readonly === null || readonly === false) {
+      this.controller = new CribbageController(game);
+      this.controller.onMessage("state", (data) => { this.handleNewState(data) });
+      this.controller.onMessage("game-state", (data) => { this.handleNewState(data) });
+      this.controller.onMessage("synopsis", (data) => { this.handleNewSynopsis(data) });
+    }
+
+    this.data = new CribbageData(game);
+    this.synopsis = {};
+
+    this.started = false;
+    this.dealt = false;
+    this.cut = false;
+    this.pegged = false;
+    this.finished = false;
+
+    this.onChange = () => {};
+
+    // Four players play as partners; the owner picks who sits where.
+    this.hasTeams = true;
+  }
+
+  async handleNewState(message) {
+    // Cribbage is a simpler game than Rush. We can always take the hand from
+    // the server as this is a turn-based game. We won't get out of sync like
+    // Rush.
+
+    // Update some metadata about game progress.
+    this.started = message.started;
+    this.dealt = message.dealt;
+    this.cut = message.cut;
+    this.pegged = message.pegged;
+    this.finished = message.finished;
+
+    // Then update the main data object.
+    this.data.hand = message?.hand ? CardHand.deserialize(message.hand) : null;
+    if (this.data.hand != null) {
+      this.data.hand.cardSort(true, false, false);
+    }
+    this.data.kept = message?.kept ? CardHand.deserialize(message.kept) : null;
+    if (this.data.kept != null) {
+      this.data.kept.cardSort(true, false, false);
+    }
+    this.data.discarded = message?.discarded;
+    this.data.turn = message?.turn;
+    this.data.dealer = message?.dealer;
+    this.data.starter = message?.starter ? Card.deserialize(message.starter) : null;
+    this.data.crib = message?.crib ? CardHand.deserialize(message.crib) : null;
+    this.data.count = message?.count;
+    this.data.pile = message?.pile ? CardHand.deserialize(message.pile) : null;
+    this.data.last_player = message?.last_player;
+    this.data.showing_crib = message?.showing_crib;
+    this.data.heels = message?.heels;
+    this.data.pegging = message?.pegging;
+    this.data.shows = message?.shows;
+    this.data.scores = message?.scores;
+    this.data.config = message?.config;
+    if (this.data.config) {
+      this.game.config = this.data.config;
+    }
+
+    this.onChange(this);
+  }
+
+  async handleNewSynopsis(message) {
+    // Cribbage is a simpler game than Rush. We can always take the hand from
+    // the server as this is a turn-based game. We won't get out of sync like
+    // Rush.
+    if (message.players) {
+      for (let player of message.players) {
+        player.user = await UserCache.FromId(player.user);
+      }
+    }
+    Object.assign(this.synopsis, message);
+
+    this.onChange(this);
+  }
+
+  // Number of cards each player puts in the crib.
+  discard_size() {
+    return +this.game.config?.num_players === 2 ? 2 : 1;
+  }
+
+  // The user sitting at the given player index, as pegging and shows refer to
+  // players by index.
+  player_user(index) {
+    for (let player of this.synopsis?.players || []) {
+      if (+player.player_index === +index) {
+        return player.user;
+      }
+    }
+
+    return null;
+  }
+
+  my_turn() {
+    return +this.data.turn === +this.game.user.id || +this.data.turn?.id === +this.game.user.id;
+  }
+
+  my_deal() {
+    return +this.data.dealer === +this.game.user.id;
+  }
+
+  async deal() {
+    return this.controller.deal();
+  }
+
+  async discard(cards) {
+    return this.controller.discard(cards);
+  }
+
+  async play(card) {
+    return this.controller.play(card);
+  }
+
+  async claim(points) {
+    return this.controller.claim(points);
+  }
+
+  close() {
+    this.controller.close();
+    this.onChange = (e) => { return true };
+  }
+}
+
+export {
+  CribbageData,
+  CribbageGame,
+  CribbageController,
+};
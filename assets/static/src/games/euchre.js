@@ -0,0 +1,204 @@
+import {
+  GameController
+} from './common.js';
+
+import {
+  UserCache
+} from '../utils/cache.js';
+
+import {
+  CardHand,
+  CardSuit,
+  Card,
+} from './card.js';
+
+class EuchreController extends GameController {
+  async assignTeams(team_data) {
+    return await this.wsController.sendAndWait({
+      'message_type': 'assign',
+      ...team_data,
+    });
+  }
+
+  async deal() {
+    return await this.wsController.sendAndWait({
+      'message_type': 'deal',
+    });
+  }
+
+  async pass() {
+    return await this.wsController.send({
+      'message_type': 'pass',
+    });
+  }
+
+  async call(suit, alone) {
+    return await this.wsController.send({
+      'message_type': 'call',
+      'suit': +suit,
+      'alone': !!alone,
+    });
+  }
+
+  async discard(card) {
+    return await this.wsController.send({
+      'message_type': 'discard',
+      'card_id': +card,
+    });
+  }
+
+  async play(card) {
+    return await this.wsController.send({
+      'message_type': 'play',
+      'card_id': +card,
+    });
+  }
+}
+
+// Unlike Rush, where we have to duplicate logic on the client and server to
+// move and drop tiles &c, here we can lazily take values from the server and
+// blindly update ours. This is because we only do a single action at a time,
+// and unless there's a network glitch (in which case server wins anyways),
+// the data always aligns after the message is confirmed by the server.
+class EuchreData {
+  constructor(game) {
+    this.game = game;
+  }
+}
+
+class EuchreGame {
+  constructor(game, readonly) {
+    this.game = game;
+
+    if (readonly === undefined || readonly === null || readonly === false) {
+      this.controller = new EuchreController(game);
+      this.controller.onMessage("state", (data) => { this.handleNewState(data) });
+      this.controller.onMessage("game-state", (data) => { this.handleNewState(data) });
+      this.controller.onMessage("synopsis", (data) => { this.handleNewSynopsis(data) });
+    }
+
+    this.data = new EuchreData(game);
+    this.synopsis = {};
+
+    this.started = false;
+    this.dealt = false;
+    this.finished = false;
+
+    this.onChange = () => {};
+
+    this.hasTeams = true;
+  }
+
+  async handleNewState(message) {
+    // Euchre is a simpler game than Rush. We can always take the hand from the
+    // server as this is a turn-based game. We won't get out of sync like Rush.
+
+    // Update some metadata about game progress.
+    this.started = message.started;
+    this.dealt = message.dealt;
+    this.finished = message.finished;
+
+    // Then update the main data object.
+    this.data.hand = message?.hand ? CardHand.deserialize(message.hand) : null;
+    if (this.data.hand != null) {
+      this.data.hand.cardSort(true, true, false);
+    }
+    this.data.tricks = message?.tricks;
+    this.data.sitting_out = message?.sitting_out;
+    this.data.turn = message?.turn;
+    this.data.leader = message?.leader;
+    this.data.dealer = message?.dealer;
+    this.data.turned_up = message?.turned_up ? Card.deserialize(message.turned_up) : null;
+    this.data.bid_round = message?.bid_round;
+    this.data.trump = message?.trump ? CardSuit.deserialize(message.trump) : null;
+    this.data.maker = message?.maker;
+    this.data.alone = message?.alone;
+    this.data.discarding = message?.discarding;
+    this.data.scores = message?.scores;
+    this.data.played = message?.played ? CardHand.deserialize(message.played) : null;
+    this.data.history = message?.history ? message.history.map(CardHand.deserialize) : null;
+    this.data.config = message?.config;
+    if (this.data.config) {
+      this.game.config = this.data.config;
+    }
+
+    // We've gotta sync up who_played with our played data.
+    if (!this.data.who_played || (message.who_played && message.played?.length === 1 && +this.data.who_played[0].id !== +message.who_played[0])) {
+      this.data.who_played = [];
+      for (let uid of message.who_played) {
+        let player = await UserCache.FromId(uid);
+        this.data.who_played.push(player);
+      }
+    }
+
+    this.onChange(this);
+  }
+
+  async handleNewSynopsis(message) {
+    // Euchre is a simpler game than Rush. We can always take the hand from the
+    // server as this is a turn-based game. We won't get out of sync like Rush.
+    if (message.players) {
+      for (let player of message.players) {
+        player.user = await UserCache.FromId(player.user);
+      }
+    }
+    Object.assign(this.synopsis, message);
+
+    this.onChange(this);
+  }
+
+  // Suits which can be named as trump in the second round of bidding: any
+  // but the one turned down.
+  valid_trumps() {
+    var result = [];
+    for (let suit of [1, 2, 3, 4]) {
+      if (this.data.turned_up && +this.data.turned_up.suit.value === suit) {
+        continue;
+      }
+
+      let card_suit = new CardSuit(suit);
+      result.push({ label: card_suit.toUnicode() + " " + card_suit.toString(), value: suit });
+    }
+
+    return result;
+  }
+
+  my_turn() {
+    return +this.data.turn === +this.game.user.id || +this.data.turn?.id === +this.game.user.id;
+  }
+
+  my_deal() {
+    return +this.data.dealer === +this.game.user.id;
+  }
+
+  async deal() {
+    return this.controller.deal();
+  }
+
+  async pass() {
+    return this.controller.pass();
+  }
+
+  async call(suit, alone) {
+    return this.controller.call(suit, alone);
+  }
+
+  async discard(card) {
+    return this.controller.discard(card);
+  }
+
+  async play(card) {
+    return this.controller.play(card);
+  }
+
+  close() {
+    this.controller.close();
+    this.onChange = (e) => { return true };
+  }
+}
+
+export {
+  EuchreData,
+  EuchreGame,
+  EuchreController,
+};
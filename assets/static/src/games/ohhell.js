@@ -0,0 +1,183 @@
+import {
+  GameController
+} from './common.js';
+
+import {
+  UserCache
+} from '../utils/cache.js';
+
+import {
+  CardHand,
+  Card,
+  CardSuit,
+} from './card.js';
+
+class OhHellController extends GameController {
+  async deal() {
+    return await this.wsController.sendAndWait({
+      'message_type': 'deal',
+    });
+  }
+
+  async bid(amount) {
+    return await this.wsController.send({
+      'message_type': 'bid',
+      'bid': +amount,
+    });
+  }
+
+  async play(card) {
+    return await this.wsController.send({
+      'message_type': 'play',
+      'card_id': +card,
+    });
+  }
+}
+
+// Unlike Rush, where we have to duplicate logic on the client and server to
+// move and drop tiles &c, here we can lazily take values from the server and
+// blindly update ours. This is because we only do a single action at a time,
+// and unless there's a network glitch (in which case server wins anyways),
+// the data always aligns after the message is confirmed by the server.
+class OhHellData {
+  constructor(game) {
+    this.game = game;
+  }
+}
+
+class OhHellGame {
+  constructor(game, readonly) {
+    this.game = game;
+
+    if (readonly === undefined || readonly === null || readonly === false) {
+      this.controller = new OhHellController(game);
+      this.controller.onMessage("state", (data) => { this.handleNewState(data) });
+      this.controller.onMessage("game-state", (data) => { this.handleNewState(data) });
+      this.controller.onMessage("synopsis", (data) => { this.handleNewSynopsis(data) });
+    }
+
+    this.data = new OhHellData(game);
+    this.synopsis = {};
+
+    this.started = false;
+    this.dealt = false;
+    this.all_bid = false;
+    this.finished = false;
+
+    this.onChange = () => {};
+  }
+
+  async handleNewState(message) {
+    // Oh Hell is a simpler game than Rush. We can always take the hand from
+    // the server as this is a turn-based game. We won't get out of sync like
+    // Rush.
+
+    // Update some metadata about game progress.
+    this.started = message.started;
+    this.dealt = message.dealt;
+    this.all_bid = message.all_bid;
+    this.finished = message.finished;
+
+    // Then update the main data object.
+    this.data.hand = message?.hand ? CardHand.deserialize(message.hand) : null;
+    if (this.data.hand != null) {
+      this.data.hand.cardSort(true, true, false);
+    }
+    this.data.bid = message?.bid;
+    this.data.tricks = message?.tricks;
+    this.data.round_score = message?.round_score;
+    this.data.score = message?.score;
+    this.data.turn = message?.turn;
+    this.data.leader = message?.leader;
+    this.data.dealer = message?.dealer;
+    this.data.hand_size = message?.hand_size;
+    this.data.round = message?.round;
+    this.data.rounds = message?.rounds;
+    this.data.turned_up = message?.turned_up ? Card.deserialize(message.turned_up) : null;
+    this.data.trump = message?.trump ? CardSuit.deserialize(message.trump) : null;
+    this.data.played = message?.played ? CardHand.deserialize(message.played) : null;
+    this.data.history = message?.history ? message.history.map(CardHand.deserialize) : null;
+    this.data.config = message?.config;
+    if (this.data.config) {
+      this.game.config = this.data.config;
+    }
+
+    // We've gotta sync up who_played with our played data.
+    this.data.who_played = [];
+    if (message?.who_played) {
+      for (let uid of message.who_played) {
+        this.data.who_played.push(await UserCache.FromId(uid));
+      }
+    }
+
+    this.onChange(this);
+  }
+
+  async handleNewSynopsis(message) {
+    // Oh Hell is a simpler game than Rush. We can always take the hand from
+    // the server as this is a turn-based game. We won't get out of sync like
+    // Rush.
+    if (message.players) {
+      for (let player of message.players) {
+        player.user = await UserCache.FromId(player.user);
+      }
+    }
+    Object.assign(this.synopsis, message);
+
+    this.onChange(this);
+  }
+
+  // Bids from zero up to the hand size. Under the hook rule, the dealer can't
+  // make the bids add up to the number of tricks.
+  valid_bids() {
+    var hook = null;
+    if (this.my_deal() && this.data.config?.hook_rule) {
+      hook = +this.data.hand_size;
+      for (let player of this.synopsis?.players || []) {
+        if (+player.player_index >= 0 && +player.user?.id !== +this.game.user.id && +player.bid > 0) {
+          hook -= +player.bid;
+        }
+      }
+    }
+
+    var result = [];
+    for (let bid = 0; bid <= +this.data.hand_size; bid++) {
+      if (bid !== hook) {
+        result.push({ label: "" + bid, value: "" + bid });
+      }
+    }
+
+    return result;
+  }
+
+  my_turn() {
+    return +this.data.turn === +this.game.user.id || +this.data.turn?.id === +this.game.user.id;
+  }
+
+  my_deal() {
+    return +this.data.dealer === +this.game.user.id;
+  }
+
+  async deal() {
+    return this.controller.deal();
+  }
+
+  async bid(amount) {
+    return this.controller.bid(amount);
+  }
+
+  async play(card) {
+    return this.controller.play(card);
+  }
+
+  close() {
+    this.controller.close();
+    this.onChange = (e) => { return true };
+  }
+}
+
+export {
+  OhHellData,
+  OhHellGame,
+  OhHellController,
+};
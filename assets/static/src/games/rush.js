@@ -15,6 +15,16 @@ class RushController extends GameController {
 
     this.draw_id = 1;
     this.remaining_tiles = 0;
+
+    // Version of a team's shared board we last saw; see handleNewState.
+    this.version = 0;
+  }
+
+  async assignTeams(team_data) {
+    return await this.wsController.sendAndWait({
+      'message_type': 'assign',
+      ...team_data,
+    });
   }
 
   async check() {
@@ -23,6 +33,12 @@ class RushController extends GameController {
     });
   }
 
+  async hint() {
+    return await this.wsController.sendAndWait({
+      'message_type': 'hint',
+    });
+  }
+
   async draw() {
     return await this.wsController.sendAndWait({
       'message_type': 'draw',
@@ -38,6 +54,7 @@ class RushController extends GameController {
     return await this.wsController.sendAndWait({
       'message_type': 'discard',
       'tile_id': tile.id,
+      'version': this.version,
     });
   }
 
@@ -49,6 +66,7 @@ class RushController extends GameController {
     return await this.wsController.send({
       'message_type': 'recall',
       'tile_id': tile.id,
+      'version': this.version,
     });
   }
 
@@ -61,6 +79,7 @@ class RushController extends GameController {
       'message_type': 'swap',
       'first_id': first.id,
       'second_id': second.id,
+      'version': this.version,
     });
   }
 
@@ -74,20 +93,29 @@ class RushController extends GameController {
       'tile_id': tile.id,
       'x': pos.x,
       'y': pos.y,
+      'version': this.version,
     });
   }
 
-  async play(tile, pos) {
+  async play(tile, pos, letter) {
     if (!(tile instanceof LetterTile) || !(pos instanceof LetterPos)) {
       console.log("Garbage data passed to RushController.play(): ", tile, pos);
     }
 
-    return await this.wsController.send({
+    var message = {
       'message_type': 'play',
       'tile_id': tile.id,
       'x': pos.x,
       'y': pos.y,
-    });
+      'version': this.version,
+    };
+
+    // Blank tiles need to be told which letter they stand for.
+    if (tile.blank) {
+      message.letter = letter;
+    }
+
+    return await this.wsController.send(message);
   }
 }
 
@@ -99,6 +127,7 @@ class RushData {
     this.grid = old?.grid ? new LetterGrid(old.grid) : new LetterGrid();
     this.bank = old?.bank ? new LetterBank(old.bank) : new LetterBank();
     this.unwords = [];
+    this.islands = [];
 
     this.onAdd = (...tiles) => {};
   }
@@ -285,8 +314,15 @@ class RushGame {
       this.controller = new RushController(game);
       this.controller.onMessage("state", (data) => { this.handleNewState(data) });
       this.controller.onMessage("game-state", (data) => { this.handleNewState(data) });
+      this.controller.onMessage("feedback", (data) => { this.handleFeedback(data) });
+      this.controller.onMessage("round-finished", (data) => { this.handleRoundFinished(data) });
     }
 
+    this.results = null;
+
+    // In team games, teammates share one board and hand.
+    this.hasTeams = !!game?.config?.team_play;
+
     this.data = new RushData(game);
 
     this.started = false;
@@ -297,6 +333,18 @@ class RushGame {
   handleNewState(message) {
     this.controller.draw_id = Math.max(message.draw_id, this.controller.draw_id);
     this.controller.remaining_tiles = message.remaining;
+    if (message.version) {
+      this.controller.version = message.version;
+    }
+
+    // A shared board changes under us as teammates play, so always take the
+    // server's copy of it.
+    if (this.hasTeams && this.started && !message.reply_to && message.board !== undefined) {
+      this.data.grid = message.board ? LetterGrid.deserialize(message.board) : new LetterGrid();
+      this.data.bank = message.hand ? LetterBank.deserialize(message.hand) : new LetterBank();
+      this.onChange(this);
+      return;
+    }
 
     // If this message was in reply to another, ignore it. Don't process added
     // events to give draw/discard a chance to work.
@@ -338,11 +386,32 @@ class RushGame {
     this.onChange(this);
   }
 
+  // Live feedback from the server about invalid words and islands of tiles
+  // not connected to the rest of the board.
+  handleFeedback(message) {
+    this.data.check(message);
+    this.data.islands = message.islands ? message.islands : [];
+    this.onChange(this);
+  }
+
+  // A round ended and the next one began: keep the results so far and start
+  // over with an empty board. The next state message deals the new hand.
+  handleRoundFinished(message) {
+    this.results = message.results ? message.results : [];
+    this.data = new RushData(this.game);
+    this.started = false;
+    this.onChange(this);
+  }
+
   async check() {
     var ret = await this.controller.check();
     return this.data.check(ret);
   }
 
+  async hint() {
+    return await this.controller.hint();
+  }
+
   async draw() {
     var ret = await this.controller.draw();
     if (ret.message_type === "error") {
@@ -388,11 +457,11 @@ class RushGame {
     return ret;
   }
 
-  async play(tile, pos) {
+  async play(tile, pos, letter) {
     var tile_pos = new LetterPos(pos[1] - this.data.grid.drift[0], pos[2] - this.data.grid.drift[1]);
 
     var ret = this.data.play(tile, pos);
-    this.controller.play(tile, tile_pos);
+    this.controller.play(tile, tile_pos, letter);
     return ret;
   }
 
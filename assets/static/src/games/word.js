@@ -11,6 +11,7 @@ class LetterTile extends String {
     this.display = obj.display ? obj.display : obj.value;
     this.value = obj.value;
     this.score = obj.score;
+    this.blank = obj.blank;
   }
 
   static deserialize(data) {
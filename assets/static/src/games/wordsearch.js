@@ -0,0 +1,164 @@
+import {
+  GameController
+} from './common.js';
+
+import {
+  UserCache
+} from '../utils/cache.js';
+
+class WordSearchController extends GameController {
+  async submit(path) {
+    return await this.wsController.send({
+      'message_type': 'submit',
+      'path': path.map(tile => +tile),
+    });
+  }
+
+  async done() {
+    return await this.wsController.send({
+      'message_type': 'done',
+    });
+  }
+}
+
+// The grid of letters everyone searches, indexed both by tile identifier and
+// by position.
+class WordSearchGrid {
+  constructor(grid) {
+    this.tiles = {};
+    this.positions = {};
+    this.at = {};
+    this.size = 0;
+
+    for (let tile of grid?.tiles || []) {
+      this.tiles[tile.id] = tile;
+    }
+
+    for (let id in grid?.positions || {}) {
+      let pos = grid.positions[id];
+      this.positions[id] = pos;
+      this.at[pos.x + "," + pos.y] = +id;
+      this.size = Math.max(this.size, +pos.x + 1, +pos.y + 1);
+    }
+  }
+
+  // Tile identifier at the given row and column, or null.
+  get(row, col) {
+    let id = this.at[col + "," + row];
+    return id === undefined ? null : id;
+  }
+
+  // Whether the tiles with these identifiers touch, including diagonally.
+  adjacent(first, second) {
+    let a = this.positions[first];
+    let b = this.positions[second];
+    if (!a || !b) {
+      return false;
+    }
+
+    let dx = Math.abs(+a.x - +b.x);
+    let dy = Math.abs(+a.y - +b.y);
+    return (dx !== 0 || dy !== 0) && dx <= 1 && dy <= 1;
+  }
+
+  // The word spelled by a path of tile identifiers.
+  spell(path) {
+    return path.map(id => this.tiles[id]?.value || "").join("");
+  }
+}
+
+// Unlike Rush, where we have to duplicate logic on the client and server to
+// move and drop tiles &c, here we can lazily take values from the server and
+// blindly update ours. The grid never changes once the game starts; only the
+// words we've found do.
+class WordSearchData {
+  constructor(game) {
+    this.game = game;
+    this.grid = new WordSearchGrid(null);
+    this.words = [];
+  }
+}
+
+class WordSearchGame {
+  constructor(game, readonly) {
+    this.game = game;
+
+    if (readonly === undefined || readonly === null || readonly === false) {
+      this.controller = new WordSearchController(game);
+      this.controller.onMessage("state", (data) => { this.handleNewState(data) });
+      this.controller.onMessage("game-state", (data) => { this.handleNewState(data) });
+      this.controller.onMessage("synopsis", (data) => { this.handleNewSynopsis(data) });
+    }
+
+    this.data = new WordSearchData(game);
+    this.synopsis = {};
+
+    this.started = false;
+    this.finished = false;
+
+    this.onChange = () => {};
+  }
+
+  async handleNewState(message) {
+    // Update some metadata about game progress.
+    this.started = message.started;
+    this.finished = message.finished;
+
+    // Then update the main data object.
+    this.data.grid = new WordSearchGrid(message?.grid);
+    if (message.message_type === "state") {
+      // Only our own state carries the words we've found; a peek carries
+      // everyone's once the game is over.
+      this.data.words = message?.words || [];
+      this.data.done = message?.done;
+    }
+    this.data.deadline = message?.deadline ? new Date(message.deadline) : null;
+    this.data.commitment = message?.commitment;
+    this.data.config = message?.config;
+    if (this.data.config) {
+      this.game.config = this.data.config;
+    }
+
+    this.onChange(this);
+  }
+
+  async handleNewSynopsis(message) {
+    if (message.players) {
+      for (let player of message.players) {
+        player.user = await UserCache.FromId(player.user);
+      }
+    }
+    Object.assign(this.synopsis, message);
+
+    this.onChange(this);
+  }
+
+  // Seconds left before the search ends, or null when there's no deadline.
+  remaining() {
+    if (!this.data.deadline || isNaN(this.data.deadline.getTime()) || this.data.deadline.getTime() <= 0) {
+      return null;
+    }
+
+    return Math.max(0, Math.ceil((this.data.deadline.getTime() - Date.now()) / 1000));
+  }
+
+  async submit(path) {
+    return this.controller.submit(path);
+  }
+
+  async done() {
+    return this.controller.done();
+  }
+
+  close() {
+    this.controller.close();
+    this.onChange = (e) => { return true };
+  }
+}
+
+export {
+  WordSearchData,
+  WordSearchGame,
+  WordSearchGrid,
+  WordSearchController,
+};
@@ -0,0 +1,258 @@
+import React from 'react';
+
+import '../../../main.scss';
+
+import { Avatar } from '@rmwc/avatar';
+import '@rmwc/avatar/styles';
+import { Button } from '@rmwc/button';
+import '@rmwc/button/styles';
+import * as c from '@rmwc/card';
+import '@rmwc/card/styles';
+import { Select } from '@rmwc/select';
+import '@rmwc/select/styles';
+
+import { BridgeStrains, callString, contractString } from '../../../games/bridge.js';
+import { gravatarify } from '../../../utils/gravatar.js';
+
+// Properties used for display card hands
+var handProps = {
+  overlap: true,
+  curve: true,
+  scale: 0.50,
+};
+
+var seatNames = ["North", "East", "South", "West"];
+
+// The auction so far, laid out in a column per seat.
+function BridgeAuction(props) {
+  var header = [];
+  for (let seat of [0, 1, 2, 3]) {
+    let user = props.player_user(seat);
+    header.push(
+      <th key={ seat } style={{ paddingLeft: "10px", paddingRight: "10px" }}>
+        { seatNames[seat] }<br />
+        { user ? <span style={{ fontWeight: "normal" }}>{ user.display }</span> : null }
+      </th>
+    );
+  }
+
+  var rows = [];
+  var row = [];
+  if (props.auction && props.auction.length > 0) {
+    for (let seat = 0; seat < +props.auction[0].player; seat++) {
+      row.push(<td key={ "blank" + seat }></td>);
+    }
+  }
+  for (let index in props.auction || []) {
+    let call = props.auction[index];
+    let strain = BridgeStrains[+call.strain];
+    row.push(<td key={ index } style={{ color: +call.kind === 1 && strain ? strain.color : null }}>{ callString(call) }</td>);
+    if (+call.player === 3) {
+      rows.push(<tr key={ rows.length }>{ row }</tr>);
+      row = [];
+    }
+  }
+  if (row.length > 0) {
+    rows.push(<tr key={ rows.length }>{ row }</tr>);
+  }
+
+  return <table style={{ margin: "0 auto", textAlign: "center" }}>
+    <thead><tr>{ header }</tr></thead>
+    <tbody>{ rows }</tbody>
+  </table>;
+}
+
+class BridgeGameComponent extends React.Component {
+  constructor(props) {
+    super(props);
+    this.state = {};
+    this.state.game = this.props.game;
+    this.state.selected = null;
+    this.state.level = null;
+    this.state.strain = null;
+    // FIXME: hack?
+    let old_handler = this.state.game.interface.onChange;
+    this.state.game.interface.onChange = () => {
+      old_handler();
+      this.setState(state => {
+        // Jinx
+        return state;
+      });
+    };
+  }
+  clearSelectAnd(then) {
+    return (...arg) => {
+      this.setState(state => Object.assign(state, {
+        selected: null,
+        level: null,
+        strain: null,
+      }));
+      return then && then(...arg);
+    };
+  }
+  selecting(card) {
+    return Object.assign(card, {
+      selected: this.state.selected === card.id,
+      onClick: () => {
+        this.setState(state => {
+          if (state.selected === card.id)
+            state.selected = null;
+          else
+            state.selected = card.id;
+          return state;
+        });
+      },
+    });
+  }
+  render() {
+    var status = a => <h3>{ a }</h3>;
+    var big_status = a => <h2>{ a }</h2>;
+    var card = (...children) => <div style={{ width: "90%" , margin: "0 auto 1em auto" }}>
+      <c.Card style={{ width: "100%" , padding: "0.5em 0.5em 0.5em 0.5em" }}>
+        <div style={{ padding: "1rem 1rem 1rem 1rem" }}>
+          { children }
+        </div>
+      </c.Card>
+    </div>;
+
+    let game = this.state.game.interface;
+    let data = game.data;
+    let annotations = null;
+    if (data.who_played) {
+      annotations = [];
+      for (let who_player of data.who_played) {
+        let annotation = <div key={ who_player.id }><Avatar src={ gravatarify(who_player) } name={ who_player.display } size="medium" /> <span title={ who_player.display }>{ who_player.display }</span></div>;
+        annotations.push(annotation);
+      }
+    }
+
+    var hand = (selectable) => card(
+      <h3 key="title">Hand</h3>,
+      <div key="hand">{ data.hand?.toImage(selectable ? this.selecting.bind(this) : null, handProps) }</div>
+    );
+
+    var auction = <BridgeAuction key="auction" auction={ data.auction } player_user={ index => game.player_user(index) } />;
+
+    if (!game.started) {
+      return status("Waiting for game to start …");
+    } else if (game.finished) {
+      return <div>
+        {status("Finished")}
+      </div>;
+    } else if (!game.dealt) {
+      return <div>
+        {
+          game.my_deal()
+          ? card(<Button key="deal" label="Deal!" unelevated ripple={false} onClick={() => game.deal()} />)
+          : card(<h3 key="wait">Waiting for the dealer to begin...</h3>)
+        }
+      </div>;
+    } else if (!game.bid) {
+      if (game.my_turn()) {
+        let calls = game.valid_calls();
+        let levels = [1, 2, 3, 4, 5, 6, 7].map(level => ({ label: "" + level, value: "" + level }));
+        let strains = Object.keys(BridgeStrains).map(strain => ({ label: BridgeStrains[strain].label + " " + BridgeStrains[strain].name, value: "" + strain }));
+        let can_bid = this.state.level !== null && this.state.strain !== null && game.valid_bid(this.state.level, this.state.strain);
+        return <div>
+          {
+            card(
+              <div key="bid">
+                { auction }
+                {big_status("Your call")}
+                <Select label="Level" enhanced options={ levels }
+                  value={ this.state.level === null ? "" : ""+this.state.level }
+                  onChange={ e => { let level = +e.currentTarget.value; this.setState(state => Object.assign(state, { level })) } }
+                />
+                <Select label="Strain" enhanced options={ strains }
+                  value={ this.state.strain === null ? "" : ""+this.state.strain }
+                  onChange={ e => { let strain = +e.currentTarget.value; this.setState(state => Object.assign(state, { strain })) } }
+                />
+                <br />
+                <Button label="Bid" raised ripple={false} disabled={ !can_bid } onClick={this.clearSelectAnd(() => game.makeBid(this.state.level, this.state.strain))} />
+                &nbsp;&nbsp;
+                <Button label="Pass" raised ripple={false} onClick={this.clearSelectAnd(() => game.pass())} />
+                {
+                  calls.double
+                  ? <>&nbsp;&nbsp;<Button label="Double" raised ripple={false} onClick={this.clearSelectAnd(() => game.double())} /></>
+                  : null
+                }
+                {
+                  calls.redouble
+                  ? <>&nbsp;&nbsp;<Button label="Redouble" raised ripple={false} onClick={this.clearSelectAnd(() => game.redouble())} /></>
+                  : null
+                }
+              </div>
+            )
+          }
+          { hand(false) }
+        </div>;
+      }
+
+      return <div>
+        { card(auction, <h3 key="wait">Waiting for the auction …</h3>) }
+        { hand(false) }
+      </div>;
+    } else {
+      let declarer = game.player_user(data.contract?.declarer);
+      let contract = status(
+        "Contract: " + contractString(data.contract) + (declarer ? " by " + (+declarer.id === +this.props.user.id ? "you" : declarer.display) : "")
+      );
+
+      // Once the opening lead is made, the dummy lays their hand face up; the
+      // declarer plays from it on the dummy's turn.
+      let dummy = null;
+      if (data.dummy_hand && !game.is_dummy()) {
+        let dummy_user = game.player_user((+data.contract.declarer + 2) % 4);
+        dummy = card(
+          <h3 key="title">Dummy{ dummy_user ? " (" + dummy_user.display + ")" : "" }</h3>,
+          <div key="hand">{ data.dummy_hand.toImage(game.playing_dummy() ? this.selecting.bind(this) : null, handProps) }</div>
+        );
+      }
+
+      let already_played = +data.played?.cards.length;
+      if (game.my_turn()) {
+        let leading = !already_played || already_played >= 4;
+        return <div>
+          {
+            card(
+              <div key="play">
+                { contract }
+                {status(leading ? (already_played ? "You took it, lead the next trick!" : "You lead off!") : already_played === 1 ? "This card was led" : "These cards have been played")}
+                { data.played?.toImage(null, null, annotations) }
+                {big_status(game.playing_dummy() ? "Play from the dummy" : "Your turn to play")}
+                {status("Choose a card")}
+                <Button label={ this.state.selected ? "Play this card" : "Pick a card!" } unelevated ripple={false} disabled={ !this.state.selected }
+                  onClick={this.clearSelectAnd(() => game.play(this.state.selected)) } />
+              </div>
+            )
+          }
+          { dummy }
+          { hand(!game.playing_dummy()) }
+        </div>;
+      }
+
+      return <div>
+        {
+          card(
+            <div key="play">
+              { contract }
+              { data.played?.toImage(null, null, annotations) }
+              {
+                game.is_dummy()
+                ? status("You're the dummy; your partner plays your hand.")
+                : status("Waiting for the other players to play …")
+              }
+            </div>
+          )
+        }
+        { dummy }
+        { hand(false) }
+      </div>;
+    }
+  }
+}
+
+export {
+  BridgeGameComponent,
+  BridgeAuction,
+};
@@ -4,6 +4,12 @@ import { ThreeThirteenGame } from '../../games/threethirteen.js';
 import { HeartsGame } from '../../games/hearts.js';
 import { EightJacksGame } from '../../games/eightjacks.js';
 import { GinGame } from '../../games/gin.js';
+import { EuchreGame } from '../../games/euchre.js';
+import { CribbageGame } from '../../games/cribbage.js';
+import { BridgeGame } from '../../games/bridge.js';
+import { OhHellGame } from '../../games/ohhell.js';
+import { CrazyEightsGame } from '../../games/crazyeights.js';
+import { WordSearchGame } from '../../games/wordsearch.js';
 
 import { killable } from '../../utils/killable.js';
 
@@ -25,6 +31,18 @@ function loadGame(game) {
       game.interface = new EightJacksGame(game);
     } else if (mode === "gin") {
       game.interface = new GinGame(game);
+    } else if (mode === "euchre") {
+      game.interface = new EuchreGame(game);
+    } else if (mode === "cribbage") {
+      game.interface = new CribbageGame(game);
+    } else if (mode === "bridge") {
+      game.interface = new BridgeGame(game);
+    } else if (mode === "oh hell") {
+      game.interface = new OhHellGame(game);
+    } else if (mode === "crazy eights") {
+      game.interface = new CrazyEightsGame(game);
+    } else if (mode === "word search") {
+      game.interface = new WordSearchGame(game);
     } else {
       console.log("Unknown game mode:", mode);
     }
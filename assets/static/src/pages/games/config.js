@@ -277,16 +277,45 @@ class CreateGameForm extends React.Component {
           : (
             +this.state.frequency === 2 ?
             <p>This uses the frequency breakdown of Bananagrams, scaled to the size of the pool.</p>
-            :
-            <p>This uses the frequency breakdown of Scrabble, scaled to the size of the pool.</p>
+            : (
+              +this.state.frequency === 3 ?
+              <p>This uses the frequency breakdown of Scrabble, scaled to the size of the pool.</p>
+              :
+              <p>This uses your own letter distribution: list each letter with its weight, such as "A:9 B:2 C:2".</p>
+            )
           )
         }
+        {
+          +this.state.frequency === 4
+          ? this.renderField(cfg.options[12])
+          : null
+        }
+        { this.renderField(cfg.options[11]) }
         <br />
         { this.renderField(cfg.options[4]) }
         { this.renderField(cfg.options[5]) }
         { this.renderField(cfg.options[6]) }
         <p>Each player will start with { pl(this.state.start_size, "tile") }. Each draw will be { pl(this.state.draw_size, "tile") }, and players who discard a tile will need to draw { this.state.discard_penalty } back.</p>
         <br/>
+        { this.renderField(cfg.options[7]) }
+        {
+          this.state.hints
+          ? this.renderField(cfg.options[8])
+          : null
+        }
+        { this.renderField(cfg.options[9]) }
+        { this.renderField(cfg.options[10]) }
+        <br/>
+        { this.renderField(cfg.options[13]) }
+        {
+          +this.state.scoring !== 0
+          ? <>
+              { this.renderField(cfg.options[14]) }
+              { this.renderField(cfg.options[15]) }
+            </>
+          : null
+        }
+        { this.renderField(cfg.options[16]) }
       </>
     );
   }
@@ -331,6 +360,11 @@ class CreateGameForm extends React.Component {
         { this.renderField(cfg.options[15]) }
         <l.ListGroupSubheader>General Options</l.ListGroupSubheader>
         { this.renderField(cfg.options[16]) }
+        { this.renderField(cfg.options[17]) }
+        <l.ListGroupSubheader>Clock Options</l.ListGroupSubheader>
+        { this.renderField(cfg.options[18]) }
+        { this.renderField(cfg.options[19]) }
+        { this.renderField(cfg.options[20]) }
       </>
     );
   }
@@ -363,6 +397,10 @@ class CreateGameForm extends React.Component {
         { this.renderField(cfg.options[13]) }
         <l.ListGroupSubheader>General Options</l.ListGroupSubheader>
         { this.renderField(cfg.options[14]) }
+        <l.ListGroupSubheader>Clock Options</l.ListGroupSubheader>
+        { this.renderField(cfg.options[15]) }
+        { this.renderField(cfg.options[16]) }
+        { this.renderField(cfg.options[17]) }
       </>
     );
   }
@@ -385,11 +423,24 @@ class CreateGameForm extends React.Component {
         { this.renderField(cfg.options[5]) }
         { this.renderField(cfg.options[6]) }
         { this.renderField(cfg.options[7]) }
-        <l.ListGroupSubheader>Hand Options</l.ListGroupSubheader>
         { this.renderField(cfg.options[8]) }
-        { this.renderField(cfg.options[9]) }
-        <l.ListGroupSubheader>General Options</l.ListGroupSubheader>
+        {
+          +this.state.board_layout === 5
+          ? <>
+              { this.renderField(cfg.options[9]) }
+              <p>Enter one row of card codes per line (or separate rows with /), such as <code>AS 10H KD *</code>. Use * for a wild square; jacks can't be placed on the board. Every other card must appear the same number of times, which sets how many decks are used.</p>
+            </>
+          : null
+        }
+        <l.ListGroupSubheader>Hand Options</l.ListGroupSubheader>
         { this.renderField(cfg.options[10]) }
+        { this.renderField(cfg.options[11]) }
+        <l.ListGroupSubheader>General Options</l.ListGroupSubheader>
+        { this.renderField(cfg.options[12]) }
+        <l.ListGroupSubheader>Clock Options</l.ListGroupSubheader>
+        { this.renderField(cfg.options[13]) }
+        { this.renderField(cfg.options[14]) }
+        { this.renderField(cfg.options[15]) }
       </>
     );
   }
@@ -423,6 +474,11 @@ class CreateGameForm extends React.Component {
         { this.renderField(cfg.options[15]) }
         <l.ListGroupSubheader>General Options</l.ListGroupSubheader>
         { this.renderField(cfg.options[16]) }
+        { this.renderField(cfg.options[17]) }
+        <l.ListGroupSubheader>Clock Options</l.ListGroupSubheader>
+        { this.renderField(cfg.options[18]) }
+        { this.renderField(cfg.options[19]) }
+        { this.renderField(cfg.options[20]) }
       </>
     );
   }
@@ -451,6 +507,163 @@ class CreateGameForm extends React.Component {
         { this.renderField(cfg.options[10]) }
         <l.ListGroupSubheader>General Options</l.ListGroupSubheader>
         { this.renderField(cfg.options[11]) }
+        <l.ListGroupSubheader>Clock Options</l.ListGroupSubheader>
+        { this.renderField(cfg.options[12]) }
+        { this.renderField(cfg.options[13]) }
+        { this.renderField(cfg.options[14]) }
+      </>
+    );
+  }
+
+  renderEuchre() {
+    var cfg = this.state.GameConfig.euchre;
+    if (!cfg) {
+      return null;
+    }
+
+    return (
+      <>
+        <l.ListGroupSubheader>Game Options</l.ListGroupSubheader>
+        { this.renderField(cfg.options[0]) }
+        <l.ListGroupSubheader>Playing Options</l.ListGroupSubheader>
+        { this.renderField(cfg.options[1]) }
+        { this.renderField(cfg.options[2]) }
+        <l.ListGroupSubheader>Scoring Options</l.ListGroupSubheader>
+        { this.renderField(cfg.options[3]) }
+        <l.ListGroupSubheader>General Options</l.ListGroupSubheader>
+        { this.renderField(cfg.options[4]) }
+        <l.ListGroupSubheader>Clock Options</l.ListGroupSubheader>
+        { this.renderField(cfg.options[5]) }
+        { this.renderField(cfg.options[6]) }
+        { this.renderField(cfg.options[7]) }
+      </>
+    );
+  }
+
+  renderCribbage() {
+    var cfg = this.state.GameConfig.cribbage;
+    if (!cfg) {
+      return null;
+    }
+
+    return (
+      <>
+        <l.ListGroupSubheader>Game Options</l.ListGroupSubheader>
+        { this.renderField(cfg.options[0]) }
+        <l.ListGroupSubheader>Scoring Options</l.ListGroupSubheader>
+        { this.renderField(cfg.options[1]) }
+        { this.renderField(cfg.options[2]) }
+        <l.ListGroupSubheader>General Options</l.ListGroupSubheader>
+        { this.renderField(cfg.options[3]) }
+        <l.ListGroupSubheader>Clock Options</l.ListGroupSubheader>
+        { this.renderField(cfg.options[4]) }
+        { this.renderField(cfg.options[5]) }
+        { this.renderField(cfg.options[6]) }
+      </>
+    );
+  }
+
+  renderBridge() {
+    var cfg = this.state.GameConfig.bridge;
+    if (!cfg) {
+      return null;
+    }
+
+    return (
+      <>
+        <l.ListGroupSubheader>Game Options</l.ListGroupSubheader>
+        { this.renderField(cfg.options[0]) }
+        <l.ListGroupSubheader>Scoring Options</l.ListGroupSubheader>
+        { this.renderField(cfg.options[1]) }
+        {
+          +this.state.scoring === 0
+          ? this.renderField(cfg.options[2])
+          : this.renderField(cfg.options[3])
+        }
+        <l.ListGroupSubheader>General Options</l.ListGroupSubheader>
+        { this.renderField(cfg.options[4]) }
+        <l.ListGroupSubheader>Clock Options</l.ListGroupSubheader>
+        { this.renderField(cfg.options[5]) }
+        { this.renderField(cfg.options[6]) }
+        { this.renderField(cfg.options[7]) }
+      </>
+    );
+  }
+
+  renderOhHell() {
+    var cfg = this.state.GameConfig['oh hell'];
+    if (!cfg) {
+      return null;
+    }
+
+    return (
+      <>
+        <l.ListGroupSubheader>Game Options</l.ListGroupSubheader>
+        { this.renderField(cfg.options[0]) }
+        { this.renderField(cfg.options[1]) }
+        { this.renderField(cfg.options[2]) }
+        <l.ListGroupSubheader>Playing Options</l.ListGroupSubheader>
+        { this.renderField(cfg.options[3]) }
+        { this.renderField(cfg.options[4]) }
+        { this.renderField(cfg.options[5]) }
+        <l.ListGroupSubheader>Scoring Options</l.ListGroupSubheader>
+        { this.renderField(cfg.options[6]) }
+        <l.ListGroupSubheader>General Options</l.ListGroupSubheader>
+        { this.renderField(cfg.options[7]) }
+        <l.ListGroupSubheader>Clock Options</l.ListGroupSubheader>
+        { this.renderField(cfg.options[8]) }
+        { this.renderField(cfg.options[9]) }
+        { this.renderField(cfg.options[10]) }
+      </>
+    );
+  }
+
+  renderCrazyEights() {
+    var cfg = this.state.GameConfig['crazy eights'];
+    if (!cfg) {
+      return null;
+    }
+
+    return (
+      <>
+        <l.ListGroupSubheader>Game Options</l.ListGroupSubheader>
+        { this.renderField(cfg.options[0]) }
+        { this.renderField(cfg.options[1]) }
+        { this.renderField(cfg.options[2]) }
+        <l.ListGroupSubheader>Special Cards</l.ListGroupSubheader>
+        { this.renderField(cfg.options[3]) }
+        { this.renderField(cfg.options[4]) }
+        { this.renderField(cfg.options[5]) }
+        { this.renderField(cfg.options[6]) }
+        <l.ListGroupSubheader>General Options</l.ListGroupSubheader>
+        { this.renderField(cfg.options[7]) }
+        <l.ListGroupSubheader>Clock Options</l.ListGroupSubheader>
+        { this.renderField(cfg.options[8]) }
+        { this.renderField(cfg.options[9]) }
+        { this.renderField(cfg.options[10]) }
+      </>
+    );
+  }
+
+  renderWordSearch() {
+    var cfg = this.state.GameConfig['word search'];
+    if (!cfg) {
+      return null;
+    }
+
+    return (
+      <>
+        <l.ListGroupSubheader>Player Options</l.ListGroupSubheader>
+        { this.renderField(cfg.options[0]) }
+        <l.ListGroupSubheader>Grid Options</l.ListGroupSubheader>
+        { this.renderField(cfg.options[1]) }
+        { this.renderField(cfg.options[2]) }
+        <l.ListGroupSubheader>Word Options</l.ListGroupSubheader>
+        { this.renderField(cfg.options[3]) }
+        { this.renderField(cfg.options[4]) }
+        { this.renderField(cfg.options[5]) }
+        <l.ListGroupSubheader>General Options</l.ListGroupSubheader>
+        { this.renderField(cfg.options[6]) }
       </>
     );
   }
@@ -477,6 +690,18 @@ class CreateGameForm extends React.Component {
       config = this.renderHearts();
     } else if (this.state.mode === 'gin') {
       config = this.renderGin();
+    } else if (this.state.mode === 'euchre') {
+      config = this.renderEuchre();
+    } else if (this.state.mode === 'cribbage') {
+      config = this.renderCribbage();
+    } else if (this.state.mode === 'bridge') {
+      config = this.renderBridge();
+    } else if (this.state.mode === 'oh hell') {
+      config = this.renderOhHell();
+    } else if (this.state.mode === 'crazy eights') {
+      config = this.renderCrazyEights();
+    } else if (this.state.mode === 'word search') {
+      config = this.renderWordSearch();
     } else if (this.state.mode !== null) {
       console.log("Unknown game mode: " + this.state.mode, this.state);
     }
@@ -0,0 +1,245 @@
+import React from 'react';
+
+import '../../../main.scss';
+
+import { Avatar } from '@rmwc/avatar';
+import '@rmwc/avatar/styles';
+import { Button } from '@rmwc/button';
+import '@rmwc/button/styles';
+import * as c from '@rmwc/card';
+import '@rmwc/card/styles';
+import * as l from '@rmwc/list';
+import '@rmwc/list/styles';
+
+import { CardHand, CardSuit, Card } from '../../../games/card.js';
+import { loadGame, addEv, notify, killable } from '../../games.js';
+import { UserCache, GameCache } from '../../../utils/cache.js';
+import { gravatarify } from '../../../utils/gravatar.js';
+
+// Properties used for display card hands
+var handProps = {
+  overlap: true,
+  curve: true,
+  scale: 0.50,
+};
+
+class CrazyEightsAfterPartyComponent extends React.Component {
+  constructor(props) {
+    super(props);
+    this.game = loadGame(this.props.game);
+    this.state = {
+      game: props.game,
+      player_mapping: null,
+      moves: null,
+      hands: null,
+      active: {
+        turn: null,
+        top: null,
+        suit: null,
+      },
+      winner: null,
+      finished: false,
+      message: "Loading results...",
+      timeout: killable(() => { this.refreshData() }, 5000),
+    };
+
+    GameCache.Invalidate(this.props.game.id);
+
+    this.unmount = addEv(this.game, {
+      "game-state": async (data) => {
+        var mapping = {};
+        for (let index in data.player_mapping) {
+          mapping[index] = await UserCache.FromId(data.player_mapping[index]);
+        }
+
+        let winner = data.finished && data.winner ? await UserCache.FromId(data.winner) : null;
+        let turn = data.turn ? await UserCache.FromId(data.turn) : null;
+
+        // HACK: When refreshData() is called from the button, we don't redraw
+        // the screen even though new data is sent. Use snapshots to send only
+        // the data we care about.
+        this.setState(state => Object.assign({}, state, { moves: null }));
+        this.setState(state => Object.assign({}, state, {
+          player_mapping: mapping,
+          moves: data.moves || [],
+          hands: data.hands ? data.hands.map(hand => CardHand.deserialize(hand || []).cardSort(true, true, false)) : null,
+          winner: winner,
+          finished: data.finished,
+          active: {
+            turn: turn,
+            top: data.top && data.top.rank ? Card.deserialize(data.top) : null,
+            suit: data.suit ? CardSuit.deserialize(data.suit) : null,
+          },
+        }));
+
+        if (data.finished) {
+          if (this.state.timeout) {
+            this.state.timeout.kill();
+          }
+
+          this.setState(state => Object.assign({}, state, { timeout: null }));
+        }
+      },
+      "error": (data) => {
+        var message = "Unable to load game data.";
+        if (data.error) {
+          message = data.error;
+        }
+
+        notify(this.props.snackbar, message, data.message_type);
+        this.setState(state => Object.assign({}, state, { message }));
+      },
+      "": data => {
+        if (data.message) {
+          notify(this.props.snackbar, data.message, data.message_type);
+        }
+      },
+    });
+  }
+  componentDidMount() {
+    this.state.timeout.exec();
+  }
+  componentWillUnmount() {
+    this.props.setGame(null);
+
+    if (this.state.timeout) {
+      this.state.timeout.kill();
+    }
+
+    if (this.unmount) this.unmount();
+  }
+  async refreshData() {
+    await this.game.interface.controller.wsController.sendAndWait({"message_type": "peek"});
+
+    if (this.state.finished) {
+      if (this.state.timeout) {
+        this.state.timeout.kill();
+        this.setState(state => Object.assign({}, state, { timeout: null }));
+      }
+    }
+  }
+  returnToRoom() {
+    if (this.props.game.interface) {
+      this.props.game.interface.close();
+    }
+
+    this.props.game.interface = null;
+
+    this.props.setGame(null);
+    this.props.setPage("room", true);
+  }
+  render() {
+    var sigil = (t,c) => <span style={{ fontSize: "170%", color: c }}>{ t }</span>;
+    var current_round = null;
+
+    if (!this.state.finished) {
+      current_round = <div>
+        <div style={{ width: "90%" , margin: "0 auto 1em auto" }}>
+          <c.Card style={{ width: "100%" , padding: "0.5em 0.5em 0.5em 0.5em" }}>
+            <div style={{ padding: "1rem 1rem 1rem 1rem" }}>
+              {
+                this.state.active.top
+                ? <>
+                    { this.state.active.top.toImage() }
+                    <br />
+                    { this.state.active.suit?.toUnicode() ? <>Suit: { sigil(this.state.active.suit.toUnicode(), this.state.active.suit.toColor()) }<br /></> : null }
+                    { this.state.active.turn ? "Waiting for " + this.state.active.turn.display + " to play..." : null }
+                  </>
+                : "Please wait for the game to begin..."
+              }
+            </div>
+          </c.Card>
+        </div>
+      </div>;
+    }
+
+    var historical_data = null;
+
+    if (this.state.finished && this.state.player_mapping) {
+      let hands_data = [];
+      for (let player_index in this.state.hands || []) {
+        let user = this.state.player_mapping[player_index];
+        let hand = this.state.hands[player_index];
+        hands_data.push(
+          <div key={ user.id }>
+            <b>{ user.display }</b>: { hand.cards.length } card{ hand.cards.length === 1 ? "" : "s" } left
+            <div style={{ paddingTop: '15px', paddingBottom: '15px' }}>
+              { hand.cards.length > 0 ? hand.toImage(handProps) : null }
+            </div>
+          </div>
+        );
+      }
+
+      let moves_data = [];
+      for (let move_index in this.state.moves || []) {
+        let move = this.state.moves[move_index];
+        let user = this.state.player_mapping[move.player];
+        let description = null;
+        if (move.card) {
+          let played = Card.deserialize(move.card);
+          let suit = new CardSuit(move.suit);
+          description = <>played { played.toString() }{ +played.suit.value !== +move.suit && suit.toUnicode() ? <> and named { sigil(suit.toUnicode(), suit.toColor()) }</> : null }</>;
+        } else if (move.drew) {
+          description = "drew " + move.drew + " card" + (+move.drew === 1 ? "" : "s");
+        } else if (move.passed) {
+          description = "passed";
+        }
+
+        moves_data.push(
+          <div key={ move_index }>
+            <Avatar src={ gravatarify(user) } name={ user.display } size="medium" /> { user.display } { description }
+          </div>
+        );
+      }
+
+      historical_data = <div style={{ width: "90%" , margin: "0 auto 0.5em auto" }}>
+        <c.Card style={{ width: "100%" , padding: "0.5em 0.5em 0.5em 0.5em" }}>
+          <div>
+            <h3>Game Analysis</h3>
+            <div style={{ textAlign: 'left' }}>
+              <l.CollapsibleList handle={
+                  <l.SimpleListItem text={ <b>Final Hands</b> } metaIcon="chevron_right" />
+                }
+              >
+                <div style={{ textAlign: 'center' }}>
+                  { hands_data }
+                </div>
+              </l.CollapsibleList>
+              <l.CollapsibleList handle={
+                  <l.SimpleListItem text={ <b>Moves</b> } metaIcon="chevron_right" />
+                }
+              >
+                <div style={{ textAlign: 'left' }}>
+                  { moves_data }
+                </div>
+              </l.CollapsibleList>
+            </div>
+          </div>
+        </c.Card>
+      </div>;
+    }
+
+    var winner_info = <h1>Please wait while the game finishes...</h1>;
+    if (this.state.finished && this.state.winner) {
+      winner_info = <h1 style={{ color: "#249724" }}>{ +this.state.winner.id === +this.props.user.id ? "You" : this.state.winner.display } won!</h1>
+    }
+
+    return (
+      <div>
+        <h1 style={{ color: "#2b6b2b" }}>Crazy Eights</h1>
+        <div>
+          { winner_info }
+          {
+            this.props.room ? <><Button onClick={ () => this.returnToRoom() } raised >Return to Room</Button><br /><br /></> : <></>
+          }
+          { current_round }
+          { historical_data }
+        </div>
+      </div>
+    );
+  }
+}
+
+export {
+  CrazyEightsAfterPartyComponent
+};
@@ -0,0 +1,147 @@
+import React from 'react';
+
+import '../../../main.scss';
+
+import { Button } from '@rmwc/button';
+import '@rmwc/button/styles';
+import * as c from '@rmwc/card';
+import '@rmwc/card/styles';
+import { Select } from '@rmwc/select';
+import '@rmwc/select/styles';
+
+// Properties used for display card hands
+var handProps = {
+  overlap: true,
+  curve: true,
+  scale: 0.50,
+};
+
+class CrazyEightsGameComponent extends React.Component {
+  constructor(props) {
+    super(props);
+    this.state = {};
+    this.state.game = this.props.game;
+    this.state.selected = null;
+    this.state.suit = null;
+    // FIXME: hack?
+    let old_handler = this.state.game.interface.onChange;
+    this.state.game.interface.onChange = () => {
+      old_handler();
+      this.setState(state => {
+        // Jinx
+        return state;
+      });
+    };
+  }
+  clearSelectAnd(then) {
+    return (...arg) => {
+      this.setState(state => Object.assign(state, {
+        selected: null,
+        suit: null,
+      }));
+      return then && then(...arg);
+    };
+  }
+  selecting(card) {
+    return Object.assign(card, {
+      selected: this.state.selected === card.id,
+      onClick: () => {
+        this.setState(state => {
+          if (state.selected === card.id)
+            state.selected = null;
+          else
+            state.selected = card.id;
+          return state;
+        });
+      },
+    });
+  }
+  render() {
+    var status = a => <h3>{ a }</h3>;
+    var big_status = a => <h2>{ a }</h2>;
+    var card = (...children) => <div style={{ width: "90%" , margin: "0 auto 1em auto" }}>
+      <c.Card style={{ width: "100%" , padding: "0.5em 0.5em 0.5em 0.5em" }}>
+        <div style={{ padding: "1rem 1rem 1rem 1rem" }}>
+          { children }
+        </div>
+      </c.Card>
+    </div>;
+
+    let game = this.state.game.interface;
+    let data = game.data;
+
+    var hand = (selectable) => card(
+      <h3 key="title">Hand</h3>,
+      <div key="hand">{ data.hand?.toImage(selectable ? this.selecting.bind(this) : null, handProps) }</div>
+    );
+
+    var suit = data.suit && data.suit.toUnicode()
+      ? <span style={{ color: data.suit.toColor() }}>{ data.suit.toUnicode() + " " + data.suit.toString() }</span>
+      : null;
+    var pile = <div key="pile">
+      {status("Top of the discard pile:")}
+      { data.top?.toImage() }
+      {
+        data.top && suit && +data.top.suit.value !== +data.suit.value
+        ? <h3>An eight named { suit }</h3>
+        : null
+      }
+      <p>{ data.draw_pile } card{ +data.draw_pile === 1 ? "" : "s" } left to draw</p>
+    </div>;
+
+    if (!game.started) {
+      return status("Waiting for game to start …");
+    } else if (game.finished) {
+      return <div>
+        {status("Finished")}
+      </div>;
+    } else if (game.my_turn()) {
+      let selected = this.state.selected === null ? null : data.hand?.cards.find(hand_card => hand_card.id === this.state.selected);
+      let playable = game.can_play(selected);
+      let wild = selected && game.is_wild(selected);
+      return <div>
+        {
+          card(
+            pile,
+            <div key="play">
+              {big_status("Your turn to play")}
+              {status(data.drawn ? "Play a card or pass" : "Play a card or draw one")}
+              {
+                wild
+                ? <><Select label="Name a suit" enhanced options={ game.suits() }
+                    value={ this.state.suit === null ? "" : ""+this.state.suit }
+                    onChange={ e => { let suit = +e.currentTarget.value; this.setState(state => Object.assign(state, { suit })) } }
+                  /><br /></>
+                : null
+              }
+              <Button label={ !selected ? "Pick a card!" : playable ? "Play this card" : "Can't play this card" } unelevated ripple={false}
+                disabled={ !playable || (wild && !this.state.suit) }
+                onClick={this.clearSelectAnd(() => game.play(selected.id, wild ? this.state.suit : 0)) } />
+              &nbsp;&nbsp;
+              {
+                !data.drawn && +data.draw_pile + +data.discard_pile > 1
+                ? <Button label="Draw a card" raised ripple={false} onClick={this.clearSelectAnd(() => game.draw())} />
+                : null
+              }
+              {
+                game.can_pass()
+                ? <Button label="Pass" raised ripple={false} onClick={this.clearSelectAnd(() => game.pass())} />
+                : null
+              }
+            </div>
+          )
+        }
+        { hand(true) }
+      </div>;
+    }
+
+    return <div>
+      { card(pile, <h3 key="wait">Waiting for the other players to play …</h3>) }
+      { hand(false) }
+    </div>;
+  }
+}
+
+export {
+  CrazyEightsGameComponent
+};
@@ -0,0 +1,87 @@
+import React from 'react';
+
+import '../../../main.scss';
+
+import { Avatar } from '@rmwc/avatar';
+import '@rmwc/avatar/styles';
+import * as c from '@rmwc/card';
+import '@rmwc/card/styles';
+
+import { GameSynopsis, sortSynopsisPlayers } from '../synopsis.js';
+import { CardSuit } from '../../../games/card.js';
+import { gravatarify } from '../../../utils/gravatar.js';
+import { PlayerAvatar } from '../../../utils/player.js';
+
+class CrazyEightsGameSynopsis extends GameSynopsis {
+  constructor(props) {
+    super(props);
+
+    this.state = this.newState();
+
+    let old_handler = this.props.game.interface.onChange;
+    this.props.game.interface.onChange = () => {
+      old_handler();
+      this.setState(state => this.newState());
+    };
+  }
+
+  newState() {
+    let new_state = { indexed_players: {}, spectators: {}, suit: undefined, direction: 1 };
+    sortSynopsisPlayers(this.props.game.interface?.synopsis, new_state);
+    if (this.props.game.interface?.synopsis?.suit) {
+      new_state.suit = CardSuit.deserialize(this.props.game.interface.synopsis.suit);
+    }
+    if (this.props.game.interface?.synopsis?.direction) {
+      new_state.direction = +this.props.game.interface.synopsis.direction;
+    }
+    return new_state;
+  }
+
+  render() {
+    var sigil = (t,c) => <span style={{ fontSize: "170%", color: c }}>{ t }</span>
+    var synopsis_columns = {
+      "user":{
+        name: "User",
+        printer: (user,player) =>
+          <PlayerAvatar user={ user }
+            size={ user.id === this.props.user.id ? "xlarge" : "large" }
+            loading={ player.is_turn }
+            />,
+      },
+      "cards_left":"Cards",
+    };
+    var spectator_columns = {
+      "user":{
+        name: "User",
+        printer: user => <Avatar src={ gravatarify(user) } name={ user.display } size={ user.id === this.props.user.id ? "xlarge" : "large" } />,
+      },
+    };
+
+    var player_view = this.renderPlayerView(synopsis_columns, spectator_columns);
+
+    var suit = null;
+    if (this.state.suit && this.state.suit.toUnicode()) {
+      suit = <span style={{ fontStyle: "italic" }}>
+        Suit: { sigil(this.state.suit.toUnicode(), this.state.suit.toColor()) }
+        &nbsp;&nbsp;
+        { this.state.direction < 0 ? "Playing right ↺" : "Playing left ↻" }
+      </span>;
+    }
+
+    return (
+      <div className="fit-content" style={{ margin: "0 auto 1em auto" }}>
+        <c.Card className="fit-content" style={{ padding: "0.5em 0.5em 0.5em 0.5em" }}>
+          <div className="scrollable-x">
+            <h1 style={{ marginBottom: suit ? 0 : null, color: "#2b6b2b" }}>Crazy Eights</h1>
+            { suit }
+            { player_view }
+          </div>
+        </c.Card>
+      </div>
+    );
+  }
+}
+
+export {
+  CrazyEightsGameSynopsis
+};
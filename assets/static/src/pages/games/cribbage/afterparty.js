@@ -0,0 +1,411 @@
+import React from 'react';
+
+import '../../../main.scss';
+
+import { Avatar } from '@rmwc/avatar';
+import '@rmwc/avatar/styles';
+import { Button } from '@rmwc/button';
+import '@rmwc/button/styles';
+import { IconButton } from '@rmwc/icon-button';
+import '@rmwc/icon-button/styles';
+import * as c from '@rmwc/card';
+import '@rmwc/card/styles';
+import * as l from '@rmwc/list';
+import '@rmwc/list/styles';
+
+import { CardHand, Card } from '../../../games/card.js';
+import { loadGame, addEv, notify, killable } from '../../games.js';
+import { UserCache, GameCache } from '../../../utils/cache.js';
+import { gravatarify } from '../../../utils/gravatar.js';
+import { CribbageShows } from './component.js';
+
+// Properties used for display card hands
+var handProps = {
+  overlap: true,
+  curve: true,
+  scale: 0.50,
+};
+
+class CribbageAfterPartyComponent extends React.Component {
+  constructor(props) {
+    super(props);
+    this.game = loadGame(this.props.game);
+    this.state = {
+      game: props.game,
+      player_mapping: null,
+      history: null,
+      historical_round: 0,
+      active: {
+        turn: null,
+        dealer: null,
+        starter: null,
+        pile: null,
+        count: 0,
+      },
+      winners: this.game?.winners,
+      scores: null,
+      dealt: false,
+      cut: false,
+      pegged: false,
+      finished: false,
+      message: "Loading results...",
+      timeout: killable(() => { this.refreshData() }, 5000),
+    };
+
+    GameCache.Invalidate(this.props.game.id);
+
+    this.unmount = addEv(this.game, {
+      "game-state": async (data) => {
+        var mapping = {};
+        for (let index in data.player_mapping) {
+          mapping[index] = await UserCache.FromId(data.player_mapping[index]);
+        }
+
+        let winners = [];
+        if (data.winners) {
+          for (let uid of data.winners) {
+            winners.push(await UserCache.FromId(uid));
+          }
+        }
+
+        let turn = data.turn ? await UserCache.FromId(data.turn) : null;
+        let dealer = data.dealer ? await UserCache.FromId(data.dealer) : null;
+
+        // HACK: When refreshData() is called from the button, we don't redraw
+        // the screen even though new data is sent. Use snapshots to send only
+        // the data we care about.
+        this.setState(state => Object.assign({}, state, { history: null }));
+        this.setState(state => Object.assign({}, state, {
+          player_mapping: mapping,
+          history: data.round_history || [],
+          winners: winners,
+          scores: data.scores,
+          dealt: data.dealt,
+          cut: data.cut,
+          pegged: data.pegged,
+          finished: data.finished,
+          active: {
+            turn: turn,
+            dealer: dealer,
+            starter: data.starter ? Card.deserialize(data.starter) : null,
+            pile: data.pile ? CardHand.deserialize(data.pile) : null,
+            count: data.count,
+          },
+        }));
+
+        if (data.finished) {
+          if (this.state.timeout) {
+            this.state.timeout.kill();
+          }
+
+          this.setState(state => Object.assign({}, state, { timeout: null }));
+        }
+      },
+      "error": (data) => {
+        var message = "Unable to load game data.";
+        if (data.error) {
+          message = data.error;
+        }
+
+        notify(this.props.snackbar, message, data.message_type);
+        this.setState(state => Object.assign({}, state, { message }));
+      },
+      "": data => {
+        if (data.message) {
+          notify(this.props.snackbar, data.message, data.message_type);
+        }
+      },
+    });
+  }
+  componentDidMount() {
+    this.state.timeout.exec();
+  }
+  componentWillUnmount() {
+    this.props.setGame(null);
+
+    if (this.state.timeout) {
+      this.state.timeout.kill();
+    }
+
+    if (this.unmount) this.unmount();
+  }
+  async refreshData() {
+    await this.game.interface.controller.wsController.sendAndWait({"message_type": "peek"});
+
+    if (this.state.finished) {
+      if (this.state.timeout) {
+        this.state.timeout.kill();
+        this.setState(state => Object.assign({}, state, { timeout: null }));
+      }
+    }
+  }
+  returnToRoom() {
+    if (this.props.game.interface) {
+      this.props.game.interface.close();
+    }
+
+    this.props.game.interface = null;
+
+    this.props.setGame(null);
+    this.props.setPage("room", true);
+  }
+  skip(amt) {
+    this.setState(state => {
+      var round = +state.historical_round + amt;
+      if (round < 0 || !state.history || round >= state.history.length) {
+        return state;
+      }
+
+      state.historical_round = round;
+      return state;
+    });
+  }
+  // Four players score as two partnerships; otherwise each player is their
+  // own side.
+  sides() {
+    return Object.keys(this.state.player_mapping).length === 4 ? 2 : Object.keys(this.state.player_mapping).length;
+  }
+  sideName(side) {
+    var names = [];
+    for (let player_index of Object.keys(this.state.player_mapping).sort()) {
+      if (+player_index % this.sides() === +side) {
+        let user = this.state.player_mapping[player_index];
+        names.push(+user.id === +this.props.user.id ? "You" : user.display);
+      }
+    }
+
+    return names.join(" and ");
+  }
+  render() {
+    var current_round = null;
+
+    if (!this.state.finished) {
+      current_round = <div>
+        <div style={{ width: "90%" , margin: "0 auto 1em auto" }}>
+          <c.Card style={{ width: "100%" , padding: "0.5em 0.5em 0.5em 0.5em" }}>
+            <div style={{ padding: "1rem 1rem 1rem 1rem" }}>
+              {
+                !this.state.dealt
+                ? "Please wait for the round to begin..."
+                : !this.state.cut
+                ? "Please wait for players to discard to the crib..."
+                : !this.state.pegged
+                ? <>
+                    { this.state.active.starter?.toImage() }
+                    <h3>Count: { this.state.active.count }</h3>
+                    { this.state.active.pile && this.state.active.pile.cards.length > 0 ? this.state.active.pile.toImage(handProps) : null }
+                  </>
+                : "Please wait for hands to be counted..."
+              }
+            </div>
+          </c.Card>
+        </div>
+      </div>;
+    }
+
+    var historical_data = null;
+    var scoreboard_data = null;
+
+    if (this.state.player_mapping && this.state.history && this.state.history.length > 0) {
+      let round_index = +this.state.historical_round;
+      let round = this.state.history[round_index];
+      let round_data = [];
+      if (round) {
+        let dealer = this.state.player_mapping[round.dealer];
+        let starter = round.starter ? Card.deserialize(round.starter) : null;
+        round_data.push(
+          <div key="summary">
+            {
+              dealer
+              ? <><b>Dealer</b>: <Avatar src={ gravatarify(dealer) } name={ dealer.display } size="medium" /> { dealer.display }<br /></>
+              : null
+            }
+            <b>Starter</b>: { starter?.toImage({ scale: 0.5 }) } { round.heels ? "(His heels)" : null }<br />
+          </div>
+        );
+
+        let hands_data = [];
+        for (let player_index in round.hands) {
+          let user = this.state.player_mapping[player_index];
+          let hand = round.hands[player_index] ? CardHand.deserialize(round.hands[player_index]).cardSort(true, false, false) : null;
+          hands_data.push(
+            <div key={ user.id }>
+              <l.List>
+                <l.CollapsibleList handle={
+                    <l.SimpleListItem text={ <b>{user.display + "'s"} Hand</b> } metaIcon="chevron_right" />
+                  }
+                >
+                  <div style={{ paddingTop: '15px', paddingBottom: '15px' }}>
+                    { hand ? hand.toImage(handProps) : null }
+                  </div>
+                </l.CollapsibleList>
+              </l.List>
+            </div>
+          );
+        }
+        let crib = round.crib ? CardHand.deserialize(round.crib).cardSort(true, false, false) : null;
+        if (crib) {
+          hands_data.push(
+            <div key="crib">
+              <l.List>
+                <l.CollapsibleList handle={
+                    <l.SimpleListItem text={ <b>Crib</b> } metaIcon="chevron_right" />
+                  }
+                >
+                  <div style={{ paddingTop: '15px', paddingBottom: '15px' }}>
+                    { crib.toImage(handProps) }
+                  </div>
+                </l.CollapsibleList>
+              </l.List>
+            </div>
+          );
+        }
+        round_data.push(
+          <l.CollapsibleList key="hands" handle={
+              <l.SimpleListItem text={ <b>Player Hands</b> } metaIcon="chevron_right" />
+            }
+          >
+            <div style={{ textAlign: 'center' }}>
+              { hands_data }
+            </div>
+          </l.CollapsibleList>
+        );
+
+        let pegging_data = [];
+        for (let peg_index in round.pegging) {
+          let peg = round.pegging[peg_index];
+          let user = this.state.player_mapping[peg.player];
+          let played = peg.card ? Card.deserialize(peg.card) : null;
+          pegging_data.push(
+            <div key={ peg_index }>
+              <Avatar src={ gravatarify(user) } name={ user.display } size="medium" /> { user.display }: { played ? played.toString() : "Go" } ({ peg.count }){ peg.points > 0 ? " +" + peg.points : null }
+            </div>
+          );
+        }
+        round_data.push(
+          <l.CollapsibleList key="pegging" handle={
+              <l.SimpleListItem text={ <b>Pegging</b> } metaIcon="chevron_right" />
+            }
+          >
+            <div style={{ textAlign: 'left' }}>
+              { pegging_data }
+            </div>
+          </l.CollapsibleList>
+        );
+
+        round_data.push(
+          <l.CollapsibleList key="shows" handle={
+              <l.SimpleListItem text={ <b>The Show</b> } metaIcon="chevron_right" />
+            }
+          >
+            <div style={{ textAlign: 'center' }}>
+              <CribbageShows shows={ round.shows } player_user={ index => this.state.player_mapping[index] } />
+            </div>
+          </l.CollapsibleList>
+        );
+      } else {
+        round_data = <b>No data found for round { round_index + 1 }!</b>;
+      }
+
+      historical_data = <div style={{ width: "90%" , margin: "0 auto 0.5em auto" }}>
+        <c.Card style={{ width: "100%" , padding: "0.5em 0.5em 0.5em 0.5em" }}>
+          <div>
+            <h3>Game Analysis</h3>
+            <div style={{ margin: "auto" }}>
+              <IconButton icon="fast_rewind" size="xsmall" onClick={ () => this.skip(-1) }/>
+              <div style={{ display: "inline-flex", flexDirection: "column", verticalAlign: "text-bottom" }}>
+                <h2 style={{ margin: 0 }}>Round { round_index + 1 }</h2>
+              </div>
+              <IconButton icon="fast_forward" size="xsmall" onClick={ () => this.skip(1) }/>
+            </div>
+            <div style={{ textAlign: 'left' }}>
+              { round_data }
+            </div>
+          </div>
+        </c.Card>
+      </div>;
+
+      var sides = [...Array(this.sides()).keys()];
+      var round_scores = [];
+      var final_scores = [];
+      var last_scores = sides.map(() => 0);
+      for (let index in this.state.history) {
+        let round = this.state.history[index];
+        let dealer = this.state.player_mapping[round.dealer];
+        let round_row = [];
+        round_row.push(<td key="round" style={{ borderTop: "10px solid transparent", borderBottom: "10px solid transparent" }}> { parseInt(index) + 1 } </td>);
+        round_row.push(<td key="dealer" style={{ whiteSpace: "nowrap" }}>{ dealer ? dealer.display : "–" }</td>);
+        for (let side of sides) {
+          let score = round.scores ? round.scores[side] : last_scores[side];
+          let incr = score - last_scores[side];
+          last_scores[side] = score;
+          round_row.push(<td key={ side+"score" } style={{ whiteSpace: "nowrap", textAlign: "right", paddingLeft: "10px" }}>{ score }&nbsp;</td>);
+          round_row.push(<td key={ side+"incr" } style={{ textAlign: "left", paddingRight: "10px", fontSize: "75%" }}>(+{ incr })</td>);
+        }
+        round_scores.push(<tr key={ index }>{ round_row }</tr>);
+      }
+      if (this.state.scores) {
+        for (let side of sides) {
+          final_scores.push(<td key={ side } colSpan={2} style={{ whiteSpace: "nowrap", borderTop: "1px solid #000" }}> { this.state.scores[side] } </td>);
+        }
+      }
+
+      scoreboard_data = <div className="fit-content" style={{ margin: "0 auto 0.5em auto", maxWidth: "90%" }}>
+        <c.Card className="fit-content" style={{ padding: "0.5em 0.5em 0.5em 0.5em", maxWidth: "100%" }}>
+          <div>
+            <h3>Score Board</h3>
+            <div style={{ overflow: "auto", maxWidth: "100%" }}>
+            <table style={{ fontSize: '1.2em', borderCollapse: "collapse", borderSpacing: 0 }}>
+              <thead>
+                <tr>
+                  <td style={{ paddingLeft: '15px', paddingRight: '15px' }}>Round</td>
+                  <td style={{ paddingLeft: '15px', paddingRight: '15px' }}>Dealer</td>
+                  {
+                    sides.map(side =>
+                      <td key={ side } colSpan={2} style={{ borderBottom: "1px solid #777", paddingLeft: '25px', paddingRight: '25px' }}>{ this.sideName(side) }</td>
+                    )
+                  }
+                </tr>
+              </thead>
+              <tbody>
+                { round_scores }
+              </tbody>
+              <tfoot>
+                <tr>
+                  <td colSpan={2}>Total</td>
+                  { final_scores }
+                </tr>
+              </tfoot>
+            </table>
+            </div>
+          </div>
+        </c.Card>
+      </div>;
+    }
+
+    var winner_info = <h1>Please wait while the game finishes...</h1>;
+    if (this.state.finished && this.state.winners && this.state.winners.length > 0) {
+      var winner_names = this.state.winners.map(winner => +winner.id === +this.props.user.id ? "You" : winner.display).join(" and ");
+      winner_info = <h1 style={{ color: "#249724" }}>{winner_names} won!</h1>
+    }
+
+    return (
+      <div>
+        <h1 style={{ color: "#2b6b2b" }}>Cribbage</h1>
+        <div>
+          { winner_info }
+          {
+            this.props.room ? <><Button onClick={ () => this.returnToRoom() } raised >Return to Room</Button><br /><br /></> : <></>
+          }
+          { current_round }
+          { scoreboard_data }
+          { historical_data }
+        </div>
+      </div>
+    );
+  }
+}
+
+export {
+  CribbageAfterPartyComponent
+};
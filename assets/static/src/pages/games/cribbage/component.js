@@ -0,0 +1,233 @@
+import React from 'react';
+
+import '../../../main.scss';
+
+import { Avatar } from '@rmwc/avatar';
+import '@rmwc/avatar/styles';
+import { Button } from '@rmwc/button';
+import '@rmwc/button/styles';
+import * as c from '@rmwc/card';
+import '@rmwc/card/styles';
+import { TextField } from '@rmwc/textfield';
+import '@rmwc/textfield/styles';
+
+import { CardHand } from '../../../games/card.js';
+import { gravatarify } from '../../../utils/gravatar.js';
+
+// Properties used for display card hands
+var handProps = {
+  overlap: true,
+  curve: true,
+  scale: 0.50,
+};
+
+// The hands counted so far this round, with who counted them.
+function CribbageShows(props) {
+  let shows = [];
+  for (let index in props.shows || []) {
+    let show = props.shows[index];
+    let user = props.player_user(show.player);
+    let name = user ? user.display : "Player " + (+show.player + 1);
+    let hand = show.hand ? CardHand.deserialize(show.hand).cardSort(true, false, false) : null;
+    shows.push(
+      <div key={ index }>
+        <h3>
+          { user ? <Avatar src={ gravatarify(user) } name={ name } size="medium" /> : null } { name + "'s " + (show.crib ? "crib" : "hand") }: { show.claimed } point{ +show.claimed === 1 ? "" : "s" }
+          { show.muggins > 0 ? " (" + show.muggins + " missed)" : null }
+        </h3>
+        { hand?.toImage(handProps) }
+      </div>
+    );
+  }
+
+  return shows;
+}
+
+class CribbageGameComponent extends React.Component {
+  constructor(props) {
+    super(props);
+    this.state = {};
+    this.state.game = this.props.game;
+    this.state.selected = new Set();
+    this.state.claim = "";
+    // FIXME: hack?
+    let old_handler = this.state.game.interface.onChange;
+    this.state.game.interface.onChange = () => {
+      old_handler();
+      this.setState(state => {
+        // Jinx
+        return state;
+      });
+    };
+  }
+  clearSelectAnd(then) {
+    return (...arg) => {
+      this.setState(state => Object.assign(state, {
+        selected: new Set(),
+        claim: "",
+      }));
+      return then && then(...arg);
+    };
+  }
+  selecting(card) {
+    return Object.assign(card, {
+      selected: this.state.selected.has(card.id),
+      onClick: () => {
+        this.setState(state => {
+          if (state.selected.has(card.id))
+            state.selected.delete(card.id);
+          else
+            state.selected.add(card.id);
+          return state;
+        });
+      },
+    });
+  }
+  selecting_one(card) {
+    return Object.assign(card, {
+      selected: this.state.selected.has(card.id),
+      onClick: () => {
+        this.setState(state => {
+          let selected = state.selected.has(card.id);
+          state.selected = new Set();
+          if (!selected)
+            state.selected.add(card.id);
+          return state;
+        });
+      },
+    });
+  }
+  render() {
+    var status = a => <h3>{ a }</h3>;
+    var big_status = a => <h2>{ a }</h2>;
+    var card = (...children) => <div style={{ width: "90%" , margin: "0 auto 1em auto" }}>
+      <c.Card style={{ width: "100%" , padding: "0.5em 0.5em 0.5em 0.5em" }}>
+        <div style={{ padding: "1rem 1rem 1rem 1rem" }}>
+          { children }
+        </div>
+      </c.Card>
+    </div>;
+
+    let game = this.state.game.interface;
+    let data = game.data;
+    var hand = (mapper) => card(
+      <h3 key="title">Hand</h3>,
+      <div key="hand">{ data.hand?.toImage(mapper ? mapper.bind(this) : null, handProps) }</div>
+    );
+
+    let shows = data.shows && data.shows.length > 0
+      ? card(<h3 key="title">The Show</h3>, <CribbageShows key="shows" shows={ data.shows } player_user={ index => game.player_user(index) } />)
+      : null;
+
+    if (!game.started) {
+      return status("Waiting for game to start …");
+    } else if (game.finished) {
+      return <div>
+        {status("Finished")}
+      </div>;
+    } else if (!game.dealt) {
+      return <div>
+        {
+          game.my_deal()
+          ? card(<Button key="deal" label="Deal!" unelevated ripple={false} onClick={() => game.deal()} />)
+          : card(<h3 key="wait">Waiting for the dealer to begin...</h3>)
+        }
+        { shows }
+      </div>;
+    } else if (!game.cut) {
+      let discard_size = game.discard_size();
+      if (!data.discarded) {
+        return <div>
+          {
+            card(
+              <div key="discard">
+                {big_status(game.my_deal() ? "Discard to your crib" : "Discard to the dealer's crib")}
+                {status("Choose " + discard_size + " card" + (discard_size === 1 ? "" : "s"))}
+                <Button label="Send to the crib" unelevated ripple={false} disabled={ this.state.selected.size !== discard_size }
+                  onClick={this.clearSelectAnd(() => game.discard([...this.state.selected])) } />
+              </div>
+            )
+          }
+          { hand(this.selecting) }
+        </div>;
+      }
+
+      return <div>
+        { card(<h3 key="wait">Waiting for everyone to discard to the crib …</h3>) }
+        { hand(null) }
+      </div>;
+    } else if (!game.pegged) {
+      let pegging = card(
+        <div key="pegging">
+          {status("Starter:")}
+          { data.starter?.toImage() }
+          {status("Count: " + data.count)}
+          { data.pile && data.pile.cards.length > 0 ? data.pile.toImage(handProps) : null }
+        </div>
+      );
+
+      if (game.my_turn()) {
+        return <div>
+          { pegging }
+          {
+            card(
+              <div key="play">
+                {big_status("Your turn to play")}
+                <Button label={ this.state.selected.size ? "Play this card" : "Pick a card!" } unelevated ripple={false} disabled={ !this.state.selected.size }
+                  onClick={this.clearSelectAnd(() => game.play([...this.state.selected][0])) } />
+              </div>
+            )
+          }
+          { hand(this.selecting_one) }
+        </div>;
+      }
+
+      return <div>
+        { pegging }
+        { card(<h3 key="wait">Waiting for the other player{ +this.state.game.config.num_players === 2 ? "" : "s" } to play …</h3>) }
+        { hand(null) }
+      </div>;
+    } else {
+      if (game.my_turn() && this.state.game.config?.muggins) {
+        let counting = data.showing_crib ? data.crib : data.kept;
+        return <div>
+          {
+            card(
+              <div key="claim">
+                {big_status(data.showing_crib ? "Count your crib" : "Count your hand")}
+                { counting?.toImage(handProps) }
+                {status("Starter:")}
+                { data.starter?.toImage() }
+                <br />
+                <TextField type="number" label="Points" min="0" value={ this.state.claim }
+                  onChange={ e => { let claim = e.target.value; this.setState(state => Object.assign(state, { claim })) } } />
+                <br />
+                <Button label="Claim points" unelevated ripple={false} disabled={ this.state.claim === "" }
+                  onClick={this.clearSelectAnd(() => game.claim(this.state.claim)) } />
+              </div>
+            )
+          }
+          { shows }
+        </div>;
+      }
+
+      return <div>
+        {
+          card(
+            <div key="wait">
+              {status("Starter:")}
+              { data.starter?.toImage() }
+              {status("Waiting for hands to be counted …")}
+            </div>
+          )
+        }
+        { shows }
+      </div>;
+    }
+  }
+}
+
+export {
+  CribbageGameComponent,
+  CribbageShows,
+};
@@ -0,0 +1,86 @@
+import React from 'react';
+
+import '../../../main.scss';
+
+import { Avatar } from '@rmwc/avatar';
+import '@rmwc/avatar/styles';
+import * as c from '@rmwc/card';
+import '@rmwc/card/styles';
+
+import { GameSynopsis, sortSynopsisPlayers } from '../synopsis.js';
+import { gravatarify } from '../../../utils/gravatar.js';
+import { PlayerAvatar } from '../../../utils/player.js';
+
+class CribbageGameSynopsis extends GameSynopsis {
+  constructor(props) {
+    super(props);
+
+    this.state = this.newState();
+
+    let old_handler = this.props.game.interface.onChange;
+    this.props.game.interface.onChange = () => {
+      old_handler();
+      this.setState(state => this.newState());
+    };
+  }
+
+  newState() {
+    let new_state = { indexed_players: {}, spectators: {} };
+    sortSynopsisPlayers(this.props.game.interface?.synopsis, new_state);
+    return new_state;
+  }
+
+  render() {
+    var sigil = (t,c) => <span style={{ fontSize: "170%", color: c }}>{ t }</span>
+    var synopsis_columns = {
+      "user":{
+        name: "User",
+        printer: (user,player) =>
+          <PlayerAvatar user={ user }
+            size={ user.id === this.props.user.id ? "xlarge" : "large" }
+            team={ +player.side+1 }
+            loading={ player.is_turn }
+            />,
+      },
+      "is_dealer":{
+        name: "Dealer",
+        printer: a => a ? sigil("♣") : "",
+      },
+      "discarded":{
+        name: "Crib",
+        printer: a => a ? "✔" : "",
+      },
+      "cards_left":"Cards",
+      "score":"Score",
+    };
+    var spectator_columns = {
+      "user":{
+        name: "User",
+        printer: user => <Avatar src={ gravatarify(user) } name={ user.display } size={ user.id === this.props.user.id ? "xlarge" : "large" } />,
+      },
+    };
+
+    var player_view = this.renderPlayerView(synopsis_columns, spectator_columns);
+
+    var count = null;
+    if (this.props.game.interface?.cut && !this.props.game.interface?.pegged) {
+      count = <span style={{ fontStyle: "italic" }}>Count: { this.props.game.interface.synopsis?.count }</span>;
+    }
+
+    return (
+      <div className="fit-content" style={{ margin: "0 auto 1em auto" }}>
+        <c.Card className="fit-content" style={{ padding: "0.5em 0.5em 0.5em 0.5em" }}>
+          <div className="scrollable-x">
+            <h1 style={{ marginBottom: count ? 0 : null, color: "#2b6b2b" }}>Cribbage</h1>
+            { count }
+            { player_view }
+          </div>
+        </c.Card>
+      </div>
+    );
+  }
+}
+
+export {
+  CribbageGameSynopsis
+};
@@ -157,6 +157,27 @@ class EightJacksGameBoard extends CardHandComponent {
         }
       }
     }
+    // Hexagonal boards are stored as rows of the y coordinate, with odd rows
+    // shifted half a square towards larger x; draw them shifted that way for
+    // the current orientation. Double square boards alternate large and small
+    // squares like a checkerboard.
+    let connectivity = +board.connectivity || 0;
+    let hex_shift = (315 * boardProps.scale) / 2;
+    let hex_transform = [
+      "translateY(" + hex_shift + "px)",
+      "translateX(" + hex_shift + "px)",
+      "translateY(-" + hex_shift + "px)",
+      "translateX(-" + hex_shift + "px)",
+    ][this.state.orientation || 0];
+    let table_padding = "4px";
+    if (connectivity === 1) {
+      table_padding = [
+        "4px 4px " + (hex_shift + 4) + "px 4px",
+        "4px " + (hex_shift + 4) + "px 4px 4px",
+        (hex_shift + 4) + "px 4px 4px 4px",
+        "4px 4px 4px " + (hex_shift + 4) + "px",
+      ][this.state.orientation || 0];
+    }
     let rows = [];
     for (let ids of view_order) {
       let col = [];
@@ -191,10 +212,19 @@ class EightJacksGameBoard extends CardHandComponent {
             tooltip = (e, handler) => <TooltipWrapper content={ name } align="bottom" clickHandler={ handler }>{e}</TooltipWrapper>;
           }
         }
+        let cell_style = { padding: 0 };
+        let square_props = boardProps;
+        if (connectivity === 1 && spot.y % 2 === 1) {
+          cell_style.transform = hex_transform;
+        } else if (connectivity === 2 && (spot.x + spot.y) % 2 === 1) {
+          square_props = Object.assign({}, boardProps, { scale: boardProps.scale * 0.7 });
+          cell_style.textAlign = "center";
+          cell_style.verticalAlign = "middle";
+        }
         col.push(
-          <td key={ spot.id } style={{ padding: 0 }}>
+          <td key={ spot.id } style={ cell_style }>
             { tooltip(
-              <CardImage suit={ suit } rank={ rank } overlay={ overlay } {...boardProps}
+              <CardImage suit={ suit } rank={ rank } overlay={ overlay } {...square_props}
                 y_part={ this.state.half_height && !last_row.includes(id) ? 0.70 : 0 }
                 transpose={ transpose }
                 onClick={ this.handleClick(spot) }
@@ -212,7 +242,7 @@ class EightJacksGameBoard extends CardHandComponent {
     }
     // overflow-y: hidden prevents unwanted vertical scrolling on iOS
     return <div className="scrollable-x" style={{ maxWidth: "100%" }}>
-      <table style={{ margin: "auto", borderSpacing: 0, lineHeight: 0, padding: "4px" }}>
+      <table style={{ margin: "auto", borderSpacing: 0, lineHeight: 0, padding: table_padding }}>
         <tbody>{ rows }</tbody>
       </table>
     </div>;
@@ -0,0 +1,235 @@
+import React from 'react';
+
+import '../../../main.scss';
+
+import { Avatar } from '@rmwc/avatar';
+import '@rmwc/avatar/styles';
+import { Button } from '@rmwc/button';
+import '@rmwc/button/styles';
+import * as c from '@rmwc/card';
+import '@rmwc/card/styles';
+import { Select } from '@rmwc/select';
+import '@rmwc/select/styles';
+import { Switch } from '@rmwc/switch';
+import '@rmwc/switch/styles';
+
+import { gravatarify } from '../../../utils/gravatar.js';
+
+// Properties used for display card hands
+var handProps = {
+  overlap: true,
+  curve: true,
+  scale: 0.50,
+};
+
+class EuchreGameComponent extends React.Component {
+  constructor(props) {
+    super(props);
+    this.state = {};
+    this.state.game = this.props.game;
+    this.state.selected = null;
+    this.state.trump = null;
+    this.state.alone = false;
+    // FIXME: hack?
+    let old_handler = this.state.game.interface.onChange;
+    this.state.game.interface.onChange = () => {
+      old_handler();
+      this.setState(state => {
+        // Jinx
+        return state;
+      });
+    };
+  }
+  clearSelectAnd(then) {
+    return (...arg) => {
+      this.setState(state => Object.assign(state, {
+        selected: null,
+        trump: null,
+        alone: false,
+      }));
+      return then && then(...arg);
+    };
+  }
+  selecting(card) {
+    return Object.assign(card, {
+      selected: this.state.selected === card.id,
+      onClick: () => {
+        this.setState(state => {
+          if (state.selected === card.id)
+            state.selected = null;
+          else
+            state.selected = card.id;
+          return state;
+        });
+      },
+    });
+  }
+  render() {
+    var status = a => <h3>{ a }</h3>;
+    var big_status = a => <h2>{ a }</h2>;
+    var card = (...children) => <div style={{ width: "90%" , margin: "0 auto 1em auto" }}>
+      <c.Card style={{ width: "100%" , padding: "0.5em 0.5em 0.5em 0.5em" }}>
+        <div style={{ padding: "1rem 1rem 1rem 1rem" }}>
+          { children }
+        </div>
+      </c.Card>
+    </div>;
+
+    let data = this.state.game.interface.data;
+    let annotations = null;
+    if (data.who_played) {
+      annotations = [];
+      for (let who_player of data.who_played) {
+        let annotation = <div key={ who_player.id }><Avatar src={ gravatarify(who_player) } name={ who_player.display } size="medium" /> <span title={ who_player.display }>{ who_player.display }</span></div>;
+        annotations.push(annotation);
+      }
+    }
+
+    var hand = (selectable) => card(
+      <h3 key="title">Hand</h3>,
+      <div key="hand">{ data.hand?.toImage(selectable ? this.selecting.bind(this) : null, handProps) }</div>
+    );
+
+    var alone_switch = this.state.game.config?.going_alone
+      ? <><Switch label="Go alone" checked={ this.state.alone } onChange={ () => this.setState(state => Object.assign(state, { alone: !state.alone })) } /><br /></>
+      : null;
+
+    if (!this.state.game.interface.started) {
+      return status("Waiting for game to start …");
+    } else if (this.state.game.interface.finished) {
+      return <div>
+        {status("Finished")}
+      </div>;
+    } else if (!this.state.game.interface.dealt) {
+      return <div>
+        {
+          this.state.game.interface.my_deal()
+          ? card(<Button key="deal" label="Deal!" unelevated ripple={false} onClick={() => this.state.game.interface.deal()} />)
+          : card(<h3 key="wait">Waiting for the dealer to begin...</h3>)
+        }
+      </div>;
+    } else if (+data.bid_round === 1) {
+      return <div>
+        {
+          card(
+            <div key="bid">
+              {status(this.state.game.interface.my_deal() ? "You turned up:" : "The dealer turned up:")}
+              { data.turned_up?.toImage() }
+              {
+                this.state.game.interface.my_turn()
+                ? <>
+                    {big_status(this.state.game.interface.my_deal() ? "Pick it up?" : "Order it up?")}
+                    { alone_switch }
+                    <Button label={ this.state.game.interface.my_deal() ? "Pick it up" : "Order it up" } raised ripple={false} onClick={this.clearSelectAnd(() => this.state.game.interface.call(data.turned_up.suit.value, this.state.alone))} />
+                    &nbsp;&nbsp;
+                    <Button label="Pass" raised ripple={false} onClick={this.clearSelectAnd(() => this.state.game.interface.pass())} />
+                  </>
+                : status("Waiting for bids …")
+              }
+            </div>
+          )
+        }
+        { hand(false) }
+      </div>;
+    } else if (+data.bid_round === 2) {
+      let stuck = this.state.game.config?.stick_the_dealer && this.state.game.interface.my_deal();
+      return <div>
+        {
+          card(
+            <div key="bid">
+              {status("Turned down:")}
+              { data.turned_up?.toImage() }
+              {
+                this.state.game.interface.my_turn()
+                ? <>
+                    {big_status(stuck ? "You're stuck: name trump" : "Name trump?")}
+                    <Select label="Trump" enhanced options={ this.state.game.interface.valid_trumps() }
+                      value={ this.state.trump === null ? "" : ""+this.state.trump }
+                      onChange={ e => { let trump = +e.currentTarget.value; this.setState(state => Object.assign(state, { trump })) } }
+                    />
+                    { alone_switch }
+                    <Button label="Name trump" raised ripple={false} disabled={ !this.state.trump } onClick={this.clearSelectAnd(() => this.state.game.interface.call(this.state.trump, this.state.alone))} />
+                    {
+                      !stuck
+                      ? <>&nbsp;&nbsp;<Button label="Pass" raised ripple={false} onClick={this.clearSelectAnd(() => this.state.game.interface.pass())} /></>
+                      : null
+                    }
+                  </>
+                : status("Waiting for bids …")
+              }
+            </div>
+          )
+        }
+        { hand(false) }
+      </div>;
+    } else if (data.discarding) {
+      if (this.state.game.interface.my_deal()) {
+        return <div>
+          {
+            card(
+              <div key="discard">
+                {big_status("Discard a card")}
+                <Button label={ this.state.selected ? "Discard this card" : "Pick a card!" } unelevated ripple={false} disabled={ !this.state.selected }
+                  onClick={this.clearSelectAnd(() => this.state.game.interface.discard(this.state.selected)) } />
+              </div>
+            )
+          }
+          { hand(true) }
+        </div>;
+      }
+
+      return <div>
+        { card(<h3 key="wait">Waiting for the dealer to discard …</h3>) }
+        { hand(false) }
+      </div>;
+    } else if (data.sitting_out) {
+      return <div>
+        {
+          card(
+            <div key="out">
+              { data.played?.toImage(null, null, annotations) }
+              {status("Your partner is going alone; sit this hand out.")}
+            </div>
+          )
+        }
+      </div>;
+    } else {
+      var already_played = +data.played.cards.length;
+      if (this.state.game.interface.my_turn()) {
+        var active_players = data.alone ? 3 : 4;
+        var leading = !already_played || already_played >= active_players;
+        return <div>
+          {
+            card(
+              <div key="play">
+                {status(leading ? (already_played ? "You took it, lead the next trick!" : "You lead off!") : already_played === 1 ? "This card was led" : "These cards have been played")}
+                { data.played?.toImage(null, null, annotations) }
+                {big_status("Your turn to play")}
+                {status("Choose a card")}
+                <Button label={ this.state.selected ? "Play this card" : "Pick a card!" } unelevated ripple={false} disabled={ !this.state.selected }
+                  onClick={this.clearSelectAnd(() => this.state.game.interface.play(this.state.selected)) } />
+              </div>
+            )
+          }
+          { hand(true) }
+        </div>;
+      } else {
+        return <div>
+          {
+            card(
+              <div key="play">
+                { data.played?.toImage(null, null, annotations) }
+                {status("Waiting for the other players to play …")}
+              </div>
+            )
+          }
+          { hand(true) }
+        </div>;
+      }
+    }
+  }
+}
+
+export {
+  EuchreGameComponent
+};
@@ -0,0 +1,409 @@
+import React from 'react';
+
+import '../../../main.scss';
+
+import { Avatar } from '@rmwc/avatar';
+import '@rmwc/avatar/styles';
+import { Button } from '@rmwc/button';
+import '@rmwc/button/styles';
+import { IconButton } from '@rmwc/icon-button';
+import '@rmwc/icon-button/styles';
+import * as c from '@rmwc/card';
+import '@rmwc/card/styles';
+import * as l from '@rmwc/list';
+import '@rmwc/list/styles';
+
+import { CardHand, CardSuit, Card } from '../../../games/card.js';
+import { loadGame, addEv, notify, killable } from '../../games.js';
+import { UserCache, GameCache } from '../../../utils/cache.js';
+import { gravatarify } from '../../../utils/gravatar.js';
+
+// Properties used for display card hands
+var handProps = {
+  overlap: true,
+  curve: true,
+  scale: 0.50,
+};
+
+class OhHellAfterPartyComponent extends React.Component {
+  constructor(props) {
+    super(props);
+    this.game = loadGame(this.props.game);
+    this.state = {
+      game: props.game,
+      player_mapping: null,
+      history: null,
+      historical_round: 0,
+      active: {
+        turn: null,
+        dealer: null,
+        played: null,
+        who_played: null,
+        trump: null,
+      },
+      winners: this.game?.winners,
+      dealt: false,
+      all_bid: false,
+      finished: false,
+      message: "Loading results...",
+      timeout: killable(() => { this.refreshData() }, 5000),
+    };
+
+    GameCache.Invalidate(this.props.game.id);
+
+    this.unmount = addEv(this.game, {
+      "game-state": async (data) => {
+        var mapping = {};
+        for (let index in data.player_mapping) {
+          mapping[index] = await UserCache.FromId(data.player_mapping[index]);
+        }
+
+        let winners = [];
+        if (data.winners) {
+          for (let uid of data.winners) {
+            winners.push(await UserCache.FromId(uid));
+          }
+        }
+
+        let turn = data.turn ? await UserCache.FromId(data.turn) : null;
+        let dealer = data.dealer ? await UserCache.FromId(data.dealer) : null;
+
+        let played = null;
+        if (data.played) {
+          played = CardHand.deserialize(data.played);
+        }
+
+        let who_played = [];
+        if (data.who_played) {
+          for (let uid of data.who_played) {
+            let player = await UserCache.FromId(uid);
+            who_played.push(player);
+          }
+        }
+
+        // HACK: When refreshData() is called from the button, we don't redraw
+        // the screen even though new data is sent. Use snapshots to send only
+        // the data we care about.
+        this.setState(state => Object.assign({}, state, { history: null }));
+        this.setState(state => Object.assign({}, state, {
+          player_mapping: mapping,
+          history: data.round_history || [],
+          winners: winners,
+          dealt: data.dealt,
+          all_bid: data.all_bid,
+          finished: data.finished,
+          active: {
+            turn: turn,
+            dealer: dealer,
+            played: played,
+            who_played: who_played,
+            trump: data.trump ? CardSuit.deserialize(data.trump) : null,
+          },
+        }));
+
+        if (data.finished) {
+          if (this.state.timeout) {
+            this.state.timeout.kill();
+          }
+
+          this.setState(state => Object.assign({}, state, { timeout: null }));
+        }
+      },
+      "error": (data) => {
+        var message = "Unable to load game data.";
+        if (data.error) {
+          message = data.error;
+        }
+
+        notify(this.props.snackbar, message, data.message_type);
+        this.setState(state => Object.assign({}, state, { message }));
+      },
+      "": data => {
+        if (data.message) {
+          notify(this.props.snackbar, data.message, data.message_type);
+        }
+      },
+    });
+  }
+  componentDidMount() {
+    this.state.timeout.exec();
+  }
+  componentWillUnmount() {
+    this.props.setGame(null);
+
+    if (this.state.timeout) {
+      this.state.timeout.kill();
+    }
+
+    if (this.unmount) this.unmount();
+  }
+  async refreshData() {
+    await this.game.interface.controller.wsController.sendAndWait({"message_type": "peek"});
+
+    if (this.state.finished) {
+      if (this.state.timeout) {
+        this.state.timeout.kill();
+        this.setState(state => Object.assign({}, state, { timeout: null }));
+      }
+    }
+  }
+  returnToRoom() {
+    if (this.props.game.interface) {
+      this.props.game.interface.close();
+    }
+
+    this.props.game.interface = null;
+
+    this.props.setGame(null);
+    this.props.setPage("room", true);
+  }
+  skip(amt) {
+    this.setState(state => {
+      var round = +state.historical_round + amt;
+      if (round < 0 || !state.history || round >= state.history.length) {
+        return state;
+      }
+
+      state.historical_round = round;
+      return state;
+    });
+  }
+  render() {
+    var sigil = (t,c) => <span style={{ fontSize: "170%", color: c }}>{ t }</span>;
+    var current_round = null;
+
+    if (this.state.active.played && this.state.active.played.cards.length > 0) {
+      var annotations = [];
+      for (let who_player of this.state.active.who_played) {
+        let annotation = <div key={ who_player.id }><Avatar src={ gravatarify(who_player) } name={ who_player.display } size="medium" /> <span title={ who_player.display }>{ who_player.display }</span></div>;
+        annotations.push(annotation);
+      }
+
+      current_round = <div>
+        <div style={{ width: "90%" , margin: "0 auto 1em auto" }}>
+          <c.Card style={{ width: "100%" , padding: "0.5em 0.5em 0.5em 0.5em" }}>
+            <div style={{ padding: "1rem 1rem 1rem 1rem" }}>
+              { this.state.active.played?.toImage(null, null, annotations) }
+            </div>
+          </c.Card>
+        </div>
+      </div>;
+    } else if (!this.state.finished) {
+      current_round = <div>
+        <div style={{ width: "90%" , margin: "0 auto 1em auto" }}>
+          <c.Card style={{ width: "100%" , padding: "0.5em 0.5em 0.5em 0.5em" }}>
+            <div style={{ padding: "1rem 1rem 1rem 1rem" }}>
+              {
+                !this.state.dealt
+                ? "Please wait for the round to begin..."
+                : !this.state.all_bid
+                ? "Please wait for players to bid..."
+                : "Please wait for a card to be played..."
+              }
+            </div>
+          </c.Card>
+        </div>
+      </div>;
+    }
+
+    var historical_data = null;
+    var scoreboard_data = null;
+
+    if (this.state.player_mapping && this.state.history && this.state.history.length > 0) {
+      let round_index = +this.state.historical_round;
+      let round = this.state.history[round_index];
+      let round_data = [];
+      if (round) {
+        let dealer = this.state.player_mapping[round.dealer];
+        let trump = new CardSuit(round.trump);
+        let turned_up = round.turned_up ? Card.deserialize(round.turned_up) : null;
+        round_data.push(
+          <div key="summary">
+            {
+              dealer
+              ? <><b>Dealer</b>: <Avatar src={ gravatarify(dealer) } name={ dealer.display } size="medium" /> { dealer.display }<br /></>
+              : null
+            }
+            <b>Hand size</b>: { round.hand_size }<br />
+            { turned_up ? <><b>Turned up</b>: { turned_up.toImage({ scale: 0.5 }) }<br /></> : null }
+            <b>Trump</b>: { trump.toUnicode() ? sigil(trump.toUnicode(), trump.toColor()) : "None" }<br />
+          </div>
+        );
+
+        let hands_data = [];
+        for (let player_index in round.players) {
+          let user = this.state.player_mapping[player_index];
+          let round_player = round.players[player_index];
+          let hand = round_player.hand ? CardHand.deserialize(round_player.hand).cardSort(true, true) : null;
+          hands_data.push(
+            <div key={ user.id }>
+              <l.List>
+                <l.CollapsibleList handle={
+                    <l.SimpleListItem text={ <b>{user.display + "'s"} Hand (bid { round_player.bid }, took { round_player.tricks })</b> } metaIcon="chevron_right" />
+                  }
+                >
+                  <div style={{ paddingTop: '15px', paddingBottom: '15px' }}>
+                    { hand ? hand.toImage(handProps) : null }
+                  </div>
+                </l.CollapsibleList>
+              </l.List>
+            </div>
+          );
+        }
+        round_data.push(
+          <l.CollapsibleList key="hands" handle={
+              <l.SimpleListItem text={ <b>Player Hands</b> } metaIcon="chevron_right" />
+            }
+          >
+            <div style={{ textAlign: 'center' }}>
+              { hands_data }
+            </div>
+          </l.CollapsibleList>
+        );
+
+        let num_players = Object.keys(this.state.player_mapping).length;
+        let tricks_data = [];
+        for (let trick_index in round.tricks) {
+          let trick = round.tricks[trick_index];
+          let winner = this.state.player_mapping[trick.winner];
+          let annotations = [];
+          for (let offset in trick.played || []) {
+            let annotation_player_index = (+trick.leader + +offset) % num_players;
+            let annotation_player = this.state.player_mapping[annotation_player_index];
+            let name = annotation_player_index === +trick.winner ? <b>{ annotation_player.display }</b> : annotation_player.display;
+            annotations.push(<div key={ annotation_player.id }><Avatar src={ gravatarify(annotation_player) } name={ annotation_player.display } size="medium" /> { name }</div>);
+          }
+          let cards = trick?.played ? CardHand.deserialize(trick.played).toImage(null, null, annotations) : null;
+          tricks_data.push(
+            <l.CollapsibleList key={ trick_index } handle={
+                <l.SimpleListItem text={ <b>Trick { parseInt(trick_index) + 1 }</b> } metaIcon="chevron_right" />
+              }
+            >
+              <div style={{ textAlign: 'left' }}>
+                {
+                  winner
+                  ? <><b>Winner</b>: <Avatar src={ gravatarify(winner) } name={ winner.display } size="medium" /> <b>{ winner.display }</b><br /></>
+                  : null
+                }
+              </div>
+              { cards }
+            </l.CollapsibleList>
+          );
+        }
+        round_data.push(
+          <l.CollapsibleList key="tricks" handle={
+              <l.SimpleListItem text={ <b>Tricks</b> } metaIcon="chevron_right" />
+            }
+          >
+            <div style={{ textAlign: 'center' }}>
+              <l.List>
+                { tricks_data }
+              </l.List>
+            </div>
+          </l.CollapsibleList>
+        );
+      } else {
+        round_data = <b>No data found for round { round_index + 1 }!</b>;
+      }
+
+      historical_data = <div style={{ width: "90%" , margin: "0 auto 0.5em auto" }}>
+        <c.Card style={{ width: "100%" , padding: "0.5em 0.5em 0.5em 0.5em" }}>
+          <div>
+            <h3>Game Analysis</h3>
+            <div style={{ margin: "auto" }}>
+              <IconButton icon="fast_rewind" size="xsmall" onClick={ () => this.skip(-1) }/>
+              <div style={{ display: "inline-flex", flexDirection: "column", verticalAlign: "text-bottom" }}>
+                <h2 style={{ margin: 0 }}>Round { round_index + 1 }</h2>
+              </div>
+              <IconButton icon="fast_forward" size="xsmall" onClick={ () => this.skip(1) }/>
+            </div>
+            <div style={{ textAlign: 'left' }}>
+              { round_data }
+            </div>
+          </div>
+        </c.Card>
+      </div>;
+
+      var score_players = [];
+      var round_scores = [];
+      var final_scores = [];
+      for (let player_index of Object.keys(this.state.player_mapping).sort()) {
+        let score_player = this.state.player_mapping[player_index];
+        score_players.push(<td key={ player_index } colSpan={2} style={{ borderBottom: "1px solid #777", paddingLeft: '25px', paddingRight: '25px' }}><Avatar src={ gravatarify(score_player) } name={ score_player.display } size="medium" /> { score_player.display }</td>);
+      }
+      for (let index in this.state.history) {
+        let round = this.state.history[index];
+        let round_row = [];
+        round_row.push(<td key="round" style={{ borderTop: "10px solid transparent", borderBottom: "10px solid transparent" }}> { parseInt(index) + 1 } </td>);
+        round_row.push(<td key="size">{ round.hand_size }</td>);
+        for (let player_index of Object.keys(this.state.player_mapping).sort()) {
+          let round_player = round.players ? round.players[player_index] : null;
+          let score = round_player ? +round_player.score : 0;
+          let round_score = round_player ? +round_player.round_score : 0;
+          let incr = round_score < 0 ? ""+round_score : "+"+round_score;
+          round_row.push(<td key={ player_index+"_score" } style={{ whiteSpace: "nowrap", textAlign: "right", paddingLeft: "10px" }}>{ score }&nbsp;</td>);
+          round_row.push(<td key={ player_index+"_incr" } style={{ textAlign: "left", paddingRight: "10px", fontSize: "75%" }}>({ incr })</td>);
+        }
+        round_scores.push(<tr key={ index }>{ round_row }</tr>);
+      }
+      let last_round = this.state.history[this.state.history.length - 1];
+      for (let player_index of Object.keys(this.state.player_mapping).sort()) {
+        let score = last_round?.players ? last_round.players[player_index]?.score : 0;
+        final_scores.push(<td key={ player_index } colSpan={2} style={{ whiteSpace: "nowrap", borderTop: "1px solid #000" }}> { score } </td>);
+      }
+
+      scoreboard_data = <div className="fit-content" style={{ margin: "0 auto 0.5em auto", maxWidth: "90%" }}>
+        <c.Card className="fit-content" style={{ padding: "0.5em 0.5em 0.5em 0.5em", maxWidth: "100%" }}>
+          <div>
+            <h3>Score Board</h3>
+            <div style={{ overflow: "auto", maxWidth: "100%" }}>
+            <table style={{ fontSize: '1.2em', borderCollapse: "collapse", borderSpacing: 0 }}>
+              <thead>
+                <tr>
+                  <td style={{ paddingLeft: '15px', paddingRight: '15px' }}>Round</td>
+                  <td style={{ paddingLeft: '15px', paddingRight: '15px' }}>Cards</td>
+                  { score_players }
+                </tr>
+              </thead>
+              <tbody>
+                { round_scores }
+              </tbody>
+              <tfoot>
+                <tr>
+                  <td colSpan={2}>Total</td>
+                  { final_scores }
+                </tr>
+              </tfoot>
+            </table>
+            </div>
+          </div>
+        </c.Card>
+      </div>;
+    }
+
+    var winner_info = <h1>Please wait while the game finishes...</h1>;
+    if (this.state.finished && this.state.winners && this.state.winners.length > 0) {
+      var winner_names = this.state.winners.map(winner => +winner.id === +this.props.user.id ? "You" : winner.display).join(" and ");
+      winner_info = <h1 style={{ color: "#249724" }}>{winner_names} won!</h1>
+    }
+
+    return (
+      <div>
+        <h1 style={{ color: "#2b6b2b" }}>Oh Hell</h1>
+        <div>
+          { winner_info }
+          {
+            this.props.room ? <><Button onClick={ () => this.returnToRoom() } raised >Return to Room</Button><br /><br /></> : <></>
+          }
+          { current_round }
+          { scoreboard_data }
+          { historical_data }
+        </div>
+      </div>
+    );
+  }
+}
+
+export {
+  OhHellAfterPartyComponent
+};
@@ -0,0 +1,192 @@
+import React from 'react';
+
+import '../../../main.scss';
+
+import { Avatar } from '@rmwc/avatar';
+import '@rmwc/avatar/styles';
+import { Button } from '@rmwc/button';
+import '@rmwc/button/styles';
+import * as c from '@rmwc/card';
+import '@rmwc/card/styles';
+import { Select } from '@rmwc/select';
+import '@rmwc/select/styles';
+
+import { gravatarify } from '../../../utils/gravatar.js';
+
+// Properties used for display card hands
+var handProps = {
+  overlap: true,
+  curve: true,
+  scale: 0.50,
+};
+
+class OhHellGameComponent extends React.Component {
+  constructor(props) {
+    super(props);
+    this.state = {};
+    this.state.game = this.props.game;
+    this.state.selected = null;
+    this.state.bid = null;
+    // FIXME: hack?
+    let old_handler = this.state.game.interface.onChange;
+    this.state.game.interface.onChange = () => {
+      old_handler();
+      this.setState(state => {
+        // Jinx
+        return state;
+      });
+    };
+  }
+  clearSelectAnd(then) {
+    return (...arg) => {
+      this.setState(state => Object.assign(state, {
+        selected: null,
+        bid: null,
+      }));
+      return then && then(...arg);
+    };
+  }
+  selecting(card) {
+    return Object.assign(card, {
+      selected: this.state.selected === card.id,
+      onClick: () => {
+        this.setState(state => {
+          if (state.selected === card.id)
+            state.selected = null;
+          else
+            state.selected = card.id;
+          return state;
+        });
+      },
+    });
+  }
+  render() {
+    var status = a => <h3>{ a }</h3>;
+    var big_status = a => <h2>{ a }</h2>;
+    var card = (...children) => <div style={{ width: "90%" , margin: "0 auto 1em auto" }}>
+      <c.Card style={{ width: "100%" , padding: "0.5em 0.5em 0.5em 0.5em" }}>
+        <div style={{ padding: "1rem 1rem 1rem 1rem" }}>
+          { children }
+        </div>
+      </c.Card>
+    </div>;
+
+    let game = this.state.game.interface;
+    let data = game.data;
+    let annotations = null;
+    if (data.who_played) {
+      annotations = [];
+      for (let who_player of data.who_played) {
+        let annotation = <div key={ who_player.id }><Avatar src={ gravatarify(who_player) } name={ who_player.display } size="medium" /> <span title={ who_player.display }>{ who_player.display }</span></div>;
+        annotations.push(annotation);
+      }
+    }
+
+    var hand = (selectable) => card(
+      <h3 key="title">Hand</h3>,
+      <div key="hand">{ data.hand?.toImage(selectable ? this.selecting.bind(this) : null, handProps) }</div>
+    );
+
+    var round = status("Round " + (+data.round + 1) + " of " + data.rounds + ": " + data.hand_size + " card" + (+data.hand_size === 1 ? "" : "s"));
+    var trump = <div key="trump">
+      {
+        data.turned_up
+        ? <>{status("Turned up:")}{ data.turned_up.toImage() }</>
+        : null
+      }
+      {status(data.trump && data.trump.toUnicode() ? "Trump: " + data.trump.toUnicode() + " " + data.trump.toString() : "No trump")}
+      {
+        this.state.game.config?.wizards
+        ? <p>Red jokers are Wizards and always win; black jokers are Jesters and always lose.</p>
+        : null
+      }
+    </div>;
+
+    if (!game.started) {
+      return status("Waiting for game to start …");
+    } else if (game.finished) {
+      return <div>
+        {status("Finished")}
+      </div>;
+    } else if (!game.dealt) {
+      return <div>
+        {
+          game.my_deal()
+          ? card(<Button key="deal" label="Deal!" unelevated ripple={false} onClick={() => game.deal()} />)
+          : card(<h3 key="wait">Waiting for the dealer to begin...</h3>)
+        }
+      </div>;
+    } else if (!game.all_bid) {
+      if (game.my_turn()) {
+        return <div>
+          {
+            card(
+              <div key="bid">
+                { round }
+                { trump }
+                {big_status("Please place your bid:")}
+                {
+                  game.my_deal() && this.state.game.config?.hook_rule
+                  ? <p>You bid last; your bid can't make the bids add up to the number of tricks.</p>
+                  : null
+                }
+                <Select label="Bid value" enhanced options={ game.valid_bids() }
+                  value={ this.state.bid === null ? "" : ""+this.state.bid }
+                  onChange={ e => { let bid = +e.currentTarget.value; this.setState(state => Object.assign(state, { bid })) } }
+                />
+                <br />
+                <Button label="Place bid" raised ripple={false} disabled={ this.state.bid === null } onClick={this.clearSelectAnd(() => game.bid(this.state.bid))} />
+              </div>
+            )
+          }
+          { hand(false) }
+        </div>;
+      }
+
+      return <div>
+        { card(round, trump, <h3 key="wait">Waiting for bids …</h3>) }
+        { hand(false) }
+      </div>;
+    } else {
+      var already_played = +data.played?.cards.length;
+      var num_players = +this.state.game.config.num_players;
+      var bid_status = status("You bid " + data.bid + " and have taken " + data.tricks + " trick" + (+data.tricks === 1 ? "" : "s"));
+      if (game.my_turn()) {
+        var leading = !already_played || already_played >= num_players;
+        return <div>
+          {
+            card(
+              <div key="play">
+                { bid_status }
+                {status(leading ? (already_played ? "You took it, lead the next trick!" : "You lead off!") : already_played === 1 ? "This card was led" : "These cards have been played")}
+                { data.played?.toImage(null, null, annotations) }
+                {big_status("Your turn to play")}
+                {status("Choose a card")}
+                <Button label={ this.state.selected ? "Play this card" : "Pick a card!" } unelevated ripple={false} disabled={ !this.state.selected }
+                  onClick={this.clearSelectAnd(() => game.play(this.state.selected)) } />
+              </div>
+            )
+          }
+          { hand(true) }
+        </div>;
+      }
+
+      return <div>
+        {
+          card(
+            <div key="play">
+              { bid_status }
+              { data.played?.toImage(null, null, annotations) }
+              {status("Waiting for the other players to play …")}
+            </div>
+          )
+        }
+        { hand(true) }
+      </div>;
+    }
+  }
+}
+
+export {
+  OhHellGameComponent
+};
@@ -0,0 +1,107 @@
+import React from 'react';
+
+import '../../../main.scss';
+
+import { Avatar } from '@rmwc/avatar';
+import '@rmwc/avatar/styles';
+import * as c from '@rmwc/card';
+import '@rmwc/card/styles';
+
+import { GameSynopsis, getSuit, sortSynopsisPlayers } from '../synopsis.js';
+import { CardSuit } from '../../../games/card.js';
+import { gravatarify } from '../../../utils/gravatar.js';
+import { PlayerAvatar } from '../../../utils/player.js';
+
+class OhHellGameSynopsis extends GameSynopsis {
+  constructor(props) {
+    super(props);
+
+    this.state = this.newState();
+
+    let old_handler = this.props.game.interface.onChange;
+    this.props.game.interface.onChange = () => {
+      old_handler();
+      this.setState(state => this.newState());
+    };
+  }
+
+  newState() {
+    let new_state = { indexed_players: {}, spectators: {}, suit: undefined, trump: undefined };
+    sortSynopsisPlayers(this.props.game.interface?.synopsis, new_state);
+    getSuit(this.props.game.interface?.synopsis, new_state);
+    if (this.props.game.interface?.synopsis?.trump) {
+      new_state.trump = CardSuit.deserialize(this.props.game.interface.synopsis.trump);
+    }
+    return new_state;
+  }
+
+  render() {
+    var sigil = (t,c) => <span style={{ fontSize: "170%", color: c }}>{ t }</span>
+    var synopsis_columns = {
+      "user":{
+        name: "User",
+        printer: (user,player) =>
+          <PlayerAvatar user={ user }
+            size={ user.id === this.props.user.id ? "xlarge" : "large" }
+            loading={ player.is_turn }
+            />,
+      },
+      "is_leader":{
+        name: "Lead",
+        printer: (is_leader,player,state) =>
+          !is_leader || !state.suit
+          ? ""
+          : state.suit instanceof CardSuit
+          ? sigil(state.suit.toUnicode() || "♣", state.suit.toColor())
+          : state.suit === "waiting"
+          ? "…"
+          : sigil("♣"),
+      },
+      "is_dealer":{
+        name: "Dealer",
+        printer: a => a ? sigil("♣") : "",
+      },
+      "bid":{
+        name: "Bid",
+        printer: a => +a >= 0 ? a : "",
+      },
+      "tricks":"Tricks",
+      "score":"Score",
+    };
+    var spectator_columns = {
+      "user":{
+        name: "User",
+        printer: user => <Avatar src={ gravatarify(user) } name={ user.display } size={ user.id === this.props.user.id ? "xlarge" : "large" } />,
+      },
+    };
+
+    var player_view = this.renderPlayerView(synopsis_columns, spectator_columns);
+
+    var trump = null;
+    if (this.state.suit !== "dealing" && this.state.suit !== undefined) {
+      trump = <span style={{ fontStyle: "italic" }}>
+        {
+          this.state.trump && this.state.trump.toUnicode()
+          ? <>Trump: { sigil(this.state.trump.toUnicode(), this.state.trump.toColor()) }</>
+          : "No trump"
+        }
+      </span>;
+    }
+
+    return (
+      <div className="fit-content" style={{ margin: "0 auto 1em auto" }}>
+        <c.Card className="fit-content" style={{ padding: "0.5em 0.5em 0.5em 0.5em" }}>
+          <div className="scrollable-x">
+            <h1 style={{ marginBottom: trump ? 0 : null, color: "#2b6b2b" }}>Oh Hell</h1>
+            { trump }
+            { player_view }
+          </div>
+        </c.Card>
+      </div>
+    );
+  }
+}
+
+export {
+  OhHellGameSynopsis
+};
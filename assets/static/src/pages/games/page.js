@@ -17,6 +17,24 @@ import { UserCache } from '../../utils/cache.js';
 import { EightJacksAfterPartyComponent } from './eightjacks/afterparty.js';
 import { EightJacksGameComponent } from './eightjacks/component.js';
 import { EightJacksGameSynopsis } from './eightjacks/synopsis.js';
+import { BridgeAfterPartyComponent } from './bridge/afterparty.js';
+import { BridgeGameComponent } from './bridge/component.js';
+import { BridgeGameSynopsis } from './bridge/synopsis.js';
+import { CribbageAfterPartyComponent } from './cribbage/afterparty.js';
+import { CribbageGameComponent } from './cribbage/component.js';
+import { CribbageGameSynopsis } from './cribbage/synopsis.js';
+import { OhHellAfterPartyComponent } from './ohhell/afterparty.js';
+import { OhHellGameComponent } from './ohhell/component.js';
+import { OhHellGameSynopsis } from './ohhell/synopsis.js';
+import { CrazyEightsAfterPartyComponent } from './crazyeights/afterparty.js';
+import { CrazyEightsGameComponent } from './crazyeights/component.js';
+import { CrazyEightsGameSynopsis } from './crazyeights/synopsis.js';
+import { WordSearchAfterPartyComponent } from './wordsearch/afterparty.js';
+import { WordSearchGameComponent } from './wordsearch/component.js';
+import { WordSearchGameSynopsis } from './wordsearch/synopsis.js';
+import { EuchreAfterPartyComponent } from './euchre/afterparty.js';
+import { EuchreGameComponent } from './euchre/component.js';
+import { EuchreGameSynopsis } from './euchre/synopsis.js';
 import { GinAfterPartyComponent } from './gin/afterparty.js';
 import { GinGameComponent } from './gin/component.js';
 import { GinGameSynopsis } from './gin/synopsis.js';
@@ -42,6 +60,54 @@ var layouts = {
     player: EightJacksGameComponent,
     afterparty: EightJacksAfterPartyComponent,
   },
+  "euchre": {
+    configuration: true,
+    finished_synopsis: false,
+    immersive: false,
+    synopsis: EuchreGameSynopsis,
+    player: EuchreGameComponent,
+    afterparty: EuchreAfterPartyComponent,
+  },
+  "cribbage": {
+    configuration: true,
+    finished_synopsis: false,
+    immersive: false,
+    synopsis: CribbageGameSynopsis,
+    player: CribbageGameComponent,
+    afterparty: CribbageAfterPartyComponent,
+  },
+  "bridge": {
+    configuration: true,
+    finished_synopsis: false,
+    immersive: false,
+    synopsis: BridgeGameSynopsis,
+    player: BridgeGameComponent,
+    afterparty: BridgeAfterPartyComponent,
+  },
+  "oh hell": {
+    configuration: true,
+    finished_synopsis: false,
+    immersive: false,
+    synopsis: OhHellGameSynopsis,
+    player: OhHellGameComponent,
+    afterparty: OhHellAfterPartyComponent,
+  },
+  "crazy eights": {
+    configuration: true,
+    finished_synopsis: false,
+    immersive: false,
+    synopsis: CrazyEightsGameSynopsis,
+    player: CrazyEightsGameComponent,
+    afterparty: CrazyEightsAfterPartyComponent,
+  },
+  "word search": {
+    configuration: true,
+    finished_synopsis: false,
+    immersive: false,
+    synopsis: WordSearchGameSynopsis,
+    player: WordSearchGameComponent,
+    afterparty: WordSearchAfterPartyComponent,
+  },
   "gin": {
     configuration: true,
     finished_synopsis: false,
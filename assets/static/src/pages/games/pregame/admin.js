@@ -17,6 +17,8 @@ import * as g from '@rmwc/grid';
 import '@rmwc/grid/styles';
 import * as l from '@rmwc/list';
 import '@rmwc/list/styles';
+import { Select } from '@rmwc/select';
+import '@rmwc/select/styles';
 import { Switch } from '@rmwc/switch';
 import '@rmwc/switch/styles';
 import { TextField } from '@rmwc/textfield';
@@ -26,7 +28,7 @@ import '@rmwc/textfield/styles';
 import '../../../App.css';
 import { loadGame, addEv, notify } from '../common.js';
 import { CreateGameForm } from '../config.js';
-import { UserCache } from '../../../utils/cache.js';
+import { isBot, UserCache } from '../../../utils/cache.js';
 import { CancellableButton } from '../../../utils/cancellable.js';
 
 class PreGameAdminPage extends React.Component {
@@ -39,6 +41,7 @@ class PreGameAdminPage extends React.Component {
       order: true,
       teams: true,
       bind_requests: [],
+      bot_difficulty: "",
     };
 
     this.game = this.props.game || {};
@@ -74,6 +77,9 @@ class PreGameAdminPage extends React.Component {
             if (data.bound_players !== undefined) {
               Object.assign(player, { bound_players: data.bound_players });
             }
+            if (data.difficulty !== undefined) {
+              Object.assign(player, { difficulty: data.difficulty });
+            }
             missing = false;
           }
         }
@@ -83,7 +89,8 @@ class PreGameAdminPage extends React.Component {
           var playing = data.playing ? true : false;
           var connected = data.connected ? true : false;
           var ready = data.ready ? true : false;
-          this.state.waitlist.push(Object.assign(user, { admitted, playing, connected, ready }));
+          var difficulty = data.difficulty || "";
+          this.state.waitlist.push(Object.assign(user, { admitted, playing, connected, ready, difficulty }));
         }
 
         this.sortUsers();
@@ -94,6 +101,12 @@ class PreGameAdminPage extends React.Component {
       "notify-join": data => userNotification(data),
       "notify-countback": data => userNotification(data),
       "notify-users": data => {
+        // Computer players are gone once they've been removed.
+        let present = data.players.map(player => +player.user);
+        this.setState(state => Object.assign({}, state, {
+          waitlist: state.waitlist.filter(user => !isBot(user.id) || present.includes(+user.id)),
+        }));
+
         for (let player of data.players) {
           userNotification(player)
         }
@@ -239,6 +252,18 @@ class PreGameAdminPage extends React.Component {
       notify(this.props.snackbar, response.message, response.type);
     }
   }
+  async addBot() {
+    var ret = await this.game.interface.controller.addBot(this.state.bot_difficulty);
+    if (ret && ret.message_type && ret.message_type === "error") {
+      notify(this.props.snackbar, ret.error, "error");
+    }
+  }
+  async removeBot(user) {
+    var ret = await this.game.interface.controller.removeBot(user.id);
+    if (ret && ret.message_type && ret.message_type === "error") {
+      notify(this.props.snackbar, ret.error, "error");
+    }
+  }
   async assignTeams(verify) {
     if (!this.state.teams) return true;
     var team_data = {};
@@ -332,6 +357,13 @@ class PreGameAdminPage extends React.Component {
       </l.ListGroup>;
     }
 
+    let bot_difficulties = [
+      { label: 'Easy', value: 'easy' },
+      { label: 'Medium', value: 'medium' },
+      { label: 'Hard', value: 'hard' },
+      { label: 'Expert', value: '' },
+    ];
+
     let teams = [{label:'None',value:null}];
     let max_team = Math.max(...this.state.waitlist.map(u => u.team).filter(isFinite)) || 0;
     for (let i=0; i<=max_team; i+=1) {
@@ -340,6 +372,13 @@ class PreGameAdminPage extends React.Component {
 
     let content = <c.Card>
       <div style={{ padding: '1rem 1rem 1rem 1rem' }} >
+        {
+          this.game.seeded
+          ? <p>
+              <b>You picked the shuffle secret for this game, so you know every deal in advance. You can only watch: make yourself a spectator before starting.</b>
+            </p>
+          : null
+        }
         <l.List twoLine>
           { invite }
           <l.CollapsibleList handle={
@@ -388,7 +427,7 @@ class PreGameAdminPage extends React.Component {
             </l.ListItem>
             { players.map((user, i) =>
                 <l.ListItem key={user.id} disabled style={{ height: "auto", minHeight: "72px" }}>
-                  <span className="unselectable">{+i + 1}.&nbsp;</span> {user.display}{user.id === this.props.user.id ? " (You)" : user.ready ? <>&nbsp;-&nbsp;<i style={{ 'verticalAlign': 'middle' }}>Ready</i></> : <>&nbsp;-&nbsp;<i style={{ 'verticalAlign': 'middle' }}>Not Ready</i></> }
+                  <span className="unselectable">{+i + 1}.&nbsp;</span> {user.display}{user.id === this.props.user.id ? " (You)" : user.bot ? <>&nbsp;-&nbsp;<i style={{ 'verticalAlign': 'middle' }}>{ bot_difficulties.find(option => option.value === (user.difficulty || "")).label }</i></> : user.ready ? <>&nbsp;-&nbsp;<i style={{ 'verticalAlign': 'middle' }}>Ready</i></> : <>&nbsp;-&nbsp;<i style={{ 'verticalAlign': 'middle' }}>Not Ready</i></> }
                   {
                     this.state.started
                     ? <Icon icon={ user.connected ? 'check' : 'hourglass_empty' } style={{ 'verticalAlign': 'middle' }} />
@@ -411,14 +450,16 @@ class PreGameAdminPage extends React.Component {
                         </>
                       : <></>
                     }
-                    { user.id === this.props.user.id
-                      ? null
+                    { user.bot
+                      ? <Button raised label="Remove" onClick={ () => this.removeBot(user) } />
+                      : user.id === this.props.user.id
+                      ? <Button raised label="Bench" onClick={ () => this.toggleSpectator(user) } />
                       : <>
                           <Button raised label="Kick out" onClick={ () => this.toggleAdmitted(user) } />
                           &nbsp;
+                          <Button raised label="Bench" onClick={ () => this.toggleSpectator(user) } />
                         </>
                     }
-                    <Button raised label="Bench" onClick={ () => this.toggleSpectator(user) } />
                     { this.state.order
                       ? <span style={{ 'whiteSpace': 'nowrap' }}>
                           <IconButton className="vertical-align-middle"
@@ -441,6 +482,15 @@ class PreGameAdminPage extends React.Component {
                 </l.ListItem>
             )}
             { players.length === 0 ? "There are no players in this game." : null }
+            <l.ListItem disabled style={{ height: "auto", minHeight: "72px" }}>
+              <Select label="Computer player" enhanced
+                value={ this.state.bot_difficulty }
+                onChange={ e => { let bot_difficulty = e.target.value; this.setState(state => Object.assign({}, state, { bot_difficulty })); } }
+                options={ bot_difficulties }
+              />
+              &nbsp;&nbsp;
+              <Button raised label="Add computer player" onClick={ () => this.addBot() } disabled={ this.state.started } />
+            </l.ListItem>
             <l.ListItem disabled>
               <h1>Spectators</h1>
             </l.ListItem>
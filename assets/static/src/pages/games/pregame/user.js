@@ -302,6 +302,13 @@ class PreGameUserPage extends React.Component {
             </p>
           : null
         }
+        {
+          this.game.seeded
+          ? <p>
+              <b>The owner picked the shuffle secret for this game, so its deals were decided in advance. The owner only watches.</b>
+            </p>
+          : null
+        }
         {
           this.state.status !== "pending"
           ? <>
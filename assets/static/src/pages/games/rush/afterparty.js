@@ -18,6 +18,7 @@ class RushAfterPartyComponent extends React.Component {
     this.state = {
       snapshots: null,
       winner: this.game.winner,
+      results: null,
       finished: false,
       message: "Loading results...",
       timeout: killable(() => { this.refreshData() }, 5000),
@@ -61,7 +62,15 @@ class RushAfterPartyComponent extends React.Component {
         // the screen even though new data is sent. Use snapshots to send only
         // the data we care about.
         this.setState(state => Object.assign({}, state, { snapshots: [] }));
-        this.setState(state => Object.assign({}, state, { snapshots: snapshots, winner: winner, finished: data.finished }));
+        var results = null;
+        if (data.results) {
+          results = [];
+          for (let result of data.results) {
+            results.push(Object.assign({}, result, { user: await UserCache.FromId(result.user) }));
+          }
+        }
+
+        this.setState(state => Object.assign({}, state, { snapshots: snapshots, winner: winner, results: results, finished: data.finished }));
 
         if (data.finished) {
           if (this.state.timeout) {
@@ -131,6 +140,25 @@ class RushAfterPartyComponent extends React.Component {
             ? <h2>That was fun, wasn't it?</h2>
             : <></>
           }
+          {
+            this.state.results
+            ? <table className="results">
+                <thead>
+                  <tr><th>Rank</th><th>Player</th><th>Rounds</th><th>Score</th></tr>
+                </thead>
+                <tbody>
+                  { this.state.results.map(result =>
+                    <tr key={ result.user.id }>
+                      <td>{ result.rank }</td>
+                      <td>{ result.user.display }</td>
+                      <td>{ result.round_scores.join(", ") }</td>
+                      <td>{ result.score }</td>
+                    </tr>
+                  ) }
+                </tbody>
+              </table>
+            : <></>
+          }
           {
             this.props.room ? <Button onClick={ () => this.returnToRoom() } raised >Return to Room</Button> : <></>
           }
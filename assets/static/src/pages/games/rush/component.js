@@ -8,6 +8,10 @@ import { Button } from '@rmwc/button';
 import '@rmwc/button/styles';
 import { CircularProgress } from '@rmwc/circular-progress';
 import '@rmwc/circular-progress/styles';
+import * as d from '@rmwc/dialog';
+import '@rmwc/dialog/styles';
+import { TextField } from '@rmwc/textfield';
+import '@rmwc/textfield/styles';
 
 // Library to disable body scrolling
 import { disableBodyScroll, enableBodyScroll, clearAllBodyScrollLocks } from 'body-scroll-lock';
@@ -42,6 +46,7 @@ class RushGameComponent extends React.Component {
         drawing: false,
         discarding: [],
         padding: this.props.readOnly ? [0,0] : PADDING,
+        blank: null,
       },
       interface: props.interface,
     };
@@ -519,6 +524,22 @@ class RushGameComponent extends React.Component {
     }
   }
 
+  async doHint() {
+    var ret = await this.state.interface.hint();
+    if (!ret || !this.props.notify) {
+      return;
+    }
+
+    if (ret.message_type === "error") {
+      this.props.notify(ret.error);
+    } else if (!ret.hints || ret.hints.length === 0) {
+      this.props.notify("No hints right now; try drawing or discarding.");
+    } else {
+      var words = ret.hints.map(hint => hint.attached ? hint.word + " (" + (hint.vertical ? "down" : "across") + " through your board)" : hint.word);
+      this.props.notify("Try " + words.join(", "));
+    }
+  }
+
   async doCheck() {
     var result = await this.state.interface.check();
     if (result) {
@@ -606,14 +627,24 @@ class RushGameComponent extends React.Component {
     };
   }
 
-  async doPlay(tile, pos, dropped) {
+  async doPlay(tile, pos, dropped, letter) {
+    // Blank tiles are played once the player picks the letter they stand for.
+    if (tile.blank && !letter) {
+      this.setState(state => {
+        state = Object.assign({}, state);
+        state.presentation.blank = { tile, pos, dropped, letter: tile.value || "" };
+        return state;
+      });
+      return;
+    }
+
     this.setState(state => {
       state = Object.assign({}, state);
       if (dropped) {
         state.presentation.dropped = state.interface.data.positionOf(tile);
       }
 
-      state.interface.play(tile, pos);
+      state.interface.play(tile, pos, letter);
 
       state.presentation.last_selected = pos;
       state.presentation.selected = null;
@@ -623,6 +654,31 @@ class RushGameComponent extends React.Component {
     });
   }
 
+  setBlankLetter(letter) {
+    this.setState(state => {
+      state = Object.assign({}, state);
+      state.presentation.blank = Object.assign({}, state.presentation.blank, { letter });
+      return state;
+    });
+  }
+
+  async doBlank(action) {
+    var blank = this.state.presentation.blank;
+    if (!blank) {
+      return;
+    }
+
+    this.setState(state => {
+      state = Object.assign({}, state);
+      state.presentation.blank = null;
+      return state;
+    });
+
+    if (action === "accept" && blank.letter) {
+      return this.doPlay(blank.tile, blank.pos, blank.dropped, blank.letter);
+    }
+  }
+
   isMove(here, there) {
     var here_tile = this.state.interface.data.get(here);
     var there_tile = this.state.interface.data.get(there);
@@ -807,6 +863,12 @@ class RushGameComponent extends React.Component {
               onClick: this.doCheck.bind(this),
               onTouchEnd: this.doCheck.bind(this),
             }, "Check"),
+            this.state.interface.game?.config?.hints ? e(Button, {
+              raised: true,
+              key: "hint",
+              theme: 'secondary',
+              onClick: this.doHint.bind(this),
+            }, "Hint") : null,
             e(Button, {
               raised: true,
               disabled: !this.state.interface.data.bank.empty() || this.state.interface.data.grid.components().length > 1,
@@ -835,7 +897,23 @@ class RushGameComponent extends React.Component {
         )
       ]
     );
-    return e('div', {className: "game-component"}, [grid, bank]);
+    let blank = this.state.presentation.blank;
+    let dialog = e(d.Dialog, {key: "blank", open: blank !== null, onClosed: ev => this.doBlank(ev.detail.action)}, [
+      e(d.DialogTitle, {key: "title"}, "Blank tile"),
+      e(d.DialogContent, {key: "content"},
+        e(TextField, {
+          label: "Which letter should this blank tile be?",
+          value: blank ? blank.letter : "",
+          maxLength: 1,
+          onChange: ev => this.setBlankLetter(ev.target.value),
+        })
+      ),
+      e(d.DialogActions, {key: "actions"}, [
+        e(d.DialogButton, {key: "cancel", action: "close", theme: "secondary"}, "Cancel"),
+        e(d.DialogButton, {key: "play", action: "accept", isDefaultAction: true, disabled: !blank || !blank.letter}, "Play"),
+      ]),
+    ]);
+    return e('div', {className: "game-component"}, [grid, bank, dialog]);
   }
 }
 
@@ -0,0 +1,254 @@
+import React from 'react';
+
+import '../../../main.scss';
+
+import { Avatar } from '@rmwc/avatar';
+import '@rmwc/avatar/styles';
+import { Button } from '@rmwc/button';
+import '@rmwc/button/styles';
+import * as c from '@rmwc/card';
+import '@rmwc/card/styles';
+import * as l from '@rmwc/list';
+import '@rmwc/list/styles';
+
+import { WordSearchGrid } from '../../../games/wordsearch.js';
+import { loadGame, addEv, notify, killable } from '../../games.js';
+import { UserCache, GameCache } from '../../../utils/cache.js';
+import { gravatarify } from '../../../utils/gravatar.js';
+
+class WordSearchAfterPartyComponent extends React.Component {
+  constructor(props) {
+    super(props);
+    this.game = loadGame(this.props.game);
+    this.state = {
+      game: props.game,
+      player_mapping: null,
+      grid: null,
+      words: null,
+      cancelled: null,
+      scores: null,
+      all_words: null,
+      seed: null,
+      commitment: null,
+      winners: null,
+      finished: false,
+      message: "Loading results...",
+      timeout: killable(() => { this.refreshData() }, 5000),
+    };
+
+    GameCache.Invalidate(this.props.game.id);
+
+    this.unmount = addEv(this.game, {
+      "game-state": async (data) => {
+        var mapping = {};
+        for (let index in data.player_mapping) {
+          mapping[index] = await UserCache.FromId(data.player_mapping[index]);
+        }
+
+        var winners = [];
+        for (let winner of data.winners || []) {
+          winners.push(await UserCache.FromId(winner));
+        }
+
+        // HACK: When refreshData() is called from the button, we don't redraw
+        // the screen even though new data is sent. Use snapshots to send only
+        // the data we care about.
+        this.setState(state => Object.assign({}, state, { words: null }));
+        this.setState(state => Object.assign({}, state, {
+          player_mapping: mapping,
+          grid: new WordSearchGrid(data.grid),
+          words: data.words || [],
+          cancelled: data.cancelled || [],
+          scores: data.scores || [],
+          all_words: data.all_words || [],
+          seed: data.seed,
+          commitment: data.commitment,
+          winners: winners,
+          finished: data.finished,
+        }));
+
+        if (data.finished) {
+          if (this.state.timeout) {
+            this.state.timeout.kill();
+          }
+
+          this.setState(state => Object.assign({}, state, { timeout: null }));
+        }
+      },
+      "error": (data) => {
+        var message = "Unable to load game data.";
+        if (data.error) {
+          message = data.error;
+        }
+
+        notify(this.props.snackbar, message, data.message_type);
+        this.setState(state => Object.assign({}, state, { message }));
+      },
+      "": data => {
+        if (data.message) {
+          notify(this.props.snackbar, data.message, data.message_type);
+        }
+      },
+    });
+  }
+  componentDidMount() {
+    this.state.timeout.exec();
+  }
+  componentWillUnmount() {
+    this.props.setGame(null);
+
+    if (this.state.timeout) {
+      this.state.timeout.kill();
+    }
+
+    if (this.unmount) this.unmount();
+  }
+  async refreshData() {
+    await this.game.interface.controller.wsController.sendAndWait({"message_type": "peek"});
+
+    if (this.state.finished) {
+      if (this.state.timeout) {
+        this.state.timeout.kill();
+        this.setState(state => Object.assign({}, state, { timeout: null }));
+      }
+    }
+  }
+  returnToRoom() {
+    if (this.props.game.interface) {
+      this.props.game.interface.close();
+    }
+
+    this.props.game.interface = null;
+
+    this.props.setGame(null);
+    this.props.setPage("room", true);
+  }
+  render() {
+    var historical_data = null;
+
+    if (this.state.finished && this.state.player_mapping && this.state.words) {
+      let found = {};
+      let order = Object.keys(this.state.player_mapping).sort((a, b) => (+this.state.scores[b] || 0) - (+this.state.scores[a] || 0));
+
+      let scores_data = [];
+      for (let player_index of order) {
+        let user = this.state.player_mapping[player_index];
+        let words = [...(this.state.words[player_index] || [])].sort();
+        let cancelled = this.state.cancelled[player_index] || [];
+        for (let word of words) {
+          found[word] = true;
+        }
+
+        scores_data.push(
+          <div key={ user.id } style={{ paddingBottom: '15px' }}>
+            <Avatar src={ gravatarify(user) } name={ user.display } size="medium" /> <b>{ user.display }</b>: { this.state.scores[player_index] || 0 } point{ +this.state.scores[player_index] === 1 ? "" : "s" }
+            <div style={{ columnWidth: "8em", paddingTop: '5px' }}>
+              {
+                words.map(word => cancelled.includes(word)
+                  ? <div key={ word } style={{ textDecoration: "line-through", color: "#888888" }}>{ word }</div>
+                  : <div key={ word }>{ word }</div>
+                )
+              }
+            </div>
+          </div>
+        );
+      }
+
+      let grid_rows = [];
+      for (let row = 0; row < this.state.grid.size; row++) {
+        let cells = [];
+        for (let col = 0; col < this.state.grid.size; col++) {
+          let id = this.state.grid.get(row, col);
+          let tile = id === null ? null : this.state.grid.tiles[id];
+          cells.push(
+            <td key={ col } style={{ width: "2em", height: "2em", fontSize: "1.4em", border: "1px solid #888888" }}>
+              { tile ? tile.display || tile.value : null }
+            </td>
+          );
+        }
+        grid_rows.push(<tr key={ row }>{ cells }</tr>);
+      }
+
+      let all_words = [...this.state.all_words].sort();
+      let missed = all_words.filter(word => !found[word]).length;
+
+      historical_data = <div style={{ width: "90%" , margin: "0 auto 0.5em auto" }}>
+        <c.Card style={{ width: "100%" , padding: "0.5em 0.5em 0.5em 0.5em" }}>
+          <div>
+            <h3>Game Analysis</h3>
+            <p>Words found by more than one player are struck out and don't score.</p>
+            <div style={{ textAlign: 'left' }}>
+              <l.CollapsibleList handle={
+                  <l.SimpleListItem text={ <b>Scores</b> } metaIcon="chevron_right" />
+                }
+              >
+                { scores_data }
+              </l.CollapsibleList>
+              <l.CollapsibleList handle={
+                  <l.SimpleListItem text={ <b>Grid</b> } metaIcon="chevron_right" />
+                }
+              >
+                <table style={{ margin: "0 auto", borderCollapse: "collapse", textAlign: "center" }}>
+                  <tbody>{ grid_rows }</tbody>
+                </table>
+              </l.CollapsibleList>
+              <l.CollapsibleList handle={
+                  <l.SimpleListItem text={ <b>All Words ({ all_words.length }, { missed } missed)</b> } metaIcon="chevron_right" />
+                }
+              >
+                <div style={{ columnWidth: "8em" }}>
+                  {
+                    all_words.map(word => found[word]
+                      ? <div key={ word }>{ word }</div>
+                      : <div key={ word } style={{ fontWeight: "bold", color: "#2b6b2b" }}>{ word }</div>
+                    )
+                  }
+                </div>
+              </l.CollapsibleList>
+              {
+                this.state.seed
+                ? <l.CollapsibleList handle={
+                      <l.SimpleListItem text={ <b>Seed</b> } metaIcon="chevron_right" />
+                    }
+                  >
+                    <p style={{ wordBreak: "break-all" }}>
+                      Secret: <code>{ this.state.seed.secret }</code><br />
+                      Commitment: <code>{ this.state.seed.commitment || this.state.commitment }</code>
+                    </p>
+                  </l.CollapsibleList>
+                : null
+              }
+            </div>
+          </div>
+        </c.Card>
+      </div>;
+    }
+
+    var winner_info = <h1>Please wait while the game finishes...</h1>;
+    if (this.state.finished && this.state.winners) {
+      if (this.state.winners.length === 0) {
+        winner_info = <h1>Nobody won</h1>;
+      } else {
+        let names = this.state.winners.map(winner => +winner.id === +this.props.user.id ? "You" : winner.display);
+        winner_info = <h1 style={{ color: "#249724" }}>{ names.join(" and ") } won!</h1>;
+      }
+    }
+
+    return (
+      <div>
+        <h1 style={{ color: "#2b6b2b" }}>Word Search</h1>
+        <div>
+          { winner_info }
+          {
+            this.props.room ? <><Button onClick={ () => this.returnToRoom() } raised >Return to Room</Button><br /><br /></> : <></>
+          }
+          { historical_data }
+        </div>
+      </div>
+    );
+  }
+}
+
+export {
+  WordSearchAfterPartyComponent
+};
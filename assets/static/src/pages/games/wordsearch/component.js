@@ -0,0 +1,174 @@
+import React from 'react';
+
+import '../../../main.scss';
+
+import { Button } from '@rmwc/button';
+import '@rmwc/button/styles';
+import * as c from '@rmwc/card';
+import '@rmwc/card/styles';
+
+// Format a number of seconds as m:ss.
+function formatRemaining(seconds) {
+  let minutes = Math.floor(seconds / 60);
+  let rest = seconds % 60;
+  return minutes + ":" + (rest < 10 ? "0" : "") + rest;
+}
+
+class WordSearchGameComponent extends React.Component {
+  constructor(props) {
+    super(props);
+    this.state = {};
+    this.state.game = this.props.game;
+    this.state.path = [];
+    this.state.remaining = this.state.game.interface.remaining();
+    this.timer = null;
+    // FIXME: hack?
+    let old_handler = this.state.game.interface.onChange;
+    this.state.game.interface.onChange = () => {
+      old_handler();
+      this.setState(state => {
+        // Jinx
+        return state;
+      });
+    };
+  }
+  componentDidMount() {
+    this.timer = setInterval(() => {
+      let remaining = this.state.game.interface.remaining();
+      this.setState(state => Object.assign(state, { remaining }));
+    }, 1000);
+  }
+  componentWillUnmount() {
+    if (this.timer) {
+      clearInterval(this.timer);
+      this.timer = null;
+    }
+  }
+  clearPathAnd(then) {
+    return (...arg) => {
+      this.setState(state => Object.assign(state, { path: [] }));
+      return then && then(...arg);
+    };
+  }
+  // Extend the traced path with this tile, or back it up to this tile when
+  // it is already on the path. Only tiles touching the end of the path may
+  // be added.
+  trace(id) {
+    let grid = this.state.game.interface.data.grid;
+    this.setState(state => {
+      let index = state.path.indexOf(id);
+      if (index >= 0) {
+        state.path = state.path.slice(0, index === state.path.length - 1 ? index : index + 1);
+      } else if (state.path.length === 0 || grid.adjacent(state.path[state.path.length - 1], id)) {
+        state.path = [...state.path, id];
+      }
+      return state;
+    });
+  }
+  render() {
+    var status = a => <h3>{ a }</h3>;
+    var big_status = a => <h2>{ a }</h2>;
+    var card = (...children) => <div style={{ width: "90%" , margin: "0 auto 1em auto" }}>
+      <c.Card style={{ width: "100%" , padding: "0.5em 0.5em 0.5em 0.5em" }}>
+        <div style={{ padding: "1rem 1rem 1rem 1rem" }}>
+          { children }
+        </div>
+      </c.Card>
+    </div>;
+
+    let game = this.state.game.interface;
+    let data = game.data;
+
+    if (!game.started) {
+      return status("Waiting for game to start …");
+    } else if (game.finished) {
+      return <div>
+        {status("Finished")}
+      </div>;
+    }
+
+    let searching = !data.done && this.state.remaining !== 0;
+    let path = this.state.path;
+    let last = path.length > 0 ? path[path.length - 1] : null;
+
+    var rows = [];
+    for (let row = 0; row < data.grid.size; row++) {
+      let cells = [];
+      for (let col = 0; col < data.grid.size; col++) {
+        let id = data.grid.get(row, col);
+        let tile = id === null ? null : data.grid.tiles[id];
+        let traced = id !== null && path.includes(id);
+        let reachable = id !== null && !traced && (last === null || data.grid.adjacent(last, id));
+        cells.push(
+          <td key={ col } style={{ padding: "0.2em" }}>
+            {
+              tile
+              ? <Button label={ tile.display || tile.value }
+                  raised={ !traced } unelevated={ traced } ripple={ false }
+                  disabled={ !searching || (!traced && !reachable) }
+                  style={{ width: "3.5em", height: "3.5em", fontSize: "1.4em", backgroundColor: id === last ? "#5fad5f" : traced ? "#249724" : null }}
+                  onMouseDown={ e => { e.preventDefault(); this.trace(id); } }
+                  onMouseEnter={ e => { if (e.buttons === 1 && reachable) this.trace(id); } }
+                />
+              : null
+            }
+          </td>
+        );
+      }
+      rows.push(<tr key={ row }>{ cells }</tr>);
+    }
+
+    let word = data.grid.spell(path);
+    let min_length = +data.config?.min_length || 3;
+    let found = data.words.includes(word.toUpperCase());
+
+    var header = <div key="header">
+      {
+        this.state.remaining === null
+        ? null
+        : big_status(this.state.remaining > 0 ? formatRemaining(this.state.remaining) + " left" : "Time's up!")
+      }
+      {
+        data.done
+        ? status("You're done searching; waiting for the others to finish …")
+        : status("Trace words of at least " + min_length + " letters through touching tiles")
+      }
+    </div>;
+
+    var board = <div key="board">
+      <table style={{ margin: "0 auto", borderCollapse: "collapse", userSelect: "none" }}>
+        <tbody>{ rows }</tbody>
+      </table>
+      <h2 style={{ minHeight: "1.5em", letterSpacing: "0.2em" }}>{ word }</h2>
+      <Button label={ found ? "Already found" : "Submit" } unelevated ripple={false}
+        disabled={ !searching || word.length < min_length || found }
+        onClick={ this.clearPathAnd(() => game.submit(path)) } />
+      &nbsp;&nbsp;
+      <Button label="Clear" raised ripple={false}
+        disabled={ path.length === 0 }
+        onClick={ this.clearPathAnd() } />
+      &nbsp;&nbsp;
+      <Button label="I'm done" raised ripple={false}
+        disabled={ !searching }
+        onClick={ this.clearPathAnd(() => game.done()) } />
+    </div>;
+
+    var words = [...data.words].sort();
+
+    return <div>
+      { card(header, board) }
+      {
+        card(
+          <h3 key="title">{ words.length } word{ words.length === 1 ? "" : "s" } found</h3>,
+          <div key="words" style={{ columnWidth: "8em", textAlign: "left" }}>
+            { words.map(found_word => <div key={ found_word }>{ found_word }</div>) }
+          </div>
+        )
+      }
+    </div>;
+  }
+}
+
+export {
+  WordSearchGameComponent
+};
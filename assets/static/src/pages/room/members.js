@@ -38,6 +38,8 @@ class RoomMembersTab extends React.Component {
       room_owner: +this.props.user.id === +this.props.room.owner,
       not_admitted: 0,
       video_chat: this.props.room.config?.video_chat,
+      allowed_words: (this.props.room.config?.words?.allowed || []).join(", "),
+      denied_words: (this.props.room.config?.words?.denied || []).join(", "),
     };
 
     this.code_ref = React.createRef();
@@ -89,6 +91,12 @@ class RoomMembersTab extends React.Component {
     }
     this.props.room.config.video_chat = this.state.video_chat;
 
+    var split = (words) => words.split(/[\s,]+/).filter(word => word.length > 0);
+    this.props.room.config.words = {
+      allowed: split(this.state.allowed_words),
+      denied: split(this.state.denied_words),
+    };
+
     var ret = await this.props.room.save();
     if (ret && (ret.error || ret.type === "error")) {
       console.log(ret);
@@ -135,6 +143,15 @@ class RoomMembersTab extends React.Component {
             <TextField fullwidth value={ this.state.video_chat } onChange={ this.inputHandler("video_chat") } />
           </l.ListItemText>
         </l.ListItem>
+        <l.ListItem disabled key="words-desc">
+          <p>Words to allow or deny in word games, separated by commas:</p>
+        </l.ListItem>
+        <l.ListItem key="words-allowed">
+          <TextField fullwidth label="Allowed words" value={ this.state.allowed_words } onChange={ this.inputHandler("allowed_words") } />
+        </l.ListItem>
+        <l.ListItem key="words-denied">
+          <TextField fullwidth label="Denied words" value={ this.state.denied_words } onChange={ this.inputHandler("denied_words") } />
+        </l.ListItem>
         <l.ListItem disabled key="chat-submit">
           <Button label="Update" raised onClick={() => this.updateConfig() } />
         </l.ListItem>
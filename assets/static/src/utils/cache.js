@@ -8,6 +8,22 @@ function grloaded(v) {
   return v !== undefined && v !== null && v.model && !v.model.error && v.model.style;
 }
 
+// Computer players don't have accounts; the server hands them identifiers
+// starting here.
+const BOT_UID_BASE = 2 ** 52;
+
+function isBot(id) {
+  return +id >= BOT_UID_BASE;
+}
+
+function botUser(id) {
+  var ret = new UserModel();
+  ret.id = +id;
+  ret.display = "Computer " + (+id - BOT_UID_BASE);
+  ret.bot = true;
+  return ret;
+}
+
 class UserCacheSingleton {
   constructor() {
     this.cache = {};
@@ -16,6 +32,7 @@ class UserCacheSingleton {
 
   async FromId(id) {
     if (typeof id !== 'number') return id;
+    if (isBot(id)) return botUser(id);
 
     var threshhold = +this.access_threshhold + Math.floor(Math.random() * 20 - 10);
     if (!uloaded(this.cache[id]) || +this.cache[id].access >= +threshhold) {
@@ -107,6 +124,7 @@ class RoomCacheSingleton {
 var RoomCache = new RoomCacheSingleton();
 
 export {
+  isBot,
   UserCache,
   GameCache,
   RoomCache,
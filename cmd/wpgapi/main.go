@@ -18,6 +18,8 @@ import (
 
 	"net/url"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/gorilla/handlers"
 	"github.com/gorilla/mux"
@@ -32,6 +34,7 @@ import (
 	"git.cipherboy.com/WillowPatchGames/wpg/pkg/api/plan"
 	"git.cipherboy.com/WillowPatchGames/wpg/pkg/api/room"
 	"git.cipherboy.com/WillowPatchGames/wpg/pkg/api/user"
+	"git.cipherboy.com/WillowPatchGames/wpg/pkg/games"
 )
 
 const dbFmt string = "host=%s port=%d user=%s password=%s dbname=%s sslmode=%s"
@@ -68,6 +71,7 @@ func main() {
 
 	var planConfig string = "configs/plans.yaml"
 	var stripeConfig string = "configs/stripe.yaml"
+	var dictionaryDirectory string = "/usr/share/dict"
 
 	flag.StringVar(&addr, "addr", "localhost:8042", "Address to listen for HTTP requests on")
 
@@ -89,6 +93,7 @@ func main() {
 
 	flag.StringVar(&planConfig, "plan_config", "configs/plans.yaml", "Path to plan configuration file")
 	flag.StringVar(&stripeConfig, "stripe_config", "configs/stripe.yaml", "Path to Stripe configuration file")
+	flag.StringVar(&dictionaryDirectory, "dictionary_dir", "/usr/share/dict", "Path to directory of word lists; send SIGHUP to reload them")
 	flag.Parse()
 
 	// Open Database connection first.
@@ -121,6 +126,19 @@ func main() {
 		panic(err)
 	}
 
+	// Word lists used by word games; reload them on SIGHUP so they can be
+	// updated without a restart.
+	games.SetDictionaryDirectory(dictionaryDirectory)
+
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	go func() {
+		for range hangup {
+			log.Println("Reloading dictionaries from " + dictionaryDirectory)
+			games.ReloadDictionaries()
+		}
+	}()
+
 	router := mux.NewRouter()
 	// Add our main API handlers. This extends the main router with relevant
 	// routes.
@@ -119,6 +119,11 @@ type Game struct {
 	Config sql.NullString
 	State  sql.NullString
 
+	// Secret the game's deals are shuffled from. The server generates one
+	// unless the owner picked it, in which case they may only watch.
+	ShuffleSecret       sql.NullString
+	PickedShuffleSecret bool
+
 	CreatedAt time.Time      `gorm:"autoCreateTime"`
 	UpdatedAt time.Time      `gorm:"autoUpdateTime"`
 	DeletedAt gorm.DeletedAt `gorm:"index"`
@@ -147,6 +152,7 @@ type GameMessage struct {
 	UserID    uint64
 	GameID    uint64
 	Timestamp time.Time
+	Outbound  bool // Sent by the server to UserID, rather than received from them.
 	Message   string
 
 	CreatedAt time.Time      `gorm:"autoCreateTime"`
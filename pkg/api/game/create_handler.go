@@ -5,6 +5,7 @@ import (
 	"errors"
 	"log"
 	"net/http"
+	"strconv"
 	"time"
 
 	"gorm.io/gorm"
@@ -21,12 +22,15 @@ import (
 	"git.cipherboy.com/WillowPatchGames/wpg/pkg/middleware/hwaterr"
 )
 
+const maxShuffleSecret = 256
+
 type createHandlerData struct {
-	RoomID   uint64                 `json:"room"`
-	Style    string                 `json:"style"`
-	Open     bool                   `json:"open"`
-	Config   map[string]interface{} `json:"config"`
-	APIToken string                 `json:"api_token,omitempty" header:"X-Auth-Token,omitempty" query:"api_token,omitempty"`
+	RoomID        uint64                 `json:"room"`
+	Style         string                 `json:"style"`
+	Open          bool                   `json:"open"`
+	Config        map[string]interface{} `json:"config"`
+	ShuffleSecret string                 `json:"shuffle_secret,omitempty"`
+	APIToken      string                 `json:"api_token,omitempty" header:"X-Auth-Token,omitempty" query:"api_token,omitempty"`
 }
 
 type createHandlerResponse struct {
@@ -37,6 +41,7 @@ type createHandlerResponse struct {
 	Open      bool      `json:"open"`
 	Code      string    `json:"code"`
 	Lifecycle string    `json:"lifecycle"`
+	Seeded    bool      `json:"seeded"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 	ExpiresAt time.Time `json:"expires_at"`
@@ -80,6 +85,16 @@ func (handle *CreateHandler) verifyRequest() error {
 		return api_errors.ErrBadValue
 	}
 
+	if handle.req.ShuffleSecret != "" {
+		if _, ok := mode.NewState().(games.SeededState); !ok {
+			return errors.New("unable to pick the shuffle secret for " + mode.String() + " games")
+		}
+
+		if len(handle.req.ShuffleSecret) > maxShuffleSecret {
+			return errors.New("shuffle secret must be at most " + strconv.Itoa(maxShuffleSecret) + " characters")
+		}
+	}
+
 	if handle.req.Config != nil {
 		handle.parsedConfig = mode.EmptyConfig()
 		if err := figgy.Load(handle.parsedConfig, handle.req.Config); err != nil {
@@ -158,6 +173,16 @@ func (handle CreateHandler) ServeErrableHTTP(w http.ResponseWriter, r *http.Requ
 			database.SetSQLFromString(&game.Config, string(data))
 		}
 
+		// Deal from a secret only the server knows, unless the owner picked one.
+		// The commitment to it is published before play starts, so deals can't
+		// be changed afterwards without anyone noticing.
+		if handle.req.ShuffleSecret != "" {
+			database.SetSQLFromString(&game.ShuffleSecret, handle.req.ShuffleSecret)
+			game.PickedShuffleSecret = true
+		} else if _, ok := games.GameModeFromString(handle.req.Style).NewState().(games.SeededState); ok {
+			database.SetSQLFromString(&game.ShuffleSecret, utils.RandomToken())
+		}
+
 		if err = tx.Create(&game).Error; err != nil {
 			return err
 		}
@@ -225,6 +250,7 @@ func (handle CreateHandler) ServeErrableHTTP(w http.ResponseWriter, r *http.Requ
 	handle.resp.Open = game.Open
 	handle.resp.Code = game.JoinCode.String
 	handle.resp.Lifecycle = game.Lifecycle
+	handle.resp.Seeded = game.PickedShuffleSecret
 
 	handle.resp.CreatedAt = game.CreatedAt
 	handle.resp.UpdatedAt = game.UpdatedAt
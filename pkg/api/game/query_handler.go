@@ -32,6 +32,7 @@ type queryHandlerResponse struct {
 	JoinCode  string      `json:"code,omitempty"`
 	Lifecycle string      `json:"lifecycle"`
 	Config    interface{} `json:"config"`
+	Seeded    bool        `json:"seeded"`
 	Admitted  bool        `json:"admitted"`
 	CreatedAt time.Time   `json:"created_at"`
 	UpdatedAt time.Time   `json:"updated_at"`
@@ -183,6 +184,7 @@ func (handle *QueryHandler) ServeErrableHTTP(w http.ResponseWriter, r *http.Requ
 		handle.resp.JoinCode = game.JoinCode.String
 		handle.resp.Lifecycle = game.Lifecycle
 		handle.resp.Config = gameConfig
+		handle.resp.Seeded = game.PickedShuffleSecret
 	}
 
 	handle.resp.CreatedAt = game.CreatedAt
@@ -0,0 +1,104 @@
+package game
+
+import (
+	"errors"
+	"net/http"
+
+	"gorm.io/gorm"
+
+	"git.cipherboy.com/WillowPatchGames/wpg/internal/database"
+	"git.cipherboy.com/WillowPatchGames/wpg/internal/utils"
+
+	api_errors "git.cipherboy.com/WillowPatchGames/wpg/pkg/errors"
+	"git.cipherboy.com/WillowPatchGames/wpg/pkg/games"
+	"git.cipherboy.com/WillowPatchGames/wpg/pkg/middleware/auth"
+	"git.cipherboy.com/WillowPatchGames/wpg/pkg/middleware/hwaterr"
+)
+
+type replayHandlerData struct {
+	GameID   uint64 `json:"id,omitempty" query:"id,omitempty" route:"GameID,omitempty"`
+	Move     int    `json:"move,omitempty" query:"move,omitempty"`
+	APIToken string `json:"api_token,omitempty" header:"X-Auth-Token,omitempty" query:"api_token,omitempty"`
+}
+
+type ReplayHandler struct {
+	auth.Authed
+	hwaterr.ErrableHandler
+	utils.HTTPRequestHandler
+
+	req  replayHandlerData
+	resp *games.Replay
+	user *database.User
+}
+
+func (handle ReplayHandler) GetResponse() interface{} {
+	return handle.resp
+}
+
+func (handle *ReplayHandler) GetObjectPointer() interface{} {
+	return &handle.req
+}
+
+func (handle *ReplayHandler) GetToken() string {
+	return handle.req.APIToken
+}
+
+func (handle *ReplayHandler) SetUser(user *database.User) {
+	handle.user = user
+}
+
+func (handle *ReplayHandler) Validate() error {
+	if handle.req.GameID == 0 {
+		return api_errors.ErrMissingRequest
+	}
+
+	if handle.req.Move < 0 {
+		return errors.New("move to seek to must be non-negative")
+	}
+
+	return nil
+}
+
+func (handle *ReplayHandler) ServeErrableHTTP(w http.ResponseWriter, r *http.Request) error {
+	err := handle.Validate()
+	if err != nil {
+		return hwaterr.WrapError(err, http.StatusBadRequest)
+	}
+
+	var game database.Game
+	var game_player database.GamePlayer
+	var players []database.GamePlayer
+	var messages []database.GameMessage
+
+	if err := database.InTransaction(func(tx *gorm.DB) error {
+		if err := tx.First(&game, handle.req.GameID).Error; err != nil {
+			return err
+		}
+
+		// Only players in the game can replay it.
+		if err := tx.First(&game_player, "user_id = ? AND game_id = ?", handle.user.ID, game.ID).Error; err != nil {
+			return err
+		}
+
+		if !game_player.Admitted || game_player.Banned {
+			err = errors.New("unable to replay a game you weren't admitted to")
+			return hwaterr.WrapError(err, http.StatusForbidden)
+		}
+
+		if err := tx.Where("game_id = ?", game.ID).Find(&players).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("game_id = ?", game.ID).Order("timestamp, id").Find(&messages).Error
+	}); err != nil {
+		return err
+	}
+
+	handle.resp, err = games.NewReplay(&game, players, messages, handle.user.ID, handle.req.Move)
+	if err != nil {
+		return err
+	}
+
+	utils.SendResponse(w, r, handle)
+	return nil
+}
@@ -3,6 +3,7 @@ package game
 import (
 	"github.com/gorilla/mux"
 
+	"git.cipherboy.com/WillowPatchGames/wpg/pkg/api/room"
 	"git.cipherboy.com/WillowPatchGames/wpg/pkg/middleware/auth"
 	"git.cipherboy.com/WillowPatchGames/wpg/pkg/middleware/hwaterr"
 	"git.cipherboy.com/WillowPatchGames/wpg/pkg/middleware/parsel"
@@ -30,6 +31,11 @@ func BuildRouter(router *mux.Router, debug bool) {
 		return auth.Require(inner)
 	}
 
+	var replayFactory = func() parsel.Parseltongue {
+		inner := new(ReplayHandler)
+		return auth.Require(inner)
+	}
+
 	var configHandler = new(ConfigHandler)
 	configHandler.Serialize()
 
@@ -38,6 +44,7 @@ func BuildRouter(router *mux.Router, debug bool) {
 	router.Handle("/api/v1/game/find", parsel.Wrap(queryFactory, config)).Methods("GET")
 	router.Handle("/api/v1/game/{GameID:[0-9]+}", parsel.Wrap(queryFactory, config)).Methods("GET")
 	router.Handle("/api/v1/game/{GameID:[0-9]+}", parsel.Wrap(deleteFactory, config)).Methods("DELETE")
+	router.Handle("/api/v1/game/{GameID:[0-9]+}/replay", parsel.Wrap(replayFactory, config)).Methods("GET")
 
 	router.Handle("/api/v1/games/config", hwaterr.Wrap(configHandler)).Methods("GET")
 	router.Handle("/api/v1/games", parsel.Wrap(createFactory, config)).Methods("POST")
@@ -45,6 +52,9 @@ func BuildRouter(router *mux.Router, debug bool) {
 	gamehub := NewHub()
 	go gamehub.Run()
 
+	// Games in a room follow changes to its word lists as they're saved.
+	room.OnConfigUpdate = gamehub.UpdateRoomConfig
+
 	var socketFactory = func() parsel.Parseltongue {
 		ret := new(SocketHandler)
 		ret.Hub = gamehub
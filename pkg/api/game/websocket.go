@@ -12,6 +12,7 @@ import (
 	"github.com/gorilla/websocket"
 
 	"git.cipherboy.com/WillowPatchGames/wpg/internal/database"
+	"git.cipherboy.com/WillowPatchGames/wpg/pkg/api/room"
 	"git.cipherboy.com/WillowPatchGames/wpg/pkg/games"
 )
 
@@ -316,6 +317,12 @@ type ClientMessage struct {
 	message []byte
 }
 
+// RoomConfigUpdate carries a room's saved configuration to the hub.
+type RoomConfigUpdate struct {
+	roomID uint64
+	config room.RoomConfig
+}
+
 // Hub maintains the mapping between WebSocket channels and the backend game
 // controller. Note that a hub has a single games.Controller instance that
 // tracks the data for all
@@ -340,6 +347,9 @@ type Hub struct {
 
 	// Process a message from the client.
 	process map[GameID]chan ClientMessage
+
+	// Room configuration changes to apply to games being played in the room.
+	roomConfigs chan RoomConfigUpdate
 }
 
 // NewHub creates a new hub.
@@ -351,6 +361,7 @@ func NewHub() *Hub {
 	ret.register = make(chan *Client, registerChannelSize)
 	ret.unregister = make(chan *Client, registerChannelSize)
 	ret.process = make(map[GameID]chan ClientMessage)
+	ret.roomConfigs = make(chan RoomConfigUpdate, registerChannelSize)
 
 	ret.controller.Init()
 
@@ -374,7 +385,28 @@ func (hub *Hub) ensureGameExists(gameid uint64) error {
 		// Save it to let others re-use the object.
 		hub.dbgames[GameID(gameid)] = &gamedb
 
-		return hub.controller.LoadGame(&gamedb)
+		if err := hub.controller.LoadGame(&gamedb); err != nil {
+			return err
+		}
+
+		// Games in a room use the room's word lists.
+		if gamedb.RoomID.Valid {
+			var roomdb database.Room
+			if err := tx.First(&roomdb, gamedb.RoomID.Int64).Error; err != nil {
+				return err
+			}
+
+			var cfg room.RoomConfig
+			if roomdb.Config.Valid {
+				if err := json.Unmarshal([]byte(roomdb.Config.String), &cfg); err != nil {
+					return err
+				}
+			}
+
+			return hub.controller.SetRoomWords(gameid, cfg.Words)
+		}
+
+		return nil
 	}); err != nil {
 		return err
 	}
@@ -556,6 +588,30 @@ func (hub *Hub) Run() {
 		case existing_client := <-hub.unregister:
 			log.Println("deleteClient:", existing_client.String())
 			hub.deleteClient(existing_client)
+		case update := <-hub.roomConfigs:
+			hub.updateRoomConfig(update)
+		}
+	}
+}
+
+// UpdateRoomConfig queues a room's saved configuration to be applied to the
+// games loaded in that room.
+func (hub *Hub) UpdateRoomConfig(roomID uint64, config room.RoomConfig) {
+	hub.roomConfigs <- RoomConfigUpdate{roomID, config}
+}
+
+func (hub *Hub) updateRoomConfig(update RoomConfigUpdate) {
+	for gameid, gamedb := range hub.dbgames {
+		if gamedb == nil || !gamedb.RoomID.Valid || uint64(gamedb.RoomID.Int64) != update.roomID {
+			continue
+		}
+
+		if !hub.controller.GameExists(uint64(gameid)) {
+			continue
+		}
+
+		if err := hub.controller.SetRoomWords(uint64(gameid), update.config.Words); err != nil {
+			log.Println("Unable to update room words for game (", gameid, "):", err)
 		}
 	}
 }
@@ -1,5 +1,18 @@
 package room
 
+import (
+	"git.cipherboy.com/WillowPatchGames/wpg/pkg/games"
+)
+
 type RoomConfig struct {
 	VideoChat string `json:"video_chat"`
+
+	// Words to allow or deny in word games played in this room, on top of
+	// the game's dictionary.
+	Words games.RoomWords `json:"words"`
 }
+
+// Called with a room's configuration after it has been saved, so games being
+// played in the room can pick up the changes. The game API, which owns the
+// running games, sets this.
+var OnConfigUpdate = func(roomID uint64, config RoomConfig) {}
@@ -179,6 +179,10 @@ func (handle *UpdateHandler) ServeErrableHTTP(w http.ResponseWriter, r *http.Req
 		handle.resp.Config = &cfg
 	}
 
+	if handle.req.Config != nil {
+		OnConfigUpdate(room.ID, cfg)
+	}
+
 	handle.resp.CreatedAt = room.CreatedAt
 	handle.resp.UpdatedAt = room.UpdatedAt
 	handle.resp.ExpiresAt = room.ExpiresAt
@@ -0,0 +1,301 @@
+package games
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"sort"
+	"time"
+
+	"git.cipherboy.com/WillowPatchGames/wpg/internal/database"
+	"git.cipherboy.com/WillowPatchGames/wpg/internal/utils"
+)
+
+const (
+	// Bots don't have database users; give them identifiers well outside of
+	// the range the database hands out so they never collide with a real
+	// player. These stay below 2^53 so the web client, which parses them as
+	// doubles, can still tell bots apart.
+	botUIDBase uint64 = 1 << 52
+
+	// Upper bound on the number of moves bots can make in response to a
+	// single inbound message. This guards against a misbehaving bot looping
+	// forever while holding the game lock.
+	maxBotMovesPerDispatch = 2048
+)
+
+// Bot difficulty levels. Easier bots occasionally make a deliberately poor
+// move; an empty difficulty plays as well as the bot knows how.
+const (
+	BotEasy   string = "easy"
+	BotMedium string = "medium"
+	BotHard   string = "hard"
+)
+
+// Fraction of decisions where a bot of the given difficulty blunders.
+var botBlunderRates = map[string]float64{
+	"":        0.0,
+	BotEasy:   0.3,
+	BotMedium: 0.1,
+	BotHard:   0.0,
+}
+
+// GameBot decides moves for a computer-controlled player. Bots are stateless:
+// everything they need is read from the game state, so they survive the game
+// being persisted and reloaded.
+type GameBot interface {
+	// Return the next message this bot wishes to send, or nil when there is
+	// nothing for it to do (e.g., it isn't its turn). The returned message is
+	// serialized and dispatched exactly as if a player had sent it.
+	NextMove(game *GameData, player *PlayerData) (interface{}, error)
+}
+
+// BotEngine is implemented by game engines that support computer opponents.
+type BotEngine interface {
+	NewBot() GameBot
+}
+
+type GameAddBot struct {
+	MessageHeader
+	Difficulty string `json:"difficulty"`
+}
+
+type GameRemoveBot struct {
+	MessageHeader
+	Target uint64 `json:"target_id"`
+}
+
+// IsBotUID reports whether the given user identifier belongs to a bot.
+func IsBotUID(uid uint64) bool {
+	return uid >= botUIDBase
+}
+
+// Whether this bot should make a poor move on this decision, based on its
+// difficulty. Bots which support difficulty levels check this before each
+// choice they make.
+func botBlunders(player *PlayerData) bool {
+	var rate = botBlunderRates[player.BotDifficulty]
+	return rate > 0 && utils.RandomFloat64() < rate
+}
+
+// Create a header for a message sent by a bot.
+func botHeader(game *GameData, player *PlayerData, messageType string) MessageHeader {
+	return MessageHeader{
+		Mode:        game.Mode.String(),
+		ID:          game.GID,
+		Player:      player.UID,
+		MessageType: messageType,
+		Timestamp:   uint64(time.Now().UnixNano() / int64(time.Millisecond)),
+	}
+}
+
+// Several games signal the end of a round or of the game by returning one of
+// these as an error. From a bot's perspective, that is a successful move.
+// Note that every card game shares the same game over and next round
+// strings.
+func isRoundSignal(err error) bool {
+	switch err.Error() {
+	case HeartsGameOver, HeartsNextRound, RushYouWon:
+		return true
+	}
+
+	return false
+}
+
+func (c *Controller) handleAddBot(message []byte, game *GameData, player *PlayerData) error {
+	// !!NO LOCK!! This should already be held elsewhere, like Dispatch.
+
+	var data GameAddBot
+	if err := json.Unmarshal(message, &data); err != nil {
+		return err
+	}
+
+	if player.UID != game.Owner {
+		return errors.New("only the owner can add computer players")
+	}
+
+	if game.State.IsStarted() || game.CountdownTimer != nil {
+		return errors.New("can't add computer players to a game that has already started")
+	}
+
+	if _, ok := botBlunderRates[data.Difficulty]; !ok {
+		return errors.New("unknown difficulty for computer player: " + data.Difficulty)
+	}
+
+	engine, ok := LookupGameEngine(game.Mode)
+	if !ok {
+		return errors.New("unknown game mode")
+	}
+
+	if _, ok := engine.(BotEngine); !ok {
+		return errors.New("computer players aren't supported in " + game.Mode.String())
+	}
+
+	var uid = botUIDBase + 1
+	for {
+		if _, present := game.ToPlayer[uid]; !present {
+			break
+		}
+
+		uid++
+	}
+
+	bot := new(PlayerData)
+	bot.UID = uid
+	bot.Index = -1
+	bot.Admitted = true
+	bot.Playing = true
+	bot.Ready = true
+	bot.Bot = true
+	bot.BotDifficulty = data.Difficulty
+	bot.OutboundID = 1
+	game.ToPlayer[uid] = bot
+
+	if err := c.notifyAdmin(game, uid); err != nil {
+		return err
+	}
+
+	c.notifyUsers(game)
+	return nil
+}
+
+func (c *Controller) handleRemoveBot(message []byte, game *GameData, player *PlayerData) error {
+	// !!NO LOCK!! This should already be held elsewhere, like Dispatch.
+
+	var data GameRemoveBot
+	if err := json.Unmarshal(message, &data); err != nil {
+		return err
+	}
+
+	if player.UID != game.Owner {
+		return errors.New("only the owner can remove computer players")
+	}
+
+	if game.State.IsStarted() || game.CountdownTimer != nil {
+		return errors.New("can't remove computer players from a game that has already started")
+	}
+
+	bot, present := game.ToPlayer[data.Target]
+	if !present || !bot.Bot {
+		return errors.New("unknown computer player")
+	}
+
+	delete(game.ToPlayer, data.Target)
+
+	c.notifyUsers(game)
+	return nil
+}
+
+// Let every admitted player know who is in the game.
+func (c *Controller) notifyUsers(game *GameData) {
+	for _, indexed_player := range game.ToPlayer {
+		if !indexed_player.Admitted {
+			continue
+		}
+
+		var users ControllerListUsersInGame
+		users.LoadFromController(game, indexed_player)
+		c.undispatch(game, indexed_player, users.MessageID, 0, users)
+	}
+}
+
+// Give every bot in the game a chance to move, repeating until none of them
+// have anything left to do. This runs synchronously after each inbound
+// message, while the game lock is held.
+func (c *Controller) runBots(game *GameData) {
+	// !!NO LOCK!! This should already be held elsewhere, like Dispatch.
+
+	if game.Paused != nil {
+		return
+	}
+
+	engine, ok := LookupGameEngine(game.Mode)
+	if !ok {
+		return
+	}
+
+	botEngine, ok := engine.(BotEngine)
+	if !ok {
+		return
+	}
+
+	var bots []*PlayerData
+	for _, indexed_player := range game.ToPlayer {
+		if indexed_player.Bot {
+			bots = append(bots, indexed_player)
+		}
+	}
+
+	if len(bots) == 0 {
+		return
+	}
+
+	// Keep a stable order so bots behave predictably.
+	sort.Slice(bots, func(i, j int) bool {
+		return bots[i].UID < bots[j].UID
+	})
+
+	var bot = botEngine.NewBot()
+	var stuck = make(map[uint64]bool)
+	for moves := 0; moves < maxBotMovesPerDispatch; {
+		var acted = false
+
+		for _, indexed_bot := range bots {
+			if stuck[indexed_bot.UID] || !indexed_bot.Playing || !game.State.IsStarted() || game.State.IsFinished() {
+				continue
+			}
+
+			move, err := bot.NextMove(game, indexed_bot)
+			if err != nil {
+				log.Println("Bot", indexed_bot.UID, "in", game.GID, "unable to decide on a move:", err)
+				stuck[indexed_bot.UID] = true
+				continue
+			}
+
+			if move == nil {
+				continue
+			}
+
+			if err := c.dispatchFor(game, indexed_bot, move); err != nil && !isRoundSignal(err) {
+				// Don't let a bot which made an illegal move try again until
+				// the state changes because of some other player.
+				log.Println("Bot", indexed_bot.UID, "in", game.GID, "made an invalid move:", err)
+				stuck[indexed_bot.UID] = true
+				continue
+			}
+
+			acted = true
+			moves++
+		}
+
+		if !acted {
+			break
+		}
+	}
+}
+
+// Dispatch a move made on a player's behalf, either by a bot or by the server
+// (like when their clock runs out), logging it as if they had sent it.
+func (c *Controller) dispatchFor(game *GameData, player *PlayerData, move interface{}) error {
+	// !!NO LOCK!! This should already be held elsewhere, like Dispatch.
+
+	message, err := json.Marshal(move)
+	if err != nil {
+		return err
+	}
+
+	header, err := parseMessageHeader(message)
+	if err != nil {
+		return err
+	}
+
+	var db_msg = database.GameMessage{
+		UserID:    player.UID,
+		GameID:    game.GID,
+		Timestamp: time.Now(),
+		Message:   string(message),
+	}
+	player.InboundMsgs = append(player.InboundMsgs, &db_msg)
+
+	return c.dispatch(message, header, game, player, 0)
+}
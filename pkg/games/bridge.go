@@ -0,0 +1,697 @@
+package games
+
+import (
+	"errors"
+	"log"
+	"strconv"
+
+	"git.cipherboy.com/WillowPatchGames/wpg/pkg/middleware/figgy"
+)
+
+const BridgeGameOver string = "game is over"
+const BridgeNextRound string = "begin next round"
+
+// Bridge is played by four players in two partnerships; partners sit across
+// from each other, so players 0 and 2 (North and South) form side 0 and
+// players 1 and 3 (East and West) form side 1.
+const bridgePlayers = 4
+
+type BridgeCallKind int
+
+const (
+	BridgePass     BridgeCallKind = iota // 0
+	BridgeBid      BridgeCallKind = iota // 1
+	BridgeDouble   BridgeCallKind = iota // 2
+	BridgeRedouble BridgeCallKind = iota // 3
+)
+
+type BridgeCall struct {
+	Player int            `json:"player"`
+	Kind   BridgeCallKind `json:"kind"`
+	Level  int            `json:"level,omitempty"`
+	Strain BridgeStrain   `json:"strain,omitempty"`
+}
+
+// Bridge scoring methods.
+const (
+	BridgeRubberScoring    int = iota // 0
+	BridgeDuplicateScoring int = iota // 1
+)
+
+type BridgePlayer struct {
+	Hand []Card `json:"hand"`
+
+	Tricks int `json:"tricks"`
+}
+
+func (bp *BridgePlayer) Init() {
+	bp.Hand = make([]Card, 0)
+}
+
+func (bp *BridgePlayer) FindCard(cardID int) (int, bool) {
+	return FindCard(bp.Hand, cardID)
+}
+
+func (bp *BridgePlayer) RemoveCard(cardID int) bool {
+	var ret bool
+	_, bp.Hand, ret = RemoveCard(bp.Hand, cardID)
+	return ret
+}
+
+type BridgeConfig struct {
+	NumPlayers int `json:"num_players" config:"type:int,min:4,default:4,max:4" label:"Number of players"` // Always four.
+
+	// Scoring
+	Scoring int `json:"scoring" config:"type:enum,default:0,options:0:Rubber scoring;1:Duplicate scoring (each board scored on its own)" label:"Scoring method"`
+	Rubbers int `json:"rubbers" config:"type:int,min:1,default:1,max:5" label:"Number of rubbers (rubber scoring)"`
+	Boards  int `json:"boards" config:"type:int,min:1,default:16,max:32" label:"Number of boards (duplicate scoring)"`
+
+	// Common game configuration options
+	Countdown bool `json:"countdown" config:"type:bool,default:true" label:"true:Show a 3... 2... 1... countdown before beginning,false:Start the game instantly"` // Whether to wait and send countdown messages.
+
+	// Chess clock style limits on how long each player may take.
+	TurnClock   int `json:"turn_clock" config:"type:int,min:0,default:0,max:600" label:"Seconds per turn (0 for no limit)"`
+	GameClock   int `json:"game_clock" config:"type:int,min:0,default:0,max:120" label:"Minutes per player for the whole game (0 for no limit)"`
+	ClockPolicy int `json:"clock_policy" config:"type:enum,default:0,options:0:Play automatically;2:Forfeit the game" label:"When a player runs out of time"` // See ClockPolicy.
+}
+
+func (cfg BridgeConfig) Validate() error {
+	return nil
+}
+
+type BridgeTrick struct {
+	Leader   int    `json:"leader"`
+	Played   []Card `json:"played"`
+	PlayedBy []int  `json:"played_by"`
+	Winner   int    `json:"winner"`
+}
+
+type BridgeRound struct {
+	Dealer     int          `json:"dealer"`
+	Deck       []*Card      `json:"deck"`
+	Seed       *ShuffleSeed `json:"seed"`
+	Hands      [][]Card     `json:"hands"`
+	Vulnerable []bool       `json:"vulnerable"`
+
+	Auction  []BridgeCall    `json:"auction"`
+	Contract *BridgeContract `json:"contract"` // Nil when passed out.
+	Tricks   []BridgeTrick   `json:"tricks"`
+
+	DeclarerTricks int         `json:"declarer_tricks"`
+	Result         BridgeScore `json:"result"`
+	RoundScores    []int       `json:"round_scores"` // By side.
+	Scores         []int       `json:"scores"`
+}
+
+type BridgeState struct {
+	Turn   int `json:"turn"`
+	Leader int `json:"leader"`
+	Dealer int `json:"dealer"`
+
+	Deck    Deck           `json:"deck"`
+	Players []BridgePlayer `json:"players"` // Left of dealer is found by incrementing one.
+
+	Auction  []BridgeCall    `json:"auction"`
+	Contract *BridgeContract `json:"contract"`
+	Dummy    int             `json:"dummy"`
+	Revealed bool            `json:"revealed"` // The dummy's hand is shown to everyone after the opening lead.
+
+	Played         []Card         `json:"played"`          // Currently played cards in this trick.
+	PlayedBy       []int          `json:"played_by"`       // Who played each card in this trick.
+	PreviousTricks [][]Card       `json:"previous_tricks"` // Contents of previous tricks in the current round; sent to clients.
+	RoundHistory   []*BridgeRound `json:"round_history"`   // Contents of previous rounds for analysis.
+	Seed           *ShuffleSeed   `json:"seed,omitempty"`  // Secret each round's shuffle seed derives from; when unset, every round gets a fresh seed.
+
+	Board      int    `json:"board"`      // Number of deals played, including those passed out.
+	Vulnerable []bool `json:"vulnerable"` // By side.
+
+	// Rubber scoring: trick points towards the current game, games won in the
+	// current rubber and completed rubbers.
+	Below   []int `json:"below"`
+	Games   []int `json:"games"`
+	Rubbers int   `json:"rubbers"`
+
+	Scores []int `json:"scores"` // By side.
+
+	Config BridgeConfig `json:"config"`
+
+	Assigned bool  `json:"assigned"` // The owner has picked where everyone sits.
+	Started  bool  `json:"started"`
+	Dealt    bool  `json:"dealt"`
+	Bid      bool  `json:"bid"` // The auction is over and play has begun.
+	Finished bool  `json:"finished"`
+	Winners  []int `json:"winners"`
+}
+
+func (bs *BridgeState) Init(cfg BridgeConfig) error {
+	var err error = figgy.Validate(cfg)
+	if err != nil {
+		log.Println("Error with BridgeConfig", err)
+		return err
+	}
+
+	bs.Config = cfg
+	bs.Turn = -1
+	bs.Dealer = -1
+	bs.Dummy = -1
+	bs.Started = false
+	bs.Finished = false
+	bs.Winners = make([]int, 0)
+
+	return nil
+}
+
+func (bs *BridgeState) GetConfiguration() figgy.Figgurable {
+	return bs.Config
+}
+
+func (bs *BridgeState) ReInit() error {
+	// No-op for now. Nothing needs to be re-initialized after reloading
+	// from JSON serialization.
+	return nil
+}
+
+func (bs *BridgeState) IsStarted() bool {
+	return bs.Started
+}
+
+func (bs *BridgeState) IsFinished() bool {
+	return bs.Finished
+}
+
+func (bs *BridgeState) SetShuffleSeed(seed *ShuffleSeed) error {
+	if bs.Started {
+		return errors.New("unable to set the shuffle seed after the game has started")
+	}
+
+	bs.Seed = seed
+	return nil
+}
+
+func (bs *BridgeState) RevealedSeeds() []*ShuffleSeed {
+	var seeds = make([]*ShuffleSeed, 0, len(bs.RoundHistory))
+	for _, round := range bs.RoundHistory {
+		seeds = append(seeds, round.Seed)
+	}
+
+	return revealedRoundSeeds(seeds, bs.Dealt && !bs.Finished)
+}
+
+func (bs *BridgeState) ResetStatus() {
+	bs.Started = false
+	bs.Finished = false
+}
+
+// Which side a player is on.
+func BridgeSide(player int) int {
+	return player % 2
+}
+
+// Record that the owner seated num_players players, so the game can start.
+func (bs *BridgeState) AssignSeats(num_players int) error {
+	if bs.Started {
+		return errors.New("cannot assign seats after already started")
+	}
+
+	var config = bs.Config
+	config.NumPlayers = num_players
+	if err := figgy.Validate(config); err != nil {
+		return err
+	}
+
+	bs.Config = config
+	bs.Assigned = true
+	return nil
+}
+
+func (bs *BridgeState) Start(players int) error {
+	var err error
+
+	if bs.Started {
+		log.Println("Error! Double start occurred...", err)
+		return errors.New("double start occurred")
+	}
+
+	bs.Config.NumPlayers = players
+	err = figgy.Validate(bs.Config)
+	if err != nil {
+		log.Println("Err with BridgeConfig after starting: ", err)
+		return err
+	}
+
+	// Create all of the players.
+	bs.Players = make([]BridgePlayer, bs.Config.NumPlayers)
+	for index := range bs.Players {
+		bs.Players[index].Init()
+	}
+
+	bs.Scores = make([]int, 2)
+	bs.Below = make([]int, 2)
+	bs.Games = make([]int, 2)
+	bs.Vulnerable = make([]bool, 2)
+
+	// Force us to call StartRound() next.
+	bs.Dealt = false
+	bs.Dealer = 0
+	bs.Board = 0
+
+	// Start the round: shuffle the cards and deal them out.
+	err = bs.StartRound()
+	if err != nil {
+		log.Println("Error starting round: ", err)
+		return err
+	}
+
+	bs.Started = true
+	return nil
+}
+
+func (bs *BridgeState) StartRound() error {
+	if bs.Dealt {
+		return errors.New("unable to call StartRound while we've already dealt")
+	}
+
+	// Under duplicate scoring, vulnerability is fixed by the board number.
+	if bs.Config.Scoring == BridgeDuplicateScoring {
+		bs.Vulnerable = BridgeBoardVulnerability(bs.Board + 1)
+	}
+
+	// Start building history of moves.
+	bs.RoundHistory = append(bs.RoundHistory, &BridgeRound{})
+	history := bs.RoundHistory[len(bs.RoundHistory)-1]
+	history.Dealer = bs.Dealer
+	history.Vulnerable = append([]bool{}, bs.Vulnerable...)
+	history.Tricks = make([]BridgeTrick, 0)
+
+	// Start with a clean deck and shuffle it.
+	bs.Deck.Init()
+	bs.Deck.AddStandard52Deck()
+	bs.Deck.Reseed(bs.Seed, len(bs.RoundHistory))
+	bs.Deck.Shuffle()
+
+	// Save the initial deck.
+	history.Deck = CopyDeck(bs.Deck.Cards)
+	history.Seed = bs.Deck.Seed.Copy()
+
+	// Clear out all round-specific status before each round.
+	for index := range bs.Players {
+		bs.Players[index].Hand = make([]Card, 0)
+		bs.Players[index].Tricks = 0
+	}
+
+	// Deal out all cards, starting left of the dealer.
+	for len(bs.Deck.Cards) > 0 {
+		for player_offset := 1; player_offset <= len(bs.Players); player_offset++ {
+			player_index := (bs.Dealer + player_offset) % len(bs.Players)
+			bs.Players[player_index].Hand = append(bs.Players[player_index].Hand, *bs.Deck.Draw())
+		}
+	}
+
+	for _, indexed_player := range bs.Players {
+		history.Hands = append(history.Hands, CopyHand(indexed_player.Hand))
+	}
+
+	bs.Auction = make([]BridgeCall, 0)
+	bs.Contract = nil
+	bs.Dummy = -1
+	bs.Revealed = false
+	bs.Played = make([]Card, 0)
+	bs.PlayedBy = make([]int, 0)
+	bs.PreviousTricks = make([][]Card, 0)
+
+	// The dealer makes the first call.
+	bs.Turn = bs.Dealer
+	bs.Leader = bs.Dealer
+	bs.Dealt = true
+	bs.Bid = false
+
+	return nil
+}
+
+// The last bid in the auction and the last call other than a pass, or -1
+// for either when there isn't one.
+func (bs *BridgeState) lastCalls() (int, int) {
+	var last_bid = -1
+	var last_call = -1
+	for index := len(bs.Auction) - 1; index >= 0; index-- {
+		if last_call == -1 && bs.Auction[index].Kind != BridgePass {
+			last_call = index
+		}
+
+		if bs.Auction[index].Kind == BridgeBid {
+			last_bid = index
+			break
+		}
+	}
+
+	return last_bid, last_call
+}
+
+// Make a call in the auction: a pass, a bid of a level and strain, a double
+// of an opponent's bid or a redouble of an opponent's double.
+func (bs *BridgeState) MakeCall(player int, kind BridgeCallKind, level int, strain BridgeStrain) error {
+	if !bs.Started {
+		return errors.New("game hasn't started yet")
+	}
+
+	if bs.Finished {
+		return errors.New("game has already finished")
+	}
+
+	if player < 0 || player >= len(bs.Players) {
+		return errors.New("not a valid player identifier: " + strconv.Itoa(player))
+	}
+
+	if !bs.Dealt {
+		return errors.New("unable to bid before dealing cards")
+	}
+
+	if bs.Bid {
+		return errors.New("the auction is already over")
+	}
+
+	if bs.Turn != player {
+		return errors.New("not your turn")
+	}
+
+	last_bid, last_call := bs.lastCalls()
+	var call = BridgeCall{Player: player, Kind: kind}
+
+	switch kind {
+	case BridgePass:
+	case BridgeBid:
+		if level < 1 || level > 7 || strain < ClubsStrain || strain > NoTrumpStrain {
+			return errors.New("not a valid bid")
+		}
+
+		if last_bid != -1 {
+			var previous = bs.Auction[last_bid]
+			if level < previous.Level || (level == previous.Level && strain <= previous.Strain) {
+				return errors.New("bid must be higher than the last bid")
+			}
+		}
+
+		call.Level = level
+		call.Strain = strain
+	case BridgeDouble:
+		if last_call == -1 || bs.Auction[last_call].Kind != BridgeBid || BridgeSide(bs.Auction[last_call].Player) == BridgeSide(player) {
+			return errors.New("can only double an opponent's bid")
+		}
+	case BridgeRedouble:
+		if last_call == -1 || bs.Auction[last_call].Kind != BridgeDouble || BridgeSide(bs.Auction[last_call].Player) == BridgeSide(player) {
+			return errors.New("can only redouble an opponent's double")
+		}
+	default:
+		return errors.New("not a valid call")
+	}
+
+	bs.Auction = append(bs.Auction, call)
+	history := bs.RoundHistory[len(bs.RoundHistory)-1]
+	history.Auction = append(history.Auction, call)
+
+	// The auction ends after three passes following a bid, or four passes if
+	// nobody bids at all.
+	var passes = 0
+	for index := len(bs.Auction) - 1; index >= 0 && bs.Auction[index].Kind == BridgePass; index-- {
+		passes += 1
+	}
+
+	last_bid, _ = bs.lastCalls()
+	if last_bid == -1 && passes == len(bs.Players) {
+		return bs.passedOut()
+	}
+
+	if last_bid == -1 || passes < len(bs.Players)-1 {
+		bs.Turn = (bs.Turn + 1) % len(bs.Players)
+		return nil
+	}
+
+	// Determine the contract: the declarer is whoever on the winning side
+	// first named the final strain.
+	var final = bs.Auction[last_bid]
+	var contract = &BridgeContract{Level: final.Level, Strain: final.Strain, Declarer: final.Player}
+	for _, indexed_call := range bs.Auction[last_bid:] {
+		if indexed_call.Kind == BridgeDouble {
+			contract.Doubled = 1
+		} else if indexed_call.Kind == BridgeRedouble {
+			contract.Doubled = 2
+		}
+	}
+
+	for _, indexed_call := range bs.Auction {
+		if indexed_call.Kind == BridgeBid && indexed_call.Strain == final.Strain && BridgeSide(indexed_call.Player) == BridgeSide(final.Player) {
+			contract.Declarer = indexed_call.Player
+			break
+		}
+	}
+
+	bs.Contract = contract
+	bs.Dummy = (contract.Declarer + 2) % len(bs.Players)
+	history.Contract = contract
+
+	// The opening lead is made by the player left of the declarer.
+	bs.Bid = true
+	bs.Leader = (contract.Declarer + 1) % len(bs.Players)
+	bs.Turn = bs.Leader
+
+	return nil
+}
+
+func (bs *BridgeState) TurnClocks() TurnClocks {
+	return newTurnClocks(bs.Config.TurnClock, bs.Config.GameClock, bs.Config.ClockPolicy)
+}
+
+// The declarer's clock runs while they play the dummy's cards too. Only
+// playing cards is clocked, not the auction.
+func (bs *BridgeState) ClockedPlayer() int {
+	if !bs.Started || bs.Finished || !bs.Dealt || !bs.Bid {
+		return -1
+	}
+
+	if bs.Turn == bs.Dummy && bs.Contract != nil {
+		return bs.Contract.Declarer
+	}
+
+	return bs.Turn
+}
+
+func (bs *BridgeState) Timeout(player int, policy ClockPolicy) error {
+	if player != bs.ClockedPlayer() {
+		return errors.New("not your turn")
+	}
+
+	if policy == ForfeitOnTimeout {
+		// The other side wins.
+		bs.Winners = make([]int, 0)
+		for index := range bs.Players {
+			if BridgeSide(index) != BridgeSide(player) {
+				bs.Winners = append(bs.Winners, index)
+			}
+		}
+
+		bs.Finished = true
+		bs.Turn = -1
+		bs.Dealer = -1
+		return errors.New(BridgeGameOver)
+	}
+
+	return playLowestCard(bs.Players[bs.Turn].Hand, true, func(card int) error {
+		return bs.PlayCard(bs.Turn, card)
+	})
+}
+
+// Play a card from the given seat's hand. Who may play for a seat is up to
+// the controller: the declarer plays the dummy's cards through the dummy's
+// binding to them.
+func (bs *BridgeState) PlayCard(seat int, cardID int) error {
+	if !bs.Started {
+		return errors.New("game hasn't started yet")
+	}
+
+	if bs.Finished {
+		return errors.New("game has already finished")
+	}
+
+	if seat < 0 || seat >= len(bs.Players) {
+		return errors.New("not a valid player identifier: " + strconv.Itoa(seat))
+	}
+
+	if !bs.Dealt || !bs.Bid {
+		return errors.New("unable to play a card before the auction is over")
+	}
+
+	if seat != bs.Turn {
+		return errors.New("not your turn")
+	}
+
+	index, found := bs.Players[seat].FindCard(cardID)
+	if !found {
+		return errors.New("unable to play card not in hand")
+	}
+
+	played := bs.Players[seat].Hand[index]
+	history := bs.RoundHistory[len(bs.RoundHistory)-1]
+
+	if len(bs.Played) == 0 || len(bs.Played) == len(bs.Players) {
+		// Leading a new trick.
+		bs.Played = make([]Card, 0)
+		bs.PlayedBy = make([]int, 0)
+		history.Tricks = append(history.Tricks, BridgeTrick{Leader: seat, Winner: -1})
+	} else if played.Suit != bs.Played[0].Suit {
+		// Must follow the lead suit if we can.
+		for _, card := range bs.Players[seat].Hand {
+			if card.Suit == bs.Played[0].Suit {
+				return errors.New("must follow the lead suit")
+			}
+		}
+	}
+
+	this_trick := &history.Tricks[len(history.Tricks)-1]
+	bs.Players[seat].RemoveCard(cardID)
+	bs.Played = append(bs.Played, played)
+	bs.PlayedBy = append(bs.PlayedBy, seat)
+	this_trick.Played = append(this_trick.Played, played)
+	this_trick.PlayedBy = append(this_trick.PlayedBy, seat)
+
+	// After the opening lead, the dummy lays their hand face up.
+	bs.Revealed = true
+
+	if len(bs.Played) == len(bs.Players) {
+		return bs.determineTrickWinner()
+	}
+
+	bs.Turn = (seat + 1) % len(bs.Players)
+	return nil
+}
+
+func (bs *BridgeState) determineTrickWinner() error {
+	history := bs.RoundHistory[len(bs.RoundHistory)-1]
+	this_trick := &history.Tricks[len(history.Tricks)-1]
+
+	var trump = bs.Contract.Strain.Suit()
+	winner := 0
+	for index, card := range bs.Played {
+		if beatsInTrick(card, bs.Played[winner], trump) {
+			winner = index
+		}
+	}
+
+	absolute_winner := bs.PlayedBy[winner]
+	bs.Leader = absolute_winner
+	bs.Turn = absolute_winner
+	bs.PreviousTricks = append(bs.PreviousTricks, bs.Played)
+	bs.Players[absolute_winner].Tricks += 1
+	this_trick.Winner = absolute_winner
+
+	if len(bs.Players[absolute_winner].Hand) == 0 {
+		// Can't play again in this round. Tabulate the round score and maybe try
+		// to play another round.
+		return bs.tabulateRoundScore()
+	}
+
+	return nil
+}
+
+// Everyone passed without bidding. Under rubber scoring, the next dealer
+// simply deals again; under duplicate scoring, the board scores nothing.
+func (bs *BridgeState) passedOut() error {
+	history := bs.RoundHistory[len(bs.RoundHistory)-1]
+	history.RoundScores = make([]int, 2)
+	history.Scores = append([]int{}, bs.Scores...)
+
+	if bs.Config.Scoring == BridgeDuplicateScoring {
+		bs.Board += 1
+		if bs.Board >= bs.Config.Boards {
+			return bs.assignWinners()
+		}
+	}
+
+	bs.Dealt = false
+	bs.Turn = -1
+	bs.Dealer = (bs.Dealer + 1) % len(bs.Players)
+	return errors.New(BridgeNextRound)
+}
+
+func (bs *BridgeState) tabulateRoundScore() error {
+	history := bs.RoundHistory[len(bs.RoundHistory)-1]
+
+	var declarer = BridgeSide(bs.Contract.Declarer)
+	var defender = 1 - declarer
+	var tricks = bs.Players[declarer].Tricks + bs.Players[declarer+2].Tricks
+	var result = bs.Contract.Score(tricks, bs.Vulnerable[declarer])
+
+	history.DeclarerTricks = tricks
+	history.Result = result
+	history.RoundScores = make([]int, 2)
+	history.RoundScores[declarer] = result.Below + result.Above
+	history.RoundScores[defender] = result.Defender
+
+	bs.Board += 1
+	var finished = false
+
+	if bs.Config.Scoring == BridgeDuplicateScoring {
+		// Each board is scored on its own, with a bonus for bidding and making
+		// game or a part score.
+		if result.Made && result.Below >= 100 && bs.Vulnerable[declarer] {
+			history.RoundScores[declarer] += 500
+		} else if result.Made && result.Below >= 100 {
+			history.RoundScores[declarer] += 300
+		} else if result.Made {
+			history.RoundScores[declarer] += 50
+		}
+
+		finished = bs.Board >= bs.Config.Boards
+	} else {
+		// Trick points accumulate towards a game of 100; winning two games wins
+		// the rubber.
+		bs.Below[declarer] += result.Below
+		if bs.Below[declarer] >= 100 {
+			bs.Games[declarer] += 1
+			bs.Vulnerable[declarer] = true
+			bs.Below = make([]int, 2)
+		}
+
+		if bs.Games[declarer] == 2 {
+			if bs.Games[defender] == 0 {
+				history.RoundScores[declarer] += 700
+			} else {
+				history.RoundScores[declarer] += 500
+			}
+
+			bs.Rubbers += 1
+			bs.Games = make([]int, 2)
+			bs.Vulnerable = make([]bool, 2)
+			finished = bs.Rubbers >= bs.Config.Rubbers
+		}
+	}
+
+	for side := range bs.Scores {
+		bs.Scores[side] += history.RoundScores[side]
+	}
+	history.Scores = append([]int{}, bs.Scores...)
+
+	if finished {
+		return bs.assignWinners()
+	}
+
+	bs.Dealt = false
+	bs.Turn = -1
+	bs.Dealer = (bs.Dealer + 1) % len(bs.Players)
+	return errors.New(BridgeNextRound)
+}
+
+// The side with the most points wins; a tie is shared.
+func (bs *BridgeState) assignWinners() error {
+	bs.Winners = make([]int, 0)
+	for index := range bs.Players {
+		var side = BridgeSide(index)
+		if bs.Scores[side] >= bs.Scores[1-side] {
+			bs.Winners = append(bs.Winners, index)
+		}
+	}
+
+	bs.Finished = true
+	bs.Turn = -1
+	bs.Dealer = -1
+	return errors.New(BridgeGameOver)
+}
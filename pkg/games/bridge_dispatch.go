@@ -0,0 +1,377 @@
+package games
+
+import (
+	"encoding/json"
+	"errors"
+
+	"git.cipherboy.com/WillowPatchGames/wpg/pkg/middleware/figgy"
+)
+
+// Bridge message types:
+//
+// 0. Assign Players
+// 1. Deal
+// 2. Call
+// 3. PlayCard
+// 4. Timeout (sent by the server when a player runs out of time)
+
+type BridgeCallMsg struct {
+	MessageHeader
+	Kind   BridgeCallKind `json:"kind"`
+	Level  int            `json:"level"`
+	Strain BridgeStrain   `json:"strain"`
+}
+
+type BridgePlayMsg struct {
+	MessageHeader
+	CardID int `json:"card_id"`
+}
+
+func (c *Controller) dispatchBridge(message []byte, header MessageHeader, game *GameData, player *PlayerData, sid uint64) error {
+	var err error
+	var state *BridgeState = game.State.(*BridgeState)
+	if state == nil {
+		panic("internal state is nil; this shouldn't happen when the game is started")
+	}
+
+	var was_finished = state.Finished
+	var send_synopsis = false
+	var send_state = false
+
+	switch header.MessageType {
+	case "assign":
+		err = c.assignPartnershipSeats(message, header, game, player, state.AssignSeats)
+	case "start":
+		if player.UID != game.Owner {
+			return errors.New("unable to start game that you're not the owner of")
+		}
+
+		var players int = 0
+		for _, player := range game.ToPlayer {
+			if player.Playing {
+				// When we click the start button again, say, after a user has come
+				// back to being active, Countback will be higher than 0, because we've
+				// already attempted to set this.
+				player.Countback = 0
+				players += 1
+			}
+		}
+
+		if players != state.Config.NumPlayers || !state.Assigned {
+			return errors.New("must finish configuring assignments for this game")
+		}
+
+		if err = figgy.Validate(state.Config); err != nil {
+			return err
+		}
+
+		if state.Config.Countdown {
+			game.Countdown = 0
+			game.CountdownTimer = nil
+
+			return c.handleCountdown(game)
+		} else {
+			return c.doBridgeStart(game, state)
+		}
+	case "cancel":
+		if player.UID != game.Owner {
+			return errors.New("unable to cancel game that you're not the owner of")
+		}
+
+		if !state.Config.Countdown {
+			return errors.New("unable to cancel game that doesn't use a countdown")
+		}
+
+		if state.Started || state.Finished {
+			return errors.New("unable to cancel game that is already started")
+		}
+
+		game.Countdown = 0
+		game.CountdownTimer = nil
+	case "join":
+		if state.Started && !state.Finished {
+			var started ControllerNotifyStarted
+			started.LoadFromController(game, player)
+			started.ReplyTo = header.MessageID
+			c.undispatch(game, player, started.MessageID, started.ReplyTo, started)
+
+			if player.Playing && player.Index >= 0 {
+				var response BridgeStateNotification
+				response.LoadData(game, state, player)
+				c.undispatch(game, player, response.MessageID, 0, response)
+
+				send_synopsis = true
+			}
+		} else if state.Finished {
+			var finished BridgeFinishedNotification
+			finished.LoadData(game, state, player)
+			finished.ReplyTo = header.MessageID
+			c.undispatch(game, player, finished.MessageID, finished.ReplyTo, finished)
+			send_synopsis = true
+		}
+	case "deal":
+		if player.Index != state.Dealer {
+			return errors.New("unable to deal round that you're not the dealer for")
+		}
+
+		err = state.StartRound()
+		send_synopsis = err == nil
+		send_state = err == nil
+	case "call":
+		var data BridgeCallMsg
+		if err = json.Unmarshal(message, &data); err != nil {
+			return err
+		}
+
+		err = state.MakeCall(player.Index, data.Kind, data.Level, data.Strain)
+		send_synopsis = err == nil
+		send_state = err == nil
+	case "play":
+		var data BridgePlayMsg
+		if err = json.Unmarshal(message, &data); err != nil {
+			return err
+		}
+
+		var seat int
+		seat, err = bridgePlayingSeat(game, state, player)
+		if err != nil {
+			return err
+		}
+
+		err = state.PlayCard(seat, data.CardID)
+		send_synopsis = true
+		send_state = true
+	case "timeout":
+		var data GameTimeout
+		if err = json.Unmarshal(message, &data); err != nil {
+			return err
+		}
+
+		err = state.Timeout(player.Index, data.Policy)
+		send_synopsis = true
+		send_state = true
+	case "peek":
+		if player.Index != -1 && !state.Finished {
+			return errors.New("can only peek once game is complete")
+		}
+
+		var response BridgePeekNotification
+		response.LoadData(game, state, player)
+		response.ReplyTo = header.MessageID
+		c.undispatch(game, player, response.MessageID, header.MessageID, response)
+
+		var synopsis BridgeSynopsisNotification
+		synopsis.LoadData(game, state, player)
+		c.undispatch(game, player, synopsis.MessageID, 0, synopsis)
+	default:
+		return errors.New("unknown message_type issued to bridge game: " + header.MessageType)
+	}
+
+	if send_state {
+		bindBridgeDummy(game, state)
+	}
+
+	// If this game ended during this dispatch call, notify everyone.
+	if !was_finished && state.Finished {
+		// Notify everyone that the game ended and which side won.
+		for _, indexed_player := range game.ToPlayer {
+			var finished BridgeFinishedNotification
+			finished.LoadData(game, state, indexed_player)
+			c.undispatch(game, indexed_player, finished.MessageID, 0, finished)
+		}
+	}
+
+	// If someone changed something, notify everyone.
+	if send_synopsis {
+		for _, indexed_player := range game.ToPlayer {
+			var synopsis BridgeSynopsisNotification
+			synopsis.LoadData(game, state, indexed_player)
+			c.undispatch(game, indexed_player, synopsis.MessageID, 0, synopsis)
+		}
+	}
+
+	// If the state changed for a bunch of people, notify them all.
+	if send_state {
+		for _, indexed_player := range game.ToPlayer {
+			if !indexed_player.Admitted {
+				continue
+			}
+
+			if indexed_player.Playing {
+				var response BridgeStateNotification
+				response.LoadData(game, state, indexed_player)
+				if indexed_player.UID == player.UID && err == nil {
+					response.ReplyTo = header.MessageID
+				}
+
+				c.undispatch(game, indexed_player, response.MessageID, response.ReplyTo, response)
+			} else {
+				var response BridgePeekNotification
+				response.LoadData(game, state, indexed_player)
+				c.undispatch(game, indexed_player, response.MessageID, 0, response)
+			}
+		}
+	}
+
+	return err
+}
+
+// Bind the dummy to the declarer for the play of the hand, so the
+// declarer's connection plays the dummy's cards. The dummy has no say in
+// the matter: only their side of the binding is made, which also keeps them
+// from playing the declarer's cards. Bindings from earlier hands are dropped.
+func bindBridgeDummy(game *GameData, state *BridgeState) {
+	for _, indexed_player := range game.ToPlayer {
+		if !indexed_player.Playing {
+			continue
+		}
+
+		for _, uid := range append([]uint64(nil), indexed_player.BoundPlayers...) {
+			if other, present := game.ToPlayer[uid]; present && other.Playing {
+				indexed_player.Unbind(uid)
+			}
+		}
+	}
+
+	if state.Contract == nil || state.Finished {
+		return
+	}
+
+	declarer_uid, found_declarer := game.ToUserID(state.Contract.Declarer)
+	dummy_uid, found_dummy := game.ToUserID(state.Dummy)
+	if found_declarer && found_dummy {
+		var dummy = game.ToPlayer[dummy_uid]
+		dummy.BoundPlayers = append(dummy.BoundPlayers, declarer_uid)
+	}
+}
+
+// The seat the player plays a card for. Spectators play for the player
+// they're bound to. A player whose seat is bound to another player, like
+// the dummy, has their cards played by that player instead of themselves.
+func bridgePlayingSeat(game *GameData, state *BridgeState, player *PlayerData) (int, error) {
+	var actor = player
+	if !player.Playing {
+		for _, uid := range player.BoundPlayers {
+			if bound, present := game.ToPlayer[uid]; present && bound.Playing && game.PlayersAreBound(uid, player.UID) {
+				actor = bound
+				break
+			}
+		}
+	}
+
+	if !actor.Playing {
+		return -1, errors.New("unable to play a card as a spectator")
+	}
+
+	turn_uid, found := game.ToUserID(state.Turn)
+	if !found {
+		return actor.Index, nil
+	}
+
+	for _, uid := range game.ToPlayer[turn_uid].BoundPlayers {
+		if delegate, present := game.ToPlayer[uid]; present && delegate.Playing {
+			if delegate.UID != actor.UID {
+				return -1, errors.New("not your turn")
+			}
+
+			return state.Turn, nil
+		}
+	}
+
+	return actor.Index, nil
+}
+
+func (c *Controller) doBridgeStart(game *GameData, state *BridgeState) error {
+	// First count the number of people playing.
+	var players int = 0
+	for _, player := range game.ToPlayer {
+		if player.Playing {
+			players += 1
+		}
+	}
+
+	if players != state.Config.NumPlayers || !state.Assigned {
+		return errors.New("must finish configuring assignments for this game")
+	}
+
+	// Then start the underlying Bridge game to populate game data. The assign
+	// message already gave everyone their seat; partners sit across from
+	// each other.
+	if err := state.Start(players); err != nil {
+		return err
+	}
+
+	// Send out initial state data to individuals who are playing. Also notify
+	// all players that the game has started.
+	for _, indexed_player := range game.ToPlayer {
+		if !indexed_player.Admitted {
+			continue
+		}
+
+		// Tell everyone interested that the game has started.
+		var started ControllerNotifyStarted
+		started.LoadFromController(game, indexed_player)
+		c.undispatch(game, indexed_player, started.MessageID, started.ReplyTo, started)
+
+		// Only send state to players who are playing initially. Everyone else
+		// (namely, admitted spectators) should send a peek event before they can
+		// view the table.
+		if indexed_player.Playing {
+			var response BridgeStateNotification
+			response.LoadData(game, state, indexed_player)
+			c.undispatch(game, indexed_player, response.MessageID, 0, response)
+		}
+
+		// Give everyone the initial synopsis.
+		var synopsis BridgeSynopsisNotification
+		synopsis.LoadData(game, state, indexed_player)
+		c.undispatch(game, indexed_player, synopsis.MessageID, 0, synopsis)
+	}
+
+	return nil
+}
+
+// bridgeEngine registers Bridge with the controller; see GameEngine.
+type bridgeEngine struct{}
+
+func init() {
+	MustRegisterGameEngine(bridgeEngine{})
+}
+
+func (bridgeEngine) Mode() GameMode {
+	return BridgeGame
+}
+
+func (bridgeEngine) Name() string {
+	return "bridge"
+}
+
+func (bridgeEngine) Title() string {
+	return "Contract Bridge (Card Game)"
+}
+
+func (bridgeEngine) Description() string {
+	return "In Contract Bridge, two partnerships bid for the right to name trump, then the declarer plays both their own hand and their partner's dummy to make the contract. Choose between rubber and duplicate scoring."
+}
+
+func (bridgeEngine) EmptyConfig() figgy.Figgurable {
+	return &BridgeConfig{}
+}
+
+func (bridgeEngine) NewState() ConfigurableState {
+	return &BridgeState{}
+}
+
+func (bridgeEngine) Init(config figgy.Figgurable) (ConfigurableState, error) {
+	var asserted *BridgeConfig = config.(*BridgeConfig)
+	var state = &BridgeState{}
+	return state, state.Init(*asserted)
+}
+
+func (bridgeEngine) Dispatch(c *Controller, message []byte, header MessageHeader, game *GameData, player *PlayerData, sid uint64) error {
+	return c.dispatchBridge(message, header, game, player, sid)
+}
+
+func (bridgeEngine) Start(c *Controller, game *GameData) error {
+	return c.doBridgeStart(game, game.State.(*BridgeState))
+}
@@ -0,0 +1,227 @@
+package games
+
+import (
+	"strings"
+)
+
+type BridgePlayerState struct {
+	Hand []Card `json:"hand"`
+
+	Tricks int `json:"tricks"`
+}
+
+type BridgeGameState struct {
+	Turn     uint64 `json:"turn"`
+	Leader   uint64 `json:"leader"`
+	Dealer   uint64 `json:"dealer"`
+	Declarer uint64 `json:"declarer"`
+	Dummy    uint64 `json:"dummy"`
+
+	Auction   []BridgeCall    `json:"auction"`
+	Contract  *BridgeContract `json:"contract"`
+	DummyHand []Card          `json:"dummy_hand,omitempty"` // Visible to everyone after the opening lead.
+
+	Played    []Card   `json:"played"`
+	WhoPlayed []uint64 `json:"who_played"`
+	History   [][]Card `json:"history"`
+
+	Board      int    `json:"board"`
+	Vulnerable []bool `json:"vulnerable"`
+	Below      []int  `json:"below"`
+	Games      []int  `json:"games"`
+	Rubbers    int    `json:"rubbers"`
+	Scores     []int  `json:"scores"`
+
+	Commitment string `json:"commitment"` // To the current round's shuffle seed; see ShuffleSeed.
+
+	Config BridgeConfig `json:"config"`
+
+	Started  bool `json:"started"`
+	Dealt    bool `json:"dealt"`
+	Bid      bool `json:"bid"`
+	Finished bool `json:"finished"`
+}
+
+func (bgs *BridgeGameState) loadGame(data *GameData, game *BridgeState) {
+	bgs.Turn, _ = data.ToUserID(game.Turn)
+	bgs.Leader, _ = data.ToUserID(game.Leader)
+	bgs.Dealer, _ = data.ToUserID(game.Dealer)
+	if game.Contract != nil {
+		bgs.Declarer, _ = data.ToUserID(game.Contract.Declarer)
+		bgs.Dummy, _ = data.ToUserID(game.Dummy)
+	}
+
+	bgs.Auction = game.Auction
+	if bgs.Auction == nil {
+		bgs.Auction = make([]BridgeCall, 0)
+	}
+	bgs.Contract = game.Contract
+	if game.Revealed && game.Dummy >= 0 {
+		bgs.DummyHand = game.Players[game.Dummy].Hand
+	}
+
+	bgs.Played = game.Played
+	if bgs.Played == nil {
+		bgs.Played = make([]Card, 0)
+	}
+
+	bgs.WhoPlayed, _ = data.ToUserIDs(game.PlayedBy)
+	if bgs.WhoPlayed == nil {
+		bgs.WhoPlayed = make([]uint64, 0)
+	}
+
+	if len(game.PreviousTricks) >= 1 {
+		bgs.History = make([][]Card, 1)
+		bgs.History[0] = game.PreviousTricks[len(game.PreviousTricks)-1]
+	}
+
+	bgs.Board = game.Board
+	bgs.Vulnerable = game.Vulnerable
+	bgs.Below = game.Below
+	bgs.Games = game.Games
+	bgs.Rubbers = game.Rubbers
+	bgs.Scores = game.Scores
+	bgs.Commitment = game.Deck.Commitment()
+	bgs.Config = game.Config
+
+	bgs.Started = game.Started
+	bgs.Dealt = game.Dealt
+	bgs.Bid = game.Bid
+	bgs.Finished = game.Finished
+}
+
+type BridgeStateNotification struct {
+	MessageHeader
+	BridgePlayerState
+	BridgeGameState
+}
+
+func (bsn *BridgeStateNotification) LoadData(data *GameData, game *BridgeState, player *PlayerData) {
+	bsn.LoadHeader(data, player)
+	bsn.MessageType = "state"
+
+	bsn.Hand = game.Players[player.Index].Hand
+	bsn.Tricks = game.Players[player.Index].Tricks
+
+	bsn.loadGame(data, game)
+}
+
+type BridgePlayerSynopsis struct {
+	UID         uint64 `json:"user"`
+	Playing     bool   `json:"playing"`
+	PlayerIndex int    `json:"player_index"`
+	Side        int    `json:"side"`
+
+	IsTurn     bool `json:"is_turn"`
+	IsLeader   bool `json:"is_leader"`
+	IsDealer   bool `json:"is_dealer"`
+	IsDeclarer bool `json:"is_declarer"`
+	IsDummy    bool `json:"is_dummy"`
+	Vulnerable bool `json:"vulnerable"`
+
+	Tricks int `json:"tricks"`
+	Score  int `json:"score"`
+}
+
+type BridgeSynopsisNotification struct {
+	MessageHeader
+
+	Players []BridgePlayerSynopsis `json:"players"`
+
+	Contract      *BridgeContract `json:"contract"`
+	SuitIndicator string          `json:"suit"`
+}
+
+func (bsn *BridgeSynopsisNotification) LoadData(data *GameData, state *BridgeState, player *PlayerData) {
+	bsn.LoadHeader(data, player)
+	bsn.MessageType = "synopsis"
+
+	for _, indexed_player := range data.ToPlayer {
+		var synopsis BridgePlayerSynopsis
+		synopsis.UID = indexed_player.UID
+		synopsis.Playing = indexed_player.Playing
+		synopsis.PlayerIndex = indexed_player.Index
+		synopsis.Side = -1
+
+		if indexed_player.Index >= 0 && indexed_player.Index < len(state.Players) {
+			synopsis.Side = BridgeSide(indexed_player.Index)
+
+			synopsis.IsTurn = indexed_player.Index == state.Turn
+			synopsis.IsLeader = indexed_player.Index == state.Leader
+			synopsis.IsDealer = indexed_player.Index == state.Dealer
+			synopsis.IsDeclarer = state.Contract != nil && indexed_player.Index == state.Contract.Declarer
+			synopsis.IsDummy = indexed_player.Index == state.Dummy
+			synopsis.Vulnerable = state.Vulnerable[synopsis.Side]
+
+			synopsis.Tricks = state.Players[indexed_player.Index].Tricks
+			synopsis.Score = state.Scores[synopsis.Side]
+		}
+
+		bsn.Players = append(bsn.Players, synopsis)
+	}
+
+	bsn.Contract = state.Contract
+
+	if !state.Dealt {
+		bsn.SuitIndicator = "dealing"
+	} else if !state.Bid {
+		bsn.SuitIndicator = "bidding"
+	} else if len(state.Played) == 0 || len(state.Played) == len(state.Players) {
+		bsn.SuitIndicator = "waiting"
+	} else {
+		bsn.SuitIndicator = state.Played[0].Suit.String()
+		bsn.SuitIndicator = strings.TrimSuffix(bsn.SuitIndicator, "Suit")
+	}
+}
+
+type BridgePeekNotification struct {
+	MessageHeader
+
+	PlayerMapping []uint64 `json:"player_mapping"`
+
+	// Info for Ended Games (Everyone)
+	RoundHistory []*BridgeRound `json:"round_history"`
+
+	// Info for Active Games (Spectators)
+	BridgeGameState
+
+	Winners []uint64 `json:"winners"`
+}
+
+func (bpn *BridgePeekNotification) LoadData(data *GameData, game *BridgeState, player *PlayerData) {
+	bpn.LoadHeader(data, player)
+	bpn.MessageType = "game-state"
+
+	for index := range game.Players {
+		player_uid, _ := data.ToUserID(index)
+		bpn.PlayerMapping = append(bpn.PlayerMapping, player_uid)
+	}
+
+	bpn.loadGame(data, game)
+
+	if !game.Finished {
+		// Allow spectators to see previous rounds before the game has ended.
+		if len(game.RoundHistory) > 0 {
+			bpn.RoundHistory = game.RoundHistory[:len(game.RoundHistory)-1]
+		}
+	} else {
+		bpn.RoundHistory = game.RoundHistory
+	}
+
+	bpn.Winners, _ = data.ToUserIDs(game.Winners)
+}
+
+type BridgeFinishedNotification struct {
+	MessageHeader
+
+	Winners []uint64 `json:"winners"`
+	Scores  []int    `json:"scores"`
+}
+
+func (bfn *BridgeFinishedNotification) LoadData(data *GameData, state *BridgeState, player *PlayerData) {
+	bfn.LoadHeader(data, player)
+	bfn.MessageType = "finished"
+
+	bfn.Winners, _ = data.ToUserIDs(state.Winners)
+	bfn.Scores = state.Scores
+}
@@ -0,0 +1,144 @@
+package games
+
+// BridgeStrain is the denomination of a bid: one of the four suits or no
+// trump, in ascending order of rank.
+type BridgeStrain int
+
+const (
+	NoStrain       BridgeStrain = iota // 0
+	ClubsStrain    BridgeStrain = iota // 1
+	DiamondsStrain BridgeStrain = iota // 2
+	HeartsStrain   BridgeStrain = iota // 3
+	SpadesStrain   BridgeStrain = iota // 4
+	NoTrumpStrain  BridgeStrain = iota // 5
+)
+
+// The trump suit for this strain, or NoneSuit in no trump.
+func (bs BridgeStrain) Suit() CardSuit {
+	switch bs {
+	case ClubsStrain:
+		return ClubsSuit
+	case DiamondsStrain:
+		return DiamondsSuit
+	case HeartsStrain:
+		return HeartsSuit
+	case SpadesStrain:
+		return SpadesSuit
+	}
+
+	return NoneSuit
+}
+
+func (bs BridgeStrain) IsMinor() bool {
+	return bs == ClubsStrain || bs == DiamondsStrain
+}
+
+type BridgeContract struct {
+	Level    int          `json:"level"`
+	Strain   BridgeStrain `json:"strain"`
+	Doubled  int          `json:"doubled"` // 0 for undoubled, 1 doubled, 2 redoubled.
+	Declarer int          `json:"declarer"`
+}
+
+// The points for a contract played out, split into trick points (which count
+// towards game, "below the line") and bonus points (overtricks, the insult
+// and slams for the declarer; penalties for the defenders, "above the line").
+// Game and part score bonuses depend on the scoring method and aren't
+// included.
+type BridgeScore struct {
+	Made     bool `json:"made"`
+	Below    int  `json:"below"`
+	Above    int  `json:"above"`
+	Defender int  `json:"defender"`
+}
+
+func bridgeMultiplier(doubled int) int {
+	return 1 << uint(doubled)
+}
+
+func (bc BridgeContract) Score(tricks int, vulnerable bool) BridgeScore {
+	var result BridgeScore
+	var needed = 6 + bc.Level
+	var multiplier = bridgeMultiplier(bc.Doubled)
+
+	if tricks < needed {
+		// Penalties for undertricks. Undoubled, each is worth the same. Doubled,
+		// they escalate and are worth more when vulnerable.
+		var under = needed - tricks
+		for trick := 1; trick <= under; trick++ {
+			if bc.Doubled == 0 && vulnerable {
+				result.Defender += 100
+			} else if bc.Doubled == 0 {
+				result.Defender += 50
+			} else if vulnerable && trick == 1 {
+				result.Defender += 100 * multiplier
+			} else if vulnerable {
+				result.Defender += 150 * multiplier
+			} else if trick == 1 {
+				result.Defender += 50 * multiplier
+			} else if trick <= 3 {
+				result.Defender += 100 * multiplier
+			} else {
+				result.Defender += 150 * multiplier
+			}
+		}
+
+		return result
+	}
+
+	result.Made = true
+
+	// Trick points for the contract itself.
+	var per_trick = 30
+	if bc.Strain.IsMinor() {
+		per_trick = 20
+	}
+
+	result.Below = per_trick * bc.Level * multiplier
+	if bc.Strain == NoTrumpStrain {
+		result.Below += 10 * multiplier
+	}
+
+	// Overtricks, worth trick value when undoubled or a fixed amount when
+	// doubled.
+	var over = tricks - needed
+	if bc.Doubled == 0 {
+		result.Above += per_trick * over
+	} else if vulnerable {
+		result.Above += 100 * multiplier * over
+	} else {
+		result.Above += 50 * multiplier * over
+	}
+
+	// The insult, for making a doubled contract.
+	if bc.Doubled > 0 {
+		result.Above += 50 * bc.Doubled
+	}
+
+	// Slam bonuses.
+	if bc.Level == 6 && vulnerable {
+		result.Above += 750
+	} else if bc.Level == 6 {
+		result.Above += 500
+	} else if bc.Level == 7 && vulnerable {
+		result.Above += 1500
+	} else if bc.Level == 7 {
+		result.Above += 1000
+	}
+
+	return result
+}
+
+// Vulnerability of each side for a duplicate board, following the standard
+// sixteen board cycle. Boards are numbered from one.
+var bridgeDuplicateVulnerability = [16][2]bool{
+	{false, false}, {true, false}, {false, true}, {true, true},
+	{true, false}, {false, true}, {true, true}, {false, false},
+	{false, true}, {true, true}, {false, false}, {true, false},
+	{true, true}, {false, false}, {true, false}, {false, true},
+}
+
+func BridgeBoardVulnerability(board int) []bool {
+	var entry = bridgeDuplicateVulnerability[(board-1)%16]
+	return []bool{entry[0], entry[1]}
+}
@@ -0,0 +1,230 @@
+package games
+
+import (
+	"testing"
+)
+
+func TestBridgeContractScore(t *testing.T) {
+	for _, test := range []struct {
+		contract   BridgeContract
+		tricks     int
+		vulnerable bool
+		result     BridgeScore
+	}{
+		{BridgeContract{Level: 4, Strain: SpadesStrain}, 10, false, BridgeScore{true, 120, 0, 0}},
+		{BridgeContract{Level: 3, Strain: NoTrumpStrain}, 10, true, BridgeScore{true, 100, 30, 0}},
+		{BridgeContract{Level: 1, Strain: NoTrumpStrain, Doubled: 1}, 7, false, BridgeScore{true, 80, 50, 0}},
+		{BridgeContract{Level: 2, Strain: ClubsStrain, Doubled: 2}, 9, true, BridgeScore{true, 160, 500, 0}},
+		{BridgeContract{Level: 6, Strain: SpadesStrain}, 12, true, BridgeScore{true, 180, 750, 0}},
+		{BridgeContract{Level: 4, Strain: HeartsStrain}, 8, false, BridgeScore{false, 0, 0, 100}},
+		{BridgeContract{Level: 4, Strain: HeartsStrain, Doubled: 1}, 7, false, BridgeScore{false, 0, 0, 500}},
+		{BridgeContract{Level: 4, Strain: HeartsStrain, Doubled: 1}, 7, true, BridgeScore{false, 0, 0, 800}},
+		{BridgeContract{Level: 4, Strain: HeartsStrain, Doubled: 2}, 6, false, BridgeScore{false, 0, 0, 1600}},
+	} {
+		if result := test.contract.Score(test.tricks, test.vulnerable); result != test.result {
+			t.Fatal("Expected", test.contract, "taking", test.tricks, "to score", test.result, "but got", result)
+		}
+	}
+}
+
+func TestBridgeAuction(t *testing.T) {
+	var state BridgeState
+	if err := state.Init(BridgeConfig{NumPlayers: 4, Rubbers: 1, Boards: 16}); err != nil {
+		t.Fatal("Unable to initialize game:", err)
+	}
+
+	if err := state.Start(4); err != nil {
+		t.Fatal("Unable to start game:", err)
+	}
+
+	if err := state.MakeCall(0, BridgeDouble, 0, NoStrain); err == nil {
+		t.Fatal("Expected double without a bid to fail")
+	}
+
+	if err := state.MakeCall(0, BridgeBid, 1, HeartsStrain); err != nil {
+		t.Fatal("Unable to bid:", err)
+	}
+
+	if err := state.MakeCall(1, BridgeBid, 1, DiamondsStrain); err == nil {
+		t.Fatal("Expected lower bid to fail")
+	}
+
+	if err := state.MakeCall(1, BridgeDouble, 0, NoStrain); err != nil {
+		t.Fatal("Unable to double:", err)
+	}
+
+	if err := state.MakeCall(2, BridgeBid, 2, HeartsStrain); err != nil {
+		t.Fatal("Unable to bid:", err)
+	}
+
+	if err := state.MakeCall(3, BridgeDouble, 0, NoStrain); err != nil {
+		t.Fatal("Unable to double:", err)
+	}
+
+	if err := state.MakeCall(0, BridgeRedouble, 0, NoStrain); err != nil {
+		t.Fatal("Unable to redouble:", err)
+	}
+
+	for player := 1; player < 4; player++ {
+		if err := state.MakeCall(player, BridgePass, 0, NoStrain); err != nil {
+			t.Fatal("Unable to pass:", err)
+		}
+	}
+
+	if !state.Bid || state.Contract == nil {
+		t.Fatal("Expected auction to be over")
+	}
+
+	var expected = BridgeContract{Level: 2, Strain: HeartsStrain, Doubled: 2, Declarer: 0}
+	if *state.Contract != expected || state.Dummy != 2 || state.Turn != 1 {
+		t.Fatal("Expected 2 hearts redoubled by the first player to name hearts:", *state.Contract)
+	}
+
+	// The player left of the declarer makes the opening lead.
+	if err := state.PlayCard(1, state.Players[1].Hand[0].ID); err != nil {
+		t.Fatal("Unable to make opening lead:", err)
+	}
+
+	if !state.Revealed {
+		t.Fatal("Expected dummy's hand to be revealed after the opening lead")
+	}
+
+	var card = state.Players[2].Hand[0]
+	for _, candidate := range state.Players[2].Hand {
+		if candidate.Suit == state.Played[0].Suit {
+			card = candidate
+		}
+	}
+
+	// The dummy is bound to the declarer, whose connection plays its cards,
+	// along with any spectator bound to the declarer.
+	var game = &GameData{GID: 1, Mode: BridgeGame, Owner: 1, State: &state, ToPlayer: make(map[uint64]*PlayerData)}
+	for index := 0; index < 4; index++ {
+		var uid = uint64(index + 1)
+		game.ToPlayer[uid] = &PlayerData{UID: uid, Index: index, Admitted: true, Playing: true}
+	}
+
+	game.ToPlayer[5] = &PlayerData{UID: 5, Index: -1, Admitted: true, BoundPlayers: []uint64{1}}
+	game.ToPlayer[1].BoundPlayers = []uint64{5}
+	bindBridgeDummy(game, &state)
+
+	for _, uid := range []uint64{2, 3} {
+		if _, err := bridgePlayingSeat(game, &state, game.ToPlayer[uid]); err == nil {
+			t.Fatal("Expected only the declarer to play the dummy's cards, not", uid)
+		}
+	}
+
+	for _, uid := range []uint64{1, 5} {
+		if seat, err := bridgePlayingSeat(game, &state, game.ToPlayer[uid]); err != nil || seat != 2 {
+			t.Fatal("Expected the dummy's cards to be played by", uid, seat, err)
+		}
+	}
+
+	if err := state.PlayCard(2, card.ID); err != nil {
+		t.Fatal("Expected the declarer to play the dummy's card:", err)
+	}
+
+	// The binding only goes one way: the dummy can't play for the declarer.
+	card = state.Players[3].Hand[0]
+	for _, candidate := range state.Players[3].Hand {
+		if candidate.Suit == state.Played[0].Suit {
+			card = candidate
+		}
+	}
+
+	if err := state.PlayCard(3, card.ID); err != nil {
+		t.Fatal("Unable to play:", err)
+	}
+
+	if seat, err := bridgePlayingSeat(game, &state, game.ToPlayer[3]); state.Turn != 0 || (err == nil && seat == state.Turn) {
+		t.Fatal("Expected the dummy to be unable to play the declarer's cards:", state.Turn, seat, err)
+	}
+
+	// A new deal drops the binding.
+	state.Dealt = false
+	if err := state.StartRound(); err != nil {
+		t.Fatal("Unable to deal:", err)
+	}
+
+	bindBridgeDummy(game, &state)
+	for _, indexed_player := range game.ToPlayer {
+		if indexed_player.Playing && len(indexed_player.BoundPlayers) > 0 && indexed_player.UID != 1 {
+			t.Fatal("Expected the dummy to be unbound after the hand:", indexed_player.UID, indexed_player.BoundPlayers)
+		}
+	}
+}
+
+func TestBridgeGame(t *testing.T) {
+	for _, config := range []BridgeConfig{
+		{NumPlayers: 4, Scoring: BridgeRubberScoring, Rubbers: 1, Boards: 16},
+		{NumPlayers: 4, Scoring: BridgeDuplicateScoring, Rubbers: 1, Boards: 4},
+	} {
+		var state BridgeState
+		if err := state.Init(config); err != nil {
+			t.Fatal("Unable to initialize game:", err)
+		}
+
+		if err := state.AssignSeats(3); err == nil || state.Assigned {
+			t.Fatal("Expected bridge to need four players")
+		}
+
+		if err := state.AssignSeats(4); err != nil || !state.Assigned {
+			t.Fatal("Unable to assign seats:", err)
+		}
+
+		if err := state.Start(4); err != nil {
+			t.Fatal("Unable to start game:", err)
+		}
+
+		for hand := 0; hand < 500 && !state.Finished; hand++ {
+			if !state.Dealt {
+				if err := state.StartRound(); err != nil {
+					t.Fatal("Unable to deal:", err)
+				}
+			}
+
+			// The dealer opens at the game level in a suit, going down to the
+			// next strain every other deal; everyone else passes.
+			var err = state.MakeCall(state.Turn, BridgeBid, 3+hand%2, BridgeStrain(1+hand%5))
+			for err == nil && !state.Bid {
+				err = state.MakeCall(state.Turn, BridgePass, 0, NoStrain)
+			}
+
+			for err == nil {
+				var seat = state.Turn
+				var card = state.Players[seat].Hand[0]
+				if len(state.Played) > 0 && len(state.Played) < 4 {
+					for _, candidate := range state.Players[seat].Hand {
+						if candidate.Suit == state.Played[0].Suit {
+							card = candidate
+							break
+						}
+					}
+				}
+
+				err = state.PlayCard(seat, card.ID)
+			}
+
+			if err.Error() != BridgeNextRound && err.Error() != BridgeGameOver {
+				t.Fatal("Unexpected error playing round:", err)
+			}
+
+			var history = state.RoundHistory[len(state.RoundHistory)-1]
+			if len(history.Tricks) != 13 || history.RoundScores[0]+history.RoundScores[1] == 0 {
+				t.Fatal("Expected thirteen tricks with a score:", history.RoundScores)
+			}
+		}
+
+		if !state.Finished || len(state.Winners) == 0 {
+			t.Fatal("Expected game to finish with a winner:", state.Scores)
+		}
+
+		if config.Scoring == BridgeDuplicateScoring && state.Board != config.Boards {
+			t.Fatal("Expected every board to be played:", state.Board)
+		}
+
+		if config.Scoring == BridgeRubberScoring && state.Rubbers != 1 {
+			t.Fatal("Expected a rubber to be completed:", state.Rubbers)
+		}
+	}
+}
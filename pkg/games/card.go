@@ -51,6 +51,20 @@ func (r CardRank) String() string {
 	return CardRankNames[r]
 }
 
+// Order of the rank in games where aces rank above kings. Jokers rank above
+// aces.
+func (r CardRank) AceHigh() int {
+	if r == AceRank {
+		return int(KingRank) + 1
+	}
+
+	if r == JokerRank {
+		return int(KingRank) + 2
+	}
+
+	return int(r)
+}
+
 type Card struct {
 	ID   int      `json:"id"`
 	Suit CardSuit `json:"suit"`
@@ -94,6 +108,19 @@ func RemoveCard(hand []Card, cardID int) (*Card, []Card, bool) {
 	return &card, remaining, true
 }
 
+// Put a card back into the hand at the given position, as when taking back
+// a play.
+func InsertCard(hand []Card, index int, card Card) []Card {
+	if index < 0 || index > len(hand) {
+		index = len(hand)
+	}
+
+	var ret = make([]Card, 0, len(hand)+1)
+	ret = append(ret, hand[:index]...)
+	ret = append(ret, card)
+	return append(ret, hand[index:]...)
+}
+
 func CopyHand(hand []Card) []Card {
 	var ret = make([]Card, len(hand))
 	copy(ret, hand)
@@ -108,6 +135,10 @@ func CopyDeck(deck []*Card) []*Card {
 
 type Deck struct {
 	Cards []*Card `json:"cards"`
+
+	// Seed the deck is shuffled from. Kept secret until the cards are no
+	// longer in play; only its commitment is published.
+	Seed *ShuffleSeed `json:"seed,omitempty"`
 }
 
 func (d *Deck) Init() {
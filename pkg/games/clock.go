@@ -0,0 +1,283 @@
+package games
+
+import (
+	"errors"
+	"log"
+	"sort"
+	"time"
+)
+
+// What happens to a player who runs out of time on their clock.
+type ClockPolicy int
+
+const (
+	AutoPlayOnTimeout ClockPolicy = iota // 0 -- play their lowest card, or draw and discard the drawn card
+	SkipOnTimeout     ClockPolicy = iota // 1 -- pass the turn on without a move
+	ForfeitOnTimeout  ClockPolicy = iota // 2 -- they lose the game
+)
+
+// Chess clock style limits on how long players may take, from the common
+// configuration options of turn-based games.
+type TurnClocks struct {
+	Turn   time.Duration // Limit on each turn; zero for none.
+	Game   time.Duration // Limit on all of a player's turns over the game; zero for none.
+	Policy ClockPolicy
+}
+
+func newTurnClocks(turn_seconds int, game_minutes int, policy int) TurnClocks {
+	return TurnClocks{
+		Turn:   time.Duration(turn_seconds) * time.Second,
+		Game:   time.Duration(game_minutes) * time.Minute,
+		Policy: ClockPolicy(policy),
+	}
+}
+
+func (tc TurnClocks) Enabled() bool {
+	return tc.Turn > 0 || tc.Game > 0
+}
+
+// Implemented by states of turn-based games, so the controller can run a
+// clock on whoever's turn it is.
+type ClockedState interface {
+	TurnClocks() TurnClocks
+
+	// The player whose turn it is, or -1 when the game isn't waiting on any
+	// one player, like while everyone passes cards at once.
+	ClockedPlayer() int
+
+	// Apply the clock policy to the player who ran out of time.
+	Timeout(player int, policy ClockPolicy) error
+}
+
+// Play the lowest ranked card from the hand which play accepts, for players
+// who ran out of time. Jokers rank highest, as do aces when ace_high is set.
+func playLowestCard(hand []Card, ace_high bool, play func(card_id int) error) error {
+	var ranked = CopyHand(hand)
+	var rank = func(card Card) int {
+		if card.Rank == AceRank && !ace_high {
+			return int(AceRank)
+		}
+
+		return card.Rank.AceHigh()
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return rank(ranked[i]) < rank(ranked[j])
+	})
+
+	var err error = errors.New("no cards left to play")
+	for _, card := range ranked {
+		err = play(card.ID)
+		if err == nil || isRoundSignal(err) {
+			return err
+		}
+	}
+
+	return err
+}
+
+// The clock of a game with turn clocks. This is part of GameData so that it
+// is persisted along with the game.
+type GameClock struct {
+	Turn      int             `json:"turn"`      // Number of turns clocked so far; lets timers tell whether they're stale.
+	Player    int             `json:"player"`    // Whose clock is running, or -1 when nobody's is.
+	Started   time.Time       `json:"started"`   // When the current turn began.
+	Deadline  time.Time       `json:"deadline"`  // When the current turn runs out of time.
+	Remaining []time.Duration `json:"remaining"` // Time each player has left in the game, when there's a game clock.
+}
+
+// Inbound messages which don't end a turn, even when sent by the player
+// whose turn it is.
+var clockIgnoredMessages = map[string]bool{
+	"join":          true,
+	"admit":         true,
+	"ready":         true,
+	"keepalive":     true,
+	"word":          true,
+	"countback":     true,
+	"start":         true,
+	"cancel":        true,
+	"assign":        true,
+	"peek":          true,
+	"sort":          true,
+	"select":        true,
+	"undo-request":  true,
+	"undo-response": true,
+	"pause":         true,
+	"resume":        true,
+	"seeds":         true,
+	"claim":         true,
+}
+
+// Inbound messages which only begin a turn in some games, leaving the player
+// to finish it with another move, like taking a card before discarding one.
+var clockMidTurnMessages = map[GameMode]map[string]bool{
+	ThreeThirteenGame: {"take": true},
+	EightJacksGame:    {"discard": true},
+	GinGame:           {"take": true},
+	CrazyEightsGame:   {"draw": true},
+}
+
+// Whether an accepted message ends the turn of the player who sent it.
+func clockEndsTurn(game *GameData, messageType string) bool {
+	return !clockIgnoredMessages[messageType] && !clockMidTurnMessages[game.Mode][messageType]
+}
+
+type GameTimeout struct {
+	MessageHeader
+	Policy ClockPolicy `json:"policy"`
+}
+
+type ControllerClockPlayer struct {
+	UID       uint64 `json:"user"`
+	Remaining int64  `json:"remaining"` // In milliseconds.
+}
+
+type ControllerNotifyClock struct {
+	MessageHeader
+	Turn     uint64                  `json:"turn"`              // Whose clock is running; zero when nobody's is.
+	Deadline time.Time               `json:"deadline"`          // When their turn runs out of time.
+	Players  []ControllerClockPlayer `json:"players,omitempty"` // Time left in the game, when there's a game clock.
+}
+
+func (cnc *ControllerNotifyClock) LoadFromController(data *GameData, player *PlayerData) {
+	cnc.LoadHeader(data, player)
+	cnc.MessageType = "clock"
+
+	var clock = data.Clock
+	if clock.Player >= 0 {
+		cnc.Turn, _ = data.ToUserID(clock.Player)
+		cnc.Deadline = clock.Deadline
+	}
+
+	for index, remaining := range clock.Remaining {
+		var uid, _ = data.ToUserID(index)
+		cnc.Players = append(cnc.Players, ControllerClockPlayer{uid, remaining.Milliseconds()})
+	}
+}
+
+// Start, stop or hand over the clock after the game has changed. The player
+// who moved, if any, ends their turn even when they keep playing (like when
+// they win a trick and lead the next one).
+func (c *Controller) updateClock(game *GameData, moved *PlayerData) {
+	// !!NO LOCK!! This should already be held elsewhere, like Dispatch.
+
+	state, ok := game.State.(ClockedState)
+	if !ok || !game.State.IsStarted() {
+		return
+	}
+
+	var clocks = state.TurnClocks()
+	if !clocks.Enabled() {
+		return
+	}
+
+	if game.Clock == nil {
+		game.Clock = &GameClock{Player: -1}
+		for _, indexed_player := range game.ToPlayer {
+			for clocks.Game > 0 && indexed_player.Playing && len(game.Clock.Remaining) <= indexed_player.Index {
+				game.Clock.Remaining = append(game.Clock.Remaining, clocks.Game)
+			}
+		}
+	}
+
+	var clock = game.Clock
+	var player = -1
+	if !game.State.IsFinished() {
+		player = state.ClockedPlayer()
+	}
+
+	var ended = moved != nil && moved.Index == clock.Player
+	if player == clock.Player && !ended {
+		return
+	}
+
+	// Charge the turn which just ended to whoever took it.
+	var now = time.Now()
+	if clock.Player >= 0 && clock.Player < len(clock.Remaining) {
+		clock.Remaining[clock.Player] -= now.Sub(clock.Started)
+		if clock.Remaining[clock.Player] < 0 {
+			clock.Remaining[clock.Player] = 0
+		}
+	}
+
+	clock.Turn += 1
+	clock.Player = player
+	clock.Started = now
+	clock.Deadline = time.Time{}
+	if player >= 0 {
+		var limit = clocks.Turn
+		if player < len(clock.Remaining) && (limit == 0 || clock.Remaining[player] < limit) {
+			limit = clock.Remaining[player]
+		}
+
+		clock.Deadline = now.Add(limit)
+		c.scheduleClockTimeout(game)
+	}
+
+	for _, indexed_player := range game.ToPlayer {
+		if !indexed_player.Admitted {
+			continue
+		}
+
+		var notification ControllerNotifyClock
+		notification.LoadFromController(game, indexed_player)
+		c.undispatch(game, indexed_player, notification.MessageID, 0, notification)
+	}
+}
+
+func (c *Controller) scheduleClockTimeout(game *GameData) {
+	if game.Clock == nil || game.Clock.Player < 0 {
+		return
+	}
+
+	var gid = game.GID
+	var turn = game.Clock.Turn
+	time.AfterFunc(time.Until(game.Clock.Deadline), func() {
+		c.handleClockTimeout(gid, turn)
+	})
+}
+
+func (c *Controller) handleClockTimeout(gid uint64, turn int) {
+	c.lock.Lock()
+	game, ok := c.ToGame[gid]
+	if !ok {
+		c.lock.Unlock()
+		return
+	}
+
+	game.lock.Lock()
+	defer game.lock.Unlock()
+	c.lock.Unlock()
+
+	if game.Clock == nil || game.Clock.Turn != turn || game.State.IsFinished() {
+		return
+	}
+
+	// Resuming a paused game pushes back the deadline and schedules a new
+	// timer for it.
+	if game.Paused != nil || time.Now().Before(game.Clock.Deadline) {
+		return
+	}
+
+	state, ok := game.State.(ClockedState)
+	if !ok {
+		return
+	}
+
+	uid, ok := game.ToUserID(game.Clock.Player)
+	if !ok {
+		return
+	}
+
+	var player = game.ToPlayer[uid]
+	var timeout = GameTimeout{botHeader(game, player, "timeout"), state.TurnClocks().Policy}
+	if err := c.dispatchFor(game, player, timeout); err != nil && !isRoundSignal(err) {
+		// Leave the clock stopped rather than timing the same turn out again.
+		log.Println("Unable to apply clock policy to", uid, "in", gid, "after running out of time:", err)
+		return
+	}
+
+	c.runBots(game)
+	c.updateClock(game, player)
+}
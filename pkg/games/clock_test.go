@@ -0,0 +1,210 @@
+package games
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Start a four player game of hearts with everyone's cards passed, so the
+// first trick is ready to be played.
+func newClockedHeartsGame(t *testing.T, c *Controller, cfg HeartsConfig) (*GameData, *HeartsState) {
+	var state HeartsState
+	if err := state.Init(cfg); err != nil {
+		t.Fatal("Unable to initialize game:", err)
+	}
+
+	if err := state.Start(4); err != nil {
+		t.Fatal("Unable to start game:", err)
+	}
+
+	for player := range state.Players {
+		var hand = state.Players[player].Hand
+		if err := state.PassCards(player, []int{hand[0].ID, hand[1].ID, hand[2].ID}); err != nil {
+			t.Fatal("Unable to pass cards:", err)
+		}
+	}
+
+	var game = &GameData{GID: 1, Mode: HeartsGame, Owner: 1, State: &state, ToPlayer: make(map[uint64]*PlayerData)}
+	for index := 0; index < 4; index++ {
+		var uid = uint64(index + 1)
+		game.ToPlayer[uid] = &PlayerData{UID: uid, Index: index, Admitted: true, Playing: true, Notifications: make(map[uint64]chan interface{})}
+	}
+
+	c.ToGame[game.GID] = game
+	return game, &state
+}
+
+func TestTurnClock(t *testing.T) {
+	var c Controller
+	c.Init()
+
+	// Long enough that the scheduled timers never fire during the test.
+	var game, state = newClockedHeartsGame(t, &c, HeartsConfig{NumPlayers: 4, NumberToPass: 3, WinAmount: 100, TurnClock: 600})
+
+	c.updateClock(game, nil)
+	if game.Clock == nil || game.Clock.Player != state.Turn || time.Until(game.Clock.Deadline) < 590*time.Second || len(game.Clock.Remaining) != 0 {
+		t.Fatal("Expected the clock to start for whoever leads:", game.Clock, state.Turn)
+	}
+
+	var broadcast = false
+	for _, message := range game.ToPlayer[2].OutboundMsgs {
+		broadcast = broadcast || (strings.Contains(message.Message, `"message_type":"clock"`) && strings.Contains(message.Message, `"turn":`+strconv.Itoa(state.Turn+1)))
+	}
+
+	if !broadcast {
+		t.Fatal("Expected everyone to be told whose clock is running")
+	}
+
+	// Timers which fire before the deadline, like after the game was paused,
+	// are ignored.
+	var leader = state.Turn
+	var turn = game.Clock.Turn
+	c.handleClockTimeout(game.GID, turn)
+	if len(state.Played) != 0 {
+		t.Fatal("Expected the clock to keep running until the deadline:", state.Played)
+	}
+
+	// Running out of time plays the lowest card and hands the clock over.
+	game.Clock.Deadline = time.Now()
+	c.handleClockTimeout(game.GID, turn)
+	if len(state.Played) != 1 || state.Turn == leader || game.Clock.Player != state.Turn || game.Clock.Turn != turn+1 {
+		t.Fatal("Expected a card to be played on the leader's behalf:", state.Played, game.Clock)
+	}
+
+	if played := state.Played[0]; played.Rank != TwoRank {
+		t.Fatal("Expected the two of clubs to be led:", played)
+	}
+
+	// Timers from earlier turns are ignored.
+	c.handleClockTimeout(game.GID, turn)
+	if len(state.Played) != 1 {
+		t.Fatal("Expected a stale timer to do nothing:", state.Played)
+	}
+
+	// Moves which don't end the turn leave the clock alone.
+	c.updateClock(game, game.ToPlayer[uint64(leader+1)])
+	if game.Clock.Turn != turn+1 {
+		t.Fatal("Expected the clock to keep running:", game.Clock)
+	}
+
+	// Forfeiting ends the game and stops the clock.
+	var loser = state.Turn
+	state.Config.ClockPolicy = int(ForfeitOnTimeout)
+	game.Clock.Deadline = time.Now()
+	c.handleClockTimeout(game.GID, game.Clock.Turn)
+	if !state.Finished || state.Winner == loser || state.Winner < 0 || game.Clock.Player != -1 {
+		t.Fatal("Expected the player to forfeit:", state.Finished, state.Winner, game.Clock)
+	}
+}
+
+func TestGameClockPersistence(t *testing.T) {
+	var c Controller
+	c.Init()
+
+	var game, state = newClockedHeartsGame(t, &c, HeartsConfig{NumPlayers: 4, NumberToPass: 3, WinAmount: 100, GameClock: 1})
+
+	c.updateClock(game, nil)
+	if len(game.Clock.Remaining) != 4 || game.Clock.Remaining[0] != time.Minute || time.Until(game.Clock.Deadline) > time.Minute {
+		t.Fatal("Expected everyone to start with a minute:", game.Clock)
+	}
+
+	// Time taken is charged against the player's game clock.
+	var leader = state.Turn
+	game.Clock.Started = game.Clock.Started.Add(-10 * time.Second)
+	playAnyHeartsCard(t, state, leader)
+	c.updateClock(game, game.ToPlayer[uint64(leader+1)])
+	if remaining := game.Clock.Remaining[leader]; remaining > 50*time.Second || remaining < 49*time.Second {
+		t.Fatal("Expected ten seconds to be charged to the leader:", remaining)
+	}
+
+	encoded, err := json.Marshal(game.Clock)
+	if err != nil {
+		t.Fatal("Unable to serialize clock:", err)
+	}
+
+	var restored GameClock
+	if err := json.Unmarshal(encoded, &restored); err != nil {
+		t.Fatal("Unable to deserialize clock:", err)
+	}
+
+	if restored.Turn != game.Clock.Turn || restored.Player != game.Clock.Player || !restored.Deadline.Equal(game.Clock.Deadline) || restored.Remaining[leader] != game.Clock.Remaining[leader] {
+		t.Fatal("Expected the clock to survive serialization:", restored, game.Clock)
+	}
+
+	// Rescheduling a clock which ran out while the game was unloaded times
+	// the player out right away.
+	restored.Deadline = time.Now()
+	game.Clock = &restored
+
+	var player = state.Turn
+	c.scheduleClockTimeout(game)
+	for attempt := 0; attempt < 100; attempt++ {
+		game.lock.Lock()
+		var timed_out = state.Turn != player
+		game.lock.Unlock()
+
+		if timed_out {
+			return
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatal("Expected the rescheduled clock to time out the player")
+}
+
+func TestTurnClockTakeThenDiscard(t *testing.T) {
+	var c Controller
+	c.Init()
+
+	var state GinState
+	if err := state.Init(GinConfig{NumPlayers: 2, HandSize: 10, LayingDownLimit: 10, WinAmount: 100, GinAmount: 10, BigGinAmount: 20, UndercutAmount: 10, TurnClock: 600}); err != nil {
+		t.Fatal("Unable to initialize game:", err)
+	}
+
+	if err := state.Start(2); err != nil {
+		t.Fatal("Unable to start game:", err)
+	}
+
+	var game = &GameData{GID: 1, Mode: GinGame, Owner: 1, State: &state, ToPlayer: make(map[uint64]*PlayerData)}
+	for index := 0; index < 2; index++ {
+		var uid = uint64(index + 1)
+		game.ToPlayer[uid] = &PlayerData{UID: uid, Index: index, Admitted: true, Playing: true, Notifications: make(map[uint64]chan interface{})}
+	}
+	c.ToGame[game.GID] = game
+
+	var send = func(message interface{}) {
+		data, err := json.Marshal(message)
+		if err != nil {
+			t.Fatal("Unable to marshal message:", err)
+		}
+
+		if _, err := c.Dispatch(data, game.GID, uint64(state.Turn+1), 0); err != nil {
+			t.Fatal("Unable to make move:", err)
+		}
+	}
+
+	var header = func(messageType string) MessageHeader {
+		return MessageHeader{Mode: "gin", ID: game.GID, Player: uint64(state.Turn + 1), MessageType: messageType}
+	}
+
+	c.updateClock(game, nil)
+	var player = state.Turn
+	var turn = game.Clock.Turn
+	var deadline = game.Clock.Deadline
+
+	// Taking a card only starts the turn; the clock keeps running.
+	send(GinTakeMsg{header("take"), false})
+	if game.Clock.Turn != turn || game.Clock.Player != player || !game.Clock.Deadline.Equal(deadline) {
+		t.Fatal("Expected taking a card to leave the clock alone:", game.Clock, turn, deadline)
+	}
+
+	// Discarding ends it and hands the clock over.
+	send(GinDiscardMsg{header("discard"), state.Players[player].Drawn.ID, false})
+	if game.Clock.Turn != turn+1 || game.Clock.Player == player || game.Clock.Player != state.Turn {
+		t.Fatal("Expected discarding to hand the clock over:", game.Clock, state.Turn)
+	}
+}
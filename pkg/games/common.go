@@ -18,103 +18,65 @@ const (
 	EightJacksGame    GameMode = iota // 3
 	HeartsGame        GameMode = iota // 4
 	GinGame           GameMode = iota // 5
+	EuchreGame        GameMode = iota // 6
+	CribbageGame      GameMode = iota // 7
+	BridgeGame        GameMode = iota // 8
+	OhHellGame        GameMode = iota // 9
+	CrazyEightsGame   GameMode = iota // 10
+	WordSearchGame    GameMode = iota // 11
 )
 
 func (gm GameMode) String() string {
-	return []string{"rush", "spades", "three thirteen", "eight jacks", "hearts", "gin"}[gm]
+	engine, ok := LookupGameEngine(gm)
+	if !ok {
+		return "unknown"
+	}
+
+	return engine.Name()
 }
 
 // Convert the representation of a GameMode to a string.
 func GameModeFromString(repr string) GameMode {
-	switch strings.ToLower(repr) {
-	case "rush":
-		return RushGame
-	case "spades":
-		return SpadesGame
-	case "three thirteen":
-		return ThreeThirteenGame
-	case "eight jacks":
-		return EightJacksGame
-	case "hearts":
-		return HeartsGame
-	case "gin":
-		return GinGame
-	default:
-		return -1
+	var name = strings.ToLower(repr)
+	for _, engine := range GameEngines() {
+		if strings.ToLower(engine.Name()) == name {
+			return engine.Mode()
+		}
 	}
+
+	return -1
 }
 
 func (gm GameMode) IsValid() bool {
-	return gm == RushGame || gm == SpadesGame || gm == ThreeThirteenGame || gm == EightJacksGame || gm == HeartsGame || gm == GinGame
+	_, ok := LookupGameEngine(gm)
+	return ok
 }
 
 func (gm GameMode) NewState() ConfigurableState {
-	switch gm {
-	case RushGame:
-		return &RushState{}
-	case SpadesGame:
-		return &SpadesState{}
-	case ThreeThirteenGame:
-		return &ThreeThirteenState{}
-	case EightJacksGame:
-		return &EightJacksState{}
-	case HeartsGame:
-		return &HeartsState{}
-	case GinGame:
-		return &GinState{}
-	default:
+	engine, ok := LookupGameEngine(gm)
+	if !ok {
 		panic("Unable to create an empty state for this game mode")
 	}
+
+	return engine.NewState()
 }
 
 func (gm GameMode) Init(config figgy.Figgurable) (ConfigurableState, error) {
-	switch gm {
-	case RushGame:
-		var asserted *RushConfig = config.(*RushConfig)
-		var state = &RushState{}
-		return state, state.Init(*asserted)
-	case SpadesGame:
-		var asserted *SpadesConfig = config.(*SpadesConfig)
-		var state = &SpadesState{}
-		return state, state.Init(*asserted)
-	case ThreeThirteenGame:
-		var asserted *ThreeThirteenConfig = config.(*ThreeThirteenConfig)
-		var state = &ThreeThirteenState{}
-		return state, state.Init(*asserted)
-	case EightJacksGame:
-		var asserted *EightJacksConfig = config.(*EightJacksConfig)
-		var state = &EightJacksState{}
-		return state, state.Init(*asserted)
-	case HeartsGame:
-		var asserted *HeartsConfig = config.(*HeartsConfig)
-		var state = &HeartsState{}
-		return state, state.Init(*asserted)
-	case GinGame:
-		var asserted *GinConfig = config.(*GinConfig)
-		var state = &GinState{}
-		return state, state.Init(*asserted)
-	default:
+	engine, ok := LookupGameEngine(gm)
+	if !ok {
 		panic("Unable to create an initialized state for this game mode")
 	}
+
+	return engine.Init(config)
 }
 
 func (gm GameMode) EmptyConfig() figgy.Figgurable {
-	switch gm {
-	case RushGame:
-		return &RushConfig{}
-	case SpadesGame:
-		return &SpadesConfig{}
-	case ThreeThirteenGame:
-		return &ThreeThirteenConfig{}
-	case EightJacksGame:
-		return &EightJacksConfig{}
-	case HeartsGame:
-		return &HeartsConfig{}
-	case GinGame:
-		return &GinConfig{}
-	default:
+	engine, ok := LookupGameEngine(gm)
+	if !ok {
 		panic("Unable to create an empty config for this game mode")
 	}
+
+	return engine.EmptyConfig()
 }
 
 // GameConfigError is a type of error specific for errors in the
@@ -141,3 +103,10 @@ type ConfigurableState interface {
 	IsFinished() bool
 	ResetStatus()
 }
+
+// Implemented by states of games which check words against a dictionary, so
+// that rooms can add or remove words.
+type WordGameState interface {
+	SetRoomWords(words RoomWords)
+	Words() WordList
+}
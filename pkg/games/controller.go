@@ -34,6 +34,10 @@ const (
 type Controller struct {
 	lock   sync.Mutex
 	ToGame map[uint64]*GameData `json:"games"`
+
+	// Whether this controller only replays games from their logs. Replays
+	// leave the database alone and don't wait on any timers.
+	replay bool
 }
 
 // Initialize a Controller object.
@@ -88,6 +92,24 @@ func (c *Controller) LoadGame(gamedb *database.Game) error {
 		if err := c.addGame(gamedb.Style, gamedb.ID, gamedb.OwnerID, config); err != nil {
 			return err
 		}
+
+		// Deal from the secret the game was created with, if any.
+		if gamedb.ShuffleSecret.Valid {
+			state, ok := c.ToGame[gamedb.ID].State.(SeededState)
+			if !ok {
+				return errors.New("unable to pick the shuffle secret for " + mode.String() + " games")
+			}
+
+			var seed = ShuffleSeedFromSecret(gamedb.ShuffleSecret.String)
+			if err := state.SetShuffleSeed(seed); err != nil {
+				return err
+			}
+
+			// Deals start from the first round, leaving round zero for the
+			// seating.
+			c.ToGame[gamedb.ID].Seating = seed.Derive(0)
+			c.ToGame[gamedb.ID].PickedSeed = gamedb.PickedShuffleSecret
+		}
 	} else {
 		// Otherwise, update our copy of the game data with missing fields and
 		// then add it to the controller.
@@ -97,12 +119,46 @@ func (c *Controller) LoadGame(gamedb *database.Game) error {
 		}
 
 		c.ToGame[gamedb.ID] = &data
+
+		// Timers don't survive being persisted; pick up the running clock where
+		// it left off.
+		c.scheduleClockTimeout(&data)
 	}
 
 	if err := c.ToGame[gamedb.ID].State.ReInit(); err != nil {
 		return err
 	}
 
+	// Deadlines kept by the game itself, like the end of a Rush round, need
+	// their timers back too.
+	if data.GID == gamedb.ID {
+		if engine, ok := LookupGameEngine(mode); ok {
+			if pausable, ok := engine.(PausableEngine); ok {
+				pausable.Reschedule(c, &data)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Apply the word lists of the room a game is played in. Games which don't
+// check words ignore them.
+func (c *Controller) SetRoomWords(gid uint64, words RoomWords) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	game, ok := c.ToGame[gid]
+	if !ok {
+		return errors.New("game with specified id (" + strconv.FormatUint(gid, 10) + ") doesn't exist in controller")
+	}
+
+	if state, ok := game.State.(WordGameState); ok {
+		game.lock.Lock()
+		state.SetRoomWords(words)
+		game.lock.Unlock()
+	}
+
 	return nil
 }
 
@@ -162,27 +218,12 @@ func (c *Controller) PersistGame(gamedb *database.Game, tx *gorm.DB) error {
 	var persist_players []DatabasePlayer
 	var persist_messages []*database.GameMessage
 	for _, indexed_player := range game.ToPlayer {
-		persist_players = append(persist_players, DatabasePlayer{indexed_player.UID, game.GID, indexed_player.Admitted})
-
-		// Only keep messages which haven't yet been saved. Everything else we can
-		// remove from this queue so it gets garbage collected.
-		var unsaved []*database.GameMessage
-		for _, message := range indexed_player.InboundMsgs {
-			if message.ID == 0 {
-				unsaved = append(unsaved, message)
-			}
-		}
-		indexed_player.InboundMsgs = unsaved
-		persist_messages = append(persist_messages, unsaved...)
+		persist_messages = append(persist_messages, unsavedMessages(indexed_player)...)
 
-		unsaved = nil
-		for _, message := range indexed_player.OutboundMsgs {
-			if message.ID == 0 {
-				unsaved = append(unsaved, message)
-			}
+		// Bots don't have a game_player entry in the database.
+		if !indexed_player.Bot {
+			persist_players = append(persist_players, DatabasePlayer{indexed_player.UID, game.GID, indexed_player.Admitted})
 		}
-		indexed_player.OutboundMsgs = unsaved
-		persist_messages = append(persist_messages, unsaved...)
 	}
 
 	// Don't hold the lock while we are writing the transaction.
@@ -252,6 +293,32 @@ func (c *Controller) PersistGame(gamedb *database.Game, tx *gorm.DB) error {
 	return candidateError
 }
 
+// Only keep messages which haven't yet been saved. Everything else we can
+// remove from the player's queues so it gets garbage collected.
+func unsavedMessages(player *PlayerData) []*database.GameMessage {
+	var ret []*database.GameMessage
+
+	var unsaved []*database.GameMessage
+	for _, message := range player.InboundMsgs {
+		if message.ID == 0 {
+			unsaved = append(unsaved, message)
+		}
+	}
+	player.InboundMsgs = unsaved
+	ret = append(ret, unsaved...)
+
+	unsaved = nil
+	for _, message := range player.OutboundMsgs {
+		if message.ID == 0 {
+			unsaved = append(unsaved, message)
+		}
+	}
+	player.OutboundMsgs = unsaved
+	ret = append(ret, unsaved...)
+
+	return ret
+}
+
 // Remove a given game once it is no longer needed.
 func (c *Controller) RemoveGame(gid uint64) error {
 	c.lock.Lock()
@@ -304,22 +371,11 @@ func (c *Controller) AddPlayer(gid uint64, uid uint64, sid uint64, admitted bool
 		return present, nil
 	}
 
-	// By default, the owner of the game is already admitted into the game.
-	var owner bool = uid == game.Owner
-	player := new(PlayerData)
-	player.UID = uid
-	player.Index = -1
-	player.Ready = owner
-	player.Admitted = admitted || owner
-	player.Playing = player.Admitted && !game.State.IsStarted() && !game.State.IsFinished() && game.CountdownTimer == nil
-	player.InboundMsgs = nil
-	player.OutboundID = 1
-	player.OutboundMsgs = nil
-	player.Notifications = make(map[uint64]chan interface{})
+	player := newPlayerData(game, uid, admitted)
 	player.Notifications[sid] = make(chan interface{}, notificationQueueLength)
 	game.ToPlayer[uid] = player
 
-	if owner {
+	if uid == game.Owner {
 		log.Println("Adding owner to game ", game)
 	}
 
@@ -435,6 +491,10 @@ func (c *Controller) Dispatch(message []byte, gid uint64, uid uint64, sid uint64
 		return false, errors.New("phantom message: message came over wrong websocket for different player: " + strconv.FormatUint(uid, 10) + " in " + strconv.FormatUint(gid, 10) + " :: " + string(message))
 	}
 
+	if header.MessageType == "timeout" {
+		return false, errors.New("only the server can time out a player's turn")
+	}
+
 	// We're going to release this lock right away, so don't bother with
 	// a defer unlock call.
 	c.lock.Lock()
@@ -454,6 +514,7 @@ func (c *Controller) Dispatch(message []byte, gid uint64, uid uint64, sid uint64
 
 	var was_started = gameData.State.IsStarted()
 	var was_finished = gameData.State.IsFinished()
+	var was_paused = gameData.Paused != nil
 
 	if gameData.Mode.String() != header.Mode {
 		return false, errors.New("game modes don't match internal expectations")
@@ -482,6 +543,16 @@ func (c *Controller) Dispatch(message []byte, gid uint64, uid uint64, sid uint64
 		c.undispatch(gameData, playerData, notification.MessageID, notification.ReplyTo, notification)
 	}
 
-	var do_update = gameData.State.IsStarted() != was_started || gameData.State.IsFinished() != was_finished
+	// Give any computer players a chance to respond to this message.
+	c.runBots(gameData)
+
+	// Hand the clock over if this message ended the player's turn.
+	var moved *PlayerData
+	if (err == nil || isRoundSignal(err)) && clockEndsTurn(gameData, header.MessageType) {
+		moved = playerData
+	}
+	c.updateClock(gameData, moved)
+
+	var do_update = gameData.State.IsStarted() != was_started || gameData.State.IsFinished() != was_finished || (gameData.Paused != nil) != was_paused
 	return do_update, err
 }
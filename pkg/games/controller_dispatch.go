@@ -45,38 +45,13 @@ type GameUnbindRequest struct {
 }
 
 func (c *Controller) dispatch(message []byte, header MessageHeader, game *GameData, player *PlayerData, sid uint64) error {
-	// Get some common started/finished information first. Because we store
-	// this in the game state, accessing it requires knowing the game mode.
-	var started = false
-	// var finished = false
-	if game.Mode == RushGame {
-		var state *RushState = game.State.(*RushState)
-		started = state.Started
-		// finished = state.Finished
-	} else if game.Mode == SpadesGame {
-		var state *SpadesState = game.State.(*SpadesState)
-		started = state.Started
-		// finished = state.Finished
-	} else if game.Mode == ThreeThirteenGame {
-		var state *ThreeThirteenState = game.State.(*ThreeThirteenState)
-		started = state.Started
-		// finished = state.Finished
-	} else if game.Mode == EightJacksGame {
-		var state *EightJacksState = game.State.(*EightJacksState)
-		started = state.Started
-		// finished = state.Finished
-	} else if game.Mode == HeartsGame {
-		var state *HeartsState = game.State.(*HeartsState)
-		started = state.Started
-		// finished = state.Finished
-	} else if game.Mode == GinGame {
-		var state *GinState = game.State.(*GinState)
-		started = state.Started
-		// finished = state.Finished
-	} else {
+	engine, ok := LookupGameEngine(game.Mode)
+	if !ok {
 		panic("Unknown game mode: " + game.Mode.String())
 	}
 
+	var started = game.State.IsStarted()
+
 	// First try and handle some common message types. Note that since c.Dispatch
 	// doesn't hold a lock, we can safely call back into c.MarkAdmitted(...) and
 	// c.MarkReady(...).
@@ -115,6 +90,18 @@ func (c *Controller) dispatch(message []byte, header MessageHeader, game *GameDa
 			users.LoadFromController(game, player)
 			c.undispatch(game, player, users.MessageID, 0, users)
 
+			if game.Clock != nil {
+				var clock ControllerNotifyClock
+				clock.LoadFromController(game, player)
+				c.undispatch(game, player, clock.MessageID, 0, clock)
+			}
+
+			if game.Paused != nil {
+				var paused ControllerNotifyPause
+				paused.LoadFromController(game, player)
+				c.undispatch(game, player, paused.MessageID, 0, paused)
+			}
+
 			// Since this user won't have an admit message, go ahead and send
 			// everyone else a message telling them of the new player.
 			for _, indexed_player := range game.ToPlayer {
@@ -170,7 +157,14 @@ func (c *Controller) dispatch(message []byte, header MessageHeader, game *GameDa
 			return err
 		}
 
-		// XXX: Handle word check requests.
+		if data.Word == "" {
+			return errors.New("no word to check")
+		}
+
+		var response ControllerNotifyWord
+		response.LoadFromController(game, player, data.Word)
+		response.ReplyTo = header.MessageID
+		c.undispatch(game, player, response.MessageID, response.ReplyTo, response)
 		return nil
 	case "countback":
 		var data GameCountback
@@ -201,6 +195,11 @@ func (c *Controller) dispatch(message []byte, header MessageHeader, game *GameDa
 			return nil
 		}
 
+		// The countdown stays frozen until the game is resumed.
+		if game.Paused != nil {
+			return nil
+		}
+
 		return c.handleCountdown(game)
 	case "bind-request":
 		return c.handleBindRequest(message, game, player)
@@ -208,28 +207,41 @@ func (c *Controller) dispatch(message []byte, header MessageHeader, game *GameDa
 		return c.handleBindAccept(message, game, player)
 	case "unbind-request":
 		return c.handleUnbindRequest(message, game, player)
+	case "add-bot":
+		return c.handleAddBot(message, game, player)
+	case "remove-bot":
+		return c.handleRemoveBot(message, game, player)
+	case "pause":
+		return c.handlePause(message, game, player)
+	case "resume":
+		return c.handleResume(game, player)
+	case "seeds":
+		return c.handleSeeds(header, game, player)
 	}
 
-	if game.Mode == RushGame {
-		return c.dispatchRush(message, header, game, player, sid)
-	} else if game.Mode == SpadesGame {
-		return c.dispatchSpades(message, header, game, player, sid)
-	} else if game.Mode == ThreeThirteenGame {
-		return c.dispatchThreeThirteen(message, header, game, player, sid)
-	} else if game.Mode == EightJacksGame {
-		return c.dispatchEightJacks(message, header, game, player, sid)
-	} else if game.Mode == HeartsGame {
-		return c.dispatchHearts(message, header, game, player, sid)
-	} else if game.Mode == GinGame {
-		return c.dispatchGin(message, header, game, player, sid)
-	} else {
-		panic("Valid but unsupported game mode: " + game.Mode.String())
+	if game.Paused != nil && !pauseAllowedMessages[header.MessageType] {
+		return errors.New("game is paused; wait for the owner to resume it")
 	}
+
+	if header.MessageType == "start" {
+		if err := checkPickedSeed(game); err != nil {
+			return err
+		}
+	}
+
+	return engine.Dispatch(c, message, header, game, player, sid)
 }
 
 func (c *Controller) handleCountdown(game *GameData) error {
 	var sendNext bool = true
 	for _, player := range game.ToPlayer {
+		// Bots don't have a connection to count back on; they're always
+		// caught up.
+		if player.Bot {
+			player.Countback = game.Countdown
+			continue
+		}
+
 		// Only check for countbacks from players. We don't care whether or not
 		// spectators can see the board.
 		if player.Admitted && player.Playing && player.Countback != game.Countdown {
@@ -241,7 +253,8 @@ func (c *Controller) handleCountdown(game *GameData) error {
 		return nil
 	}
 
-	if game.Countdown == 0 && game.CountdownTimer == nil {
+	// Replays don't count down; the game starts straight away.
+	if game.Countdown == 0 && game.CountdownTimer == nil && !c.replay {
 		game.Countdown = 4
 		game.CountdownTimer = time.NewTimer(1 * time.Nanosecond)
 		// Fall through -- this decrements the above countdown by one and sends out
@@ -250,40 +263,20 @@ func (c *Controller) handleCountdown(game *GameData) error {
 		game.Countdown = 0
 		game.CountdownTimer = nil
 
-		if game.Mode == RushGame {
-			var state *RushState = game.State.(*RushState)
-			if !state.Started {
-				return c.doRushStart(game, state)
-				// Must return!
-			}
-		} else if game.Mode == SpadesGame {
-			var state *SpadesState = game.State.(*SpadesState)
-			if !state.Started {
-				return c.doSpadesStart(game, state)
-			}
-		} else if game.Mode == ThreeThirteenGame {
-			var state *ThreeThirteenState = game.State.(*ThreeThirteenState)
-			if !state.Started {
-				return c.doThreeThirteenStart(game, state)
-			}
-		} else if game.Mode == EightJacksGame {
-			var state *EightJacksState = game.State.(*EightJacksState)
-			if !state.Started {
-				return c.doEightJacksStart(game, state)
-			}
-		} else if game.Mode == HeartsGame {
-			var state *HeartsState = game.State.(*HeartsState)
-			if !state.Started {
-				return c.doHeartsStart(game, state)
-			}
-		} else if game.Mode == GinGame {
-			var state *GinState = game.State.(*GinState)
-			if !state.Started {
-				return c.doGinStart(game, state)
-			}
-		} else {
+		engine, ok := LookupGameEngine(game.Mode)
+		if !ok {
 			panic("Unknown game mode: " + game.Mode.String())
 		}
+
+		if !game.State.IsStarted() {
+			// The owner could have joined the players during the countdown.
+			if err := checkPickedSeed(game); err != nil {
+				return err
+			}
+
+			return engine.Start(c, game)
+			// Must return!
+		}
 	}
 
 	// Here we need to guard against multiple players entering the game with
@@ -26,10 +26,12 @@ func (mh *MessageHeader) LoadHeader(data *GameData, player *PlayerData) {
 
 type ControllerNotifyAdminJoin struct {
 	MessageHeader
-	Joined   uint64 `json:"joined"`
-	Admitted bool   `json:"admitted"`
-	Playing  bool   `json:"playing"`
-	Ready    bool   `json:"ready"`
+	Joined     uint64 `json:"joined"`
+	Admitted   bool   `json:"admitted"`
+	Playing    bool   `json:"playing"`
+	Ready      bool   `json:"ready"`
+	Bot        bool   `json:"bot,omitempty"`
+	Difficulty string `json:"difficulty,omitempty"`
 }
 
 func (cnaj *ControllerNotifyAdminJoin) LoadFromController(data *GameData, player *PlayerData, joined *PlayerData) {
@@ -40,6 +42,8 @@ func (cnaj *ControllerNotifyAdminJoin) LoadFromController(data *GameData, player
 	cnaj.Admitted = joined.Admitted
 	cnaj.Playing = joined.Playing
 	cnaj.Ready = joined.Ready
+	cnaj.Bot = joined.Bot
+	cnaj.Difficulty = joined.BotDifficulty
 }
 
 type ControllerNotifyAdminCountback struct {
@@ -126,10 +130,15 @@ type ControllerNotifyWord struct {
 
 func (cnw *ControllerNotifyWord) LoadFromController(data *GameData, player *PlayerData, word string) {
 	cnw.LoadHeader(data, player)
-	cnw.MessageType = "countdown"
+	cnw.MessageType = "word"
 
+	// Check against the game's own dictionary when it has one.
 	cnw.Word = word
-	cnw.Valid = IsWord(word)
+	if state, ok := data.State.(WordGameState); ok {
+		cnw.Valid = state.Words().IsWord(word)
+	} else {
+		cnw.Valid = IsWord(word)
+	}
 }
 
 type ControllerPlayerState struct {
@@ -137,6 +146,8 @@ type ControllerPlayerState struct {
 	Playing      bool     `json:"playing"`
 	Ready        bool     `json:"ready"`
 	BoundPlayers []uint64 `json:"bound_players"`
+	Bot          bool     `json:"bot,omitempty"`
+	Difficulty   string   `json:"difficulty,omitempty"`
 }
 
 type ControllerListUsersInGame struct {
@@ -155,6 +166,8 @@ func (cluig *ControllerListUsersInGame) LoadFromController(data *GameData, playe
 			state.UID = indexed_player.UID
 			state.Playing = indexed_player.Playing
 			state.Ready = indexed_player.Ready
+			state.Bot = indexed_player.Bot
+			state.Difficulty = indexed_player.BotDifficulty
 
 			for _, other_player := range indexed_player.BoundPlayers {
 				if data.PlayersAreBound(indexed_player.UID, other_player) {
@@ -51,6 +51,23 @@ func (c *Controller) addGame(modeRepr string, gid uint64, owner uint64, config f
 	return nil
 }
 
+// Create a player who just connected to the game, without any sessions yet.
+func newPlayerData(game *GameData, uid uint64, admitted bool) *PlayerData {
+	// By default, the owner of the game is already admitted into the game.
+	var owner bool = uid == game.Owner
+	player := new(PlayerData)
+	player.UID = uid
+	player.Index = -1
+	player.Ready = owner
+	player.Admitted = admitted || owner
+	player.Playing = player.Admitted && !game.State.IsStarted() && !game.State.IsFinished() && game.CountdownTimer == nil
+	player.InboundMsgs = nil
+	player.OutboundID = 1
+	player.OutboundMsgs = nil
+	player.Notifications = make(map[uint64]chan interface{})
+	return player
+}
+
 func (c *Controller) notifyAdmin(game *GameData, uid uint64) error {
 	// !!NO LOCK!! This should already be held elsewhere, like Dispatch.
 	// Don't notify the owner that they joined. Presumably, they already know.
@@ -145,7 +162,9 @@ func (c *Controller) markAdmitted(us uint64, gid uint64, uid uint64, admitted bo
 		player.Playing = playing
 	}
 
-	if err := database.InTransaction(func(tx *gorm.DB) error {
+	if player.Bot || c.replay {
+		// Bots aren't tracked in the database, and replays leave it alone.
+	} else if err := database.InTransaction(func(tx *gorm.DB) error {
 		var game_player database.GamePlayer
 		if err := tx.First(&game_player, "user_id = ? AND game_id = ?", uid, gid).Error; err != nil {
 			return err
@@ -179,6 +198,11 @@ func (c *Controller) markAdmitted(us uint64, gid uint64, uid uint64, admitted bo
 func (c *Controller) undispatch(data *GameData, player *PlayerData, message_id int, reply_to int, obj interface{}) {
 	// !!NO LOCK!! This should already be held elsewhere, like Dispatch.
 
+	if player.Bot {
+		// Bots read the game state directly; they don't need notifications.
+		return
+	}
+
 	if player.Notifications == nil {
 		log.Println("Player disconnected; refusing to send message to peer.", player.UID)
 		return
@@ -198,6 +222,7 @@ func (c *Controller) undispatch(data *GameData, player *PlayerData, message_id i
 		UserID:    player.UID,
 		GameID:    data.GID,
 		Timestamp: time.Now(),
+		Outbound:  true,
 		Message:   string(message),
 	}
 	player.OutboundMsgs = append(player.OutboundMsgs, &db_msg)
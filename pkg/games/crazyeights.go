@@ -0,0 +1,428 @@
+package games
+
+import (
+	"errors"
+	"log"
+	"strconv"
+
+	"git.cipherboy.com/WillowPatchGames/wpg/pkg/middleware/figgy"
+)
+
+const CrazyEightsGameOver string = "game is over"
+
+type CrazyEightsPlayer struct {
+	Hand []Card `json:"hand"`
+
+	Drawn bool `json:"drawn"` // Whether this player drew on their current turn.
+}
+
+func (cep *CrazyEightsPlayer) Init() {
+	cep.Hand = make([]Card, 0)
+}
+
+func (cep *CrazyEightsPlayer) FindCard(cardID int) (int, bool) {
+	return FindCard(cep.Hand, cardID)
+}
+
+func (cep *CrazyEightsPlayer) RemoveCard(cardID int) bool {
+	var ret bool
+	_, cep.Hand, ret = RemoveCard(cep.Hand, cardID)
+	return ret
+}
+
+type CrazyEightsConfig struct {
+	NumPlayers int `json:"num_players" config:"type:int,min:2,default:4,max:8" label:"Number of players"`
+
+	HandSize int `json:"hand_size" config:"type:int,min:3,default:7,max:10" label:"Hand size"`
+	NumDecks int `json:"num_decks" config:"type:int,min:0,default:0,max:3" label:"Number of decks (0 to add more decks for larger groups)"` // Zero picks enough decks for the number of players.
+
+	// Special cards
+	WildEights bool `json:"wild_eights" config:"type:bool,default:true" label:"true:Eights are wild and change the suit,false:Eights are ordinary cards"`
+	Skips      bool `json:"skips" config:"type:bool,default:false" label:"true:Queens skip the next player,false:Queens are ordinary cards"`
+	Reverses   bool `json:"reverses" config:"type:bool,default:false" label:"true:Aces reverse the direction of play,false:Aces are ordinary cards"`
+	DrawTwos   bool `json:"draw_twos" config:"type:bool,default:false" label:"true:Twos make the next player draw two cards and miss their turn,false:Twos are ordinary cards"`
+
+	// Common game configuration options
+	Countdown bool `json:"countdown" config:"type:bool,default:true" label:"true:Show a 3... 2... 1... countdown before beginning,false:Start the game instantly"` // Whether to wait and send countdown messages.
+
+	// Chess clock style limits on how long each player may take.
+	TurnClock   int `json:"turn_clock" config:"type:int,min:0,default:0,max:600" label:"Seconds per turn (0 for no limit)"`
+	GameClock   int `json:"game_clock" config:"type:int,min:0,default:0,max:120" label:"Minutes per player for the whole game (0 for no limit)"`
+	ClockPolicy int `json:"clock_policy" config:"type:enum,default:0,options:0:Play automatically;1:Skip their turn;2:Forfeit the game" label:"When a player runs out of time"` // See ClockPolicy.
+}
+
+func (cfg CrazyEightsConfig) Validate() error {
+	return nil
+}
+
+// A single move in the game: a card played (with the suit named, for a wild
+// eight) or a number of cards drawn.
+type CrazyEightsMove struct {
+	Player int      `json:"player"`
+	Card   *Card    `json:"card,omitempty"`
+	Suit   CardSuit `json:"suit,omitempty"`
+	Drew   int      `json:"drew,omitempty"`
+	Passed bool     `json:"passed,omitempty"`
+}
+
+type CrazyEightsState struct {
+	Turn      int `json:"turn"`
+	Direction int `json:"direction"` // 1 to the left; -1 to the right after a reverse.
+
+	Deck    Deck                `json:"deck"`    // The draw pile.
+	Discard []Card              `json:"discard"` // The top of the discard pile is the last card.
+	Players []CrazyEightsPlayer `json:"players"`
+	Suit    CardSuit            `json:"suit"` // Suit to match; differs from the top card after a wild eight.
+
+	Moves       []CrazyEightsMove `json:"moves"`        // All moves in the game, for analysis.
+	InitialDeck []*Card           `json:"initial_deck"` // The deck as first shuffled, before dealing.
+
+	Config CrazyEightsConfig `json:"config"`
+
+	Started  bool `json:"started"`
+	Finished bool `json:"finished"`
+	Winner   int  `json:"winner"`
+}
+
+func (ces *CrazyEightsState) Init(cfg CrazyEightsConfig) error {
+	var err error = figgy.Validate(cfg)
+	if err != nil {
+		log.Println("Error with CrazyEightsConfig", err)
+		return err
+	}
+
+	ces.Config = cfg
+	ces.Turn = -1
+	ces.Direction = 1
+	ces.Started = false
+	ces.Finished = false
+	ces.Winner = -1
+
+	return nil
+}
+
+func (ces *CrazyEightsState) GetConfiguration() figgy.Figgurable {
+	return ces.Config
+}
+
+func (ces *CrazyEightsState) ReInit() error {
+	// No-op for now. Nothing needs to be re-initialized after reloading
+	// from JSON serialization.
+	return nil
+}
+
+func (ces *CrazyEightsState) IsStarted() bool {
+	return ces.Started
+}
+
+func (ces *CrazyEightsState) IsFinished() bool {
+	return ces.Finished
+}
+
+// The deck is shuffled from the seed's first round, so that revealing it
+// doesn't give away the secret.
+func (ces *CrazyEightsState) SetShuffleSeed(seed *ShuffleSeed) error {
+	if ces.Started {
+		return errors.New("unable to set the shuffle seed after the game has started")
+	}
+
+	ces.Deck.Seed = seed.Derive(1)
+	return nil
+}
+
+// The deck's seed, once the game is over.
+func (ces *CrazyEightsState) RevealedSeeds() []*ShuffleSeed {
+	if !ces.Finished || ces.Deck.Seed == nil {
+		return []*ShuffleSeed{}
+	}
+
+	return []*ShuffleSeed{ces.Deck.Seed}
+}
+
+func (ces *CrazyEightsState) ResetStatus() {
+	ces.Started = false
+	ces.Finished = false
+}
+
+// Number of decks to play with: either as configured, or enough that at least
+// half of the cards remain to draw from after dealing.
+func (ces *CrazyEightsState) numDecks() int {
+	if ces.Config.NumDecks > 0 {
+		return ces.Config.NumDecks
+	}
+
+	var decks = 1
+	for decks*52 < 2*ces.Config.NumPlayers*ces.Config.HandSize {
+		decks += 1
+	}
+
+	return decks
+}
+
+func (ces *CrazyEightsState) Start(players int) error {
+	var err error
+
+	if ces.Started {
+		log.Println("Error! Double start occurred...", err)
+		return errors.New("double start occurred")
+	}
+
+	ces.Config.NumPlayers = players
+	err = figgy.Validate(ces.Config)
+	if err != nil {
+		log.Println("Err with CrazyEightsConfig after starting: ", err)
+		return err
+	}
+
+	if ces.numDecks()*52 <= players*ces.Config.HandSize {
+		return errors.New("not enough cards to deal to everyone; add more decks")
+	}
+
+	// Create all of the players.
+	ces.Players = make([]CrazyEightsPlayer, ces.Config.NumPlayers)
+	for index := range ces.Players {
+		ces.Players[index].Init()
+	}
+
+	ces.Deck.Init()
+	for deck := 0; deck < ces.numDecks(); deck++ {
+		ces.Deck.AddStandard52Deck()
+	}
+	ces.Deck.Shuffle()
+	ces.InitialDeck = CopyDeck(ces.Deck.Cards)
+
+	// Deal out all cards, starting with the first player.
+	for round := 0; round < ces.Config.HandSize; round++ {
+		for player_index := range ces.Players {
+			ces.Players[player_index].Hand = append(ces.Players[player_index].Hand, *ces.Deck.Draw())
+		}
+	}
+
+	// Turn over the first card of the discard pile. Any special effect it
+	// might have is ignored.
+	var starter = ces.Deck.Draw()
+	ces.Discard = []Card{*starter}
+	ces.Suit = starter.Suit
+	ces.Moves = make([]CrazyEightsMove, 0)
+
+	ces.Turn = 0
+	ces.Direction = 1
+	ces.Started = true
+	return nil
+}
+
+func (ces *CrazyEightsState) checkTurn(player int) error {
+	if !ces.Started {
+		return errors.New("game hasn't started yet")
+	}
+
+	if ces.Finished {
+		return errors.New("game has already finished")
+	}
+
+	if player < 0 || player >= len(ces.Players) {
+		return errors.New("not a valid player identifier: " + strconv.Itoa(player))
+	}
+
+	if ces.Turn != player {
+		return errors.New("not your turn")
+	}
+
+	return nil
+}
+
+func (ces *CrazyEightsState) isWild(card Card) bool {
+	return ces.Config.WildEights && card.Rank == EightRank
+}
+
+// Whether this card may be played on the discard pile.
+func (ces *CrazyEightsState) CanPlay(card Card) bool {
+	var top = ces.Discard[len(ces.Discard)-1]
+	return ces.isWild(card) || card.Suit == ces.Suit || card.Rank == top.Rank
+}
+
+// Draw a card from the draw pile, shuffling the discard pile (other than the
+// top card) back in when it runs out. Returns nil when there's nothing left.
+func (ces *CrazyEightsState) drawCard() *Card {
+	if len(ces.Deck.Cards) == 0 && len(ces.Discard) > 1 {
+		for index := range ces.Discard[:len(ces.Discard)-1] {
+			ces.Deck.Cards = append(ces.Deck.Cards, ces.Discard[index].Copy())
+		}
+
+		ces.Discard = ces.Discard[len(ces.Discard)-1:]
+		ces.Deck.Shuffle()
+	}
+
+	return ces.Deck.Draw()
+}
+
+// Move on to the next player, skipping the given number of players first.
+func (ces *CrazyEightsState) advance(skip int) {
+	var count = len(ces.Players)
+	ces.Players[ces.Turn].Drawn = false
+	ces.Turn = ((ces.Turn+ces.Direction*(1+skip))%count + count) % count
+}
+
+func (ces *CrazyEightsState) DrawCard(player int) error {
+	if err := ces.checkTurn(player); err != nil {
+		return err
+	}
+
+	if ces.Players[player].Drawn {
+		return errors.New("already drew a card this turn; play or pass")
+	}
+
+	var card = ces.drawCard()
+	if card == nil {
+		return errors.New("no cards left to draw; pass instead")
+	}
+
+	ces.Players[player].Hand = append(ces.Players[player].Hand, *card)
+	ces.Players[player].Drawn = true
+	ces.Moves = append(ces.Moves, CrazyEightsMove{Player: player, Drew: 1})
+	return nil
+}
+
+// End a turn without playing, after drawing a card (or when there's nothing
+// left to draw).
+func (ces *CrazyEightsState) Pass(player int) error {
+	if err := ces.checkTurn(player); err != nil {
+		return err
+	}
+
+	if !ces.Players[player].Drawn && len(ces.Deck.Cards)+len(ces.Discard) > 1 {
+		return errors.New("must draw a card before passing")
+	}
+
+	ces.Moves = append(ces.Moves, CrazyEightsMove{Player: player, Passed: true})
+	ces.advance(0)
+	return nil
+}
+
+func (ces *CrazyEightsState) TurnClocks() TurnClocks {
+	return newTurnClocks(ces.Config.TurnClock, ces.Config.GameClock, ces.Config.ClockPolicy)
+}
+
+func (ces *CrazyEightsState) ClockedPlayer() int {
+	if !ces.Started || ces.Finished {
+		return -1
+	}
+
+	return ces.Turn
+}
+
+func (ces *CrazyEightsState) Timeout(player int, policy ClockPolicy) error {
+	if err := ces.checkTurn(player); err != nil {
+		return err
+	}
+
+	if policy == SkipOnTimeout {
+		ces.Moves = append(ces.Moves, CrazyEightsMove{Player: player, Passed: true})
+		ces.advance(0)
+		return nil
+	}
+
+	if policy == ForfeitOnTimeout {
+		// Whoever else is closest to going out wins.
+		var winner = -1
+		for index, indexed_player := range ces.Players {
+			if index != player && (winner == -1 || len(indexed_player.Hand) < len(ces.Players[winner].Hand)) {
+				winner = index
+			}
+		}
+
+		ces.Winner = winner
+		ces.Finished = true
+		ces.Turn = -1
+		return errors.New(CrazyEightsGameOver)
+	}
+
+	// Wild eights name their own suit.
+	var play = func(card int) error {
+		index, _ := ces.Players[player].FindCard(card)
+		return ces.PlayCard(player, card, ces.Players[player].Hand[index].Suit)
+	}
+
+	if err := playLowestCard(ces.Players[player].Hand, false, play); err == nil || isRoundSignal(err) {
+		return err
+	}
+
+	if !ces.Players[player].Drawn && ces.DrawCard(player) == nil {
+		if err := playLowestCard(ces.Players[player].Hand, false, play); err == nil || isRoundSignal(err) {
+			return err
+		}
+	}
+
+	return ces.Pass(player)
+}
+
+// Play a card from hand; suit is the suit named when playing a wild eight.
+func (ces *CrazyEightsState) PlayCard(player int, cardID int, suit CardSuit) error {
+	if err := ces.checkTurn(player); err != nil {
+		return err
+	}
+
+	index, found := ces.Players[player].FindCard(cardID)
+	if !found {
+		return errors.New("unable to play card not in hand")
+	}
+
+	played := ces.Players[player].Hand[index]
+	if !ces.CanPlay(played) {
+		return errors.New("card must match the suit or rank of the top card")
+	}
+
+	if ces.isWild(played) {
+		if suit < ClubsSuit || suit > DiamondsSuit {
+			return errors.New("must name a suit when playing a wild eight")
+		}
+	} else {
+		suit = played.Suit
+	}
+
+	ces.Players[player].RemoveCard(cardID)
+	ces.Discard = append(ces.Discard, played)
+	ces.Suit = suit
+
+	var move = CrazyEightsMove{Player: player, Card: played.Copy(), Suit: suit}
+	ces.Moves = append(ces.Moves, move)
+
+	if len(ces.Players[player].Hand) == 0 {
+		ces.Winner = player
+		ces.Finished = true
+		ces.Turn = -1
+		return errors.New(CrazyEightsGameOver)
+	}
+
+	var skip = 0
+	if ces.Config.Skips && played.Rank == QueenRank {
+		skip = 1
+	}
+
+	if ces.Config.Reverses && played.Rank == AceRank {
+		if len(ces.Players) == 2 {
+			// With two players, reversing acts like a skip.
+			skip = 1
+		} else {
+			ces.Direction = -ces.Direction
+		}
+	}
+
+	if ces.Config.DrawTwos && played.Rank == TwoRank {
+		// The next player draws two and misses their turn.
+		ces.advance(0)
+		var victim = ces.Turn
+		var drew = 0
+		for count := 0; count < 2; count++ {
+			if card := ces.drawCard(); card != nil {
+				ces.Players[victim].Hand = append(ces.Players[victim].Hand, *card)
+				drew += 1
+			}
+		}
+
+		ces.Moves = append(ces.Moves, CrazyEightsMove{Player: victim, Drew: drew})
+	}
+
+	ces.advance(skip)
+	return nil
+}
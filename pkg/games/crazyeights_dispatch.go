@@ -0,0 +1,278 @@
+package games
+
+import (
+	"encoding/json"
+	"errors"
+
+	"git.cipherboy.com/WillowPatchGames/wpg/pkg/middleware/figgy"
+)
+
+// Crazy Eights message types:
+//
+// 1. DrawCard
+// 2. Pass
+// 3. PlayCard
+// 4. Timeout (sent by the server when a player runs out of time)
+
+type CrazyEightsPlayMsg struct {
+	MessageHeader
+	CardID int      `json:"card_id"`
+	Suit   CardSuit `json:"suit"` // Suit named when playing a wild eight.
+}
+
+func (c *Controller) dispatchCrazyEights(message []byte, header MessageHeader, game *GameData, player *PlayerData, sid uint64) error {
+	var err error
+	var state *CrazyEightsState = game.State.(*CrazyEightsState)
+	if state == nil {
+		panic("internal state is nil; this shouldn't happen when the game is started")
+	}
+
+	var was_finished = state.Finished
+	var send_synopsis = false
+	var send_state = false
+
+	switch header.MessageType {
+	case "start":
+		if player.UID != game.Owner {
+			return errors.New("unable to start game that you're not the owner of")
+		}
+
+		var players int = 0
+		for _, player := range game.ToPlayer {
+			if player.Playing {
+				// When we click the start button again, say, after a user has come
+				// back to being active, Countback will be higher than 0, because we've
+				// already attempted to set this.
+				player.Countback = 0
+				players += 1
+			}
+		}
+
+		state.Config.NumPlayers = players
+		if err = figgy.Validate(state.Config); err != nil {
+			return err
+		}
+
+		if state.Config.Countdown {
+			game.Countdown = 0
+			game.CountdownTimer = nil
+
+			return c.handleCountdown(game)
+		} else {
+			return c.doCrazyEightsStart(game, state)
+		}
+	case "cancel":
+		if player.UID != game.Owner {
+			return errors.New("unable to cancel game that you're not the owner of")
+		}
+
+		if !state.Config.Countdown {
+			return errors.New("unable to cancel game that doesn't use a countdown")
+		}
+
+		if state.Started || state.Finished {
+			return errors.New("unable to cancel game that is already started")
+		}
+
+		game.Countdown = 0
+		game.CountdownTimer = nil
+	case "join":
+		if state.Started && !state.Finished {
+			var started ControllerNotifyStarted
+			started.LoadFromController(game, player)
+			started.ReplyTo = header.MessageID
+			c.undispatch(game, player, started.MessageID, started.ReplyTo, started)
+
+			if player.Playing && player.Index >= 0 {
+				var response CrazyEightsStateNotification
+				response.LoadData(game, state, player)
+				c.undispatch(game, player, response.MessageID, 0, response)
+
+				send_synopsis = true
+			}
+		} else if state.Finished {
+			var finished CrazyEightsFinishedNotification
+			finished.LoadData(game, state, player)
+			finished.ReplyTo = header.MessageID
+			c.undispatch(game, player, finished.MessageID, finished.ReplyTo, finished)
+			send_synopsis = true
+		}
+	case "draw":
+		err = state.DrawCard(player.Index)
+		send_synopsis = err == nil
+		send_state = err == nil
+	case "pass":
+		err = state.Pass(player.Index)
+		send_synopsis = err == nil
+		send_state = err == nil
+	case "play":
+		var data CrazyEightsPlayMsg
+		if err = json.Unmarshal(message, &data); err != nil {
+			return err
+		}
+
+		err = state.PlayCard(player.Index, data.CardID, data.Suit)
+		send_synopsis = true
+		send_state = true
+	case "timeout":
+		var data GameTimeout
+		if err = json.Unmarshal(message, &data); err != nil {
+			return err
+		}
+
+		err = state.Timeout(player.Index, data.Policy)
+		send_synopsis = true
+		send_state = true
+	case "peek":
+		if player.Index != -1 && !state.Finished {
+			return errors.New("can only peek once game is complete")
+		}
+
+		var response CrazyEightsPeekNotification
+		response.LoadData(game, state, player)
+		response.ReplyTo = header.MessageID
+		c.undispatch(game, player, response.MessageID, header.MessageID, response)
+
+		var synopsis CrazyEightsSynopsisNotification
+		synopsis.LoadData(game, state, player)
+		c.undispatch(game, player, synopsis.MessageID, 0, synopsis)
+	default:
+		return errors.New("unknown message_type issued to crazy eights game: " + header.MessageType)
+	}
+
+	// If this game ended during this dispatch call, notify everyone.
+	if !was_finished && state.Finished {
+		// Notify everyone that the game ended and who won.
+		for _, indexed_player := range game.ToPlayer {
+			var finished CrazyEightsFinishedNotification
+			finished.LoadData(game, state, indexed_player)
+			c.undispatch(game, indexed_player, finished.MessageID, 0, finished)
+		}
+	}
+
+	// If someone changed something, notify everyone.
+	if send_synopsis {
+		for _, indexed_player := range game.ToPlayer {
+			var synopsis CrazyEightsSynopsisNotification
+			synopsis.LoadData(game, state, indexed_player)
+			c.undispatch(game, indexed_player, synopsis.MessageID, 0, synopsis)
+		}
+	}
+
+	// If the state changed for a bunch of people, notify them all.
+	if send_state {
+		for _, indexed_player := range game.ToPlayer {
+			if !indexed_player.Admitted {
+				continue
+			}
+
+			if indexed_player.Playing {
+				var response CrazyEightsStateNotification
+				response.LoadData(game, state, indexed_player)
+				if indexed_player.UID == player.UID && err == nil {
+					response.ReplyTo = header.MessageID
+				}
+
+				c.undispatch(game, indexed_player, response.MessageID, response.ReplyTo, response)
+			} else {
+				var response CrazyEightsPeekNotification
+				response.LoadData(game, state, indexed_player)
+				c.undispatch(game, indexed_player, response.MessageID, 0, response)
+			}
+		}
+	}
+
+	return err
+}
+
+func (c *Controller) doCrazyEightsStart(game *GameData, state *CrazyEightsState) error {
+	// First count the number of people playing.
+	var players int = 0
+	for _, player := range game.ToPlayer {
+		if player.Playing {
+			players += 1
+		}
+	}
+
+	// Then start the underlying Crazy Eights game to populate game data.
+	if err := state.Start(players); err != nil {
+		return err
+	}
+
+	// Assign indices to players before sending notifications.
+	seatPlayers(game)
+
+	// Send out initial state data to individuals who are playing. Also notify
+	// all players that the game has started.
+	for _, indexed_player := range game.ToPlayer {
+		if !indexed_player.Admitted {
+			continue
+		}
+
+		// Tell everyone interested that the game has started.
+		var started ControllerNotifyStarted
+		started.LoadFromController(game, indexed_player)
+		c.undispatch(game, indexed_player, started.MessageID, started.ReplyTo, started)
+
+		// Only send state to players who are playing initially. Everyone else
+		// (namely, admitted spectators) should send a peek event before they can
+		// view the table.
+		if indexed_player.Playing {
+			var response CrazyEightsStateNotification
+			response.LoadData(game, state, indexed_player)
+			c.undispatch(game, indexed_player, response.MessageID, 0, response)
+		}
+
+		// Give everyone the initial synopsis.
+		var synopsis CrazyEightsSynopsisNotification
+		synopsis.LoadData(game, state, indexed_player)
+		c.undispatch(game, indexed_player, synopsis.MessageID, 0, synopsis)
+	}
+
+	return nil
+}
+
+// crazyeightsEngine registers Crazy Eights with the controller; see GameEngine.
+type crazyeightsEngine struct{}
+
+func init() {
+	MustRegisterGameEngine(crazyeightsEngine{})
+}
+
+func (crazyeightsEngine) Mode() GameMode {
+	return CrazyEightsGame
+}
+
+func (crazyeightsEngine) Name() string {
+	return "crazy eights"
+}
+
+func (crazyeightsEngine) Title() string {
+	return "Crazy Eights (Card Game)"
+}
+
+func (crazyeightsEngine) Description() string {
+	return "In Crazy Eights, match the suit or rank of the top card to be the first to empty your hand. Eights are wild, and optional skips, reverses and draw twos keep everyone on their toes!"
+}
+
+func (crazyeightsEngine) EmptyConfig() figgy.Figgurable {
+	return &CrazyEightsConfig{}
+}
+
+func (crazyeightsEngine) NewState() ConfigurableState {
+	return &CrazyEightsState{}
+}
+
+func (crazyeightsEngine) Init(config figgy.Figgurable) (ConfigurableState, error) {
+	var asserted *CrazyEightsConfig = config.(*CrazyEightsConfig)
+	var state = &CrazyEightsState{}
+	return state, state.Init(*asserted)
+}
+
+func (crazyeightsEngine) Dispatch(c *Controller, message []byte, header MessageHeader, game *GameData, player *PlayerData, sid uint64) error {
+	return c.dispatchCrazyEights(message, header, game, player, sid)
+}
+
+func (crazyeightsEngine) Start(c *Controller, game *GameData) error {
+	return c.doCrazyEightsStart(game, game.State.(*CrazyEightsState))
+}
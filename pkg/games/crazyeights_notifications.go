@@ -0,0 +1,154 @@
+package games
+
+type CrazyEightsPlayerState struct {
+	Hand []Card `json:"hand"`
+
+	Drawn bool `json:"drawn"`
+}
+
+type CrazyEightsGameState struct {
+	Turn      uint64 `json:"turn"`
+	Direction int    `json:"direction"`
+
+	Top         Card     `json:"top"`
+	Suit        CardSuit `json:"suit"`
+	DrawPile    int      `json:"draw_pile"`    // Number of cards left to draw.
+	DiscardPile int      `json:"discard_pile"` // Number of cards in the discard pile.
+	Commitment  string   `json:"commitment"`   // To the deck's shuffle seed; see ShuffleSeed.
+
+	Config CrazyEightsConfig `json:"config"`
+
+	Started  bool `json:"started"`
+	Finished bool `json:"finished"`
+}
+
+func (cgs *CrazyEightsGameState) loadGame(data *GameData, game *CrazyEightsState) {
+	cgs.Turn, _ = data.ToUserID(game.Turn)
+	cgs.Direction = game.Direction
+
+	if len(game.Discard) > 0 {
+		cgs.Top = game.Discard[len(game.Discard)-1]
+	}
+	cgs.Suit = game.Suit
+	cgs.DrawPile = len(game.Deck.Cards)
+	cgs.DiscardPile = len(game.Discard)
+	cgs.Commitment = game.Deck.Commitment()
+
+	cgs.Config = game.Config
+
+	cgs.Started = game.Started
+	cgs.Finished = game.Finished
+}
+
+type CrazyEightsStateNotification struct {
+	MessageHeader
+	CrazyEightsPlayerState
+	CrazyEightsGameState
+}
+
+func (csn *CrazyEightsStateNotification) LoadData(data *GameData, game *CrazyEightsState, player *PlayerData) {
+	csn.LoadHeader(data, player)
+	csn.MessageType = "state"
+
+	csn.Hand = game.Players[player.Index].Hand
+	csn.Drawn = game.Players[player.Index].Drawn
+
+	csn.loadGame(data, game)
+}
+
+type CrazyEightsPlayerSynopsis struct {
+	UID         uint64 `json:"user"`
+	Playing     bool   `json:"playing"`
+	PlayerIndex int    `json:"player_index"`
+
+	IsTurn    bool `json:"is_turn"`
+	CardsLeft int  `json:"cards_left"`
+}
+
+type CrazyEightsSynopsisNotification struct {
+	MessageHeader
+
+	Players []CrazyEightsPlayerSynopsis `json:"players"`
+
+	Top       Card     `json:"top"`
+	Suit      CardSuit `json:"suit"`
+	Direction int      `json:"direction"`
+}
+
+func (csn *CrazyEightsSynopsisNotification) LoadData(data *GameData, state *CrazyEightsState, player *PlayerData) {
+	csn.LoadHeader(data, player)
+	csn.MessageType = "synopsis"
+
+	for _, indexed_player := range data.ToPlayer {
+		var synopsis CrazyEightsPlayerSynopsis
+		synopsis.UID = indexed_player.UID
+		synopsis.Playing = indexed_player.Playing
+		synopsis.PlayerIndex = indexed_player.Index
+
+		if indexed_player.Index >= 0 && indexed_player.Index < len(state.Players) {
+			synopsis.IsTurn = indexed_player.Index == state.Turn
+			synopsis.CardsLeft = len(state.Players[indexed_player.Index].Hand)
+		}
+
+		csn.Players = append(csn.Players, synopsis)
+	}
+
+	if len(state.Discard) > 0 {
+		csn.Top = state.Discard[len(state.Discard)-1]
+	}
+	csn.Suit = state.Suit
+	csn.Direction = state.Direction
+}
+
+type CrazyEightsPeekNotification struct {
+	MessageHeader
+
+	PlayerMapping []uint64 `json:"player_mapping"`
+
+	// Info for Ended Games (Everyone)
+	Moves []CrazyEightsMove `json:"moves,omitempty"`
+	Hands [][]Card          `json:"hands,omitempty"`
+	Deck  []*Card           `json:"deck,omitempty"`
+	Seed  *ShuffleSeed      `json:"seed,omitempty"`
+
+	// Info for Active Games (Spectators)
+	CrazyEightsGameState
+
+	Winner uint64 `json:"winner"`
+}
+
+func (cpn *CrazyEightsPeekNotification) LoadData(data *GameData, game *CrazyEightsState, player *PlayerData) {
+	cpn.LoadHeader(data, player)
+	cpn.MessageType = "game-state"
+
+	for index := range game.Players {
+		player_uid, _ := data.ToUserID(index)
+		cpn.PlayerMapping = append(cpn.PlayerMapping, player_uid)
+	}
+
+	cpn.loadGame(data, game)
+
+	if game.Finished {
+		cpn.Moves = game.Moves
+		for _, indexed_player := range game.Players {
+			cpn.Hands = append(cpn.Hands, indexed_player.Hand)
+		}
+		cpn.Deck = game.InitialDeck
+		cpn.Seed = game.Deck.Seed
+	}
+
+	cpn.Winner, _ = data.ToUserID(game.Winner)
+}
+
+type CrazyEightsFinishedNotification struct {
+	MessageHeader
+
+	Winner uint64 `json:"winner"`
+}
+
+func (cfn *CrazyEightsFinishedNotification) LoadData(data *GameData, state *CrazyEightsState, player *PlayerData) {
+	cfn.LoadHeader(data, player)
+	cfn.MessageType = "finished"
+
+	cfn.Winner, _ = data.ToUserID(state.Winner)
+}
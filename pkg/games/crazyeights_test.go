@@ -0,0 +1,128 @@
+package games
+
+import (
+	"testing"
+)
+
+func TestCrazyEightsSpecialCards(t *testing.T) {
+	var state CrazyEightsState
+	if err := state.Init(CrazyEightsConfig{NumPlayers: 4, HandSize: 5, WildEights: true, Skips: true, Reverses: true, DrawTwos: true}); err != nil {
+		t.Fatal("Unable to initialize game:", err)
+	}
+
+	if err := state.Start(4); err != nil {
+		t.Fatal("Unable to start game:", err)
+	}
+
+	// Give the first player a known hand to play from.
+	state.Discard = []Card{{100, ClubsSuit, FiveRank}}
+	state.Suit = ClubsSuit
+	state.Players[0].Hand = []Card{{101, HeartsSuit, EightRank}, {102, HeartsSuit, QueenRank}, {103, HeartsSuit, AceRank}, {104, SpadesSuit, SixRank}}
+	state.Players[1].Hand = []Card{{105, HeartsSuit, TwoRank}, {106, HeartsSuit, ThreeRank}}
+
+	if err := state.PlayCard(0, 104, NoneSuit); err == nil {
+		t.Fatal("Expected playing a card not matching suit or rank to fail")
+	}
+
+	if err := state.PlayCard(0, 101, NoneSuit); err == nil {
+		t.Fatal("Expected playing a wild eight without naming a suit to fail")
+	}
+
+	if err := state.PlayCard(0, 101, HeartsSuit); err != nil || state.Suit != HeartsSuit || state.Turn != 1 {
+		t.Fatal("Unable to play wild eight:", err)
+	}
+
+	// A two makes the next player draw two and miss their turn.
+	var before = len(state.Players[2].Hand)
+	if err := state.PlayCard(1, 105, NoneSuit); err != nil {
+		t.Fatal("Unable to play draw two:", err)
+	}
+
+	if len(state.Players[2].Hand) != before+2 || state.Turn != 3 {
+		t.Fatal("Expected the next player to draw two and be skipped")
+	}
+
+	// Back around to the first player: a queen skips, an ace reverses.
+	state.Turn = 0
+	if err := state.PlayCard(0, 102, NoneSuit); err != nil || state.Turn != 2 {
+		t.Fatal("Expected queen to skip the next player:", err, state.Turn)
+	}
+
+	state.Turn = 0
+	if err := state.PlayCard(0, 103, NoneSuit); err != nil || state.Turn != 3 || state.Direction != -1 {
+		t.Fatal("Expected ace to reverse direction:", err, state.Turn)
+	}
+
+	if err := state.Pass(3); err == nil {
+		t.Fatal("Expected passing without drawing to fail")
+	}
+
+	if err := state.DrawCard(3); err != nil {
+		t.Fatal("Unable to draw:", err)
+	}
+
+	if err := state.DrawCard(3); err == nil {
+		t.Fatal("Expected drawing twice to fail")
+	}
+
+	if err := state.Pass(3); err != nil || state.Turn != 2 {
+		t.Fatal("Unable to pass after drawing:", err)
+	}
+}
+
+func TestCrazyEightsGame(t *testing.T) {
+	for _, config := range []CrazyEightsConfig{
+		{NumPlayers: 2, HandSize: 7, WildEights: true},
+		{NumPlayers: 8, HandSize: 7, WildEights: true, Skips: true, Reverses: true, DrawTwos: true},
+	} {
+		var state CrazyEightsState
+		if err := state.Init(config); err != nil {
+			t.Fatal("Unable to initialize game:", err)
+		}
+
+		if err := state.Start(config.NumPlayers); err != nil {
+			t.Fatal("Unable to start game:", err)
+		}
+
+		var cards = len(state.Deck.Cards) + len(state.Discard)
+		for _, indexed_player := range state.Players {
+			cards += len(indexed_player.Hand)
+		}
+
+		if cards != state.numDecks()*52 || (config.NumPlayers == 8 && state.numDecks() != 3) {
+			t.Fatal("Expected to play with all cards from", state.numDecks(), "decks:", cards)
+		}
+
+		for move := 0; move < 10000 && !state.Finished; move++ {
+			var player = state.Turn
+			var played = false
+			for _, card := range state.Players[player].Hand {
+				if state.CanPlay(card) {
+					err := state.PlayCard(player, card.ID, HeartsSuit)
+					if err != nil && err.Error() != CrazyEightsGameOver {
+						t.Fatal("Unable to play:", err)
+					}
+
+					played = true
+					break
+				}
+			}
+
+			if played {
+				continue
+			}
+
+			if err := state.DrawCard(player); err == nil {
+				continue
+			}
+
+			if err := state.Pass(player); err != nil {
+				t.Fatal("Unable to pass:", err)
+			}
+		}
+
+		if !state.Finished || len(state.Players[state.Winner].Hand) != 0 {
+			t.Fatal("Expected game to finish with a winner")
+		}
+	}
+}
@@ -0,0 +1,679 @@
+package games
+
+import (
+	"errors"
+	"log"
+	"strconv"
+
+	"git.cipherboy.com/WillowPatchGames/wpg/pkg/middleware/figgy"
+)
+
+const CribbageGameOver string = "game is over"
+const CribbageNextRound string = "begin next round"
+
+type CribbagePlayer struct {
+	Hand []Card `json:"hand"` // Cards not yet pegged.
+	Kept []Card `json:"kept"` // Cards left after discarding to the crib, for the show.
+
+	Discarded bool `json:"discarded"`
+}
+
+func (cp *CribbagePlayer) Init() {
+	cp.Hand = make([]Card, 0)
+	cp.Kept = make([]Card, 0)
+}
+
+func (cp *CribbagePlayer) FindCard(cardID int) (int, bool) {
+	return FindCard(cp.Hand, cardID)
+}
+
+func (cp *CribbagePlayer) RemoveCard(cardID int) bool {
+	var ret bool
+	_, cp.Hand, ret = RemoveCard(cp.Hand, cardID)
+	return ret
+}
+
+type CribbageConfig struct {
+	NumPlayers int `json:"num_players" config:"type:int,min:2,default:2,max:4" label:"Number of players"` // Two or three play alone; four play as partners.
+
+	Muggins bool `json:"muggins" config:"type:bool,default:false" label:"true:Players count their own hands and opponents take missed points (muggins),false:Count hands automatically"` // Whether players have to claim their own points.
+
+	WinAmount int `json:"win_amount" config:"type:enum,default:121,options:61:61 Points;121:121 Points" label:"Ending amount"` // Number of points to end the game at.
+
+	// Common game configuration options
+	Countdown bool `json:"countdown" config:"type:bool,default:true" label:"true:Show a 3... 2... 1... countdown before beginning,false:Start the game instantly"` // Whether to wait and send countdown messages.
+
+	// Chess clock style limits on how long each player may take.
+	TurnClock   int `json:"turn_clock" config:"type:int,min:0,default:0,max:600" label:"Seconds per turn (0 for no limit)"`
+	GameClock   int `json:"game_clock" config:"type:int,min:0,default:0,max:120" label:"Minutes per player for the whole game (0 for no limit)"`
+	ClockPolicy int `json:"clock_policy" config:"type:enum,default:0,options:0:Play automatically;2:Forfeit the game" label:"When a player runs out of time"` // See ClockPolicy.
+}
+
+func (cfg CribbageConfig) Validate() error {
+	return nil
+}
+
+// A single card played while pegging, or a go when Card is nil.
+type CribbagePeg struct {
+	Player int   `json:"player"`
+	Card   *Card `json:"card"`
+	Count  int   `json:"count"`
+	Points int   `json:"points"`
+}
+
+// A single hand (or the crib) counted during the show.
+type CribbageShow struct {
+	Player  int    `json:"player"`
+	Crib    bool   `json:"crib"`
+	Hand    []Card `json:"hand"`
+	Points  int    `json:"points"`
+	Claimed int    `json:"claimed"`
+	Muggins int    `json:"muggins"` // Points missed by the player and taken by an opponent.
+}
+
+type CribbageRound struct {
+	Dealer  int          `json:"dealer"`
+	Deck    []*Card      `json:"deck"`
+	Seed    *ShuffleSeed `json:"seed"`
+	Hands   [][]Card     `json:"hands"`
+	Crib    []Card       `json:"crib"`
+	Starter *Card        `json:"starter"`
+	Heels   bool         `json:"heels"`
+
+	Pegging []CribbagePeg  `json:"pegging"`
+	Shows   []CribbageShow `json:"shows"`
+
+	Scores []int `json:"scores"` // By side.
+}
+
+type CribbageState struct {
+	Turn   int `json:"turn"`
+	Dealer int `json:"dealer"`
+
+	Deck    Deck             `json:"deck"`
+	Players []CribbagePlayer `json:"players"` // Left of dealer is found by incrementing one.
+	Crib    []Card           `json:"crib"`
+	Starter *Card            `json:"starter"`
+
+	Count      int    `json:"count"`       // Running count while pegging.
+	Pile       []Card `json:"pile"`        // Cards played since the count was last reset.
+	LastPlayer int    `json:"last_player"` // Who played the most recent card, for scoring a go.
+	Showing    int    `json:"showing"`     // Index into the order hands are shown in; the crib is last.
+
+	Scores       []int            `json:"scores"` // By side; see Side().
+	RoundHistory []*CribbageRound `json:"round_history"`
+	Seed         *ShuffleSeed     `json:"seed,omitempty"` // Secret each round's shuffle seed derives from; when unset, every round gets a fresh seed.
+
+	Config CribbageConfig `json:"config"`
+
+	Assigned bool  `json:"assigned"` // The owner has picked where everyone sits.
+	Started  bool  `json:"started"`
+	Dealt    bool  `json:"dealt"`
+	Cut      bool  `json:"cut"`    // Everyone has discarded and the starter is turned up.
+	Pegged   bool  `json:"pegged"` // All cards have been played; hands are being shown.
+	Finished bool  `json:"finished"`
+	Winners  []int `json:"winners"`
+}
+
+func (cs *CribbageState) Init(cfg CribbageConfig) error {
+	var err error = figgy.Validate(cfg)
+	if err != nil {
+		log.Println("Error with CribbageConfig", err)
+		return err
+	}
+
+	cs.Config = cfg
+	cs.Turn = -1
+	cs.Dealer = -1
+	cs.LastPlayer = -1
+	cs.Showing = -1
+	cs.Started = false
+	cs.Finished = false
+	cs.Winners = make([]int, 0)
+
+	return nil
+}
+
+func (cs *CribbageState) GetConfiguration() figgy.Figgurable {
+	return cs.Config
+}
+
+func (cs *CribbageState) ReInit() error {
+	// No-op for now. Nothing needs to be re-initialized after reloading
+	// from JSON serialization.
+	return nil
+}
+
+func (cs *CribbageState) IsStarted() bool {
+	return cs.Started
+}
+
+func (cs *CribbageState) IsFinished() bool {
+	return cs.Finished
+}
+
+func (cs *CribbageState) SetShuffleSeed(seed *ShuffleSeed) error {
+	if cs.Started {
+		return errors.New("unable to set the shuffle seed after the game has started")
+	}
+
+	cs.Seed = seed
+	return nil
+}
+
+func (cs *CribbageState) RevealedSeeds() []*ShuffleSeed {
+	var seeds = make([]*ShuffleSeed, 0, len(cs.RoundHistory))
+	for _, round := range cs.RoundHistory {
+		seeds = append(seeds, round.Seed)
+	}
+
+	return revealedRoundSeeds(seeds, cs.Dealt && !cs.Finished)
+}
+
+func (cs *CribbageState) ResetStatus() {
+	cs.Started = false
+	cs.Finished = false
+}
+
+// Which side a player scores for: with four players, partners sit across
+// from each other and share a score.
+func (cs *CribbageState) Side(player int) int {
+	if len(cs.Players) == 4 {
+		return player % 2
+	}
+
+	return player
+}
+
+func (cs *CribbageState) sides() int {
+	if len(cs.Players) == 4 {
+		return 2
+	}
+
+	return len(cs.Players)
+}
+
+func (cs *CribbageState) handSize() int {
+	if len(cs.Players) == 2 {
+		return 6
+	}
+
+	return 5
+}
+
+// Number of cards each player contributes to the crib.
+func (cs *CribbageState) discardSize() int {
+	if len(cs.Players) == 2 {
+		return 2
+	}
+
+	return 1
+}
+
+func (cs *CribbageState) TurnClocks() TurnClocks {
+	return newTurnClocks(cs.Config.TurnClock, cs.Config.GameClock, cs.Config.ClockPolicy)
+}
+
+func (cs *CribbageState) ClockedPlayer() int {
+	// Only pegging is clocked; everyone discards to the crib at once.
+	if !cs.Started || cs.Finished || !cs.Dealt || !cs.Cut || cs.Pegged {
+		return -1
+	}
+
+	return cs.Turn
+}
+
+func (cs *CribbageState) Timeout(player int, policy ClockPolicy) error {
+	if player != cs.ClockedPlayer() {
+		return errors.New("not your turn")
+	}
+
+	if policy == ForfeitOnTimeout {
+		return cs.forfeit(player)
+	}
+
+	return playLowestCard(cs.Players[player].Hand, false, func(card int) error {
+		return cs.PlayCard(player, card)
+	})
+}
+
+// End the game early, with the highest scoring of the other sides winning.
+func (cs *CribbageState) forfeit(player int) error {
+	var winner = -1
+	for side, score := range cs.Scores {
+		if side != cs.Side(player) && (winner == -1 || score > cs.Scores[winner]) {
+			winner = side
+		}
+	}
+
+	cs.Winners = make([]int, 0)
+	for index := range cs.Players {
+		if cs.Side(index) == winner {
+			cs.Winners = append(cs.Winners, index)
+		}
+	}
+
+	cs.Finished = true
+	cs.Turn = -1
+	cs.Dealer = -1
+	return errors.New(CribbageGameOver)
+}
+
+// Record that the owner seated num_players players, so the game can start.
+func (cs *CribbageState) AssignSeats(num_players int) error {
+	if cs.Started {
+		return errors.New("cannot assign seats after already started")
+	}
+
+	var config = cs.Config
+	config.NumPlayers = num_players
+	if err := figgy.Validate(config); err != nil {
+		return err
+	}
+
+	cs.Config = config
+	cs.Assigned = true
+	return nil
+}
+
+func (cs *CribbageState) Start(players int) error {
+	var err error
+
+	if cs.Started {
+		log.Println("Error! Double start occurred...", err)
+		return errors.New("double start occurred")
+	}
+
+	cs.Config.NumPlayers = players
+	err = figgy.Validate(cs.Config)
+	if err != nil {
+		log.Println("Err with CribbageConfig after starting: ", err)
+		return err
+	}
+
+	// Create all of the players.
+	cs.Players = make([]CribbagePlayer, cs.Config.NumPlayers)
+	for index := range cs.Players {
+		cs.Players[index].Init()
+	}
+
+	cs.Scores = make([]int, cs.sides())
+
+	// Force us to call StartRound() next.
+	cs.Dealt = false
+	cs.Dealer = 0
+
+	// Start the round: shuffle the cards and deal them out.
+	err = cs.StartRound()
+	if err != nil {
+		log.Println("Error starting round: ", err)
+		return err
+	}
+
+	cs.Started = true
+	return nil
+}
+
+func (cs *CribbageState) StartRound() error {
+	if cs.Dealt {
+		return errors.New("unable to call StartRound while we've already dealt")
+	}
+
+	// Start building history of moves.
+	cs.RoundHistory = append(cs.RoundHistory, &CribbageRound{})
+	history := cs.RoundHistory[len(cs.RoundHistory)-1]
+	history.Dealer = cs.Dealer
+	history.Pegging = make([]CribbagePeg, 0)
+	history.Shows = make([]CribbageShow, 0)
+
+	// Start with a clean deck and shuffle it.
+	cs.Deck.Init()
+	cs.Deck.AddStandard52Deck()
+	cs.Deck.Reseed(cs.Seed, len(cs.RoundHistory))
+	cs.Deck.Shuffle()
+
+	// Save the initial deck.
+	history.Deck = CopyDeck(cs.Deck.Cards)
+	history.Seed = cs.Deck.Seed.Copy()
+
+	// Clear out all round-specific status before each round.
+	for index := range cs.Players {
+		cs.Players[index].Hand = make([]Card, 0)
+		cs.Players[index].Kept = make([]Card, 0)
+		cs.Players[index].Discarded = false
+	}
+
+	cs.Crib = make([]Card, 0)
+	cs.Starter = nil
+	cs.Count = 0
+	cs.Pile = make([]Card, 0)
+	cs.LastPlayer = -1
+	cs.Showing = -1
+
+	// Deal out all cards, starting left of the dealer.
+	for round := 0; round < cs.handSize(); round++ {
+		for player_offset := 1; player_offset <= len(cs.Players); player_offset++ {
+			player_index := (cs.Dealer + player_offset) % len(cs.Players)
+			cs.Players[player_index].Hand = append(cs.Players[player_index].Hand, *cs.Deck.Draw())
+		}
+	}
+
+	// With three players, the crib gets a card of its own from the deck.
+	if len(cs.Players) == 3 {
+		cs.Crib = append(cs.Crib, *cs.Deck.Draw())
+	}
+
+	for _, indexed_player := range cs.Players {
+		history.Hands = append(history.Hands, CopyHand(indexed_player.Hand))
+	}
+
+	// Everyone discards at once.
+	cs.Turn = -1
+	cs.Dealt = true
+	cs.Cut = false
+	cs.Pegged = false
+
+	return nil
+}
+
+// Give points to a player's side, returning true when that ends the game.
+func (cs *CribbageState) award(player int, points int) bool {
+	var side = cs.Side(player)
+	cs.Scores[side] += points
+
+	if cs.Scores[side] < cs.Config.WinAmount {
+		return false
+	}
+
+	// The game ends as soon as anyone reaches the winning amount, even in the
+	// middle of pegging or counting.
+	cs.Winners = make([]int, 0)
+	for index := range cs.Players {
+		if cs.Side(index) == side {
+			cs.Winners = append(cs.Winners, index)
+		}
+	}
+
+	history := cs.RoundHistory[len(cs.RoundHistory)-1]
+	history.Scores = append([]int{}, cs.Scores...)
+
+	cs.Finished = true
+	cs.Turn = -1
+	cs.Dealer = -1
+	return true
+}
+
+func (cs *CribbageState) checkPlayer(player int) error {
+	if !cs.Started {
+		return errors.New("game hasn't started yet")
+	}
+
+	if cs.Finished {
+		return errors.New("game has already finished")
+	}
+
+	if player < 0 || player >= len(cs.Players) {
+		return errors.New("not a valid player identifier: " + strconv.Itoa(player))
+	}
+
+	if !cs.Dealt {
+		return errors.New("unable to play before dealing cards")
+	}
+
+	return nil
+}
+
+// Put cards from a player's hand into the dealer's crib. Once everyone has,
+// the starter is cut and pegging begins.
+func (cs *CribbageState) Discard(player int, cardIDs []int) error {
+	if err := cs.checkPlayer(player); err != nil {
+		return err
+	}
+
+	if cs.Players[player].Discarded {
+		return errors.New("already discarded to the crib")
+	}
+
+	if len(cardIDs) != cs.discardSize() {
+		return errors.New("must discard exactly " + strconv.Itoa(cs.discardSize()) + " cards to the crib")
+	}
+
+	for index, cardID := range cardIDs {
+		if _, found := cs.Players[player].FindCard(cardID); !found {
+			return errors.New("unable to discard card not in hand")
+		}
+
+		for _, otherID := range cardIDs[index+1:] {
+			if otherID == cardID {
+				return errors.New("unable to discard the same card twice")
+			}
+		}
+	}
+
+	for _, cardID := range cardIDs {
+		index, _ := cs.Players[player].FindCard(cardID)
+		cs.Crib = append(cs.Crib, cs.Players[player].Hand[index])
+		cs.Players[player].RemoveCard(cardID)
+	}
+
+	cs.Players[player].Discarded = true
+	cs.Players[player].Kept = CopyHand(cs.Players[player].Hand)
+
+	for _, indexed_player := range cs.Players {
+		if !indexed_player.Discarded {
+			return nil
+		}
+	}
+
+	// Cut the starter. A jack gives the dealer two points for his heels.
+	history := cs.RoundHistory[len(cs.RoundHistory)-1]
+	cs.Starter = cs.Deck.Draw()
+	cs.Cut = true
+	history.Crib = CopyHand(cs.Crib)
+	history.Starter = cs.Starter.Copy()
+
+	if cs.Starter.Rank == JackRank {
+		history.Heels = true
+		if cs.award(cs.Dealer, 2) {
+			return errors.New(CribbageGameOver)
+		}
+	}
+
+	cs.Turn = (cs.Dealer + 1) % len(cs.Players)
+	return nil
+}
+
+func (cs *CribbageState) canPlay(player int) bool {
+	for _, card := range cs.Players[player].Hand {
+		if cs.Count+cribbageValue(card) <= 31 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// The next player after the given one who is able to play on the current
+// count; the given player is checked last. -1 when nobody can.
+func (cs *CribbageState) nextToPlay(player int) int {
+	for offset := 1; offset <= len(cs.Players); offset++ {
+		next := (player + offset) % len(cs.Players)
+		if cs.canPlay(next) {
+			return next
+		}
+	}
+
+	return -1
+}
+
+func (cs *CribbageState) PlayCard(player int, cardID int) error {
+	if err := cs.checkPlayer(player); err != nil {
+		return err
+	}
+
+	if !cs.Cut {
+		return errors.New("unable to play a card before everyone has discarded to the crib")
+	}
+
+	if cs.Pegged {
+		return errors.New("unable to play a card after pegging has finished")
+	}
+
+	if cs.Turn != player {
+		return errors.New("not your turn")
+	}
+
+	index, found := cs.Players[player].FindCard(cardID)
+	if !found {
+		return errors.New("unable to play card not in hand")
+	}
+
+	played := cs.Players[player].Hand[index]
+	if cs.Count+cribbageValue(played) > 31 {
+		return errors.New("unable to play a card taking the count past 31")
+	}
+
+	history := cs.RoundHistory[len(cs.RoundHistory)-1]
+
+	cs.Players[player].RemoveCard(cardID)
+	cs.Count += cribbageValue(played)
+	cs.Pile = append(cs.Pile, played)
+	cs.LastPlayer = player
+
+	points := CribbagePegPoints(cs.Pile, cs.Count)
+	history.Pegging = append(history.Pegging, CribbagePeg{player, played.Copy(), cs.Count, points})
+	if cs.award(player, points) {
+		return errors.New(CribbageGameOver)
+	}
+
+	// Thirty-one was already scored above; start the count over.
+	if cs.Count == 31 {
+		cs.Count = 0
+		cs.Pile = make([]Card, 0)
+	}
+
+	next := cs.nextToPlay(player)
+	if next == -1 && cs.Count > 0 {
+		// Nobody can play on this count: the last player to play gets one for
+		// the go (or the last card), and the count starts over.
+		history.Pegging = append(history.Pegging, CribbagePeg{player, nil, cs.Count, 1})
+		if cs.award(player, 1) {
+			return errors.New(CribbageGameOver)
+		}
+
+		cs.Count = 0
+		cs.Pile = make([]Card, 0)
+		next = cs.nextToPlay(player)
+	}
+
+	if next != -1 {
+		cs.Turn = next
+		return nil
+	}
+
+	// Everyone is out of cards; move on to the show.
+	cs.Pegged = true
+	cs.Showing = 0
+	cs.Turn = cs.showPlayer()
+
+	if cs.Config.Muggins {
+		return nil
+	}
+
+	for {
+		_, points := cs.showScore()
+		if err := cs.show(points); err != nil {
+			return err
+		}
+	}
+}
+
+// Who is showing, in order starting left of the dealer and ending with the
+// dealer's crib.
+func (cs *CribbageState) showPlayer() int {
+	if cs.Showing >= len(cs.Players) {
+		return cs.Dealer
+	}
+
+	return (cs.Dealer + 1 + cs.Showing) % len(cs.Players)
+}
+
+func (cs *CribbageState) showScore() ([]Card, int) {
+	if cs.Showing >= len(cs.Players) {
+		return cs.Crib, CribbageScoreHand(cs.Crib, *cs.Starter, true)
+	}
+
+	var hand = cs.Players[cs.showPlayer()].Kept
+	return hand, CribbageScoreHand(hand, *cs.Starter, false)
+}
+
+// Score the current show with the points claimed for it, giving any missed
+// points to the next opponent, and advance to the next show or round.
+func (cs *CribbageState) show(claimed int) error {
+	history := cs.RoundHistory[len(cs.RoundHistory)-1]
+	player := cs.showPlayer()
+	hand, points := cs.showScore()
+
+	var show = CribbageShow{player, cs.Showing >= len(cs.Players), CopyHand(hand), points, claimed, points - claimed}
+	history.Shows = append(history.Shows, show)
+
+	if cs.award(player, claimed) {
+		return errors.New(CribbageGameOver)
+	}
+
+	if show.Muggins > 0 {
+		opponent := (player + 1) % len(cs.Players)
+		for cs.Side(opponent) == cs.Side(player) {
+			opponent = (opponent + 1) % len(cs.Players)
+		}
+
+		if cs.award(opponent, show.Muggins) {
+			return errors.New(CribbageGameOver)
+		}
+	}
+
+	cs.Showing += 1
+	if cs.Showing <= len(cs.Players) {
+		cs.Turn = cs.showPlayer()
+		return nil
+	}
+
+	history.Scores = append([]int{}, cs.Scores...)
+
+	cs.Dealt = false
+	cs.Turn = -1
+	cs.Showing = -1
+	cs.Dealer = (cs.Dealer + 1) % len(cs.Players)
+	return errors.New(CribbageNextRound)
+}
+
+// In muggins games, players count their own hand (and the dealer, their
+// crib); opponents take any points they miss.
+func (cs *CribbageState) Claim(player int, points int) error {
+	if err := cs.checkPlayer(player); err != nil {
+		return err
+	}
+
+	if !cs.Config.Muggins {
+		return errors.New("hands are counted automatically in this game")
+	}
+
+	if !cs.Pegged {
+		return errors.New("unable to count hands before pegging has finished")
+	}
+
+	if cs.Turn != player {
+		return errors.New("not your turn")
+	}
+
+	if points < 0 {
+		return errors.New("unable to claim a negative number of points")
+	}
+
+	// Don't say what the hand is worth; that's for the player to count.
+	if _, actual := cs.showScore(); points > actual {
+		return errors.New("that hand isn't worth that much")
+	}
+
+	return cs.show(points)
+}
@@ -0,0 +1,315 @@
+package games
+
+import (
+	"encoding/json"
+	"errors"
+
+	"git.cipherboy.com/WillowPatchGames/wpg/pkg/middleware/figgy"
+)
+
+// Cribbage message types:
+//
+// 0. Assign Players
+// 1. Deal
+// 2. Discard
+// 3. PlayCard
+// 4. Claim
+// 5. Timeout (sent by the server when a player runs out of time)
+
+type CribbageDiscardMsg struct {
+	MessageHeader
+	CardIDs []int `json:"card_ids"`
+}
+
+type CribbagePlayMsg struct {
+	MessageHeader
+	CardID int `json:"card_id"`
+}
+
+type CribbageClaimMsg struct {
+	MessageHeader
+	Points int `json:"points"`
+}
+
+func (c *Controller) dispatchCribbage(message []byte, header MessageHeader, game *GameData, player *PlayerData, sid uint64) error {
+	var err error
+	var state *CribbageState = game.State.(*CribbageState)
+	if state == nil {
+		panic("internal state is nil; this shouldn't happen when the game is started")
+	}
+
+	var was_finished = state.Finished
+	var send_synopsis = false
+	var send_state = false
+
+	switch header.MessageType {
+	case "assign":
+		err = c.assignPartnershipSeats(message, header, game, player, state.AssignSeats)
+	case "start":
+		if player.UID != game.Owner {
+			return errors.New("unable to start game that you're not the owner of")
+		}
+
+		var players int = 0
+		for _, player := range game.ToPlayer {
+			if player.Playing {
+				// When we click the start button again, say, after a user has come
+				// back to being active, Countback will be higher than 0, because we've
+				// already attempted to set this.
+				player.Countback = 0
+				players += 1
+			}
+		}
+
+		if players != state.Config.NumPlayers || !state.Assigned {
+			return errors.New("must finish configuring assignments for this game")
+		}
+
+		if err = figgy.Validate(state.Config); err != nil {
+			return err
+		}
+
+		if state.Config.Countdown {
+			game.Countdown = 0
+			game.CountdownTimer = nil
+
+			return c.handleCountdown(game)
+		} else {
+			return c.doCribbageStart(game, state)
+		}
+	case "cancel":
+		if player.UID != game.Owner {
+			return errors.New("unable to cancel game that you're not the owner of")
+		}
+
+		if !state.Config.Countdown {
+			return errors.New("unable to cancel game that doesn't use a countdown")
+		}
+
+		if state.Started || state.Finished {
+			return errors.New("unable to cancel game that is already started")
+		}
+
+		game.Countdown = 0
+		game.CountdownTimer = nil
+	case "join":
+		if state.Started && !state.Finished {
+			var started ControllerNotifyStarted
+			started.LoadFromController(game, player)
+			started.ReplyTo = header.MessageID
+			c.undispatch(game, player, started.MessageID, started.ReplyTo, started)
+
+			if player.Playing && player.Index >= 0 {
+				var response CribbageStateNotification
+				response.LoadData(game, state, player)
+				c.undispatch(game, player, response.MessageID, 0, response)
+
+				send_synopsis = true
+			}
+		} else if state.Finished {
+			var finished CribbageFinishedNotification
+			finished.LoadData(game, state, player)
+			finished.ReplyTo = header.MessageID
+			c.undispatch(game, player, finished.MessageID, finished.ReplyTo, finished)
+			send_synopsis = true
+		}
+	case "deal":
+		if player.Index != state.Dealer {
+			return errors.New("unable to deal round that you're not the dealer for")
+		}
+
+		err = state.StartRound()
+		send_synopsis = err == nil
+		send_state = err == nil
+	case "discard":
+		var data CribbageDiscardMsg
+		if err = json.Unmarshal(message, &data); err != nil {
+			return err
+		}
+
+		err = state.Discard(player.Index, data.CardIDs)
+		send_synopsis = true
+		send_state = true
+	case "play":
+		var data CribbagePlayMsg
+		if err = json.Unmarshal(message, &data); err != nil {
+			return err
+		}
+
+		err = state.PlayCard(player.Index, data.CardID)
+		send_synopsis = true
+		send_state = true
+	case "claim":
+		var data CribbageClaimMsg
+		if err = json.Unmarshal(message, &data); err != nil {
+			return err
+		}
+
+		err = state.Claim(player.Index, data.Points)
+		send_synopsis = true
+		send_state = true
+	case "timeout":
+		var data GameTimeout
+		if err = json.Unmarshal(message, &data); err != nil {
+			return err
+		}
+
+		err = state.Timeout(player.Index, data.Policy)
+		send_synopsis = true
+		send_state = true
+	case "peek":
+		if player.Index != -1 && !state.Finished {
+			return errors.New("can only peek once game is complete")
+		}
+
+		var response CribbagePeekNotification
+		response.LoadData(game, state, player)
+		response.ReplyTo = header.MessageID
+		c.undispatch(game, player, response.MessageID, header.MessageID, response)
+
+		var synopsis CribbageSynopsisNotification
+		synopsis.LoadData(game, state, player)
+		c.undispatch(game, player, synopsis.MessageID, 0, synopsis)
+	default:
+		return errors.New("unknown message_type issued to cribbage game: " + header.MessageType)
+	}
+
+	// If this game ended during this dispatch call, notify everyone.
+	if !was_finished && state.Finished {
+		// Notify everyone that the game ended and which side won.
+		for _, indexed_player := range game.ToPlayer {
+			var finished CribbageFinishedNotification
+			finished.LoadData(game, state, indexed_player)
+			c.undispatch(game, indexed_player, finished.MessageID, 0, finished)
+		}
+	}
+
+	// If someone changed something, notify everyone.
+	if send_synopsis {
+		for _, indexed_player := range game.ToPlayer {
+			var synopsis CribbageSynopsisNotification
+			synopsis.LoadData(game, state, indexed_player)
+			c.undispatch(game, indexed_player, synopsis.MessageID, 0, synopsis)
+		}
+	}
+
+	// If the state changed for a bunch of people, notify them all.
+	if send_state {
+		for _, indexed_player := range game.ToPlayer {
+			if !indexed_player.Admitted {
+				continue
+			}
+
+			if indexed_player.Playing {
+				var response CribbageStateNotification
+				response.LoadData(game, state, indexed_player)
+				if indexed_player.UID == player.UID && err == nil {
+					response.ReplyTo = header.MessageID
+				}
+
+				c.undispatch(game, indexed_player, response.MessageID, response.ReplyTo, response)
+			} else {
+				var response CribbagePeekNotification
+				response.LoadData(game, state, indexed_player)
+				c.undispatch(game, indexed_player, response.MessageID, 0, response)
+			}
+		}
+	}
+
+	return err
+}
+
+func (c *Controller) doCribbageStart(game *GameData, state *CribbageState) error {
+	// First count the number of people playing.
+	var players int = 0
+	for _, player := range game.ToPlayer {
+		if player.Playing {
+			players += 1
+		}
+	}
+
+	if players != state.Config.NumPlayers || !state.Assigned {
+		return errors.New("must finish configuring assignments for this game")
+	}
+
+	// Then start the underlying Cribbage game to populate game data. The assign
+	// message already gave everyone their seat; with four
+	// players, partners sit across from each other.
+	if err := state.Start(players); err != nil {
+		return err
+	}
+
+	// Send out initial state data to individuals who are playing. Also notify
+	// all players that the game has started.
+	for _, indexed_player := range game.ToPlayer {
+		if !indexed_player.Admitted {
+			continue
+		}
+
+		// Tell everyone interested that the game has started.
+		var started ControllerNotifyStarted
+		started.LoadFromController(game, indexed_player)
+		c.undispatch(game, indexed_player, started.MessageID, started.ReplyTo, started)
+
+		// Only send state to players who are playing initially. Everyone else
+		// (namely, admitted spectators) should send a peek event before they can
+		// view the table.
+		if indexed_player.Playing {
+			var response CribbageStateNotification
+			response.LoadData(game, state, indexed_player)
+			c.undispatch(game, indexed_player, response.MessageID, 0, response)
+		}
+
+		// Give everyone the initial synopsis.
+		var synopsis CribbageSynopsisNotification
+		synopsis.LoadData(game, state, indexed_player)
+		c.undispatch(game, indexed_player, synopsis.MessageID, 0, synopsis)
+	}
+
+	return nil
+}
+
+// cribbageEngine registers Cribbage with the controller; see GameEngine.
+type cribbageEngine struct{}
+
+func init() {
+	MustRegisterGameEngine(cribbageEngine{})
+}
+
+func (cribbageEngine) Mode() GameMode {
+	return CribbageGame
+}
+
+func (cribbageEngine) Name() string {
+	return "cribbage"
+}
+
+func (cribbageEngine) Title() string {
+	return "Cribbage (Card Game)"
+}
+
+func (cribbageEngine) Description() string {
+	return "In Cribbage, players discard to the crib, peg their way to 31 and count fifteens, pairs and runs in their hands. First to 121 wins -- watch out for muggins!"
+}
+
+func (cribbageEngine) EmptyConfig() figgy.Figgurable {
+	return &CribbageConfig{}
+}
+
+func (cribbageEngine) NewState() ConfigurableState {
+	return &CribbageState{}
+}
+
+func (cribbageEngine) Init(config figgy.Figgurable) (ConfigurableState, error) {
+	var asserted *CribbageConfig = config.(*CribbageConfig)
+	var state = &CribbageState{}
+	return state, state.Init(*asserted)
+}
+
+func (cribbageEngine) Dispatch(c *Controller, message []byte, header MessageHeader, game *GameData, player *PlayerData, sid uint64) error {
+	return c.dispatchCribbage(message, header, game, player, sid)
+}
+
+func (cribbageEngine) Start(c *Controller, game *GameData) error {
+	return c.doCribbageStart(game, game.State.(*CribbageState))
+}
@@ -0,0 +1,189 @@
+package games
+
+type CribbagePlayerState struct {
+	Hand []Card `json:"hand"`
+	Kept []Card `json:"kept"`
+
+	Discarded bool `json:"discarded"`
+}
+
+type CribbageGameState struct {
+	Turn   uint64 `json:"turn"`
+	Dealer uint64 `json:"dealer"`
+
+	Starter     *Card  `json:"starter,omitempty"`
+	Crib        []Card `json:"crib,omitempty"` // Only once it is being shown.
+	Count       int    `json:"count"`
+	Pile        []Card `json:"pile"`
+	LastPlayer  uint64 `json:"last_player"`
+	ShowingCrib bool   `json:"showing_crib"`
+
+	Heels   bool           `json:"heels"`
+	Pegging []CribbagePeg  `json:"pegging"`
+	Shows   []CribbageShow `json:"shows"`
+
+	Scores     []int          `json:"scores"`
+	Commitment string         `json:"commitment"` // To the current round's shuffle seed; see ShuffleSeed.
+	Config     CribbageConfig `json:"config"`
+
+	Started  bool `json:"started"`
+	Dealt    bool `json:"dealt"`
+	Cut      bool `json:"cut"`
+	Pegged   bool `json:"pegged"`
+	Finished bool `json:"finished"`
+}
+
+func (cgs *CribbageGameState) loadGame(data *GameData, game *CribbageState) {
+	cgs.Turn, _ = data.ToUserID(game.Turn)
+	cgs.Dealer, _ = data.ToUserID(game.Dealer)
+
+	cgs.Starter = game.Starter
+	if game.Pegged {
+		cgs.Crib = game.Crib
+		cgs.ShowingCrib = game.Showing >= len(game.Players)
+	}
+
+	cgs.Count = game.Count
+	cgs.Pile = game.Pile
+	if cgs.Pile == nil {
+		cgs.Pile = make([]Card, 0)
+	}
+	cgs.LastPlayer, _ = data.ToUserID(game.LastPlayer)
+
+	// Pegging and shows stay visible until the next deal, so everyone can see
+	// how the last round was counted.
+	if len(game.RoundHistory) > 0 {
+		history := game.RoundHistory[len(game.RoundHistory)-1]
+		cgs.Heels = history.Heels
+		cgs.Pegging = history.Pegging
+		cgs.Shows = history.Shows
+	}
+
+	cgs.Scores = game.Scores
+	cgs.Commitment = game.Deck.Commitment()
+	cgs.Config = game.Config
+
+	cgs.Started = game.Started
+	cgs.Dealt = game.Dealt
+	cgs.Cut = game.Cut
+	cgs.Pegged = game.Pegged
+	cgs.Finished = game.Finished
+}
+
+type CribbageStateNotification struct {
+	MessageHeader
+	CribbagePlayerState
+	CribbageGameState
+}
+
+func (csn *CribbageStateNotification) LoadData(data *GameData, game *CribbageState, player *PlayerData) {
+	csn.LoadHeader(data, player)
+	csn.MessageType = "state"
+
+	csn.Hand = game.Players[player.Index].Hand
+	csn.Kept = game.Players[player.Index].Kept
+	csn.Discarded = game.Players[player.Index].Discarded
+
+	csn.loadGame(data, game)
+}
+
+type CribbagePlayerSynopsis struct {
+	UID         uint64 `json:"user"`
+	Playing     bool   `json:"playing"`
+	PlayerIndex int    `json:"player_index"`
+	Side        int    `json:"side"`
+
+	IsTurn    bool `json:"is_turn"`
+	IsDealer  bool `json:"is_dealer"`
+	Discarded bool `json:"discarded"`
+	CardsLeft int  `json:"cards_left"`
+
+	Score int `json:"score"`
+}
+
+type CribbageSynopsisNotification struct {
+	MessageHeader
+
+	Players []CribbagePlayerSynopsis `json:"players"`
+	Count   int                      `json:"count"`
+}
+
+func (csn *CribbageSynopsisNotification) LoadData(data *GameData, state *CribbageState, player *PlayerData) {
+	csn.LoadHeader(data, player)
+	csn.MessageType = "synopsis"
+
+	for _, indexed_player := range data.ToPlayer {
+		var synopsis CribbagePlayerSynopsis
+		synopsis.UID = indexed_player.UID
+		synopsis.Playing = indexed_player.Playing
+		synopsis.PlayerIndex = indexed_player.Index
+		synopsis.Side = -1
+
+		if indexed_player.Index >= 0 && indexed_player.Index < len(state.Players) {
+			synopsis.Side = state.Side(indexed_player.Index)
+
+			synopsis.IsTurn = indexed_player.Index == state.Turn
+			synopsis.IsDealer = indexed_player.Index == state.Dealer
+			synopsis.Discarded = state.Players[indexed_player.Index].Discarded
+			synopsis.CardsLeft = len(state.Players[indexed_player.Index].Hand)
+
+			synopsis.Score = state.Scores[synopsis.Side]
+		}
+
+		csn.Players = append(csn.Players, synopsis)
+	}
+
+	csn.Count = state.Count
+}
+
+type CribbagePeekNotification struct {
+	MessageHeader
+
+	PlayerMapping []uint64 `json:"player_mapping"`
+
+	// Info for Ended Games (Everyone)
+	RoundHistory []*CribbageRound `json:"round_history"`
+
+	// Info for Active Games (Spectators)
+	CribbageGameState
+
+	Winners []uint64 `json:"winners"`
+}
+
+func (cpn *CribbagePeekNotification) LoadData(data *GameData, game *CribbageState, player *PlayerData) {
+	cpn.LoadHeader(data, player)
+	cpn.MessageType = "game-state"
+
+	for index := range game.Players {
+		player_uid, _ := data.ToUserID(index)
+		cpn.PlayerMapping = append(cpn.PlayerMapping, player_uid)
+	}
+
+	cpn.loadGame(data, game)
+
+	if !game.Finished {
+		// Allow spectators to see previous rounds before the game has ended.
+		if len(game.RoundHistory) > 0 {
+			cpn.RoundHistory = game.RoundHistory[:len(game.RoundHistory)-1]
+		}
+	} else {
+		cpn.RoundHistory = game.RoundHistory
+	}
+
+	cpn.Winners, _ = data.ToUserIDs(game.Winners)
+}
+
+type CribbageFinishedNotification struct {
+	MessageHeader
+
+	Winners []uint64 `json:"winners"`
+	Scores  []int    `json:"scores"`
+}
+
+func (cfn *CribbageFinishedNotification) LoadData(data *GameData, state *CribbageState, player *PlayerData) {
+	cfn.LoadHeader(data, player)
+	cfn.MessageType = "finished"
+
+	cfn.Winners, _ = data.ToUserIDs(state.Winners)
+	cfn.Scores = state.Scores
+}
@@ -0,0 +1,138 @@
+package games
+
+// Scoring for Cribbage: a card's count is its rank, with face cards counting
+// ten. Runs use the rank order, with aces always low.
+func cribbageValue(card Card) int {
+	if card.Rank >= TenRank {
+		return 10
+	}
+
+	return int(card.Rank)
+}
+
+// Points scored for the last card played during pegging; played holds the
+// cards played since the count was last reset, ending with the new card.
+func CribbagePegPoints(played []Card, count int) int {
+	var points = 0
+	if count == 15 || count == 31 {
+		points += 2
+	}
+
+	// Pairs, pair royals and double pair royals are scored by the number of
+	// matching ranks at the end of the pile.
+	var last = played[len(played)-1]
+	var same = 1
+	for index := len(played) - 2; index >= 0 && played[index].Rank == last.Rank; index-- {
+		same += 1
+	}
+	points += same * (same - 1)
+
+	// Runs may be played in any order, so long as the last few cards form one
+	// without any interruption.
+	for length := len(played); length >= 3; length-- {
+		if cribbageIsRun(played[len(played)-length:]) {
+			points += length
+			break
+		}
+	}
+
+	return points
+}
+
+func cribbageIsRun(cards []Card) bool {
+	var seen = make(map[CardRank]bool)
+	var low = cards[0].Rank
+	var high = cards[0].Rank
+	for _, card := range cards {
+		if seen[card.Rank] {
+			return false
+		}
+
+		seen[card.Rank] = true
+		if card.Rank < low {
+			low = card.Rank
+		}
+		if card.Rank > high {
+			high = card.Rank
+		}
+	}
+
+	return int(high-low) == len(cards)-1
+}
+
+// Points scored when showing a hand (or the crib) along with the starter.
+func CribbageScoreHand(hand []Card, starter Card, crib bool) int {
+	var cards = append(CopyHand(hand), starter)
+	var points = 0
+
+	// Fifteens: every combination of cards summing to fifteen.
+	for mask := 1; mask < 1<<len(cards); mask++ {
+		var sum = 0
+		for index, card := range cards {
+			if mask&(1<<index) != 0 {
+				sum += cribbageValue(card)
+			}
+		}
+
+		if sum == 15 {
+			points += 2
+		}
+	}
+
+	// Pairs: every two cards of the same rank.
+	for left := 0; left < len(cards); left++ {
+		for right := left + 1; right < len(cards); right++ {
+			if cards[left].Rank == cards[right].Rank {
+				points += 2
+			}
+		}
+	}
+
+	// Runs: the longest sequence of consecutive ranks, counted once for each
+	// way of choosing its cards.
+	var counts = make(map[CardRank]int)
+	for _, card := range cards {
+		counts[card.Rank] += 1
+	}
+
+	for start := AceRank; start <= KingRank; start++ {
+		if counts[start] == 0 || (start > AceRank && counts[start-1] > 0) {
+			continue
+		}
+
+		var length = 0
+		var ways = 1
+		for rank := start; rank <= KingRank && counts[rank] > 0; rank++ {
+			length += 1
+			ways *= counts[rank]
+		}
+
+		if length >= 3 {
+			points += length * ways
+		}
+	}
+
+	// Flushes: four in hand, or five including the starter. The crib only
+	// counts a five card flush.
+	var flush = true
+	for _, card := range hand {
+		if card.Suit != hand[0].Suit {
+			flush = false
+		}
+	}
+
+	if flush && starter.Suit == hand[0].Suit {
+		points += len(hand) + 1
+	} else if flush && !crib {
+		points += len(hand)
+	}
+
+	// His nobs: the jack of the same suit as the starter.
+	for _, card := range hand {
+		if card.Rank == JackRank && card.Suit == starter.Suit {
+			points += 1
+		}
+	}
+
+	return points
+}
@@ -0,0 +1,153 @@
+package games
+
+import (
+	"testing"
+)
+
+func TestCribbageScoreHand(t *testing.T) {
+	for _, test := range []struct {
+		hand    []Card
+		starter Card
+		crib    bool
+		points  int
+	}{
+		{[]Card{{0, ClubsSuit, FiveRank}, {0, DiamondsSuit, FiveRank}, {0, SpadesSuit, FiveRank}, {0, HeartsSuit, JackRank}}, Card{0, HeartsSuit, FiveRank}, false, 29},
+		{[]Card{{0, ClubsSuit, AceRank}, {0, DiamondsSuit, TwoRank}, {0, HeartsSuit, ThreeRank}, {0, SpadesSuit, FourRank}}, Card{0, ClubsSuit, NineRank}, false, 8},
+		{[]Card{{0, HeartsSuit, TwoRank}, {0, HeartsSuit, FourRank}, {0, HeartsSuit, SixRank}, {0, HeartsSuit, EightRank}}, Card{0, ClubsSuit, KingRank}, false, 4},
+		{[]Card{{0, HeartsSuit, TwoRank}, {0, HeartsSuit, FourRank}, {0, HeartsSuit, SixRank}, {0, HeartsSuit, EightRank}}, Card{0, ClubsSuit, KingRank}, true, 0},
+		{[]Card{{0, HeartsSuit, TwoRank}, {0, HeartsSuit, FourRank}, {0, HeartsSuit, SixRank}, {0, HeartsSuit, EightRank}}, Card{0, HeartsSuit, KingRank}, true, 5},
+		{[]Card{{0, ClubsSuit, ThreeRank}, {0, DiamondsSuit, FourRank}, {0, HeartsSuit, FiveRank}, {0, SpadesSuit, FiveRank}}, Card{0, ClubsSuit, KingRank}, false, 12},
+	} {
+		if points := CribbageScoreHand(test.hand, test.starter, test.crib); points != test.points {
+			t.Fatal("Expected", test.hand, "with", test.starter, "to score", test.points, "but got", points)
+		}
+	}
+}
+
+func TestCribbagePegPoints(t *testing.T) {
+	for _, test := range []struct {
+		ranks  []CardRank
+		points int
+	}{
+		{[]CardRank{FiveRank, KingRank}, 2},
+		{[]CardRank{SevenRank, SevenRank}, 2},
+		{[]CardRank{SevenRank, SevenRank, SevenRank}, 6},
+		{[]CardRank{ThreeRank, FiveRank, FourRank}, 3},
+		{[]CardRank{ThreeRank, FiveRank, FourRank, SixRank}, 4},
+		{[]CardRank{ThreeRank, FiveRank, FourRank, FourRank}, 2},
+		{[]CardRank{TenRank, KingRank, AceRank}, 0},
+	} {
+		var played []Card
+		var count = 0
+		for _, rank := range test.ranks {
+			played = append(played, Card{0, ClubsSuit, rank})
+			count += cribbageValue(played[len(played)-1])
+		}
+
+		if points := CribbagePegPoints(played, count); points != test.points {
+			t.Fatal("Expected", played, "to peg", test.points, "but got", points)
+		}
+	}
+}
+
+func TestCribbageGame(t *testing.T) {
+	for _, config := range []CribbageConfig{
+		{NumPlayers: 2, WinAmount: 121},
+		{NumPlayers: 3, WinAmount: 61},
+		{NumPlayers: 4, WinAmount: 121, Muggins: true},
+	} {
+		var state CribbageState
+		if err := state.Init(config); err != nil {
+			t.Fatal("Unable to initialize game:", err)
+		}
+
+		if err := state.AssignSeats(config.NumPlayers + 3); err == nil || state.Assigned {
+			t.Fatal("Expected too many players to be rejected")
+		}
+
+		if err := state.AssignSeats(config.NumPlayers); err != nil || !state.Assigned {
+			t.Fatal("Unable to assign seats:", err)
+		}
+
+		if err := state.Start(config.NumPlayers); err != nil {
+			t.Fatal("Unable to start game:", err)
+		}
+
+		if err := state.AssignSeats(config.NumPlayers); err == nil {
+			t.Fatal("Expected seats to be fixed once the game started")
+		}
+
+		for hand := 0; hand < 200 && !state.Finished; hand++ {
+			if !state.Dealt {
+				if err := state.StartRound(); err != nil {
+					t.Fatal("Unable to deal:", err)
+				}
+			}
+
+			var err error
+			for player := range state.Players {
+				var discards []int
+				for _, card := range state.Players[player].Hand[:state.discardSize()] {
+					discards = append(discards, card.ID)
+				}
+
+				err = state.Discard(player, discards)
+				if err != nil && err.Error() != CribbageGameOver {
+					t.Fatal("Unable to discard:", err)
+				}
+			}
+
+			if len(state.Crib) != 4 {
+				t.Fatal("Expected crib to have four cards:", state.Crib)
+			}
+
+			for err == nil && !state.Pegged {
+				var played = false
+				for _, card := range state.Players[state.Turn].Hand {
+					if state.Count+cribbageValue(card) <= 31 {
+						err = state.PlayCard(state.Turn, card.ID)
+						played = true
+						break
+					}
+				}
+
+				if !played {
+					t.Fatal("Expected the player whose turn it is to be able to play")
+				}
+			}
+
+			// Under muggins, alternate between claiming everything and missing
+			// everything.
+			for err == nil {
+				var points = 0
+				if hand%2 == 0 {
+					_, points = state.showScore()
+				}
+
+				_, actual := state.showScore()
+				if claim_err := state.Claim(state.Turn, actual+1); claim_err == nil || claim_err.Error() != "that hand isn't worth that much" {
+					t.Fatal("Expected claiming more than the hand is worth to fail without saying what it's worth:", claim_err)
+				}
+
+				err = state.Claim(state.Turn, points)
+			}
+
+			if err.Error() != CribbageNextRound && err.Error() != CribbageGameOver {
+				t.Fatal("Unexpected error playing round:", err)
+			}
+
+			if err.Error() == CribbageNextRound && len(state.RoundHistory[len(state.RoundHistory)-1].Pegging) < config.NumPlayers*4 {
+				t.Fatal("Expected every card to have been pegged")
+			}
+		}
+
+		if !state.Finished || len(state.Winners) != config.NumPlayers/state.sides() {
+			t.Fatal("Expected game to finish with a winner:", state.Scores)
+		}
+
+		var side = state.Side(state.Winners[0])
+		if state.Scores[side] < config.WinAmount {
+			t.Fatal("Expected winner to reach the threshold:", state.Scores)
+		}
+	}
+}
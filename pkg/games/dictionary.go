@@ -2,42 +2,214 @@ package games
 
 import (
 	"bufio"
+	"errors"
+	"hash/fnv"
 	"io"
+	"io/ioutil"
+	"log"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 
 	"github.com/dghubble/trie"
+
+	"git.cipherboy.com/WillowPatchGames/wpg/pkg/middleware/figgy"
 )
 
-var dictionaryLock sync.Mutex
-var dictionary *trie.RuneTrie
+// Name of the dictionary used when a game doesn't pick one.
+const DefaultDictionary = "words"
+
+// Dictionaries are word files (one word per line) in this directory, named
+// by their file name.
+var dictionaryDirectory = "/usr/share/dict"
+
+// Dictionaries we have labels for, in option order. These are offered when
+// the dictionary directory can't be read; other word files found there are
+// labelled by their file name.
+var knownDictionaries = []struct {
+	Name  string
+	Label string
+}{
+	{DefaultDictionary, "Default word list"},
+	{"american-english", "American English"},
+	{"british-english", "British English"},
+	{"french", "French"},
+	{"ngerman", "German"},
+	{"spanish", "Spanish"},
+	{"house", "House word list"},
+}
+
+// A dictionary selectable through the Dictionary option of word games.
+type dictionaryOption struct {
+	Name  string
+	Label string
+	Value int
+}
+
+var dictionaryLock sync.RWMutex
+var dictionaries = make(map[string]*trie.RuneTrie)
+var dictionaryOptions []dictionaryOption
+
+// Names of every dictionary offered so far, by option value. Running games
+// keep their dictionary even after its word file goes away.
+var dictionaryNames = make(map[int]string)
+
+func init() {
+	loadDictionaryOptions()
+}
+
+// Option value of the named dictionary. Values are stored in game configs,
+// so they can't depend on what else is in the directory: known dictionaries
+// use their position in knownDictionaries, and others a hash of their name.
+func dictionaryValue(name string) int {
+	for index, known := range knownDictionaries {
+		if known.Name == name {
+			return index
+		}
+	}
+
+	var hash = fnv.New32a()
+	hash.Write([]byte(name))
+	return len(knownDictionaries) + int(hash.Sum32()%(1<<24))
+}
+
+// Build the Dictionary option from the word files in the dictionary
+// directory. The default dictionary is always offered, so the option's
+// default stays valid.
+func loadDictionaryOptions() {
+	dictionaryLock.RLock()
+	var directory = dictionaryDirectory
+	dictionaryLock.RUnlock()
+
+	var found = make(map[string]bool)
+	files, err := ioutil.ReadDir(directory)
+	if err != nil {
+		log.Println("Unable to list dictionaries in", directory, err)
+		for _, known := range knownDictionaries {
+			found[known.Name] = true
+		}
+	}
+
+	for _, file := range files {
+		if file.Mode().IsRegular() && !strings.HasPrefix(file.Name(), ".") {
+			found[file.Name()] = true
+		}
+	}
+
+	var options = []dictionaryOption{{DefaultDictionary, knownDictionaries[0].Label, 0}}
+	for _, known := range knownDictionaries[1:] {
+		if found[known.Name] {
+			options = append(options, dictionaryOption{known.Name, known.Label, dictionaryValue(known.Name)})
+		}
+	}
+
+	var others []string
+	for name := range found {
+		if dictionaryValue(name) >= len(knownDictionaries) {
+			others = append(others, name)
+		}
+	}
+	sort.Strings(others)
+
+	var values = make(map[int]bool)
+	for _, option := range options {
+		values[option.Value] = true
+	}
+
+	for _, name := range others {
+		var value = dictionaryValue(name)
+		if values[value] {
+			log.Println("Skipping dictionary", name, "whose option value is already taken")
+			continue
+		}
+
+		values[value] = true
+		options = append(options, dictionaryOption{name, name, value})
+	}
 
-func loadDictionary() {
-	// Because we swap in the dictionary only once it has been fully built, we
-	// don't need to lock to check if the dictionary has been loaded already.
-	if dictionary != nil {
-		return
+	var option_values = make([]int, 0, len(options))
+	var labels = make([]string, 0, len(options))
+	for _, option := range options {
+		option_values = append(option_values, option.Value)
+		labels = append(labels, option.Label)
 	}
 
 	dictionaryLock.Lock()
 	defer dictionaryLock.Unlock()
 
-	// We might've raced some other thread in checking dictionary != nil, while
-	// it still held the lock. This ensures we only build the dictionary when
-	// it hasn't been built by another thread, since we now hold the lock.
-	if dictionary != nil {
-		return
+	dictionaryOptions = options
+	for _, option := range options {
+		dictionaryNames[option.Value] = option.Name
+	}
+
+	figgy.RegisterOptionValues("dictionaries", option_values, labels)
+}
+
+// Name of the dictionary picked by a game's Dictionary option.
+func dictionaryName(option int) string {
+	if option >= 0 && option < len(knownDictionaries) {
+		return knownDictionaries[option].Name
+	}
+
+	dictionaryLock.RLock()
+	defer dictionaryLock.RUnlock()
+
+	if name, ok := dictionaryNames[option]; ok {
+		return name
+	}
+
+	return DefaultDictionary
+}
+
+// Set the directory dictionaries are loaded from. This drops any
+// dictionaries which were already loaded and offers the ones found in the
+// new directory.
+func SetDictionaryDirectory(directory string) {
+	dictionaryLock.Lock()
+	dictionaryDirectory = directory
+	dictionaries = make(map[string]*trie.RuneTrie)
+	dictionaryLock.Unlock()
+
+	loadDictionaryOptions()
+}
+
+func dictionaryPath(name string) (string, error) {
+	if name == "" || name != filepath.Base(name) || strings.HasPrefix(name, ".") {
+		return "", errors.New("invalid dictionary name: " + name)
+	}
+
+	dictionaryLock.RLock()
+	defer dictionaryLock.RUnlock()
+
+	return filepath.Join(dictionaryDirectory, name), nil
+}
+
+// Whether the named dictionary is present in the dictionary directory.
+func DictionaryExists(name string) bool {
+	path, err := dictionaryPath(name)
+	if err != nil {
+		return false
+	}
+
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
+
+func readDictionary(name string) (*trie.RuneTrie, error) {
+	path, err := dictionaryPath(name)
+	if err != nil {
+		return nil, err
 	}
 
 	var dict *trie.RuneTrie = trie.NewRuneTrie()
 
-	// TODO: make configurable
-	var wordfile = "/usr/share/dict/words"
-	file, err := os.Open(wordfile)
+	file, err := os.Open(path)
 	if err != nil {
-		panic(err)
+		return nil, err
 	}
+	defer file.Close()
 
 	reader := bufio.NewReader(file)
 
@@ -47,21 +219,167 @@ func loadDictionary() {
 			continue
 		}
 
-		word := strings.ToUpper(string(line))
-		if !strings.ContainsAny(word, "!&',-./0123456789 _`~\"?+") {
+		word := strings.ToUpper(strings.TrimSpace(string(line)))
+		if word != "" && !strings.ContainsAny(word, "!&',-./0123456789 _`~\"?+") {
 			dict.Put(word, true)
 		}
 	}
 
 	if err != io.EOF {
-		panic(err)
+		return nil, err
 	}
 
-	dictionary = dict
+	return dict, nil
 }
 
-func IsWord(word string) bool {
-	loadDictionary()
+func loadDictionary(name string) (*trie.RuneTrie, error) {
+	dictionaryLock.RLock()
+	dict, ok := dictionaries[name]
+	dictionaryLock.RUnlock()
+
+	if ok {
+		return dict, nil
+	}
+
+	// Build the dictionary without holding the lock; if we raced another
+	// thread loading the same dictionary, keep whichever finished first.
+	dict, err := readDictionary(name)
+	if err != nil {
+		return nil, err
+	}
+
+	dictionaryLock.Lock()
+	defer dictionaryLock.Unlock()
+
+	if existing, ok := dictionaries[name]; ok {
+		return existing, nil
+	}
+
+	dictionaries[name] = dict
+	return dict, nil
+}
+
+// Re-read every dictionary which has already been loaded, for instance after
+// the word files were updated on disk. A dictionary which fails to load keeps
+// its previous contents. Word files added or removed since change which
+// dictionaries games can pick.
+func ReloadDictionaries() {
+	loadDictionaryOptions()
+
+	dictionaryLock.RLock()
+	var names = make([]string, 0, len(dictionaries))
+	for name := range dictionaries {
+		names = append(names, name)
+	}
+	dictionaryLock.RUnlock()
+
+	for _, name := range names {
+		dict, err := readDictionary(name)
+		if err != nil {
+			log.Println("Unable to reload dictionary", name, err)
+			continue
+		}
+
+		dictionaryLock.Lock()
+		dictionaries[name] = dict
+		dictionaryLock.Unlock()
+	}
+}
+
+// Words a room adds to, or removes from, the dictionary of its games.
+type RoomWords struct {
+	Allowed []string `json:"allowed,omitempty"`
+	Denied  []string `json:"denied,omitempty"`
+}
+
+// The words accepted by a game: a named dictionary plus the room's
+// additions and removals.
+type WordList struct {
+	Dictionary string `json:"dictionary"`
+	RoomWords
+}
+
+func containsWord(words []string, word string) bool {
+	for _, candidate := range words {
+		if strings.EqualFold(strings.TrimSpace(candidate), word) {
+			return true
+		}
+	}
+
+	return false
+}
 
-	return dictionary.Get(strings.ToUpper(word)) != nil
+// The room's removed words, upper case, for checking many words against.
+func (rw RoomWords) deniedSet() map[string]bool {
+	var ret = make(map[string]bool, len(rw.Denied))
+	for _, word := range rw.Denied {
+		ret[strings.ToUpper(strings.TrimSpace(word))] = true
+	}
+
+	return ret
+}
+
+// Name of the dictionary the list builds on.
+func (wl WordList) name() string {
+	if wl.Dictionary == "" {
+		return DefaultDictionary
+	}
+
+	return wl.Dictionary
+}
+
+func (wl WordList) IsWord(word string) bool {
+	if containsWord(wl.Denied, word) {
+		return false
+	}
+
+	if containsWord(wl.Allowed, word) {
+		return true
+	}
+
+	var name = wl.name()
+	dict, err := loadDictionary(name)
+	if err != nil {
+		log.Println("Unable to load dictionary", name, err)
+		return false
+	}
+
+	return dict.Get(strings.ToUpper(word)) != nil
+}
+
+// Visit every word in the list, in no particular order. Words are upper
+// case.
+func (wl WordList) Walk(visitor func(word string) error) error {
+	dict, err := loadDictionary(wl.name())
+	if err != nil {
+		return err
+	}
+
+	var denied = wl.deniedSet()
+	err = dict.Walk(func(key string, value interface{}) error {
+		if denied[key] {
+			return nil
+		}
+
+		return visitor(key)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, word := range wl.Allowed {
+		word = strings.ToUpper(strings.TrimSpace(word))
+		if word != "" && dict.Get(word) == nil && !denied[word] {
+			if err := visitor(word); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Whether word is in the default dictionary.
+func IsWord(word string) bool {
+	return WordList{}.IsWord(word)
 }
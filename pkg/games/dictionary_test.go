@@ -1,7 +1,15 @@
 package games
 
 import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"testing"
+
+	"git.cipherboy.com/WillowPatchGames/wpg/pkg/middleware/figgy"
 )
 
 func TestWordlist(t *testing.T) {
@@ -25,3 +33,163 @@ func TestWordlist(t *testing.T) {
 		t.Fatal("catz is a word but shouldn't be")
 	}
 }
+
+func TestNamedDictionaries(t *testing.T) {
+	directory, err := ioutil.TempDir("", "wpg-dictionaries")
+	if err != nil {
+		t.Fatal("Unable to create dictionary directory:", err)
+	}
+	defer os.RemoveAll(directory)
+
+	SetDictionaryDirectory(directory)
+	defer SetDictionaryDirectory("/usr/share/dict")
+
+	if err := ioutil.WriteFile(filepath.Join(directory, "house"), []byte("zebra\nQuokka\n"), 0644); err != nil {
+		t.Fatal("Unable to write dictionary:", err)
+	}
+
+	if !DictionaryExists("house") || DictionaryExists("french") || DictionaryExists("../house") {
+		t.Fatal("Unexpected dictionaries available")
+	}
+
+	var house = WordList{Dictionary: "house"}
+	if !house.IsWord("zebra") || !house.IsWord("quokka") || house.IsWord("cat") {
+		t.Fatal("Expected only words from the house dictionary")
+	}
+
+	house.Allowed = []string{"Wombat"}
+	house.Denied = []string{"ZEBRA"}
+	if !house.IsWord("wombat") || house.IsWord("zebra") || !house.IsWord("quokka") {
+		t.Fatal("Expected room allow and deny lists to apply")
+	}
+
+	var walked []string
+	if err := house.Walk(func(word string) error {
+		walked = append(walked, word)
+		return nil
+	}); err != nil {
+		t.Fatal("Unable to walk dictionary:", err)
+	}
+
+	sort.Strings(walked)
+	if strings.Join(walked, ",") != "QUOKKA,WOMBAT" {
+		t.Fatal("Unexpected words walked:", walked)
+	}
+
+	// Updated word files are only picked up once reloaded.
+	if err := ioutil.WriteFile(filepath.Join(directory, "house"), []byte("zebra\nkoala\n"), 0644); err != nil {
+		t.Fatal("Unable to write dictionary:", err)
+	}
+
+	if house.IsWord("koala") {
+		t.Fatal("Didn't expect koala before reloading")
+	}
+
+	ReloadDictionaries()
+	if !house.IsWord("koala") || house.IsWord("quokka") {
+		t.Fatal("Expected the reloaded dictionary")
+	}
+
+	// Games may only use dictionaries that exist.
+	var config = RushConfig{NumPlayers: 2, NumTiles: 75, Frequency: 1, StartSize: 12, DrawSize: 1, DiscardPenalty: 3, Dictionary: 6}
+	if err := config.Validate(); err != nil {
+		t.Fatal("Expected the house dictionary to be valid:", err)
+	}
+
+	config.Dictionary = 3
+	if err := config.Validate(); err == nil {
+		t.Fatal("Expected a missing dictionary to be rejected")
+	}
+
+	// Word files are offered as dictionaries once the directory is reloaded.
+	if err := ioutil.WriteFile(filepath.Join(directory, "klingon"), []byte("qapla\n"), 0644); err != nil {
+		t.Fatal("Unable to write dictionary:", err)
+	}
+
+	ReloadDictionaries()
+	var labels = dictionaryLabels(t)
+	if !labels["Default word list"] || !labels["House word list"] || !labels["klingon"] || labels["French"] {
+		t.Fatal("Expected the options to follow the dictionary directory:", labels)
+	}
+
+	config.Dictionary = dictionaryValue("klingon")
+	if err := figgy.Validate(&config); err != nil || config.DictionaryName() != "klingon" || !(WordList{Dictionary: config.DictionaryName()}).IsWord("qapla") {
+		t.Fatal("Expected the klingon dictionary to be usable:", err, config.DictionaryName())
+	}
+
+	// Games which picked a dictionary keep it after its option goes away.
+	if err := os.Remove(filepath.Join(directory, "klingon")); err != nil {
+		t.Fatal("Unable to remove dictionary:", err)
+	}
+
+	ReloadDictionaries()
+	if dictionaryLabels(t)["klingon"] || config.DictionaryName() != "klingon" {
+		t.Fatal("Expected the klingon option to go away but its name to stay known")
+	}
+}
+
+// The labels of the options offered for the Dictionary option.
+func dictionaryLabels(t *testing.T) map[string]bool {
+	options, err := figgy.SerializeOptions(&RushConfig{})
+	if err != nil {
+		t.Fatal("Unable to serialize options:", err)
+	}
+
+	var ret = make(map[string]bool)
+	for _, option := range options {
+		if option["name"] != "dictionary" {
+			continue
+		}
+
+		encoded, err := json.Marshal(option)
+		if err != nil {
+			t.Fatal("Unable to encode option:", err)
+		}
+
+		var decoded struct {
+			Values struct {
+				Options []struct {
+					Label string `json:"label"`
+				} `json:"options"`
+			} `json:"values"`
+		}
+		if err := json.Unmarshal(encoded, &decoded); err != nil {
+			t.Fatal("Unable to decode option:", err)
+		}
+
+		for _, option := range decoded.Values.Options {
+			ret[option.Label] = true
+		}
+	}
+
+	return ret
+}
+
+func TestDictionaryOptions(t *testing.T) {
+	var config RushConfig
+	options, err := figgy.SerializeOptions(&config)
+	if err != nil {
+		t.Fatal("Unable to serialize options:", err)
+	}
+
+	for _, option := range options {
+		if option["name"] != "dictionary" {
+			continue
+		}
+
+		encoded, err := json.Marshal(option)
+		if err != nil {
+			t.Fatal("Unable to encode option:", err)
+		}
+
+		for _, dictionary := range dictionaryOptions {
+			if !strings.Contains(string(encoded), `"label":"`+dictionary.Label+`"`) {
+				t.Fatal("Expected the dictionary option to list", dictionary.Label, string(encoded))
+			}
+		}
+
+		return
+	}
+
+	t.Fatal("Expected a dictionary option:", options)
+}
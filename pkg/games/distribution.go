@@ -1,7 +1,14 @@
 package games
 
 import (
-	"git.cipherboy.com/WillowPatchGames/wpg/internal/utils"
+	"errors"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	math_rand "math/rand"
 )
 
 var standardFrequencies = map[string]float64{
@@ -91,12 +98,44 @@ var scrabbleFrequencies = map[string]float64{
 	"Z": 1.00,
 }
 
+// The 144 tiles in a real Bananagrams bag. The frequencies above are
+// rounded from these, so an exact bag uses the counts instead.
+var bananagramsBag = map[string]float64{
+	"A": 13,
+	"B": 3,
+	"C": 3,
+	"D": 6,
+	"E": 18,
+	"F": 3,
+	"G": 4,
+	"H": 3,
+	"I": 12,
+	"J": 2,
+	"K": 2,
+	"L": 5,
+	"M": 3,
+	"N": 8,
+	"O": 11,
+	"P": 3,
+	"Q": 2,
+	"R": 9,
+	"S": 6,
+	"T": 9,
+	"U": 6,
+	"V": 3,
+	"W": 3,
+	"X": 2,
+	"Y": 3,
+	"Z": 2,
+}
+
 var frequencyMap []map[string]float64 = []map[string]float64{
 	nil,
 	standardFrequencies,
 	bananagramsFrequencies,
 	scrabbleFrequencies,
 	nil,
+	nil,
 }
 
 type Frequency int
@@ -106,35 +145,148 @@ const (
 	StandardFreq    Frequency = iota
 	BananagramsFreq Frequency = iota
 	ScrabbleFreq    Frequency = iota
+	CustomFreq      Frequency = iota // Given by RushConfig.CustomFrequency.
 	EndFreqRange    Frequency = iota
 )
 
-func randomTile(freq Frequency) string {
+// Parse a custom letter distribution, such as "A:9 B:2 C:2 ...". Entries are
+// separated by spaces or commas, and each gives a letter (or a short group of
+// letters on a single tile) and its positive weight. Weights are relative, so
+// they may be percentages or tile counts.
+func ParseFrequencies(spec string) (map[string]float64, error) {
+	var ret = make(map[string]float64)
+	for _, entry := range strings.FieldsFunc(spec, func(r rune) bool { return r == ',' || unicode.IsSpace(r) }) {
+		var parts = strings.Split(entry, ":")
+		if len(parts) != 2 {
+			return nil, errors.New("expected letter:weight in letter distribution but got: " + entry)
+		}
+
+		var letter = strings.ToUpper(parts[0])
+		var runes = []rune(letter)
+		if len(runes) == 0 || len(runes) > 3 {
+			return nil, errors.New("expected one to three letters per tile but got: " + parts[0])
+		}
+
+		for _, r := range runes {
+			if !unicode.IsLetter(r) {
+				return nil, errors.New("expected only letters on a tile but got: " + parts[0])
+			}
+		}
+
+		weight, err := strconv.ParseFloat(parts[1], 64)
+		if err != nil || weight <= 0 || math.IsInf(weight, 0) || math.IsNaN(weight) {
+			return nil, errors.New("expected a positive weight for " + letter + " but got: " + parts[1])
+		}
+
+		if _, ok := ret[letter]; ok {
+			return nil, errors.New("letter appears twice in letter distribution: " + letter)
+		}
+
+		ret[letter] = weight
+	}
+
+	if len(ret) == 0 {
+		return nil, errors.New("letter distribution is empty")
+	}
+
+	return ret, nil
+}
+
+// Pick a letter at random following the given weights. Letters are visited
+// in order so that the same source always picks the same letter.
+func randomTile(weights map[string]float64, source *math_rand.Rand) string {
+	var letters = make([]string, 0, len(weights))
 	var sum float64 = 0.01
-	for letter := range frequencyMap[freq] {
-		sum += frequencyMap[freq][letter]
+	for letter := range weights {
+		letters = append(letters, letter)
+		sum += weights[letter]
 	}
+	sort.Strings(letters)
 
-	var choice float64 = utils.RandomFloat64() * sum
-	for letter := range frequencyMap[freq] {
-		choice -= frequencyMap[freq][letter]
+	var choice float64 = source.Float64() * sum
+	for _, letter := range letters {
+		choice -= weights[letter]
 		if choice <= 0.0 {
 			return letter
 		}
 	}
 
-	return "E"
+	// Rounding left us past the end; pick the most common letter.
+	var best string
+	for letter := range weights {
+		if best == "" || weights[letter] > weights[best] || (weights[letter] == weights[best] && letter < best) {
+			best = letter
+		}
+	}
+
+	return best
+}
+
+// Build an exact bag of count letters, with each letter's share in
+// proportion to its weight. Shares are rounded down and the leftover tiles go
+// to the letters with the largest remainders.
+func bagTiles(weights map[string]float64, count int) []string {
+	var letters = make([]string, 0, len(weights))
+	var sum float64 = 0
+	for letter, weight := range weights {
+		letters = append(letters, letter)
+		sum += weight
+	}
+	sort.Strings(letters)
+
+	var ret = make([]string, 0, count)
+	var remainders = make(map[string]float64)
+	for _, letter := range letters {
+		var share = weights[letter] * float64(count) / sum
+		var whole = int(math.Floor(share))
+		remainders[letter] = share - float64(whole)
+		for i := 0; i < whole && len(ret) < count; i++ {
+			ret = append(ret, letter)
+		}
+	}
+
+	sort.SliceStable(letters, func(i, j int) bool {
+		return remainders[letters[i]] > remainders[letters[j]]
+	})
+
+	for index := 0; len(ret) < count; index = (index + 1) % len(letters) {
+		ret = append(ret, letters[index])
+	}
+
+	return ret
 }
 
-func GenerateTiles(count int, wildcards bool, freq Frequency) []LetterTile {
+// Generate count tiles, of which blanks are blank tiles and the rest are
+// letters following the given weights: either drawn independently at random
+// or, when bag is set, as an exact bag scaled to the number of tiles.
+func GenerateTiles(count int, blanks int, weights map[string]float64, bag bool) []LetterTile {
+	return GenerateSeededTiles(NewShuffleSeed(), count, blanks, weights, bag)
+}
+
+// Generate tiles as GenerateTiles does, drawing from the next shuffle of the
+// given seed so that the tiles can be recomputed from it.
+func GenerateSeededTiles(seed *ShuffleSeed, count int, blanks int, weights map[string]float64, bag bool) []LetterTile {
 	var ret []LetterTile = make([]LetterTile, count)
+	var source = seed.Rand()
+
+	var letters []string
+	if bag && count > blanks {
+		letters = bagTiles(weights, count-blanks)
+	}
 
 	for index := range ret {
 		ret[index].ID = index + 1
-		ret[index].Value = randomTile(freq)
+		if index < blanks {
+			ret[index].Blank = true
+			ret[index].Unassign()
+		} else if bag {
+			ret[index].Value = letters[index-blanks]
+		} else {
+			ret[index].Value = randomTile(weights, source)
+		}
 	}
 
-	utils.SecureRand.Shuffle(len(ret), func(i, j int) {
+	source.Shuffle(len(ret), func(i, j int) {
 		ret[i], ret[j] = ret[j], ret[i]
 	})
 
@@ -0,0 +1,71 @@
+package games
+
+import (
+	"testing"
+)
+
+func TestParseFrequencies(t *testing.T) {
+	weights, err := ParseFrequencies("a:9, B:2\nñ:1 ch:0.5")
+	if err != nil {
+		t.Fatal("Unexpected error parsing distribution:", err)
+	}
+
+	if len(weights) != 4 || weights["A"] != 9 || weights["B"] != 2 || weights["Ñ"] != 1 || weights["CH"] != 0.5 {
+		t.Fatal("Unexpected weights:", weights)
+	}
+
+	for _, spec := range []string{"", "A", "A:0", "A:-1", "A:x", "1:3", "A:1 A:2", "ABCD:1"} {
+		if _, err := ParseFrequencies(spec); err == nil {
+			t.Fatal("Expected error parsing distribution:", spec)
+		}
+	}
+}
+
+func TestGenerateTilesBag(t *testing.T) {
+	var tiles = GenerateTiles(144, 0, bananagramsBag, true)
+	var counts = make(map[string]int)
+	for _, tile := range tiles {
+		counts[tile.Value]++
+	}
+
+	for letter, count := range bananagramsBag {
+		if counts[letter] != int(count) {
+			t.Fatal("Expected", count, "of", letter, "in a full bag but got", counts[letter])
+		}
+	}
+
+	// Scaled bags keep the total exact and can't run away with rare letters.
+	tiles = GenerateTiles(75, 3, bananagramsBag, true)
+	counts = make(map[string]int)
+	var blanks = 0
+	for _, tile := range tiles {
+		if tile.Blank {
+			blanks++
+		} else {
+			counts[tile.Value]++
+		}
+	}
+
+	if len(tiles) != 75 || blanks != 3 {
+		t.Fatal("Unexpected bag size or blanks:", len(tiles), counts)
+	}
+
+	if counts["Q"] > 1 || counts["E"] < 9 || counts["E"] > 10 {
+		t.Fatal("Unexpected scaled bag:", counts)
+	}
+
+	var config = RushConfig{NumPlayers: 2, NumTiles: 30, Frequency: CustomFreq, CustomFrequency: "A:1 B:x", StartSize: 5, DrawSize: 1}
+	if err := config.Validate(); err == nil {
+		t.Fatal("Expected invalid custom distribution to fail validation")
+	}
+
+	config.CustomFrequency = "Α:5 Β:1"
+	if err := config.Validate(); err != nil {
+		t.Fatal("Expected custom distribution to pass validation:", err)
+	}
+
+	weights, err := config.LetterWeights()
+	if err != nil || weights["Α"] != 5 {
+		t.Fatal("Unexpected custom weights:", weights, err)
+	}
+}
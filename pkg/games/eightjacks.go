@@ -63,7 +63,7 @@ type EightJacksBoardMode int
 
 const (
 	SquareGridEightJacks       EightJacksBoardMode = iota // 0
-	HexGridEightJacks          EightJacksBoardMode = iota // 1
+	HexGridEightJacks          EightJacksBoardMode = iota // 1 -- rows of hexagons, with odd rows shifted right.
 	DoubleSquareGridEightJacks EightJacksBoardMode = iota // 2 -- a tiling pattern using two different sizes of squares.
 )
 
@@ -142,31 +142,87 @@ type EightJacksConfig struct {
 	RunLength int `json:"run_length" config:"type:int,min:2,default:4,max:6" label:"Run length"` // Length of a run for it to count.
 	WinLimit  int `json:"win_limit" config:"type:int,min:1,default:2,max:5" label:"Win limit"`   // Number of runs to win.
 
-	BoardWidth   int  `json:"board_width" config:"type:int,min:8,default:10,max:10" label:"Board width"`
-	BoardHeight  int  `json:"board_height" config:"type:int,min:8,default:10,max:10" label:"Board height"`
-	RemoveUnused bool `json:"remove_unused" config:"type:bool,default:true" label:"true:Remove cards not used on the board,false:Keep all cards even if not present on the board"` // Whether to remove cards not used on the board.
-	WildCorners  bool `json:"wild_corners" config:"type:bool,default:true" label:"true:Add wild cards in the corners,false:Don't fill in corners with wild cards"`                 // Whether corners are wild (free for everyone to play on).
-	BoardLayout  int  `json:"board_layout" config:"type:enum,default:1,options:1:Sorted;2:Spiral;3:Pinwheel;4:Random" label:"Board layout"`
+	BoardWidth   int    `json:"board_width" config:"type:int,min:8,default:10,max:16" label:"Board width"` // Boards with more than 96 squares use extra decks.
+	BoardHeight  int    `json:"board_height" config:"type:int,min:8,default:10,max:16" label:"Board height"`
+	RemoveUnused bool   `json:"remove_unused" config:"type:bool,default:true" label:"true:Remove cards not used on the board,false:Keep all cards even if not present on the board"` // Whether to remove cards not used on the board.
+	WildCorners  bool   `json:"wild_corners" config:"type:bool,default:true" label:"true:Add wild cards in the corners,false:Don't fill in corners with wild cards"`                 // Whether corners are wild (free for everyone to play on).
+	BoardLayout  int    `json:"board_layout" config:"type:enum,default:1,options:1:Sorted;2:Spiral;3:Pinwheel;4:Random;5:Custom" label:"Board layout"`
+	BoardShape   int    `json:"board_shape" config:"type:enum,default:0,options:0:Square grid;1:Hexagonal grid;2:Large and small squares" label:"Board shape"` // See EightJacksBoardMode.
+	CustomLayout string `json:"custom_layout" config:"type:string,max:2048" label:"Custom board layout"`                                                       // See ParseEJSLayout; only used with the custom board layout.
 
 	HandSize   int `json:"hand_size" config:"type:int,min:2,default:7,max:15" label:"Hand size"`     // Number of cards in the hand.
 	JokerCount int `json:"joker_count" config:"type:int,min:0,default:8,max:16" label:"Joker count"` // "dual-use jacks".
 
 	// Common game configuration options
 	Countdown bool `json:"countdown" config:"type:bool,default:true" label:"true:Show a 3... 2... 1... countdown before beginning,false:Start the game instantly"` // Whether to wait and send countdown messages.
+
+	// Chess clock style limits on how long each player may take.
+	TurnClock   int `json:"turn_clock" config:"type:int,min:0,default:0,max:600" label:"Seconds per turn (0 for no limit)"`
+	GameClock   int `json:"game_clock" config:"type:int,min:0,default:0,max:120" label:"Minutes per player for the whole game (0 for no limit)"`
+	ClockPolicy int `json:"clock_policy" config:"type:enum,default:0,options:0:Play automatically;1:Skip their turn;2:Forfeit the game" label:"When a player runs out of time"` // See ClockPolicy.
 }
 
 func (cfg EightJacksConfig) Validate() error {
-	if cfg.BoardWidth == 10 && cfg.BoardHeight == 10 && !cfg.WildCorners {
-		return GameConfigError{"wild corners", strconv.Itoa(cfg.JokerCount), "true if using a 10x10 board"}
+	// Without wild corners, a 10x10 board has more squares than two decks
+	// can fill evenly.
+	if cfg.BoardLayout != 5 && cfg.BoardWidth == 10 && cfg.BoardHeight == 10 && !cfg.WildCorners {
+		return GameConfigError{"wild corners", strconv.FormatBool(cfg.WildCorners), "true if using a 10x10 board"}
+	}
+
+	if cfg.BoardLayout == 3 && (cfg.BoardWidth != 10 || cfg.BoardHeight != 10 || !cfg.WildCorners) {
+		return GameConfigError{"board width and height", strconv.Itoa(cfg.BoardWidth) + "," + strconv.Itoa(cfg.BoardHeight), "10x10 with wild corners if using a pinwheel board layout"}
 	}
 
-	if cfg.BoardLayout == 3 && (cfg.BoardWidth != 10 || cfg.BoardHeight != 10) {
-		return GameConfigError{"board width and height", strconv.Itoa(cfg.BoardWidth) + "," + strconv.Itoa(cfg.BoardHeight), "10x10 if using a pinwheel board layout"}
+	if cfg.BoardLayout == 5 {
+		layout, err := ejs_board_cache.GetCustom(cfg.CustomLayout)
+		if err != nil {
+			return GameConfigError{"custom board layout", cfg.CustomLayout, "a valid layout: " + err.Error()}
+		}
+
+		if layout.Width < cfg.RunLength && layout.Height < cfg.RunLength {
+			return GameConfigError{"custom board layout", strconv.Itoa(layout.Width) + "x" + strconv.Itoa(layout.Height), "large enough to fit a run of " + strconv.Itoa(cfg.RunLength)}
+		}
 	}
 
 	return nil
 }
 
+// Width and height of the board, taken from the custom layout when one is
+// used.
+func (cfg EightJacksConfig) BoardSize() (int, int) {
+	if cfg.BoardLayout == 5 {
+		if layout, err := ejs_board_cache.GetCustom(cfg.CustomLayout); err == nil {
+			return layout.Width, layout.Height
+		}
+	}
+
+	return cfg.BoardWidth, cfg.BoardHeight
+}
+
+// Number of standard decks the game is played with. Every non-jack card
+// needs to appear on the board equally often, so boards with more squares
+// than two decks can fill use additional decks. Custom layouts say how many
+// decks they need.
+func (cfg EightJacksConfig) NumDecks() int {
+	if cfg.BoardLayout == 5 {
+		if layout, err := ejs_board_cache.GetCustom(cfg.CustomLayout); err == nil {
+			return layout.Decks
+		}
+	}
+
+	var squares = cfg.BoardWidth * cfg.BoardHeight
+	if cfg.WildCorners {
+		squares -= 4
+	}
+
+	var decks = (squares + ejsCardsPerDeck - 1) / ejsCardsPerDeck
+	if decks < 2 {
+		decks = 2
+	}
+
+	return decks
+}
+
 type EightJacksTurn struct {
 	Player int `json:"player"`
 
@@ -191,6 +247,7 @@ type EightJacksState struct {
 
 	GlobalHistory []Card           `json:"global_history"`
 	TurnHistory   []EightJacksTurn `json:"turn_history"`
+	InitialDeck   []*Card          `json:"initial_deck"` // The deck as shuffled, before dealing.
 
 	Config EightJacksConfig `json:"config"`
 
@@ -242,11 +299,89 @@ func (ejs *EightJacksState) IsFinished() bool {
 	return ejs.Finished
 }
 
+// The deck, and with it a randomly laid out board, is shuffled from the
+// seed's first round, so that revealing it doesn't give away the secret.
+func (ejs *EightJacksState) SetShuffleSeed(seed *ShuffleSeed) error {
+	if ejs.Started {
+		return errors.New("unable to set the shuffle seed after the game has started")
+	}
+
+	ejs.Deck.Seed = seed.Derive(1)
+	return nil
+}
+
+// The deck's seed, once the game is over.
+func (ejs *EightJacksState) RevealedSeeds() []*ShuffleSeed {
+	if !ejs.Finished || ejs.Deck.Seed == nil {
+		return []*ShuffleSeed{}
+	}
+
+	return []*ShuffleSeed{ejs.Deck.Seed}
+}
+
 func (ejs *EightJacksState) ResetStatus() {
 	ejs.Started = false
 	ejs.Finished = false
 }
 
+func (ejs *EightJacksState) TurnClocks() TurnClocks {
+	return newTurnClocks(ejs.Config.TurnClock, ejs.Config.GameClock, ejs.Config.ClockPolicy)
+}
+
+func (ejs *EightJacksState) ClockedPlayer() int {
+	if !ejs.Started || ejs.Finished || !ejs.Dealt {
+		return -1
+	}
+
+	return ejs.Turn
+}
+
+func (ejs *EightJacksState) Timeout(player int, policy ClockPolicy) error {
+	if player != ejs.ClockedPlayer() {
+		return errors.New("not your turn")
+	}
+
+	if policy == SkipOnTimeout {
+		ejs.Turn = (ejs.Turn + 1) % len(ejs.Players)
+		return nil
+	}
+
+	if policy == ForfeitOnTimeout {
+		// The other team with the most runs wins.
+		var winner = -1
+		for index, indexed_player := range ejs.Players {
+			if indexed_player.Team != ejs.Players[player].Team && (winner == -1 || len(indexed_player.Runs) > len(ejs.Players[winner].Runs)) {
+				winner = index
+			}
+		}
+
+		ejs.Winners = make([]int, 0)
+		for index, indexed_player := range ejs.Players {
+			if winner != -1 && indexed_player.Team == ejs.Players[winner].Team {
+				ejs.Winners = append(ejs.Winners, index)
+			}
+		}
+
+		ejs.Finished = true
+		ejs.Turn = -1
+		ejs.Dealer = -1
+		return errors.New(EightJacksGameOver)
+	}
+
+	// Play the lowest card on the first square which takes it.
+	return playLowestCard(ejs.Players[player].Hand, true, func(card int) error {
+		var err error = errors.New("no square to play the card on")
+		for _, square := range ejs.Board.Squares {
+			err = ejs.PlayCard(player, card, square.ID)
+			if err == nil || isRoundSignal(err) {
+				return err
+			}
+		}
+
+		return err
+	})
+}
+
 func (ejs *EightJacksState) Start() error {
 	var err error
 
@@ -321,10 +456,13 @@ func (ejs *EightJacksState) StartRound() error {
 		return err
 	}
 
-	// Start with two decks and shuffle them. This will be used to create the board.
+	// Start with the same decks used to create the board; these get shuffled
+	// and dealt below.
+	var decks = ejs.Config.NumDecks()
 	ejs.Deck.Init()
-	ejs.Deck.AddStandard52Deck()
-	ejs.Deck.AddStandard52Deck()
+	for i := 0; i < decks; i++ {
+		ejs.Deck.AddStandard52Deck()
+	}
 
 	if ejs.Config.RemoveUnused {
 		// Remove cards that aren't present on the board -- except jacks.
@@ -345,9 +483,10 @@ func (ejs *EightJacksState) StartRound() error {
 				}
 
 				if !have_card {
-					// Remove twice -- one for each deck.
-					ejs.Deck.RemoveCard(target, suit)
-					ejs.Deck.RemoveCard(target, suit)
+					// Remove once for each deck.
+					for i := 0; i < decks; i++ {
+						ejs.Deck.RemoveCard(target, suit)
+					}
 				}
 			}
 		}
@@ -360,6 +499,7 @@ func (ejs *EightJacksState) StartRound() error {
 
 	// Shuffle the deck.
 	ejs.Deck.Shuffle()
+	ejs.InitialDeck = CopyDeck(ejs.Deck.Cards)
 
 	starting_player := (ejs.Dealer + 1) % len(ejs.Players)
 	for i := 0; i < ejs.Config.HandSize; i++ {
@@ -377,17 +517,23 @@ func (ejs *EightJacksState) StartRound() error {
 }
 
 func (ejs *EightJacksState) CreateBoard() error {
-	ejs.Board.Init(ejs.Config.BoardWidth, ejs.Config.BoardHeight)
+	width, height := ejs.Config.BoardSize()
+	ejs.Board.Init(width, height)
+	ejs.Board.Connectivity = EightJacksBoardMode(ejs.Config.BoardShape)
 
-	// Sorted, spiral, or random order use the same logic.
+	// Sorted, spiral, or random order use the same logic. Custom layouts don't
+	// use the deck.
+	var decks = ejs.Config.NumDecks()
 	ejs.Deck.Init()
-	ejs.Deck.AddStandard52Deck()
-	ejs.Deck.AddStandard52Deck()
+	for i := 0; i < decks; i++ {
+		ejs.Deck.AddStandard52Deck()
+	}
 
 	for _, suit := range StandardCardSuits {
-		// Remove twice -- one for each deck.
-		ejs.Deck.RemoveCard(JackRank, suit)
-		ejs.Deck.RemoveCard(JackRank, suit)
+		// Remove once for each deck.
+		for i := 0; i < decks; i++ {
+			ejs.Deck.RemoveCard(JackRank, suit)
+		}
 	}
 
 	// Random order
@@ -411,12 +557,17 @@ func (ejs *EightJacksState) CreateBoard() error {
 			is_corner = is_corner || (x == 0 && y == ejs.Board.Height-1)
 			is_corner = is_corner || (x == ejs.Board.Width-1 && y == 0)
 			is_corner = is_corner || (x == ejs.Board.Width-1 && y == ejs.Board.Height-1)
-			if is_corner && ejs.Config.WildCorners {
+			if is_corner && ejs.Config.WildCorners && ejs.Config.BoardLayout != 5 {
 				piece.Value.ID = 0
 				piece.Value.Rank = JokerRank
 				piece.Value.Suit = NoneSuit
 			} else {
-				piece.Value = EJSBoardIndexScheme(&ejs.Deck, ejs.Config.BoardLayout, ejs.Board.Width, ejs.Board.Height, x, y, ejs.Config.WildCorners)
+				value, err := EJSBoardIndexScheme(&ejs.Deck, ejs.Config.BoardLayout, ejs.Config.CustomLayout, ejs.Board.Width, ejs.Board.Height, x, y, ejs.Config.WildCorners)
+				if err != nil {
+					return err
+				}
+
+				piece.Value = value
 				piece.Value.ID = 0
 			}
 
@@ -645,88 +796,43 @@ func (ejs *EightJacksState) MarkRun(run []int) error {
 		return errors.New("can only mark runs of size " + strconv.Itoa(ejs.Config.RunLength))
 	}
 
-	// XXX: HACK: because we construct identifiers in increasing order, left to
-	// right, top to bottom, we can sort the identifiers given in run -- when we
-	// iterate, we know that the previous tile always occurs earlier on the board.
-	// This lets us check for a valid run by iterating over tiles, checking that
-	// the marker lines up, and that it is connected either diagonally (nw/se or
-	// ne/sw), up/down, or left/right. If it is valid, we can further validate it
-	// against the previous list of runs in players' own assignments.
+	// Sort the identifiers so runs are stored consistently regardless of the
+	// order they were selected in; whether they form a line on this board's
+	// connectivity is checked by the board itself.
 	sort.Ints(run)
 
-	is_diagonal_se := true
-	is_diagonal_sw := true
-	is_down := true
-	is_right := true
-
 	have_marked := false
 	marker := -1
 
-	for index := 1; index < len(run); index++ {
-		last_tile_id := run[index-1]
-		this_tile_id := run[index]
-
-		last_tile, found := ejs.Board.IDMapped[last_tile_id]
-		if !found {
-			return errors.New("unable to find last square with specified id")
-		}
-
-		this_tile, found := ejs.Board.IDMapped[this_tile_id]
+	var tiles = make([]*EightJacksSquare, 0, len(run))
+	for _, tile_id := range run {
+		tile, found := ejs.Board.IDMapped[tile_id]
 		if !found {
-			return errors.New("unable to find this square with specified id")
-		}
-
-		if last_tile.Marker != -1 {
-			have_marked = true
-			if marker == -1 {
-				marker = last_tile.Marker
-			}
-		} else if last_tile.Value.Rank != JokerRank {
-			return errors.New("square was not marked")
+			return errors.New("unable to find square with specified id")
 		}
 
-		if this_tile.Marker != -1 {
+		if tile.Marker != -1 {
 			have_marked = true
 			if marker == -1 {
-				marker = this_tile.Marker
+				marker = tile.Marker
+			} else if tile.Marker != marker {
+				return errors.New("run is covered with different colored markers")
 			}
-		} else if this_tile.Value.Rank != JokerRank {
+		} else if tile.Value.Rank != JokerRank {
 			return errors.New("square was not marked")
 		}
 
-		if marker != -1 && this_tile.Marker != -1 && this_tile.Marker != marker {
-			return errors.New("run is covered with different colored markers")
-		}
-
-		if last_tile.X+1 != this_tile.X || last_tile.Y+1 != this_tile.Y {
-			is_diagonal_se = false
-		}
-
-		if last_tile.X+1 != this_tile.X || last_tile.Y-1 != this_tile.Y {
-			is_diagonal_sw = false
-		}
-
-		if last_tile.X != this_tile.X || last_tile.Y+1 != this_tile.Y {
-			is_down = false
-		}
-
-		if last_tile.X+1 != this_tile.X || last_tile.Y != this_tile.Y {
-			is_right = false
-		}
+		tiles = append(tiles, tile)
 	}
 
 	if !have_marked {
 		return errors.New("all squares in a run must be played on first or be wild")
 	}
 
-	if !is_diagonal_se && !is_diagonal_sw && !is_down && !is_right {
+	if !ejs.Board.IsLine(tiles) {
 		return errors.New("must have connected squares in the run")
 	}
 
-	if is_diagonal_se && is_diagonal_sw && is_down && is_right {
-		return errors.New("confusing board -- internal invariant violated")
-	}
-
 	// Validate that we don't overlap with more than one square from anyone
 	// else's run.
 	for _, indexed_player := range ejs.Players {
@@ -1,11 +1,288 @@
 package games
 
 import (
+	"errors"
 	"log"
-
+	"strconv"
+	"strings"
 	"sync"
+	"unicode"
 )
 
+// Axes along which runs may be formed, as (x, y) steps. Square grids (and
+// double square grids) can form runs down, right, and along both diagonals.
+var ejsSquareAxes = [][2]int{{0, 1}, {1, 0}, {1, 1}, {1, -1}}
+
+// Hex grids are stored as rows (y) of cells (x), with odd rows shifted half
+// a cell to the right. Steps along the three hex axes are given in axial
+// coordinates (q, r): east/west, southeast/northwest and southwest/northeast.
+var ejsHexAxes = [][2]int{{1, 0}, {0, 1}, {-1, 1}}
+
+// Number of axes along which runs can be formed.
+func (ejb *EightJacksBoard) Axes() int {
+	if ejb.Connectivity == HexGridEightJacks {
+		return len(ejsHexAxes)
+	}
+
+	return len(ejsSquareAxes)
+}
+
+// In the double square grid, large squares and small squares alternate like
+// a checkerboard. Small squares sit in the gaps between the corners of the
+// large squares, so they only connect along rows and columns; diagonal runs
+// are made from large squares alone.
+func (ejb *EightJacksBoard) IsLargeSquare(square *EightJacksSquare) bool {
+	return ejb.Connectivity != DoubleSquareGridEightJacks || (square.X+square.Y)%2 == 0
+}
+
+// Find the square the given number of steps away along an axis; negative
+// steps go the other way. Returns false when there is no such square or it
+// isn't connected to this one along that axis.
+func (ejb *EightJacksBoard) Step(square *EightJacksSquare, axis int, steps int) (*EightJacksSquare, bool) {
+	if axis < 0 || axis >= ejb.Axes() {
+		return nil, false
+	}
+
+	var x = square.X
+	var y = square.Y
+
+	if ejb.Connectivity == HexGridEightJacks {
+		// Convert to axial coordinates, step, and convert back.
+		var q = x - (y-(y&1))/2
+		var r = y
+		q += ejsHexAxes[axis][0] * steps
+		r += ejsHexAxes[axis][1] * steps
+		x = q + (r-(r&1))/2
+		y = r
+	} else {
+		if ejsSquareAxes[axis][0] != 0 && ejsSquareAxes[axis][1] != 0 && !ejb.IsLargeSquare(square) {
+			return nil, false
+		}
+
+		x += ejsSquareAxes[axis][0] * steps
+		y += ejsSquareAxes[axis][1] * steps
+	}
+
+	next, ok := ejb.XYMapped[x][y]
+	return next, ok
+}
+
+// All squares connected to the given square.
+func (ejb *EightJacksBoard) Neighbors(square *EightJacksSquare) []*EightJacksSquare {
+	var ret []*EightJacksSquare
+	for axis := 0; axis < ejb.Axes(); axis++ {
+		for _, steps := range []int{-1, 1} {
+			if next, ok := ejb.Step(square, axis, steps); ok {
+				ret = append(ret, next)
+			}
+		}
+	}
+
+	return ret
+}
+
+// Whether the given squares (in any order) form a single straight line.
+func (ejb *EightJacksBoard) IsLine(squares []*EightJacksSquare) bool {
+	var wanted = make(map[int]bool)
+	for _, square := range squares {
+		wanted[square.ID] = true
+	}
+
+	if len(squares) == 0 || len(wanted) != len(squares) {
+		return false
+	}
+
+	for _, start := range squares {
+		for axis := 0; axis < ejb.Axes(); axis++ {
+			var found = true
+			for steps := 1; steps < len(squares) && found; steps++ {
+				next, ok := ejb.Step(start, axis, steps)
+				found = ok && wanted[next.ID]
+			}
+
+			if found {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Every straight line of the given length on the board.
+func (ejb *EightJacksBoard) Lines(length int) [][]*EightJacksSquare {
+	var ret [][]*EightJacksSquare
+
+	for _, square := range ejb.Squares {
+		for axis := 0; axis < ejb.Axes(); axis++ {
+			var line = []*EightJacksSquare{square}
+			for steps := 1; steps < length; steps++ {
+				next, ok := ejb.Step(square, axis, steps)
+				if !ok {
+					break
+				}
+
+				line = append(line, next)
+			}
+
+			if len(line) == length {
+				ret = append(ret, line)
+			}
+		}
+	}
+
+	return ret
+}
+
+// Number of distinct non-jack cards in a standard deck; each deck added to
+// the game can fill this many squares on the board.
+const ejsCardsPerDeck = 48
+
+// Largest board (in either dimension) we'll create, whether from the
+// configured width and height or from a custom layout.
+const ejsMaxBoardSize = 16
+
+// A custom board layout, uploaded by the room owner as a grid of card codes.
+type EJSLayout struct {
+	Width  int `json:"width"`
+	Height int `json:"height"`
+
+	// Cards on the board, indexed by x and then y. Wild squares hold a joker.
+	Cards [][]Card `json:"cards"`
+
+	// Number of decks needed to fill the board: every non-jack card must
+	// appear exactly this many times.
+	Decks int `json:"decks"`
+}
+
+// Parse a card code such as "AS", "10H", "TD" or "kc" into a card. A single
+// "*" denotes a wild square. Jacks can't be placed on the board.
+func ejsParseCardCode(code string) (Card, error) {
+	var card Card
+	if code == "*" {
+		card.Rank = JokerRank
+		card.Suit = NoneSuit
+		return card, nil
+	}
+
+	code = strings.ToUpper(code)
+	if len(code) < 2 {
+		return card, errors.New("unknown card code: " + code)
+	}
+
+	switch code[len(code)-1] {
+	case 'C':
+		card.Suit = ClubsSuit
+	case 'H':
+		card.Suit = HeartsSuit
+	case 'S':
+		card.Suit = SpadesSuit
+	case 'D':
+		card.Suit = DiamondsSuit
+	default:
+		return card, errors.New("unknown suit in card code: " + code)
+	}
+
+	var rank = code[:len(code)-1]
+	switch rank {
+	case "A":
+		card.Rank = AceRank
+	case "T":
+		card.Rank = TenRank
+	case "Q":
+		card.Rank = QueenRank
+	case "K":
+		card.Rank = KingRank
+	case "J":
+		return card, errors.New("jacks can't be placed on the board: " + code)
+	default:
+		value, err := strconv.Atoi(rank)
+		if err != nil || value < int(TwoRank) || value > int(TenRank) {
+			return card, errors.New("unknown rank in card code: " + code)
+		}
+		card.Rank = CardRank(value)
+	}
+
+	return card, nil
+}
+
+// Parse a custom board layout. Rows are separated by newlines or slashes,
+// and cells within a row by spaces or commas. Each cell is a card code (see
+// ejsParseCardCode) or "*" for a wild square. Every row must be the same
+// length, and every non-jack card must appear the same number of times; that
+// count is the number of decks the game is played with.
+func ParseEJSLayout(layout string) (*EJSLayout, error) {
+	var rows [][]string
+	for _, line := range strings.FieldsFunc(layout, func(r rune) bool { return r == '\n' || r == '/' }) {
+		var cells = strings.FieldsFunc(line, func(r rune) bool { return r == ',' || unicode.IsSpace(r) })
+		if len(cells) == 0 {
+			continue
+		}
+
+		if len(rows) > 0 && len(cells) != len(rows[0]) {
+			return nil, errors.New("every row of the board layout must have the same number of squares; row " + strconv.Itoa(len(rows)+1) + " has " + strconv.Itoa(len(cells)) + " but expected " + strconv.Itoa(len(rows[0])))
+		}
+
+		rows = append(rows, cells)
+	}
+
+	if len(rows) == 0 {
+		return nil, errors.New("board layout is empty")
+	}
+
+	var ret = new(EJSLayout)
+	ret.Width = len(rows[0])
+	ret.Height = len(rows)
+	if ret.Width > ejsMaxBoardSize || ret.Height > ejsMaxBoardSize {
+		return nil, errors.New("board layout can be at most " + strconv.Itoa(ejsMaxBoardSize) + " squares wide and tall")
+	}
+
+	var counts = make(map[CardSuit]map[CardRank]int)
+	for _, suit := range StandardCardSuits {
+		counts[suit] = make(map[CardRank]int)
+	}
+
+	ret.Cards = make([][]Card, ret.Width)
+	for x := 0; x < ret.Width; x++ {
+		ret.Cards[x] = make([]Card, ret.Height)
+		for y := 0; y < ret.Height; y++ {
+			card, err := ejsParseCardCode(rows[y][x])
+			if err != nil {
+				return nil, err
+			}
+
+			ret.Cards[x][y] = card
+			if card.Rank != JokerRank {
+				counts[card.Suit][card.Rank]++
+			}
+		}
+	}
+
+	ret.Decks = counts[ClubsSuit][AceRank]
+	for _, suit := range StandardCardSuits {
+		for _, rank := range StandardCardRanks {
+			if rank == JackRank {
+				continue
+			}
+
+			var card = Card{0, suit, rank}
+			if counts[suit][rank] != ret.Decks {
+				return nil, errors.New("every card must appear on the board the same number of times: found " + strconv.Itoa(counts[suit][rank]) + " of " + card.String() + " but " + strconv.Itoa(ret.Decks) + " of " + Card{0, ClubsSuit, AceRank}.String())
+			}
+		}
+	}
+
+	if ret.Decks == 0 {
+		return nil, errors.New("board layout must include every non-jack card at least once")
+	}
+
+	return ret, nil
+}
+
+// Cap on the number of distinct custom layouts we keep parsed; these come
+// from users, so we don't want to cache them without bound.
+const ejsMaxCachedLayouts = 64
+
 type EJSBoardCache struct {
 	mutex       sync.Mutex
 	initialized bool
@@ -17,6 +294,9 @@ type EJSBoardCache struct {
 	// int: x coordinate
 	// int: y coordinate
 	// int: Value: deck index to pull from
+	//
+	// The common board sizes are computed up front; others are computed the
+	// first time they're requested.
 	Spiral map[bool]map[int]map[int]map[int]map[int]int
 
 	// Key Structure:
@@ -24,16 +304,19 @@ type EJSBoardCache struct {
 	// int: y coordinate
 	// int: Value: deck index to pull from
 	Pinwheel map[int]map[int]int
+
+	// Parsed custom layouts, keyed by the layout as given by the user.
+	Custom map[string]*EJSLayout
 }
 
 func (ebc *EJSBoardCache) Initialize() {
-	if ebc.initialized {
-		return
-	}
-
 	ebc.mutex.Lock()
 	defer ebc.mutex.Unlock()
 
+	ebc.initialize()
+}
+
+func (ebc *EJSBoardCache) initialize() {
 	if ebc.initialized {
 		return
 	}
@@ -45,23 +328,64 @@ func (ebc *EJSBoardCache) Initialize() {
 		for width := 8; width <= 10; width++ {
 			ebc.Spiral[wild_corners][width] = make(map[int]map[int]map[int]int)
 			for height := 8; height <= 10; height++ {
-				if !wild_corners && width == 10 && height == 10 {
-					continue
-				}
-
 				ebc.Spiral[wild_corners][width][height] = ejsComputeSpiral(wild_corners, width, height)
 			}
 		}
 	}
 
 	ebc.Pinwheel = ejsComputePinwheel()
+	ebc.Custom = make(map[string]*EJSLayout)
 
 	ebc.initialized = true
 }
 
+// Get the spiral indexing scheme for a board of the given size, computing
+// and caching it if necessary.
+func (ebc *EJSBoardCache) GetSpiral(wild_corners bool, width int, height int) map[int]map[int]int {
+	ebc.mutex.Lock()
+	defer ebc.mutex.Unlock()
+
+	ebc.initialize()
+
+	if _, ok := ebc.Spiral[wild_corners][width]; !ok {
+		ebc.Spiral[wild_corners][width] = make(map[int]map[int]map[int]int)
+	}
+
+	if _, ok := ebc.Spiral[wild_corners][width][height]; !ok {
+		ebc.Spiral[wild_corners][width][height] = ejsComputeSpiral(wild_corners, width, height)
+	}
+
+	return ebc.Spiral[wild_corners][width][height]
+}
+
+// Get the parsed form of a custom layout, parsing and caching it if
+// necessary. The result is shared and must not be modified.
+func (ebc *EJSBoardCache) GetCustom(layout string) (*EJSLayout, error) {
+	ebc.mutex.Lock()
+	defer ebc.mutex.Unlock()
+
+	ebc.initialize()
+
+	if parsed, ok := ebc.Custom[layout]; ok {
+		return parsed, nil
+	}
+
+	parsed, err := ParseEJSLayout(layout)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ebc.Custom) >= ejsMaxCachedLayouts {
+		ebc.Custom = make(map[string]*EJSLayout)
+	}
+
+	ebc.Custom[layout] = parsed
+	return parsed, nil
+}
+
 var ejs_board_cache EJSBoardCache
 
-func EJSBoardIndexScheme(deck *Deck, layout int, width int, height int, x int, y int, wild_corners bool) Card {
+func EJSBoardIndexScheme(deck *Deck, layout int, custom string, width int, height int, x int, y int, wild_corners bool) (Card, error) {
 	// XXX: Hack: Since the identifiers must be preserved, we need to iterate
 	// over the board left to right, top to bottom to preserve the scheme
 	// (or compute it artificially). However, since the main eightjacks file is
@@ -72,20 +396,34 @@ func EJSBoardIndexScheme(deck *Deck, layout int, width int, height int, x int, y
 	// cached into the ejs_board_cache variable and initialized once per server
 	// startup. This allows us to compute the answer (rather than hard-coding it),
 	// while limiting the computation to a single time.
+	//
+	// Custom layouts don't use the deck at all: they already say which card
+	// goes on each square.
 	ejs_board_cache.Initialize()
 
 	if layout == 1 || layout == 4 {
 		// Sorted and Random layouts can simply take the top card. No need to go
 		// into the cache.
-		return *deck.Draw()
+		return *deck.Draw(), nil
 	} else if layout == 2 {
-		cache := ejs_board_cache.Spiral[wild_corners][width][height]
-		return *deck.Cards[cache[x][y]]
+		cache := ejs_board_cache.GetSpiral(wild_corners, width, height)
+		return *deck.Cards[cache[x][y]], nil
 	} else if layout == 3 {
-		return *deck.Cards[ejs_board_cache.Pinwheel[x][y]]
+		return *deck.Cards[ejs_board_cache.Pinwheel[x][y]], nil
+	} else if layout == 5 {
+		parsed, err := ejs_board_cache.GetCustom(custom)
+		if err != nil {
+			return Card{}, errors.New("invalid custom board layout: " + err.Error())
+		}
+
+		if x >= parsed.Width || y >= parsed.Height {
+			return Card{}, errors.New("custom board layout is smaller than the board")
+		}
+
+		return parsed.Cards[x][y], nil
 	}
 
-	panic("unknown type of request")
+	return Card{}, errors.New("unknown board layout: " + strconv.Itoa(layout))
 }
 
 func ejsComputeSpiral(wild_corners bool, width int, height int) map[int]map[int]int {
@@ -0,0 +1,239 @@
+package games
+
+import (
+	"strings"
+	"testing"
+)
+
+func newTestEightJacksBoard(connectivity EightJacksBoardMode, width int, height int) *EightJacksBoard {
+	var board EightJacksBoard
+	board.Init(width, height)
+	board.Connectivity = connectivity
+
+	id := 1
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			square := &EightJacksSquare{ID: id, X: x, Y: y, Marker: -1, WhoMarked: -1}
+			id++
+
+			board.Squares = append(board.Squares, square)
+			board.XYMapped[x][y] = square
+			board.IDMapped[square.ID] = square
+		}
+	}
+
+	return &board
+}
+
+func eightJacksSquares(board *EightJacksBoard, coordinates ...[2]int) []*EightJacksSquare {
+	var ret []*EightJacksSquare
+	for _, coordinate := range coordinates {
+		ret = append(ret, board.XYMapped[coordinate[0]][coordinate[1]])
+	}
+
+	return ret
+}
+
+func TestEightJacksBoardLines(t *testing.T) {
+	for _, test := range []struct {
+		connectivity EightJacksBoardMode
+		squares      [][2]int
+		line         bool
+	}{
+		// Square grid: rows, columns and both diagonals.
+		{SquareGridEightJacks, [][2]int{{0, 0}, {1, 0}, {2, 0}}, true},
+		{SquareGridEightJacks, [][2]int{{3, 1}, {3, 2}, {3, 3}}, true},
+		{SquareGridEightJacks, [][2]int{{2, 2}, {0, 0}, {1, 1}}, true},
+		{SquareGridEightJacks, [][2]int{{0, 2}, {1, 1}, {2, 0}}, true},
+		{SquareGridEightJacks, [][2]int{{0, 0}, {1, 0}, {3, 0}}, false},
+		{SquareGridEightJacks, [][2]int{{0, 0}, {1, 0}, {1, 1}}, false},
+
+		// Hex grid: odd rows are shifted right, so the southeast neighbor of
+		// (1, 0) is (1, 1) and of (1, 1) is (2, 2).
+		{HexGridEightJacks, [][2]int{{0, 1}, {1, 1}, {2, 1}}, true},
+		{HexGridEightJacks, [][2]int{{1, 0}, {1, 1}, {2, 2}, {2, 3}}, true},
+		{HexGridEightJacks, [][2]int{{3, 0}, {2, 1}, {2, 2}, {1, 3}}, true},
+		{HexGridEightJacks, [][2]int{{1, 0}, {1, 1}, {1, 2}}, false},
+		{HexGridEightJacks, [][2]int{{0, 0}, {1, 1}, {2, 2}}, false},
+
+		// Double square grid: large squares ((x+y) even) connect diagonally,
+		// small ones only along rows and columns.
+		{DoubleSquareGridEightJacks, [][2]int{{0, 0}, {1, 0}, {2, 0}}, true},
+		{DoubleSquareGridEightJacks, [][2]int{{0, 0}, {1, 1}, {2, 2}}, true},
+		{DoubleSquareGridEightJacks, [][2]int{{1, 0}, {2, 1}, {3, 2}}, false},
+		{DoubleSquareGridEightJacks, [][2]int{{0, 2}, {1, 1}, {2, 0}}, true},
+	} {
+		board := newTestEightJacksBoard(test.connectivity, 5, 5)
+		if board.IsLine(eightJacksSquares(board, test.squares...)) != test.line {
+			t.Error("Expected IsLine to be", test.line, "for", test.squares, "on board shape", test.connectivity)
+		}
+	}
+}
+
+func TestEightJacksBoardNeighbors(t *testing.T) {
+	for _, test := range []struct {
+		connectivity EightJacksBoardMode
+		square       [2]int
+		count        int
+	}{
+		{SquareGridEightJacks, [2]int{2, 2}, 8},
+		{SquareGridEightJacks, [2]int{0, 0}, 3},
+		{HexGridEightJacks, [2]int{2, 2}, 6},
+		{HexGridEightJacks, [2]int{2, 1}, 6},
+		{DoubleSquareGridEightJacks, [2]int{2, 2}, 8},
+		{DoubleSquareGridEightJacks, [2]int{2, 1}, 4},
+	} {
+		board := newTestEightJacksBoard(test.connectivity, 5, 5)
+		neighbors := board.Neighbors(board.XYMapped[test.square[0]][test.square[1]])
+		if len(neighbors) != test.count {
+			t.Error("Expected", test.count, "neighbors for", test.square, "on board shape", test.connectivity, "got:", len(neighbors))
+		}
+	}
+}
+
+// A 7x7 layout using every non-jack card once, with a wild square in the
+// middle.
+func eightJacksTestLayout() string {
+	var cells []string
+	for _, suit := range []string{"C", "H", "S", "D"} {
+		for _, rank := range []string{"A", "2", "3", "4", "5", "6", "7", "8", "9", "10", "Q", "K"} {
+			cells = append(cells, rank+suit)
+		}
+	}
+
+	cells = append(cells[:24], append([]string{"*"}, cells[24:]...)...)
+
+	var rows []string
+	for row := 0; row < 7; row++ {
+		rows = append(rows, strings.Join(cells[row*7:row*7+7], ","))
+	}
+
+	return strings.Join(rows, "\n")
+}
+
+func TestParseEJSLayout(t *testing.T) {
+	layout, err := ParseEJSLayout(eightJacksTestLayout())
+	if err != nil {
+		t.Fatal("Unable to parse layout:", err)
+	}
+
+	if layout.Width != 7 || layout.Height != 7 || layout.Decks != 1 {
+		t.Fatal("Unexpected layout dimensions:", layout.Width, layout.Height, layout.Decks)
+	}
+
+	if layout.Cards[0][0].Rank != AceRank || layout.Cards[0][0].Suit != ClubsSuit {
+		t.Fatal("Expected the ace of clubs in the top left corner:", layout.Cards[0][0])
+	}
+
+	if layout.Cards[1][0].Rank != TwoRank || layout.Cards[0][1].Rank != EightRank {
+		t.Fatal("Expected rows to run along x:", layout.Cards[1][0], layout.Cards[0][1])
+	}
+
+	if layout.Cards[3][3].Rank != JokerRank {
+		t.Fatal("Expected a wild square in the middle:", layout.Cards[3][3])
+	}
+
+	// Two copies of the layout side by side use two decks; slashes and
+	// spaces work as separators too.
+	var rows = strings.Split(eightJacksTestLayout(), "\n")
+	for index, row := range rows {
+		rows[index] = strings.ReplaceAll(row, ",", " ") + " " + strings.ToLower(row)
+	}
+
+	layout, err = ParseEJSLayout(strings.Join(rows, " / "))
+	if err != nil {
+		t.Fatal("Unable to parse doubled layout:", err)
+	}
+
+	if layout.Width != 14 || layout.Height != 7 || layout.Decks != 2 {
+		t.Fatal("Unexpected doubled layout dimensions:", layout.Width, layout.Height, layout.Decks)
+	}
+
+	for _, bad := range []string{
+		"",
+		strings.Replace(eightJacksTestLayout(), "AC", "JC", 1),
+		strings.Replace(eightJacksTestLayout(), "AC", "AX", 1),
+		strings.Replace(eightJacksTestLayout(), "AC", "2C", 1),
+		strings.Replace(eightJacksTestLayout(), "AC,", "", 1),
+		strings.Replace(eightJacksTestLayout(), "AC", "*", 1),
+	} {
+		if _, err := ParseEJSLayout(bad); err == nil {
+			t.Fatal("Expected layout to be rejected:", bad)
+		}
+	}
+}
+
+func TestEightJacksLargeBoard(t *testing.T) {
+	for _, test := range []struct {
+		config EightJacksConfig
+		decks  int
+	}{
+		{EightJacksConfig{BoardWidth: 10, BoardHeight: 10, WildCorners: true, BoardLayout: 3}, 2},
+		{EightJacksConfig{BoardWidth: 12, BoardHeight: 12, BoardLayout: 2}, 3},
+		{EightJacksConfig{BoardWidth: 16, BoardHeight: 12, WildCorners: true, BoardLayout: 2}, 4},
+		{EightJacksConfig{BoardWidth: 16, BoardHeight: 16, BoardLayout: 4}, 6},
+		{EightJacksConfig{BoardLayout: 5, CustomLayout: eightJacksTestLayout()}, 1},
+	} {
+		var state EightJacksState
+		state.Config = test.config
+		if decks := state.Config.NumDecks(); decks != test.decks {
+			t.Fatal("Expected", test.decks, "decks but got", decks, "for", test.config)
+		}
+
+		if err := state.CreateBoard(); err != nil {
+			t.Fatal("Unable to create board:", err)
+		}
+
+		// Every non-jack card must appear equally often on the board, apart
+		// from leftovers when the board isn't a multiple of the deck size.
+		var counts = make(map[CardSuit]map[CardRank]int)
+		var wild = 0
+		for _, square := range state.Board.Squares {
+			if square.Value.Rank == JokerRank {
+				wild++
+				continue
+			}
+
+			if square.Value.Rank == JackRank {
+				t.Fatal("Jack placed on the board:", square)
+			}
+
+			if counts[square.Value.Suit] == nil {
+				counts[square.Value.Suit] = make(map[CardRank]int)
+			}
+			counts[square.Value.Suit][square.Value.Rank]++
+		}
+
+		for suit, ranks := range counts {
+			for rank, count := range ranks {
+				if count > test.decks {
+					t.Fatal("Card", rank, suit, "appears", count, "times with only", test.decks, "decks")
+				}
+			}
+		}
+
+		if len(state.Board.Squares) != state.Board.Width*state.Board.Height {
+			t.Fatal("Expected a full board but got", len(state.Board.Squares), "squares")
+		}
+	}
+}
+
+func TestEightJacksBoardValidation(t *testing.T) {
+	for _, config := range []EightJacksConfig{
+		{BoardWidth: 10, BoardHeight: 10, BoardLayout: 1},
+		{BoardWidth: 12, BoardHeight: 10, BoardLayout: 3, WildCorners: true},
+		{BoardLayout: 5, CustomLayout: "AS 2S"},
+	} {
+		if err := config.Validate(); err == nil {
+			t.Fatal("Expected config to be rejected:", config)
+		}
+	}
+
+	// Layouts which slipped past validation are reported rather than
+	// crashing the server.
+	var state EightJacksState
+	state.Config = EightJacksConfig{BoardWidth: 8, BoardHeight: 8, BoardLayout: 5, CustomLayout: "AS 2S"}
+	if err := state.CreateBoard(); err == nil {
+		t.Fatal("Expected an invalid custom layout to be rejected")
+	}
+}
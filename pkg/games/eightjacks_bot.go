@@ -0,0 +1,281 @@
+package games
+
+import (
+	"errors"
+
+	"git.cipherboy.com/WillowPatchGames/wpg/internal/utils"
+)
+
+// Rough cost of spending a jack (or joker) instead of a regular card; these
+// are rare, so a bot should only use them when it gains a good deal.
+const (
+	eightJacksBotJackCost  = 40
+	eightJacksBotJokerCost = 60
+)
+
+// EightJacksBot is a computer opponent for Eight Jacks. It scores every
+// legal placement by the runs it extends and the opponent runs it blocks,
+// saves its jacks and jokers for moves which are worth them, uses one-eyed
+// jacks to break up opponents' threats, and marks runs for its team as soon
+// as they're complete. Its difficulty sets how often it plays a random card
+// instead.
+type EightJacksBot struct{}
+
+func (eightJacksEngine) NewBot() GameBot {
+	return EightJacksBot{}
+}
+
+type eightJacksMove struct {
+	card   Card
+	square *EightJacksSquare
+	score  int
+}
+
+func (ejb EightJacksBot) NextMove(game *GameData, player *PlayerData) (interface{}, error) {
+	var state *EightJacksState = game.State.(*EightJacksState)
+	if state == nil || !state.Started || !state.Dealt || state.Finished {
+		return nil, nil
+	}
+
+	var index = player.Index
+	if index < 0 || index >= len(state.Players) {
+		return nil, nil
+	}
+
+	var team = state.Players[index].Team
+	var lines = state.Board.Lines(state.Config.RunLength)
+	var in_runs = state.squaresInRuns()
+
+	// Marking runs isn't turn-based; claim any of ours as soon as we see it.
+	if run := ejb.findRun(state, team, lines, in_runs); run != nil {
+		var msg EightJacksMarkMsg
+		msg.MessageHeader = botHeader(game, player, "mark")
+		msg.Squares = run
+		return msg, nil
+	}
+
+	if state.Turn != index {
+		return nil, nil
+	}
+
+	// Throw away dead cards before playing so we draw something useful.
+	for _, card := range state.Players[index].Hand {
+		if card.Rank == JackRank || card.Rank == JokerRank {
+			continue
+		}
+
+		if ejb.isDead(state, card) {
+			var msg EightJacksDiscardMsg
+			msg.MessageHeader = botHeader(game, player, "discard")
+			msg.CardID = card.ID
+			return msg, nil
+		}
+	}
+
+	var moves = ejb.legalMoves(state, index, in_runs)
+	if len(moves) == 0 {
+		return nil, errors.New("no legal plays available")
+	}
+
+	// Score every move before picking one, since scores can be negative.
+	var best_index = -1
+	if botBlunders(player) {
+		best_index = utils.SecureRand.Intn(len(moves))
+	} else {
+		for move_index := range moves {
+			moves[move_index].score = ejb.scoreMove(state, team, lines, in_runs, moves[move_index])
+			if best_index == -1 || moves[move_index].score > moves[best_index].score {
+				best_index = move_index
+			}
+		}
+	}
+
+	var best = moves[best_index]
+
+	var msg EightJacksPlayMsg
+	msg.MessageHeader = botHeader(game, player, "play")
+	msg.CardID = best.card.ID
+	msg.SquareID = best.square.ID
+	return msg, nil
+}
+
+// Map from square identifier to the runs (as indices into a flattened list
+// of every player's runs) it belongs to.
+func (ejs *EightJacksState) squaresInRuns() map[int][]int {
+	var ret = make(map[int][]int)
+	var run_index = 0
+	for _, indexed_player := range ejs.Players {
+		for _, run := range indexed_player.Runs {
+			for _, square := range run {
+				ret[square] = append(ret[square], run_index)
+			}
+			run_index++
+		}
+	}
+
+	return ret
+}
+
+// Whether a line could still be marked as a run: MarkRun allows sharing at
+// most one square with each existing run.
+func (ejb EightJacksBot) lineAvailable(line []*EightJacksSquare, in_runs map[int][]int) bool {
+	var overlaps = make(map[int]int)
+	for _, square := range line {
+		for _, run := range in_runs[square.ID] {
+			overlaps[run]++
+			if overlaps[run] > 1 {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// Number of squares in the line which count towards the given team's run,
+// or -1 when another team holds any square in the line.
+func (ejb EightJacksBot) lineCount(line []*EightJacksSquare, team int) int {
+	var count = 0
+	for _, square := range line {
+		if square.Value.Rank == JokerRank {
+			count++
+		} else if square.Marker == team {
+			count++
+		} else if square.Marker != -1 {
+			return -1
+		}
+	}
+
+	return count
+}
+
+// The team holding squares in this line, if exactly one does.
+func (ejb EightJacksBot) lineOwner(line []*EightJacksSquare) int {
+	var owner = -1
+	for _, square := range line {
+		if square.Marker == -1 {
+			continue
+		}
+
+		if owner != -1 && square.Marker != owner {
+			return -1
+		}
+
+		owner = square.Marker
+	}
+
+	return owner
+}
+
+func (ejb EightJacksBot) findRun(state *EightJacksState, team int, lines [][]*EightJacksSquare, in_runs map[int][]int) []int {
+	for _, line := range lines {
+		if ejb.lineOwner(line) != team || ejb.lineCount(line, team) != len(line) || !ejb.lineAvailable(line, in_runs) {
+			continue
+		}
+
+		var run = make([]int, 0, len(line))
+		for _, square := range line {
+			run = append(run, square.ID)
+		}
+
+		return run
+	}
+
+	return nil
+}
+
+// A regular card is dead when every square showing it is already taken.
+func (ejb EightJacksBot) isDead(state *EightJacksState, card Card) bool {
+	for _, square := range state.Board.Squares {
+		if square.Value.Rank == card.Rank && square.Value.Suit == card.Suit && square.Marker == -1 {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (ejb EightJacksBot) legalMoves(state *EightJacksState, player int, in_runs map[int][]int) []eightJacksMove {
+	var team = state.Players[player].Team
+	var ret []eightJacksMove
+
+	for _, card := range state.Players[player].Hand {
+		for _, square := range state.Board.Squares {
+			if square.CanPlay(card) != nil {
+				continue
+			}
+
+			if square.Marker != -1 {
+				// Removal with a one-eyed jack: only opponents' markers outside of
+				// existing runs.
+				if square.Marker == team || len(in_runs[square.ID]) > 0 {
+					continue
+				}
+			}
+
+			ret = append(ret, eightJacksMove{card, square, 0})
+		}
+	}
+
+	return ret
+}
+
+// Value of having count squares of a line towards a run.
+func eightJacksLineValue(count int, length int) int {
+	if count >= length {
+		return 10000
+	}
+
+	if count == length-1 {
+		return 1000
+	}
+
+	return 1 << uint(2*count)
+}
+
+func (ejb EightJacksBot) scoreMove(state *EightJacksState, team int, lines [][]*EightJacksSquare, in_runs map[int][]int, move eightJacksMove) int {
+	var score = 0
+	var length = state.Config.RunLength
+
+	for _, line := range lines {
+		var contains = false
+		for _, square := range line {
+			if square == move.square {
+				contains = true
+				break
+			}
+		}
+
+		if !contains || !ejb.lineAvailable(line, in_runs) {
+			continue
+		}
+
+		if move.square.Marker != -1 {
+			// Removing an opponent's marker: worth however close they were to
+			// completing this line.
+			var owner = move.square.Marker
+			if ejb.lineOwner(line) == owner {
+				score += eightJacksLineValue(ejb.lineCount(line, owner), length)
+			}
+			continue
+		}
+
+		// Extending our own line.
+		if count := ejb.lineCount(line, team); count >= 0 {
+			score += eightJacksLineValue(count+1, length) - eightJacksLineValue(count, length)
+		}
+
+		// Blocking an opponent's line.
+		if owner := ejb.lineOwner(line); owner != -1 && owner != team {
+			score += eightJacksLineValue(ejb.lineCount(line, owner), length) * 4 / 5
+		}
+	}
+
+	if move.card.Rank == JokerRank {
+		score -= eightJacksBotJokerCost
+	} else if move.card.Rank == JackRank {
+		score -= eightJacksBotJackCost
+	}
+
+	return score
+}
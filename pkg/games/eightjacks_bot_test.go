@@ -0,0 +1,118 @@
+package games
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestEightJacksBots(t *testing.T) {
+	for _, test := range []struct {
+		config EightJacksConfig
+		teams  [][]int
+	}{
+		{EightJacksConfig{NumPlayers: 2, RunLength: 4, WinLimit: 2, BoardWidth: 10, BoardHeight: 10, RemoveUnused: true, WildCorners: true, BoardLayout: 1, HandSize: 7, JokerCount: 8}, [][]int{{0}, {1}}},
+		{EightJacksConfig{NumPlayers: 4, RunLength: 5, WinLimit: 1, BoardWidth: 10, BoardHeight: 10, RemoveUnused: true, WildCorners: true, BoardLayout: 2, HandSize: 5, JokerCount: 4}, [][]int{{0, 2}, {1, 3}}},
+		{EightJacksConfig{NumPlayers: 3, RunLength: 3, WinLimit: 3, BoardWidth: 8, BoardHeight: 8, BoardLayout: 4, HandSize: 6}, [][]int{{0}, {1}, {2}}},
+		{EightJacksConfig{NumPlayers: 2, RunLength: 4, WinLimit: 2, BoardWidth: 10, BoardHeight: 10, RemoveUnused: true, WildCorners: true, BoardLayout: 1, BoardShape: int(HexGridEightJacks), HandSize: 7, JokerCount: 8}, [][]int{{0}, {1}}},
+		{EightJacksConfig{NumPlayers: 2, RunLength: 4, WinLimit: 2, BoardWidth: 10, BoardHeight: 10, RemoveUnused: true, WildCorners: true, BoardLayout: 2, BoardShape: int(DoubleSquareGridEightJacks), HandSize: 7, JokerCount: 8}, [][]int{{0}, {1}}},
+		{EightJacksConfig{NumPlayers: 4, RunLength: 5, WinLimit: 2, BoardWidth: 14, BoardHeight: 14, RemoveUnused: true, WildCorners: true, BoardLayout: 2, HandSize: 7, JokerCount: 8}, [][]int{{0, 2}, {1, 3}}},
+		{EightJacksConfig{NumPlayers: 2, RunLength: 4, WinLimit: 1, BoardWidth: 10, BoardHeight: 10, RemoveUnused: true, BoardLayout: 5, CustomLayout: eightJacksTestLayout(), HandSize: 7, JokerCount: 4}, [][]int{{0}, {1}}},
+	} {
+		var c Controller
+		c.Init()
+
+		game := newBotGame(t, &c, "eight jacks", test.config, test.config.NumPlayers)
+		state := game.State.(*EightJacksState)
+		if err := state.Init(test.config); err != nil {
+			t.Fatal("Unable to initialize eight jacks:", err)
+		}
+
+		if err := state.AssignTeams(0, test.config.NumPlayers, test.teams); err != nil {
+			t.Fatal("Unable to assign teams:", err)
+		}
+
+		var uids []uint64
+		for uid, player := range game.ToPlayer {
+			if player.Bot {
+				uids = append(uids, uid)
+			}
+		}
+		sort.Slice(uids, func(i, j int) bool { return uids[i] < uids[j] })
+		for index, uid := range uids {
+			game.ToPlayer[uid].Index = index
+		}
+
+		if err := (eightJacksEngine{}).Start(&c, game); err != nil {
+			t.Fatal("Unable to start eight jacks:", err)
+		}
+
+		playBotGame(t, &c, game)
+
+		if len(state.Winners) == 0 {
+			t.Fatal("Expected the bots to complete enough runs to win")
+		}
+	}
+}
+
+func TestEightJacksBotNegativeScores(t *testing.T) {
+	var config = EightJacksConfig{NumPlayers: 2, RunLength: 4, WinLimit: 2, BoardWidth: 10, BoardHeight: 10, RemoveUnused: true, WildCorners: true, BoardLayout: 1, HandSize: 7, JokerCount: 8}
+
+	var c Controller
+	c.Init()
+
+	game := newBotGame(t, &c, "eight jacks", config, config.NumPlayers)
+	state := game.State.(*EightJacksState)
+	if err := state.Init(config); err != nil {
+		t.Fatal("Unable to initialize eight jacks:", err)
+	}
+
+	if err := state.AssignTeams(0, config.NumPlayers, [][]int{{0}, {1}}); err != nil {
+		t.Fatal("Unable to assign teams:", err)
+	}
+
+	var player *PlayerData
+	for _, indexed_player := range game.ToPlayer {
+		if indexed_player.Bot {
+			indexed_player.Index = int(indexed_player.UID-botUIDBase) - 1
+			indexed_player.BotDifficulty = BotHard
+			if indexed_player.Index == 0 {
+				player = indexed_player
+			}
+		}
+	}
+
+	if err := (eightJacksEngine{}).Start(&c, game); err != nil {
+		t.Fatal("Unable to start eight jacks:", err)
+	}
+
+	// With only a joker on an empty board, every placement costs more than
+	// it's worth.
+	state.Turn = player.Index
+	state.Players[player.Index].Hand = []Card{{ID: 1000, Rank: JokerRank, Suit: FancySuit}}
+
+	var bot EightJacksBot
+	var team = state.Players[player.Index].Team
+	var lines = state.Board.Lines(state.Config.RunLength)
+	var in_runs = state.squaresInRuns()
+	var best = 0
+	var scores = make(map[int]int)
+	for index, move := range bot.legalMoves(state, player.Index, in_runs) {
+		scores[move.square.ID] = bot.scoreMove(state, team, lines, in_runs, move)
+		if scores[move.square.ID] >= 0 {
+			t.Fatal("Expected every placement of the joker to score below zero:", move.square.ID, scores[move.square.ID])
+		}
+
+		if index == 0 || scores[move.square.ID] > best {
+			best = scores[move.square.ID]
+		}
+	}
+
+	move, err := bot.NextMove(game, player)
+	if err != nil {
+		t.Fatal("Unable to pick a move:", err)
+	}
+
+	if square := move.(EightJacksPlayMsg).SquareID; scores[square] != best {
+		t.Fatal("Expected the least costly placement to be picked:", square, scores[square], best)
+	}
+}
@@ -14,6 +14,7 @@ import (
 // 2. Discard duplicate
 // 3. Play card
 // 4. Mark run (anyone -- including spectators)
+// 5. Timeout (sent by the server when a player runs out of time)
 
 type EightJacksAssignMsg struct {
 	MessageHeader
@@ -213,6 +214,15 @@ func (c *Controller) dispatchEightJacks(message []byte, header MessageHeader, ga
 
 		err = state.Order(player.Index, data.Order)
 		send_state = true
+	case "timeout":
+		var data GameTimeout
+		if err = json.Unmarshal(message, &data); err != nil {
+			return err
+		}
+
+		err = state.Timeout(player.Index, data.Policy)
+		send_synopsis = true
+		send_state = true
 	case "peek":
 		var response EightJacksPeekNotification
 		response.LoadData(game, state, player)
@@ -373,3 +383,48 @@ func (c *Controller) doEightJacksStart(game *GameData, state *EightJacksState) e
 
 	return nil
 }
+
+// eightJacksEngine registers EightJacks with the controller; see GameEngine.
+type eightJacksEngine struct{}
+
+func init() {
+	MustRegisterGameEngine(eightJacksEngine{})
+}
+
+func (eightJacksEngine) Mode() GameMode {
+	return EightJacksGame
+}
+
+func (eightJacksEngine) Name() string {
+	return "eight jacks"
+}
+
+func (eightJacksEngine) Title() string {
+	return "Eight Jacks (Card Game)"
+}
+
+func (eightJacksEngine) Description() string {
+	return "In Eight Jacks, players compete to create runs of cards on the board. Runs can be diagonal, left or right, or up and down. Watch those jacks and jokers carefully!"
+}
+
+func (eightJacksEngine) EmptyConfig() figgy.Figgurable {
+	return &EightJacksConfig{}
+}
+
+func (eightJacksEngine) NewState() ConfigurableState {
+	return &EightJacksState{}
+}
+
+func (eightJacksEngine) Init(config figgy.Figgurable) (ConfigurableState, error) {
+	var asserted *EightJacksConfig = config.(*EightJacksConfig)
+	var state = &EightJacksState{}
+	return state, state.Init(*asserted)
+}
+
+func (eightJacksEngine) Dispatch(c *Controller, message []byte, header MessageHeader, game *GameData, player *PlayerData, sid uint64) error {
+	return c.dispatchEightJacks(message, header, game, player, sid)
+}
+
+func (eightJacksEngine) Start(c *Controller, game *GameData) error {
+	return c.doEightJacksStart(game, game.State.(*EightJacksState))
+}
@@ -33,8 +33,9 @@ type EightJacksGameState struct {
 	Turn   uint64 `json:"turn"`
 	Dealer uint64 `json:"dealer"`
 
-	Board  EightJacksBoard  `json:"board"`
-	Config EightJacksConfig `json:"config"`
+	Board      EightJacksBoard  `json:"board"`
+	Commitment string           `json:"commitment"` // To the deck's shuffle seed; see ShuffleSeed.
+	Config     EightJacksConfig `json:"config"`
 
 	GlobalHistory []Card `json:"global_history"`
 
@@ -76,6 +77,7 @@ func (ejsn *EightJacksStateNotification) LoadData(data *GameData, game *EightJac
 	ejsn.Dealer, _ = data.ToUserID(game.Dealer)
 
 	ejsn.Board = game.Board
+	ejsn.Commitment = game.Deck.Commitment()
 	ejsn.Config = game.Config
 	ejsn.GlobalHistory = game.GlobalHistory
 
@@ -157,6 +159,8 @@ type EightJacksPeekNotification struct {
 	Winners []uint64                     `json:"winners"`
 
 	Turns []EightJacksTurn `json:"turns,omitempty"`
+	Deck  []*Card          `json:"deck,omitempty"`
+	Seed  *ShuffleSeed     `json:"seed,omitempty"`
 }
 
 func (ejsn *EightJacksPeekNotification) LoadData(data *GameData, game *EightJacksState, player *PlayerData) {
@@ -167,6 +171,7 @@ func (ejsn *EightJacksPeekNotification) LoadData(data *GameData, game *EightJack
 	ejsn.Dealer, _ = data.ToUserID(game.Dealer)
 
 	ejsn.Board = game.Board
+	ejsn.Commitment = game.Deck.Commitment()
 	ejsn.Config = game.Config
 	ejsn.GlobalHistory = game.GlobalHistory
 
@@ -201,6 +206,8 @@ func (ejsn *EightJacksPeekNotification) LoadData(data *GameData, game *EightJack
 	// done with the game.
 	if game.Finished {
 		ejsn.Turns = game.TurnHistory
+		ejsn.Deck = game.InitialDeck
+		ejsn.Seed = game.Deck.Seed
 	}
 }
 
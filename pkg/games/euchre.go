@@ -0,0 +1,705 @@
+package games
+
+import (
+	"errors"
+	"log"
+	"strconv"
+
+	"git.cipherboy.com/WillowPatchGames/wpg/pkg/middleware/figgy"
+)
+
+const EuchreGameOver string = "game is over"
+const EuchreNextRound string = "begin next round"
+
+// Euchre is always played by four players in two partnerships; partners sit
+// across from each other, so players 0 and 2 form team 0 and players 1 and 3
+// form team 1.
+const euchrePlayers = 4
+const euchreHandSize = 5
+
+type EuchrePlayer struct {
+	Hand []Card `json:"hand"`
+
+	Tricks     int  `json:"tricks"`
+	SittingOut bool `json:"sitting_out"` // When their partner goes alone.
+}
+
+func (ep *EuchrePlayer) Init() {
+	ep.Hand = make([]Card, 0)
+}
+
+func (ep *EuchrePlayer) FindCard(cardID int) (int, bool) {
+	return FindCard(ep.Hand, cardID)
+}
+
+func (ep *EuchrePlayer) RemoveCard(cardID int) bool {
+	var ret bool
+	_, ep.Hand, ret = RemoveCard(ep.Hand, cardID)
+	return ret
+}
+
+type EuchreConfig struct {
+	NumPlayers int `json:"num_players" config:"type:int,min:4,default:4,max:4" label:"Number of players"` // Always four.
+
+	StickTheDealer bool `json:"stick_the_dealer" config:"type:bool,default:false" label:"true:Dealer must name trump if everyone else passes (stick the dealer),false:Redeal when everyone passes"` // Whether the dealer is forced to call trump in the second round of bidding.
+	GoingAlone     bool `json:"going_alone" config:"type:bool,default:true" label:"true:Makers may go alone,false:Partners always play together"`                                                   // Whether the maker may play without their partner.
+
+	// Scoring
+	WinAmount int `json:"win_amount" config:"type:int,min:5,default:10,max:25" label:"Winning point threshold"`
+
+	// Common game configuration options
+	Countdown bool `json:"countdown" config:"type:bool,default:true" label:"true:Show a 3... 2... 1... countdown before beginning,false:Start the game instantly"` // Whether to wait and send countdown messages.
+
+	// Chess clock style limits on how long each player may take.
+	TurnClock   int `json:"turn_clock" config:"type:int,min:0,default:0,max:600" label:"Seconds per turn (0 for no limit)"`
+	GameClock   int `json:"game_clock" config:"type:int,min:0,default:0,max:120" label:"Minutes per player for the whole game (0 for no limit)"`
+	ClockPolicy int `json:"clock_policy" config:"type:enum,default:0,options:0:Play automatically;2:Forfeit the game" label:"When a player runs out of time"` // See ClockPolicy.
+}
+
+func (cfg EuchreConfig) Validate() error {
+	return nil
+}
+
+type EuchreTrick struct {
+	Leader   int    `json:"leader"`
+	Played   []Card `json:"played"`
+	PlayedBy []int  `json:"played_by"`
+	Winner   int    `json:"winner"`
+}
+
+type EuchreRound struct {
+	Dealer   int          `json:"dealer"`
+	Deck     []*Card      `json:"deck"`
+	Seed     *ShuffleSeed `json:"seed"`
+	TurnedUp Card         `json:"turned_up"`
+
+	Hands     [][]Card `json:"hands"`
+	Discarded *Card    `json:"discarded,omitempty"`
+
+	Maker  int           `json:"maker"`
+	Trump  CardSuit      `json:"trump"`
+	Alone  bool          `json:"alone"`
+	Tricks []EuchreTrick `json:"tricks"`
+
+	RoundScores []int `json:"round_scores"` // By team.
+	Scores      []int `json:"scores"`
+}
+
+type EuchreState struct {
+	Turn   int `json:"turn"`
+	Leader int `json:"leader"`
+	Dealer int `json:"dealer"`
+
+	Deck     Deck           `json:"deck"`
+	Players  []EuchrePlayer `json:"players"`   // Left of dealer is found by incrementing one.
+	Kitty    []Card         `json:"kitty"`     // Cards not dealt, after the turned up card.
+	TurnedUp *Card          `json:"turned_up"` // Proposed trump, until picked up or turned down.
+
+	// Bidding: in the first round, players may order the dealer to pick up the
+	// turned up card; in the second, they may name any other suit as trump.
+	// Zero once trump has been decided.
+	BidRound   int      `json:"bid_round"`
+	Trump      CardSuit `json:"trump"`
+	Maker      int      `json:"maker"`
+	Alone      bool     `json:"alone"`
+	Discarding bool     `json:"discarding"` // Dealer picked up and must discard.
+
+	Played         []Card         `json:"played"`          // Currently played cards in this trick.
+	PlayedBy       []int          `json:"played_by"`       // Who played each card in this trick.
+	PreviousTricks [][]Card       `json:"previous_tricks"` // Contents of previous tricks in the current round; sent to clients.
+	RoundHistory   []*EuchreRound `json:"round_history"`   // Contents of previous rounds for analysis.
+	Seed           *ShuffleSeed   `json:"seed,omitempty"`  // Secret each round's shuffle seed derives from; when unset, every round gets a fresh seed.
+
+	Scores []int `json:"scores"` // By team.
+
+	Config EuchreConfig `json:"config"`
+
+	Assigned bool  `json:"assigned"` // The owner has picked where everyone sits.
+	Started  bool  `json:"started"`
+	Dealt    bool  `json:"dealt"`
+	Finished bool  `json:"finished"`
+	Winners  []int `json:"winners"`
+}
+
+func (es *EuchreState) Init(cfg EuchreConfig) error {
+	var err error = figgy.Validate(cfg)
+	if err != nil {
+		log.Println("Error with EuchreConfig", err)
+		return err
+	}
+
+	es.Config = cfg
+	es.Turn = -1
+	es.Dealer = -1
+	es.Maker = -1
+	es.Started = false
+	es.Finished = false
+	es.Winners = make([]int, 0)
+
+	return nil
+}
+
+func (es *EuchreState) GetConfiguration() figgy.Figgurable {
+	return es.Config
+}
+
+func (es *EuchreState) ReInit() error {
+	// No-op for now. Nothing needs to be re-initialized after reloading
+	// from JSON serialization.
+	return nil
+}
+
+func (es *EuchreState) IsStarted() bool {
+	return es.Started
+}
+
+func (es *EuchreState) IsFinished() bool {
+	return es.Finished
+}
+
+func (es *EuchreState) SetShuffleSeed(seed *ShuffleSeed) error {
+	if es.Started {
+		return errors.New("unable to set the shuffle seed after the game has started")
+	}
+
+	es.Seed = seed
+	return nil
+}
+
+func (es *EuchreState) RevealedSeeds() []*ShuffleSeed {
+	var seeds = make([]*ShuffleSeed, 0, len(es.RoundHistory))
+	for _, round := range es.RoundHistory {
+		seeds = append(seeds, round.Seed)
+	}
+
+	return revealedRoundSeeds(seeds, es.Dealt && !es.Finished)
+}
+
+func (es *EuchreState) ResetStatus() {
+	es.Started = false
+	es.Finished = false
+}
+
+// Which team a player is on.
+func EuchreTeam(player int) int {
+	return player % 2
+}
+
+func (es *EuchreState) partner(player int) int {
+	return (player + 2) % euchrePlayers
+}
+
+// The next player to the left who is playing this round.
+func (es *EuchreState) nextPlayer(player int) int {
+	var next = (player + 1) % euchrePlayers
+	if es.Players[next].SittingOut {
+		next = (next + 1) % euchrePlayers
+	}
+
+	return next
+}
+
+func (es *EuchreState) activePlayers() int {
+	if es.Alone {
+		return euchrePlayers - 1
+	}
+
+	return euchrePlayers
+}
+
+// The other jack of the same color as trump.
+func euchreLeftBowerSuit(trump CardSuit) CardSuit {
+	switch trump {
+	case ClubsSuit:
+		return SpadesSuit
+	case SpadesSuit:
+		return ClubsSuit
+	case HeartsSuit:
+		return DiamondsSuit
+	case DiamondsSuit:
+		return HeartsSuit
+	}
+
+	return NoneSuit
+}
+
+// The suit a card belongs to once trump is known: the left bower counts as
+// trump.
+func EuchreEffectiveSuit(card Card, trump CardSuit) CardSuit {
+	if card.Rank == JackRank && card.Suit == euchreLeftBowerSuit(trump) {
+		return trump
+	}
+
+	return card.Suit
+}
+
+// Relative strength of a card in a trick with the given lead suit. Cards
+// which neither follow suit nor are trump can't win and have no strength.
+func EuchreCardPower(card Card, trump CardSuit, lead CardSuit) int {
+	var suit = EuchreEffectiveSuit(card, trump)
+	if suit == trump {
+		if card.Rank == JackRank && card.Suit == trump {
+			return 200 // Right bower
+		}
+
+		if card.Rank == JackRank {
+			return 199 // Left bower
+		}
+
+		return 100 + card.Rank.AceHigh()
+	}
+
+	if suit == lead {
+		return card.Rank.AceHigh()
+	}
+
+	return 0
+}
+
+func (es *EuchreState) TurnClocks() TurnClocks {
+	return newTurnClocks(es.Config.TurnClock, es.Config.GameClock, es.Config.ClockPolicy)
+}
+
+func (es *EuchreState) ClockedPlayer() int {
+	// Only playing cards is clocked, not bidding or the dealer's discard.
+	if !es.Started || es.Finished || !es.Dealt || es.BidRound != 0 || es.Discarding {
+		return -1
+	}
+
+	return es.Turn
+}
+
+func (es *EuchreState) Timeout(player int, policy ClockPolicy) error {
+	if player != es.ClockedPlayer() {
+		return errors.New("not your turn")
+	}
+
+	if policy == ForfeitOnTimeout {
+		// The other team wins.
+		var team = 1 - EuchreTeam(player)
+		es.Finished = true
+		es.Winners = []int{team, team + 2}
+		es.Turn = -1
+		es.Dealer = -1
+		return errors.New(EuchreGameOver)
+	}
+
+	return playLowestCard(es.Players[player].Hand, true, func(card int) error {
+		return es.PlayCard(player, card)
+	})
+}
+
+// Record that the owner seated num_players players, so the game can start.
+func (es *EuchreState) AssignSeats(num_players int) error {
+	if es.Started {
+		return errors.New("cannot assign seats after already started")
+	}
+
+	var config = es.Config
+	config.NumPlayers = num_players
+	if err := figgy.Validate(config); err != nil {
+		return err
+	}
+
+	es.Config = config
+	es.Assigned = true
+	return nil
+}
+
+func (es *EuchreState) Start(players int) error {
+	var err error
+
+	if es.Started {
+		log.Println("Error! Double start occurred...", err)
+		return errors.New("double start occurred")
+	}
+
+	es.Config.NumPlayers = players
+	err = figgy.Validate(es.Config)
+	if err != nil {
+		log.Println("Err with EuchreConfig after starting: ", err)
+		return err
+	}
+
+	// Create all of the players.
+	es.Players = make([]EuchrePlayer, es.Config.NumPlayers)
+	for index := range es.Players {
+		es.Players[index].Init()
+	}
+
+	es.Scores = make([]int, 2)
+
+	// Force us to call StartRound() next.
+	es.Dealt = false
+	es.Dealer = 0
+	es.Played = make([]Card, 0)
+
+	// Start the round: shuffle the cards and deal them out.
+	err = es.StartRound()
+	if err != nil {
+		log.Println("Error starting round: ", err)
+		return err
+	}
+
+	es.Started = true
+	return nil
+}
+
+func (es *EuchreState) StartRound() error {
+	if es.Dealt {
+		return errors.New("unable to call StartRound while we've already dealt")
+	}
+
+	// Start building history of moves.
+	es.RoundHistory = append(es.RoundHistory, &EuchreRound{})
+	history := es.RoundHistory[len(es.RoundHistory)-1]
+	history.Dealer = es.Dealer
+	history.Maker = -1
+	history.Tricks = make([]EuchreTrick, 0)
+
+	// Start with a clean deck and shuffle it.
+	es.Deck.Init()
+	es.Deck.AddEuchre24Deck()
+	es.Deck.Reseed(es.Seed, len(es.RoundHistory))
+	es.Deck.Shuffle()
+
+	// Save the initial deck.
+	history.Deck = CopyDeck(es.Deck.Cards)
+	history.Seed = es.Deck.Seed.Copy()
+
+	// Clear out all round-specific status before each round.
+	for index := range es.Players {
+		es.Players[index].Hand = make([]Card, 0)
+		es.Players[index].Tricks = 0
+		es.Players[index].SittingOut = false
+	}
+
+	es.Trump = NoneSuit
+	es.Maker = -1
+	es.Alone = false
+	es.Discarding = false
+	es.Played = make([]Card, 0)
+	es.PlayedBy = make([]int, 0)
+	es.PreviousTricks = make([][]Card, 0)
+
+	// Deal out five cards to each player, starting left of the dealer.
+	for round := 0; round < euchreHandSize; round++ {
+		for player_offset := 1; player_offset <= len(es.Players); player_offset++ {
+			player_index := (es.Dealer + player_offset) % len(es.Players)
+			es.Players[player_index].Hand = append(es.Players[player_index].Hand, *es.Deck.Draw())
+		}
+	}
+
+	// Turn up the next card as the proposed trump and leave the rest in the
+	// kitty.
+	es.TurnedUp = es.Deck.Draw()
+	history.TurnedUp = *es.TurnedUp
+	es.Kitty = make([]Card, 0)
+	for _, card := range es.Deck.Cards {
+		es.Kitty = append(es.Kitty, *card)
+	}
+	es.Deck.Cards = make([]*Card, 0)
+
+	for _, indexed_player := range es.Players {
+		history.Hands = append(history.Hands, CopyHand(indexed_player.Hand))
+	}
+
+	// Bidding starts left of the dealer.
+	es.BidRound = 1
+	es.Turn = (es.Dealer + 1) % len(es.Players)
+	es.Leader = es.Turn
+	es.Dealt = true
+
+	return nil
+}
+
+func (es *EuchreState) checkBidding(player int) error {
+	if !es.Started {
+		return errors.New("game hasn't started yet")
+	}
+
+	if es.Finished {
+		return errors.New("game has already finished")
+	}
+
+	if player < 0 || player >= len(es.Players) {
+		return errors.New("not a valid player identifier: " + strconv.Itoa(player))
+	}
+
+	if !es.Dealt {
+		return errors.New("unable to bid before dealing cards")
+	}
+
+	if es.BidRound == 0 {
+		return errors.New("trump has already been decided")
+	}
+
+	if es.Turn != player {
+		return errors.New("not your turn")
+	}
+
+	return nil
+}
+
+// Decline to order up the turned up card, or to name trump.
+func (es *EuchreState) Pass(player int) error {
+	if err := es.checkBidding(player); err != nil {
+		return err
+	}
+
+	if player == es.Dealer {
+		if es.BidRound == 2 && es.Config.StickTheDealer {
+			return errors.New("dealer must name trump")
+		}
+
+		if es.BidRound == 1 {
+			// Everyone turned down the card; it gets turned face down and the
+			// second round of bidding begins.
+			es.BidRound = 2
+			es.Turn = (es.Dealer + 1) % len(es.Players)
+			return nil
+		}
+
+		// Everyone passed twice; throw in the hand and move on to the next
+		// dealer.
+		es.Dealt = false
+		es.TurnedUp = nil
+		es.BidRound = 0
+		es.Turn = -1
+		es.Dealer = (es.Dealer + 1) % len(es.Players)
+		return nil
+	}
+
+	es.Turn = (es.Turn + 1) % len(es.Players)
+	return nil
+}
+
+// Make trump: in the first round of bidding, this orders the dealer to pick
+// up the turned up card and suit must be its suit; in the second round, suit
+// is any other suit.
+func (es *EuchreState) Call(player int, suit CardSuit, alone bool) error {
+	if err := es.checkBidding(player); err != nil {
+		return err
+	}
+
+	if alone && !es.Config.GoingAlone {
+		return errors.New("going alone isn't allowed in this game")
+	}
+
+	if es.BidRound == 1 {
+		if suit == NoneSuit {
+			suit = es.TurnedUp.Suit
+		}
+
+		if suit != es.TurnedUp.Suit {
+			return errors.New("can only order up the suit of the turned up card")
+		}
+	} else {
+		if suit < ClubsSuit || suit > DiamondsSuit {
+			return errors.New("must name a suit as trump")
+		}
+
+		if suit == es.TurnedUp.Suit {
+			return errors.New("can't name the suit which was turned down")
+		}
+	}
+
+	es.Trump = suit
+	es.Maker = player
+	es.Alone = alone
+	if alone {
+		es.Players[es.partner(player)].SittingOut = true
+	}
+
+	history := es.RoundHistory[len(es.RoundHistory)-1]
+	history.Maker = player
+	history.Trump = suit
+	history.Alone = alone
+
+	// When ordered up, the dealer picks up the card and discards another --
+	// unless the dealer is sitting out.
+	if es.BidRound == 1 && !es.Players[es.Dealer].SittingOut {
+		es.Players[es.Dealer].Hand = append(es.Players[es.Dealer].Hand, *es.TurnedUp)
+		es.Discarding = true
+		es.BidRound = 0
+		es.Turn = es.Dealer
+		return nil
+	}
+
+	es.BidRound = 0
+	es.beginPlay()
+	return nil
+}
+
+// After picking up the turned up card, the dealer discards down to five.
+func (es *EuchreState) Discard(player int, cardID int) error {
+	if !es.Started {
+		return errors.New("game hasn't started yet")
+	}
+
+	if es.Finished {
+		return errors.New("game has already finished")
+	}
+
+	if player != es.Dealer || !es.Discarding {
+		return errors.New("only the dealer discards after picking up")
+	}
+
+	index, found := es.Players[player].FindCard(cardID)
+	if !found {
+		return errors.New("unable to discard card not in hand")
+	}
+
+	var discarded = es.Players[player].Hand[index]
+	es.Players[player].RemoveCard(cardID)
+	es.Kitty = append(es.Kitty, discarded)
+
+	history := es.RoundHistory[len(es.RoundHistory)-1]
+	history.Discarded = discarded.Copy()
+
+	es.Discarding = false
+	es.beginPlay()
+	return nil
+}
+
+func (es *EuchreState) beginPlay() {
+	es.TurnedUp = nil
+	es.Leader = es.nextPlayer(es.Dealer)
+	es.Turn = es.Leader
+	es.Played = make([]Card, 0)
+	es.PlayedBy = make([]int, 0)
+}
+
+func (es *EuchreState) PlayCard(player int, cardID int) error {
+	if !es.Started {
+		return errors.New("game hasn't started yet")
+	}
+
+	if es.Finished {
+		return errors.New("game has already finished")
+	}
+
+	if player < 0 || player >= len(es.Players) {
+		return errors.New("not a valid player identifier: " + strconv.Itoa(player))
+	}
+
+	if !es.Dealt {
+		return errors.New("unable to play a card before dealing cards")
+	}
+
+	if es.BidRound != 0 || es.Discarding {
+		return errors.New("unable to play a card before trump is decided")
+	}
+
+	if es.Turn != player {
+		return errors.New("not your turn")
+	}
+
+	index, found := es.Players[player].FindCard(cardID)
+	if !found {
+		return errors.New("unable to play card not in hand")
+	}
+
+	played := es.Players[player].Hand[index]
+	history := es.RoundHistory[len(es.RoundHistory)-1]
+
+	if len(es.Played) == 0 || len(es.Played) == es.activePlayers() {
+		// Leading a new trick.
+		es.Played = make([]Card, 0)
+		es.PlayedBy = make([]int, 0)
+		history.Tricks = append(history.Tricks, EuchreTrick{Leader: player, Winner: -1})
+	} else {
+		// Must follow the lead suit (with the left bower counting as trump) if
+		// we can.
+		lead_suit := EuchreEffectiveSuit(es.Played[0], es.Trump)
+		if EuchreEffectiveSuit(played, es.Trump) != lead_suit {
+			for _, card := range es.Players[player].Hand {
+				if EuchreEffectiveSuit(card, es.Trump) == lead_suit {
+					return errors.New("must follow the lead suit")
+				}
+			}
+		}
+	}
+
+	this_trick := &history.Tricks[len(history.Tricks)-1]
+	es.Players[player].RemoveCard(cardID)
+	es.Played = append(es.Played, played)
+	es.PlayedBy = append(es.PlayedBy, player)
+	this_trick.Played = append(this_trick.Played, played)
+	this_trick.PlayedBy = append(this_trick.PlayedBy, player)
+
+	if len(es.Played) == es.activePlayers() {
+		return es.determineTrickWinner()
+	}
+
+	es.Turn = es.nextPlayer(player)
+	return nil
+}
+
+func (es *EuchreState) determineTrickWinner() error {
+	history := es.RoundHistory[len(es.RoundHistory)-1]
+	this_trick := &history.Tricks[len(history.Tricks)-1]
+
+	lead_suit := EuchreEffectiveSuit(es.Played[0], es.Trump)
+	winner := 0
+	for index, card := range es.Played {
+		if EuchreCardPower(card, es.Trump, lead_suit) > EuchreCardPower(es.Played[winner], es.Trump, lead_suit) {
+			winner = index
+		}
+	}
+
+	absolute_winner := es.PlayedBy[winner]
+	es.Leader = absolute_winner
+	es.Turn = absolute_winner
+	es.PreviousTricks = append(es.PreviousTricks, es.Played)
+	es.Players[absolute_winner].Tricks += 1
+	this_trick.Winner = absolute_winner
+
+	if len(es.Players[absolute_winner].Hand) == 0 {
+		// Can't play again in this round. Tabulate the round score and maybe try
+		// to play another round.
+		return es.tabulateRoundScore()
+	}
+
+	return nil
+}
+
+func (es *EuchreState) tabulateRoundScore() error {
+	history := es.RoundHistory[len(es.RoundHistory)-1]
+
+	var makers = EuchreTeam(es.Maker)
+	var tricks = es.Players[makers].Tricks + es.Players[makers+2].Tricks
+
+	history.RoundScores = make([]int, 2)
+	if tricks < 3 {
+		// Euchred: the defenders score two.
+		history.RoundScores[1-makers] = 2
+	} else if tricks == euchreHandSize && es.Alone {
+		history.RoundScores[makers] = 4
+	} else if tricks == euchreHandSize {
+		history.RoundScores[makers] = 2
+	} else {
+		history.RoundScores[makers] = 1
+	}
+
+	for team := range es.Scores {
+		es.Scores[team] += history.RoundScores[team]
+	}
+	history.Scores = append([]int{}, es.Scores...)
+
+	for team, score := range es.Scores {
+		if score >= es.Config.WinAmount {
+			es.Finished = true
+			es.Winners = []int{team, team + 2}
+			es.Turn = -1
+			es.Dealer = -1
+			return errors.New(EuchreGameOver)
+		}
+	}
+
+	// Otherwise, the deal passes to the left.
+	es.Dealt = false
+	es.Dealer = (es.Dealer + 1) % len(es.Players)
+
+	return errors.New(EuchreNextRound)
+}
@@ -0,0 +1,213 @@
+package games
+
+import (
+	"strings"
+)
+
+type EuchrePlayerState struct {
+	Hand []Card `json:"hand"`
+
+	Tricks     int  `json:"tricks"`
+	SittingOut bool `json:"sitting_out"`
+}
+
+type EuchreGameState struct {
+	Turn   uint64 `json:"turn"`
+	Leader uint64 `json:"leader"`
+	Dealer uint64 `json:"dealer"`
+
+	TurnedUp   *Card    `json:"turned_up,omitempty"`
+	BidRound   int      `json:"bid_round"`
+	Trump      CardSuit `json:"trump"`
+	Maker      uint64   `json:"maker"`
+	Alone      bool     `json:"alone"`
+	Discarding bool     `json:"discarding"`
+
+	Played    []Card   `json:"played"`
+	WhoPlayed []uint64 `json:"who_played"`
+	History   [][]Card `json:"history"`
+
+	Scores     []int        `json:"scores"`
+	Commitment string       `json:"commitment"` // To the current round's shuffle seed; see ShuffleSeed.
+	Config     EuchreConfig `json:"config"`
+
+	Started  bool `json:"started"`
+	Dealt    bool `json:"dealt"`
+	Finished bool `json:"finished"`
+}
+
+func (egs *EuchreGameState) loadGame(data *GameData, game *EuchreState) {
+	egs.Turn, _ = data.ToUserID(game.Turn)
+	egs.Leader, _ = data.ToUserID(game.Leader)
+	egs.Dealer, _ = data.ToUserID(game.Dealer)
+
+	egs.TurnedUp = game.TurnedUp
+	egs.BidRound = game.BidRound
+	egs.Trump = game.Trump
+	egs.Maker, _ = data.ToUserID(game.Maker)
+	egs.Alone = game.Alone
+	egs.Discarding = game.Discarding
+
+	egs.Played = game.Played
+	if egs.Played == nil {
+		egs.Played = make([]Card, 0)
+	}
+
+	egs.WhoPlayed, _ = data.ToUserIDs(game.PlayedBy)
+	if egs.WhoPlayed == nil {
+		egs.WhoPlayed = make([]uint64, 0)
+	}
+
+	if len(game.PreviousTricks) >= 1 {
+		egs.History = make([][]Card, 1)
+		egs.History[0] = game.PreviousTricks[len(game.PreviousTricks)-1]
+	}
+
+	egs.Scores = game.Scores
+	egs.Commitment = game.Deck.Commitment()
+	egs.Config = game.Config
+
+	egs.Started = game.Started
+	egs.Dealt = game.Dealt
+	egs.Finished = game.Finished
+}
+
+type EuchreStateNotification struct {
+	MessageHeader
+	EuchrePlayerState
+	EuchreGameState
+}
+
+func (esn *EuchreStateNotification) LoadData(data *GameData, game *EuchreState, player *PlayerData) {
+	esn.LoadHeader(data, player)
+	esn.MessageType = "state"
+
+	esn.Hand = game.Players[player.Index].Hand
+	esn.Tricks = game.Players[player.Index].Tricks
+	esn.SittingOut = game.Players[player.Index].SittingOut
+
+	esn.loadGame(data, game)
+}
+
+type EuchrePlayerSynopsis struct {
+	UID         uint64 `json:"user"`
+	Playing     bool   `json:"playing"`
+	PlayerIndex int    `json:"player_index"`
+	Team        int    `json:"team"`
+
+	IsTurn     bool `json:"is_turn"`
+	IsLeader   bool `json:"is_leader"`
+	IsDealer   bool `json:"is_dealer"`
+	IsMaker    bool `json:"is_maker"`
+	SittingOut bool `json:"sitting_out"`
+
+	Tricks int `json:"tricks"`
+	Score  int `json:"score"`
+}
+
+type EuchreSynopsisNotification struct {
+	MessageHeader
+
+	Players []EuchrePlayerSynopsis `json:"players"`
+
+	Trump         CardSuit `json:"trump"`
+	SuitIndicator string   `json:"suit"`
+}
+
+func (esn *EuchreSynopsisNotification) LoadData(data *GameData, state *EuchreState, player *PlayerData) {
+	esn.LoadHeader(data, player)
+	esn.MessageType = "synopsis"
+
+	for _, indexed_player := range data.ToPlayer {
+		var synopsis EuchrePlayerSynopsis
+		synopsis.UID = indexed_player.UID
+		synopsis.Playing = indexed_player.Playing
+		synopsis.PlayerIndex = indexed_player.Index
+		synopsis.Team = -1
+
+		if indexed_player.Index >= 0 && indexed_player.Index < len(state.Players) {
+			synopsis.Team = EuchreTeam(indexed_player.Index)
+
+			synopsis.IsTurn = indexed_player.Index == state.Turn
+			synopsis.IsLeader = indexed_player.Index == state.Leader
+			synopsis.IsDealer = indexed_player.Index == state.Dealer
+			synopsis.IsMaker = indexed_player.Index == state.Maker
+			synopsis.SittingOut = state.Players[indexed_player.Index].SittingOut
+
+			synopsis.Tricks = state.Players[indexed_player.Index].Tricks
+			synopsis.Score = state.Scores[synopsis.Team]
+		}
+
+		esn.Players = append(esn.Players, synopsis)
+	}
+
+	esn.Trump = state.Trump
+
+	if !state.Dealt {
+		esn.SuitIndicator = "dealing"
+	} else if state.BidRound != 0 || state.Discarding {
+		esn.SuitIndicator = "bidding"
+	} else if len(state.Played) == 0 || len(state.Played) == state.activePlayers() {
+		esn.SuitIndicator = "waiting"
+	} else {
+		esn.SuitIndicator = EuchreEffectiveSuit(state.Played[0], state.Trump).String()
+		esn.SuitIndicator = strings.TrimSuffix(esn.SuitIndicator, "Suit")
+	}
+}
+
+type EuchrePeekNotification struct {
+	MessageHeader
+
+	PlayerMapping []uint64 `json:"player_mapping"`
+
+	// Info for Ended Games (Everyone)
+	RoundHistory []*EuchreRound `json:"round_history"`
+
+	// Info for Active Games (Spectators)
+	EuchreGameState
+
+	Winners []uint64 `json:"winners"`
+}
+
+func (epn *EuchrePeekNotification) LoadData(data *GameData, game *EuchreState, player *PlayerData) {
+	epn.LoadHeader(data, player)
+	epn.MessageType = "game-state"
+
+	for index := range game.Players {
+		player_uid, _ := data.ToUserID(index)
+		epn.PlayerMapping = append(epn.PlayerMapping, player_uid)
+	}
+
+	if !game.Finished {
+		epn.loadGame(data, game)
+
+		// Allow spectators to see previous rounds before the game has ended.
+		if len(game.RoundHistory) > 0 {
+			epn.RoundHistory = game.RoundHistory[:len(game.RoundHistory)-1]
+		}
+	} else {
+		epn.RoundHistory = game.RoundHistory
+		epn.Scores = game.Scores
+		epn.Config = game.Config
+		epn.Started = game.Started
+		epn.Dealt = game.Dealt
+		epn.Finished = game.Finished
+	}
+
+	epn.Winners, _ = data.ToUserIDs(game.Winners)
+}
+
+type EuchreFinishedNotification struct {
+	MessageHeader
+
+	Winners []uint64 `json:"winners"`
+	Scores  []int    `json:"scores"`
+}
+
+func (efn *EuchreFinishedNotification) LoadData(data *GameData, state *EuchreState, player *PlayerData) {
+	efn.LoadHeader(data, player)
+	efn.MessageType = "finished"
+
+	efn.Winners, _ = data.ToUserIDs(state.Winners)
+	efn.Scores = state.Scores
+}
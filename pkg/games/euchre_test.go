@@ -0,0 +1,220 @@
+package games
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestEuchreBowers(t *testing.T) {
+	var right = Card{0, HeartsSuit, JackRank}
+	var left = Card{0, DiamondsSuit, JackRank}
+	var ace = Card{0, HeartsSuit, AceRank}
+	var nine = Card{0, HeartsSuit, NineRank}
+	var off_ace = Card{0, ClubsSuit, AceRank}
+
+	if EuchreEffectiveSuit(left, HeartsSuit) != HeartsSuit {
+		t.Fatal("Expected left bower to count as trump")
+	}
+
+	if EuchreEffectiveSuit(left, ClubsSuit) != DiamondsSuit {
+		t.Fatal("Expected jack of diamonds to be a diamond when clubs are trump")
+	}
+
+	var order = []Card{right, left, ace, nine, off_ace}
+	for index := 1; index < len(order); index++ {
+		if EuchreCardPower(order[index-1], HeartsSuit, ClubsSuit) <= EuchreCardPower(order[index], HeartsSuit, ClubsSuit) {
+			t.Fatal("Expected", order[index-1], "to beat", order[index])
+		}
+	}
+
+	if EuchreCardPower(off_ace, HeartsSuit, SpadesSuit) != 0 {
+		t.Fatal("Expected off-suit card to be unable to win a trick")
+	}
+}
+
+// Play the first legal card in hand for the player whose turn it is.
+func playEuchreCard(t *testing.T, state *EuchreState) error {
+	var err error
+	for _, card := range state.Players[state.Turn].Hand {
+		err = state.PlayCard(state.Turn, card.ID)
+		if err == nil || err.Error() == EuchreNextRound || err.Error() == EuchreGameOver {
+			return err
+		}
+	}
+
+	t.Fatal("Unable to find a legal card to play:", err)
+	return err
+}
+
+func TestEuchreGame(t *testing.T) {
+	for _, config := range []EuchreConfig{
+		{NumPlayers: 4, WinAmount: 10, GoingAlone: true},
+		{NumPlayers: 4, WinAmount: 5, StickTheDealer: true},
+	} {
+		var state EuchreState
+		if err := state.Init(config); err != nil {
+			t.Fatal("Unable to initialize game:", err)
+		}
+
+		if err := state.Start(4); err != nil {
+			t.Fatal("Unable to start game:", err)
+		}
+
+		for hand := 0; hand < 200 && !state.Finished; hand++ {
+			if !state.Dealt {
+				if err := state.StartRound(); err != nil {
+					t.Fatal("Unable to deal:", err)
+				}
+			}
+
+			if len(state.Kitty) != 3 || state.TurnedUp == nil {
+				t.Fatal("Expected three cards in the kitty and one turned up")
+			}
+
+			// Everyone passes the first round; the dealer's partner names the
+			// first other suit, going alone on every other hand.
+			var turned_down = state.TurnedUp.Suit
+			for state.BidRound == 1 {
+				if err := state.Pass(state.Turn); err != nil {
+					t.Fatal("Unable to pass:", err)
+				}
+			}
+
+			if err := state.Call(state.Turn, turned_down, false); err == nil {
+				t.Fatal("Expected naming the turned down suit to fail")
+			}
+
+			for state.Turn != (state.Dealer+2)%4 {
+				if err := state.Pass(state.Turn); err != nil {
+					t.Fatal("Unable to pass:", err)
+				}
+			}
+
+			var trump = ClubsSuit
+			if trump == turned_down {
+				trump = HeartsSuit
+			}
+
+			var alone = config.GoingAlone && hand%2 == 1
+			if err := state.Call(state.Turn, trump, alone); err != nil {
+				t.Fatal("Unable to call trump:", err)
+			}
+
+			if state.Players[state.Dealer].SittingOut != alone {
+				t.Fatal("Expected the dealer to sit out only when their partner goes alone")
+			}
+
+			var err error
+			for err == nil {
+				err = playEuchreCard(t, &state)
+			}
+
+			if err.Error() == EuchreNextRound && state.Dealt {
+				t.Fatal("Expected next round to require a deal")
+			}
+		}
+
+		if !state.Finished || len(state.Winners) != 2 {
+			t.Fatal("Expected game to finish with a winning team:", state.Scores)
+		}
+
+		var team = EuchreTeam(state.Winners[0])
+		if state.Scores[team] < config.WinAmount || state.Scores[team] <= state.Scores[1-team] {
+			t.Fatal("Expected winning team to reach the threshold:", state.Scores)
+		}
+	}
+}
+
+func TestEuchreOrderUp(t *testing.T) {
+	var state EuchreState
+	if err := state.Init(EuchreConfig{NumPlayers: 4, WinAmount: 10, StickTheDealer: true}); err != nil {
+		t.Fatal("Unable to initialize game:", err)
+	}
+
+	if err := state.Start(4); err != nil {
+		t.Fatal("Unable to start game:", err)
+	}
+
+	var turned_up = *state.TurnedUp
+	if err := state.Call(state.Turn, NoneSuit, true); err == nil {
+		t.Fatal("Expected going alone to be disallowed")
+	}
+
+	if err := state.Call(state.Turn, turned_up.Suit, false); err != nil {
+		t.Fatal("Unable to order up:", err)
+	}
+
+	if !state.Discarding || state.Turn != state.Dealer || len(state.Players[state.Dealer].Hand) != 6 {
+		t.Fatal("Expected dealer to pick up and discard")
+	}
+
+	if err := state.PlayCard(state.Turn, state.Players[state.Turn].Hand[0].ID); err == nil {
+		t.Fatal("Expected play before discarding to fail")
+	}
+
+	if err := state.Discard(state.Dealer, turned_up.ID); err != nil {
+		t.Fatal("Unable to discard:", err)
+	}
+
+	if len(state.Players[state.Dealer].Hand) != 5 || state.Turn != 1 {
+		t.Fatal("Expected play to begin left of the dealer")
+	}
+}
+
+func TestEuchreSeating(t *testing.T) {
+	var c Controller
+	c.Init()
+
+	var config = EuchreConfig{NumPlayers: 4, WinAmount: 10}
+	if err := c.addGame("euchre", 1, 1, &config); err != nil {
+		t.Fatal("Unable to add game:", err)
+	}
+
+	var game = c.ToGame[1]
+	for uid := uint64(1); uid <= 4; uid++ {
+		game.ToPlayer[uid] = &PlayerData{UID: uid, Index: -1, Admitted: true, Playing: true, Notifications: make(map[uint64]chan interface{})}
+	}
+
+	var send = func(uid uint64, message string) error {
+		_, err := c.Dispatch([]byte(`{"game_mode":"euchre","game_id":1,"player_id":`+strconv.FormatUint(uid, 10)+`,`+message+`}`), 1, uid, uid)
+		return err
+	}
+
+	if err := send(1, `"message_type":"start"`); err == nil {
+		t.Fatal("Expected to need seats assigned before starting")
+	}
+
+	if err := send(2, `"message_type":"assign","num_players":4,"player_map":[1,2,3,4],"team_assignments":[[0],[1],[2],[3]]`); err == nil {
+		t.Fatal("Expected only the owner to assign seats")
+	}
+
+	if err := send(1, `"message_type":"assign","num_players":4,"player_map":[1,2,3,4],"team_assignments":[[0,1,2],[3]]`); err == nil {
+		t.Fatal("Expected partners to be assigned in pairs")
+	}
+
+	// Teammates sit across from each other.
+	if err := send(1, `"message_type":"assign","num_players":4,"player_map":[1,2,3,4],"team_assignments":[[0,1],[2,3]]`); err != nil {
+		t.Fatal("Unable to assign seats:", err)
+	}
+
+	if game.ToPlayer[1].Index != 0 || game.ToPlayer[3].Index != 1 || game.ToPlayer[2].Index != 2 || game.ToPlayer[4].Index != 3 {
+		t.Fatal("Expected partners to sit across from each other:", game.ToPlayer[1].Index, game.ToPlayer[2].Index, game.ToPlayer[3].Index, game.ToPlayer[4].Index)
+	}
+
+	// Without teams, players sit in the order given.
+	if err := send(1, `"message_type":"assign","num_players":4,"player_map":[4,3,2,1],"team_assignments":[[0],[1],[2],[3]]`); err != nil {
+		t.Fatal("Unable to assign seats:", err)
+	}
+
+	if game.ToPlayer[4].Index != 0 || game.ToPlayer[1].Index != 3 {
+		t.Fatal("Expected players to sit in the order given:", game.ToPlayer[4].Index, game.ToPlayer[1].Index)
+	}
+
+	if err := send(1, `"message_type":"start"`); err != nil {
+		t.Fatal("Unable to start game:", err)
+	}
+
+	if state := game.State.(*EuchreState); !state.Started || state.Dealer != 0 || len(state.Players[3].Hand) != 5 {
+		t.Fatal("Expected the game to start with the seats assigned:", state.Started)
+	}
+}
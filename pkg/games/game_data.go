@@ -30,6 +30,23 @@ type GameData struct {
 
 	// Timer to ensure we delay between countdown events.
 	CountdownTimer *time.Timer `json:"-"`
+
+	// Pending request to take back a player's last action, if any.
+	Undo *UndoRequest `json:"undo,omitempty"`
+
+	// Clock of whoever's turn it is, in games with turn clocks.
+	Clock *GameClock `json:"clock,omitempty"`
+
+	// Set while the owner has paused the game.
+	Paused *GamePause `json:"paused,omitempty"`
+
+	// Whether the owner picked the secret the game is dealt from, rather than
+	// the server. They know every deal, so they can only watch.
+	PickedSeed bool `json:"picked_seed,omitempty"`
+
+	// Seed the order players are seated in is drawn from, when the game is
+	// dealt from a shuffle secret.
+	Seating *ShuffleSeed `json:"seating,omitempty"`
 }
 
 // Map a player identifier to Index.
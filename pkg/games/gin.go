@@ -59,6 +59,11 @@ type GinConfig struct {
 
 	// Common game configuration options
 	Countdown bool `json:"countdown" config:"type:bool,default:true" label:"true:Show a 3... 2... 1... countdown before beginning,false:Start the game instantly"` // Whether to wait and send countdown messages.
+
+	// Chess clock style limits on how long each player may take.
+	TurnClock   int `json:"turn_clock" config:"type:int,min:0,default:0,max:600" label:"Seconds per turn (0 for no limit)"`
+	GameClock   int `json:"game_clock" config:"type:int,min:0,default:0,max:120" label:"Minutes per player for the whole game (0 for no limit)"`
+	ClockPolicy int `json:"clock_policy" config:"type:enum,default:0,options:0:Play automatically;1:Skip their turn;2:Forfeit the game" label:"When a player runs out of time"` // See ClockPolicy.
 }
 
 func (cfg GinConfig) Validate() error {
@@ -96,17 +101,19 @@ type GinRound struct {
 	Turns   []GinTurn        `json:"plays"`
 	Discard []*Card          `json:"discard"`
 
-	Deck []*Card `json:"deck"`
+	Deck []*Card      `json:"deck"`
+	Seed *ShuffleSeed `json:"seed"`
 }
 
 type GinState struct {
 	Turn   int `json:"turn"`
 	Dealer int `json:"dealer"`
 
-	Deck         Deck        `json:"deck"`
-	Discard      []*Card     `json:"discard"`
-	Players      []GinPlayer `json:"players"` // Left of dealer is found by incrementing one.
-	RoundHistory []*GinRound `json:"round_history"`
+	Deck         Deck         `json:"deck"`
+	Discard      []*Card      `json:"discard"`
+	Players      []GinPlayer  `json:"players"` // Left of dealer is found by incrementing one.
+	RoundHistory []*GinRound  `json:"round_history"`
+	Seed         *ShuffleSeed `json:"seed,omitempty"` // Secret each round's shuffle seed derives from; when unset, every round gets a fresh seed.
 
 	Config GinConfig `json:"config"`
 
@@ -160,11 +167,89 @@ func (gs *GinState) IsFinished() bool {
 	return gs.Finished
 }
 
+func (gs *GinState) SetShuffleSeed(seed *ShuffleSeed) error {
+	if gs.Started {
+		return errors.New("unable to set the shuffle seed after the game has started")
+	}
+
+	gs.Seed = seed
+	return nil
+}
+
+func (gs *GinState) RevealedSeeds() []*ShuffleSeed {
+	var seeds = make([]*ShuffleSeed, 0, len(gs.RoundHistory))
+	for _, round := range gs.RoundHistory {
+		seeds = append(seeds, round.Seed)
+	}
+
+	return revealedRoundSeeds(seeds, gs.Dealt && !gs.Finished)
+}
+
 func (gs *GinState) ResetStatus() {
 	gs.Started = false
 	gs.Finished = false
 }
 
+func (gs *GinState) TurnClocks() TurnClocks {
+	return newTurnClocks(gs.Config.TurnClock, gs.Config.GameClock, gs.Config.ClockPolicy)
+}
+
+func (gs *GinState) ClockedPlayer() int {
+	// Once someone goes out, everyone scores at once.
+	if !gs.Started || gs.Finished || !gs.Dealt || gs.LaidDown != -1 {
+		return -1
+	}
+
+	return gs.Turn
+}
+
+func (gs *GinState) Timeout(player int, policy ClockPolicy) error {
+	if player != gs.ClockedPlayer() {
+		return errors.New("not your turn")
+	}
+
+	if policy == SkipOnTimeout && gs.Players[player].Drawn == nil {
+		gs.Turn = (gs.Turn + 1) % len(gs.Players)
+		return nil
+	}
+
+	if policy == ForfeitOnTimeout {
+		gs.Winner = gs.forfeitWinner(player)
+		gs.Dealer = -1
+		gs.Turn = -1
+		gs.Finished = true
+		return errors.New(GinGameOver)
+	}
+
+	// Draw from the deck and discard the drawn card. A player who already
+	// picked up the discard can't put it back, so they discard from their hand.
+	if gs.Players[player].Drawn == nil {
+		if err := gs.TakeCard(player, false); err != nil {
+			return err
+		}
+	}
+
+	if !gs.Players[player].PickedUpDiscard {
+		return gs.DiscardCard(player, gs.Players[player].Drawn.ID, false)
+	}
+
+	return playLowestCard(gs.Players[player].Hand, false, func(card int) error {
+		return gs.DiscardCard(player, card, false)
+	})
+}
+
+// Whoever else has the highest score wins when a player forfeits.
+func (gs *GinState) forfeitWinner(player int) int {
+	var winner = -1
+	for index, indexed_player := range gs.Players {
+		if index != player && (winner == -1 || indexed_player.Score > gs.Players[winner].Score) {
+			winner = index
+		}
+	}
+
+	return winner
+}
+
 func (gs *GinState) Start(players int) error {
 	var err error
 
@@ -224,10 +309,12 @@ func (gs *GinState) StartRound() error {
 	if gs.Config.AddJokers {
 		gs.Deck.AddJokers(2, true)
 	}
+	gs.Deck.Reseed(gs.Seed, len(gs.RoundHistory))
 	gs.Deck.Shuffle()
 
 	// Save the initial deck.
 	history.Deck = CopyDeck(gs.Deck.Cards)
+	history.Seed = gs.Deck.Seed.Copy()
 
 	// Clear out all round-specific status before each round.
 	for index := range gs.Players {
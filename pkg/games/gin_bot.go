@@ -0,0 +1,230 @@
+package games
+
+import (
+	"git.cipherboy.com/WillowPatchGames/wpg/internal/utils"
+)
+
+// GinBot is a computer opponent for Gin. It relies on the GinSolver to pick
+// up the discard only when it reduces deadwood, to discard whichever card
+// leaves the least deadwood behind, and to lay down with the best grouping
+// (playing off of the other player's groups when allowed). Its difficulty
+// sets how often it blunders a draw or discard.
+type GinBot struct{}
+
+func (ginEngine) NewBot() GameBot {
+	return GinBot{}
+}
+
+func (gb GinBot) NextMove(game *GameData, player *PlayerData) (interface{}, error) {
+	var state *GinState = game.State.(*GinState)
+	if state == nil || !state.Started || state.Finished {
+		return nil, nil
+	}
+
+	var index = player.Index
+	if index < 0 || index >= len(state.Players) {
+		return nil, nil
+	}
+
+	if !state.Dealt {
+		if state.Dealer != index {
+			return nil, nil
+		}
+
+		return botHeader(game, player, "deal"), nil
+	}
+
+	if state.Turn != index {
+		return nil, nil
+	}
+
+	var solver = state.GinSolver()
+	var us = &state.Players[index]
+
+	if state.LaidDown != -1 {
+		if us.RoundScore != -1 || us.Drawn != nil {
+			return nil, nil
+		}
+
+		var msg GinScoreByGroupsMsg
+		msg.MessageHeader = botHeader(game, player, "score_by_groups")
+		msg.Groups, msg.Leftover = gb.chooseGroups(state, &solver, index)
+		return msg, nil
+	}
+
+	if us.Drawn == nil {
+		var msg GinTakeMsg
+		msg.MessageHeader = botHeader(game, player, "take")
+		if len(state.Discard) > 0 {
+			msg.FromDiscard = ginBotWantsDiscard(&solver, us.Hand, *state.Discard[len(state.Discard)-1])
+			if len(state.Deck.Cards) == 0 {
+				msg.FromDiscard = true
+			} else if botBlunders(player) {
+				msg.FromDiscard = !msg.FromDiscard
+			}
+		}
+
+		return msg, nil
+	}
+
+	var blunder = botBlunders(player)
+
+	var msg GinDiscardMsg
+	msg.MessageHeader = botHeader(game, player, "discard")
+
+	if state.Config.BigGinAmount != -1 && !blunder {
+		// Keep every card when they all fit into groups.
+		var everything = append(CopyHand(us.Hand), *us.Drawn)
+		if solver.MinScoreBelow(everything, 0) == 0 {
+			msg.CardID = -1
+			msg.LayingDown = true
+			return msg, nil
+		}
+	}
+
+	var score int
+	msg.CardID, score = ginBotChooseDiscard(&solver, us.Hand, *us.Drawn, us.PickedUpDiscard, blunder)
+	msg.LayingDown = score <= state.Config.LayingDownLimit
+	return msg, nil
+}
+
+// Pick the groups to score with. When the other player laid down without
+// going gin, try playing off of their groups as well.
+func (gb GinBot) chooseGroups(state *GinState, solver *GinSolver, player int) ([][]int, []int) {
+	var hand = CopyHand(state.Players[player].Hand)
+	var ours = len(hand)
+	var using = make([][]int, 0)
+
+	var laid_down = state.Players[state.LaidDown]
+	if player != state.LaidDown && laid_down.RoundScore != 0 {
+		// Mirror ScoreByGroups: only the other player's grouped cards are
+		// available to play off of.
+		var leftover = make(map[int]bool)
+		for _, cardID := range laid_down.Leftover {
+			leftover[cardID] = true
+		}
+
+		for _, card := range laid_down.Hand {
+			if !leftover[card.ID] {
+				hand = append(hand, card)
+			}
+		}
+
+		for _, group := range laid_down.Groups {
+			var indices = make([]int, 0, len(group))
+			for _, cardID := range group {
+				if index, found := FindCard(hand, cardID); found {
+					indices = append(indices, index)
+				}
+			}
+			using = append(using, indices)
+		}
+	}
+
+	groups, leftover, _ := solver.GroupingsUsing(hand, using)
+
+	// Only send the other player's groups when we actually played off of
+	// them; otherwise, ScoreByGroups expects only our own cards.
+	var played_off = false
+	for _, group := range groups {
+		var mine = false
+		var theirs = false
+		for _, index := range group {
+			mine = mine || index < ours
+			theirs = theirs || index >= ours
+		}
+		played_off = played_off || (mine && theirs)
+	}
+
+	var ret = make([][]int, 0, len(groups))
+	for _, group := range groups {
+		var only_theirs = true
+		for _, index := range group {
+			only_theirs = only_theirs && index >= ours
+		}
+
+		if only_theirs && !played_off {
+			continue
+		}
+
+		ret = append(ret, ginBotCardIDs(hand, group))
+	}
+
+	return ret, ginBotCardIDs(hand, leftover)
+}
+
+// Whether taking the top card of the discard pile leaves less deadwood than
+// the hand currently has.
+func ginBotWantsDiscard(solver *GinSolver, hand []Card, top Card) bool {
+	_, with := ginBotChooseDiscard(solver, hand, top, true, false)
+	return with < solver.MinScore(hand)
+}
+
+// Choose the card to discard after drawing, returning its identifier and the
+// deadwood left in the hand afterwards. A card picked up from the discard
+// pile isn't discarded again. Ties go to discarding the card worth the most
+// points. When blundering, a random card is discarded instead.
+func ginBotChooseDiscard(solver *GinSolver, hand []Card, drawn Card, from_discard bool, blunder bool) (int, int) {
+	var with_drawn = func(skip int) []Card {
+		var ret = make([]Card, 0, len(hand))
+		for index, card := range hand {
+			if index != skip {
+				ret = append(ret, card)
+			}
+		}
+
+		if skip >= 0 {
+			ret = append(ret, drawn)
+		}
+
+		return ret
+	}
+
+	if blunder {
+		var choice = utils.SecureRand.Intn(len(hand) + 1)
+		if choice == len(hand) && !from_discard {
+			return drawn.ID, solver.MinScore(hand)
+		}
+
+		choice = choice % len(hand)
+		return hand[choice].ID, solver.MinScore(with_drawn(choice))
+	}
+
+	var best_id = -1
+	var best_score = 0
+	var best_value = 0
+	var consider = func(card Card, skip int) {
+		var score = solver.MinScore(with_drawn(skip))
+		var value = solver.PointValue[card.Rank]
+		if solver.IsWildCard(card) {
+			// Never give away a wild card on a tie.
+			value = -1
+		}
+
+		if best_id == -1 || score < best_score || (score == best_score && value > best_value) {
+			best_id = card.ID
+			best_score = score
+			best_value = value
+		}
+	}
+
+	if !from_discard {
+		consider(drawn, -1)
+	}
+
+	for index, card := range hand {
+		consider(card, index)
+	}
+
+	return best_id, best_score
+}
+
+// Convert indices into hand to card identifiers.
+func ginBotCardIDs(hand []Card, indices []int) []int {
+	var ret = make([]int, 0, len(indices))
+	for _, index := range indices {
+		ret = append(ret, hand[index].ID)
+	}
+
+	return ret
+}
@@ -0,0 +1,52 @@
+package games
+
+import (
+	"testing"
+)
+
+func TestGinBots(t *testing.T) {
+	for _, difficulty := range []string{BotHard, BotEasy} {
+		var config = GinConfig{NumPlayers: 2, HandSize: 10, SameSuitRuns: true, LayingDownLimit: 10, WinAmount: 50, GinAmount: 10, BigGinAmount: 20, UndercutAmount: 10, SuggestBetter: true}
+
+		var c Controller
+		c.Init()
+
+		game := newBotGame(t, &c, "gin", config, config.NumPlayers)
+		for _, player := range game.ToPlayer {
+			if player.Bot {
+				player.BotDifficulty = difficulty
+			}
+		}
+
+		if err := game.State.(*GinState).Init(config); err != nil {
+			t.Fatal("Unable to initialize gin:", err)
+		}
+
+		if err := (ginEngine{}).Start(&c, game); err != nil {
+			t.Fatal("Unable to start gin:", err)
+		}
+
+		playBotGame(t, &c, game)
+	}
+}
+
+func TestThreeThirteenBots(t *testing.T) {
+	for _, config := range []ThreeThirteenConfig{
+		{NumPlayers: 3, MinDrawSize: 15, AddJokers: true, WildAsRank: true, SameSuitRuns: true, AllowLastDraw: true, ToPointLimit: 50, GolfScoring: true, SuggestBetter: true},
+		{NumPlayers: 2, MinDrawSize: 13, LayingDownLimit: 5, AllowMostlyWild: true, ToPointLimit: 100, SuggestBetter: true},
+	} {
+		var c Controller
+		c.Init()
+
+		game := newBotGame(t, &c, "three thirteen", config, config.NumPlayers)
+		if err := game.State.(*ThreeThirteenState).Init(config); err != nil {
+			t.Fatal("Unable to initialize three thirteen:", err)
+		}
+
+		if err := (threeThirteenEngine{}).Start(&c, game); err != nil {
+			t.Fatal("Unable to start three thirteen:", err)
+		}
+
+		playBotGame(t, &c, game)
+	}
+}
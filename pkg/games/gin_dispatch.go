@@ -13,6 +13,7 @@ import (
 // 2. Take
 // 3. Discard
 // 4. Score
+// 5. Timeout (sent by the server when a player runs out of time)
 
 type GinTakeMsg struct {
 	MessageHeader
@@ -176,6 +177,15 @@ func (c *Controller) dispatchGin(message []byte, header MessageHeader, game *Gam
 
 		err = state.Order(player.Index, data.Order)
 		send_state = true
+	case "timeout":
+		var data GameTimeout
+		if err = json.Unmarshal(message, &data); err != nil {
+			return err
+		}
+
+		err = state.Timeout(player.Index, data.Policy)
+		send_synopsis = true
+		send_state = true
 	case "peek":
 		if player.Index != -1 && !state.Finished {
 			return errors.New("can only peek once game is complete")
@@ -255,13 +265,7 @@ func (c *Controller) doGinStart(game *GameData, state *GinState) error {
 	}
 
 	// Assign indices to players before sending notifications.
-	var player_index int = 0
-	for _, indexed_player := range game.ToPlayer {
-		if indexed_player.Admitted && indexed_player.Playing {
-			indexed_player.Index = player_index
-			player_index++
-		}
-	}
+	seatPlayers(game)
 
 	// Send out initial state data to individuals who are playing. Also notify
 	// all players that the game has started.
@@ -292,3 +296,48 @@ func (c *Controller) doGinStart(game *GameData, state *GinState) error {
 
 	return nil
 }
+
+// ginEngine registers Gin with the controller; see GameEngine.
+type ginEngine struct{}
+
+func init() {
+	MustRegisterGameEngine(ginEngine{})
+}
+
+func (ginEngine) Mode() GameMode {
+	return GinGame
+}
+
+func (ginEngine) Name() string {
+	return "gin"
+}
+
+func (ginEngine) Title() string {
+	return "Gin (Card Game)"
+}
+
+func (ginEngine) Description() string {
+	return "In Gin, players compete against each other to go out each round."
+}
+
+func (ginEngine) EmptyConfig() figgy.Figgurable {
+	return &GinConfig{}
+}
+
+func (ginEngine) NewState() ConfigurableState {
+	return &GinState{}
+}
+
+func (ginEngine) Init(config figgy.Figgurable) (ConfigurableState, error) {
+	var asserted *GinConfig = config.(*GinConfig)
+	var state = &GinState{}
+	return state, state.Init(*asserted)
+}
+
+func (ginEngine) Dispatch(c *Controller, message []byte, header MessageHeader, game *GameData, player *PlayerData, sid uint64) error {
+	return c.dispatchGin(message, header, game, player, sid)
+}
+
+func (ginEngine) Start(c *Controller, game *GameData) error {
+	return c.doGinStart(game, game.State.(*GinState))
+}
@@ -22,6 +22,8 @@ type GinGameState struct {
 	Discard  []Card `json:"discard"`
 	DrawDeck int    `json:"draw_deck"`
 
+	Commitment string `json:"commitment"` // To the current round's shuffle seed; see ShuffleSeed.
+
 	Config GinConfig `json:"config"`
 
 	Started  bool `json:"started"`
@@ -67,6 +69,7 @@ func (gsn *GinStateNotification) LoadData(data *GameData, game *GinState, player
 	}
 	gsn.DrawDeck = len(game.Deck.Cards)
 
+	gsn.Commitment = game.Deck.Commitment()
 	gsn.Config = game.Config
 
 	gsn.Started = game.Started
@@ -523,7 +523,6 @@ func (gs *GinSolver) MinScoreBelowUsing(hand []Card, maxScore int, using [][]int
 				usingHere = append(usingHere, groupHere)
 			}
 		}
-		log.Println("usingHere", usingHere, rankedHere)
 		// Partition it into disjoint subsets
 		// that cannot be connected by `nwilds` wild cards
 		divided := gs.DivideHandBy(rankedHere, nwilds)
@@ -710,7 +709,6 @@ AllMatches:
 				match.wc.min = wc
 			}
 		}
-		log.Println("Adding", match)
 		all = append(all, match)
 	}
 
@@ -803,3 +801,254 @@ func choose(n int, c int) [][]int {
 	}
 	return r
 }
+
+// Arranges the hand into groups so that the value of the leftover cards is
+// as small as possible, returning the groups, the leftover cards (both as
+// indices into the hand) and their value. This is the constructive
+// counterpart to MinScore.
+func (gs *GinSolver) Groupings(hand []Card) ([][]int, []int, int) {
+	return gs.GroupingsUsing(hand, make([][]int, 0))
+}
+
+// For gin: like Groupings, but `using` holds groups (indices into the hand)
+// which another player has already laid down. These may be extended with
+// cards from the hand, but are always kept whole and their cards never count
+// towards the leftover value. Every group in `using` is returned, extended
+// or not.
+func (gs *GinSolver) GroupingsUsing(hand []Card, using [][]int) ([][]int, []int, int) {
+	var search = groupingSearch{
+		gs:     gs,
+		hand:   hand,
+		using:  using,
+		memo:   make(map[[2]uint64]groupingChoice),
+		budget: maxGroupingChecks,
+	}
+
+	var free uint64 = 0
+	for index := range hand {
+		free |= 1 << uint(index)
+	}
+	for _, group := range using {
+		for _, index := range group {
+			free &^= 1 << uint(index)
+		}
+	}
+
+	var groups = make([][]int, 0)
+	var leftover = make([]int, 0)
+	var extended = make(map[int][]int)
+	var score = search.solve(free, 0)
+
+	var used uint64 = 0
+	for free != 0 {
+		choice := search.memo[[2]uint64{free, used}]
+		if choice.base >= 0 {
+			extended[choice.base] = choice.cards
+			used |= 1 << uint(choice.base)
+		} else if len(choice.cards) > 1 {
+			groups = append(groups, choice.cards)
+		} else {
+			leftover = append(leftover, choice.cards...)
+		}
+
+		for _, index := range choice.cards {
+			free &^= 1 << uint(index)
+		}
+	}
+
+	for base, group := range using {
+		var result = append(make([]int, 0, len(group)), group...)
+		result = append(result, extended[base]...)
+		groups = append(groups, result)
+	}
+
+	return groups, leftover, score
+}
+
+type groupingChoice struct {
+	score int
+	cards []int // Cards placed by this choice; a lone card is leftover.
+	base  int   // Index of the laid down group extended by cards, or -1.
+}
+
+// Upper bound on the number of candidate groups GroupingsUsing validates.
+// Bots call it while holding the game lock, so a hand with a great many
+// overlapping groups (such as most of a suit in three thirteen's last round)
+// settles for the best grouping found within this many checks rather than
+// searching exhaustively.
+const maxGroupingChecks = 1 << 16
+
+type groupingSearch struct {
+	gs     *GinSolver
+	hand   []Card
+	using  [][]int
+	memo   map[[2]uint64]groupingChoice
+	budget int // Remaining calls to IsValidGroup; see maxGroupingChecks.
+}
+
+// Tracks whether a growing set of cards could still be part of a kind or a
+// run, ignoring wild cards. Once a set of cards can be neither, adding more
+// cards never helps, so larger sets needn't be considered.
+type groupingShape struct {
+	kind  bool
+	run   bool
+	rank  CardRank
+	suit  CardSuit
+	ranks uint64
+}
+
+func newGroupingShape() groupingShape {
+	return groupingShape{kind: true, run: true, rank: NoneRank}
+}
+
+func (shape groupingShape) with(gs *GinSolver, card Card) groupingShape {
+	if gs.IsWildCard(card) {
+		return shape
+	}
+
+	var bit uint64 = 1 << uint(card.Rank)
+	if shape.rank == NoneRank {
+		shape.rank = card.Rank
+		shape.suit = card.Suit
+	}
+
+	// Kinds share a rank; runs never repeat a rank and might need to share a
+	// suit.
+	shape.kind = shape.kind && card.Rank == shape.rank
+	shape.run = shape.run && card.Rank >= AceRank && card.Rank <= KingRank && shape.ranks&bit == 0 && (!gs.SameSuitRuns || card.Suit == shape.suit)
+	shape.ranks |= bit
+	return shape
+}
+
+func (shape groupingShape) possible() bool {
+	return shape.kind || shape.run
+}
+
+// Call visit with each subset of candidates which could still form a group
+// with the cards already chosen (as described by shape), along with the free
+// cards remaining after placing them.
+func (search *groupingSearch) eachSubset(shape groupingShape, cards []int, candidates []int, after uint64, visit func(cards []int, after uint64)) {
+	if search.budget <= 0 {
+		return
+	}
+
+	visit(cards, after)
+
+	for offset, index := range candidates {
+		var next = shape.with(search.gs, search.hand[index])
+		if !next.possible() {
+			continue
+		}
+
+		var chosen = append(append(make([]int, 0, len(cards)+1), cards...), index)
+		search.eachSubset(next, chosen, candidates[offset+1:], after&^(1<<uint(index)), visit)
+	}
+}
+
+// Like IsValidGroup, but counted against the search's budget; once that runs
+// out, no more groups are formed.
+func (search *groupingSearch) isValidGroup(cards []int) bool {
+	if len(cards) < 3 || search.budget <= 0 {
+		return false
+	}
+
+	search.budget--
+	return search.gs.IsValidGroup(search.hand, cards)
+}
+
+// Whether two cards could ever appear in the same group.
+func (search *groupingSearch) related(left Card, right Card) bool {
+	if search.gs.IsWildCard(left) || search.gs.IsWildCard(right) || left.Rank == right.Rank {
+		return true
+	}
+
+	if search.gs.SameSuitRuns && left.Suit != right.Suit {
+		return false
+	}
+
+	var delta = int(left.Rank) - int(right.Rank)
+	if delta < 0 {
+		delta = -delta
+	}
+
+	return delta < len(search.hand) || search.gs.AceHigh || search.gs.RunsWrap
+}
+
+// Find the minimum leftover value of the free cards (a bitmask of indices
+// into the hand) given the laid down groups already extended (a bitmask of
+// indices into using). The first free card is either left over, starts a
+// new group, or extends a laid down group; every option is tried and the
+// best is memoized for reconstruction.
+func (search *groupingSearch) solve(free uint64, extended uint64) int {
+	if free == 0 {
+		return 0
+	}
+
+	var key = [2]uint64{free, extended}
+	if choice, ok := search.memo[key]; ok {
+		return choice.score
+	}
+
+	// Prefer to start from a ranked card: wild cards can join any group, so
+	// they're best placed alongside the ranked cards they complete.
+	var first = -1
+	for index := range search.hand {
+		if free&(1<<uint(index)) != 0 && (first == -1 || (search.gs.IsWildCard(search.hand[first]) && !search.gs.IsWildCard(search.hand[index]))) {
+			first = index
+		}
+	}
+
+	var candidates []int
+	for index := range search.hand {
+		if index != first && free&(1<<uint(index)) != 0 && search.related(search.hand[first], search.hand[index]) {
+			candidates = append(candidates, index)
+		}
+	}
+
+	var remaining = free &^ (1 << uint(first))
+	var best = groupingChoice{
+		score: search.gs.PointValue[search.hand[first].Rank] + search.solve(remaining, extended),
+		cards: []int{first},
+		base:  -1,
+	}
+
+	var shape = newGroupingShape().with(search.gs, search.hand[first])
+	search.eachSubset(shape, []int{first}, candidates, remaining, func(cards []int, after uint64) {
+		if !search.isValidGroup(cards) {
+			return
+		}
+
+		if score := search.solve(after, extended); score < best.score {
+			best = groupingChoice{score, cards, -1}
+		}
+	})
+
+	for base, group := range search.using {
+		if extended&(1<<uint(base)) != 0 {
+			continue
+		}
+
+		var shape = newGroupingShape()
+		for _, index := range append(append([]int{}, group...), first) {
+			shape = shape.with(search.gs, search.hand[index])
+		}
+
+		if !shape.possible() {
+			continue
+		}
+
+		search.eachSubset(shape, []int{first}, candidates, remaining, func(cards []int, after uint64) {
+			var whole = append(append(make([]int, 0, len(group)+len(cards)), group...), cards...)
+			if !search.isValidGroup(whole) {
+				return
+			}
+
+			if score := search.solve(after, extended|1<<uint(base)); score < best.score {
+				best = groupingChoice{score, cards, base}
+			}
+		})
+	}
+
+	search.memo[key] = best
+	return best.score
+}
@@ -4,6 +4,7 @@ import (
 	"reflect"
 	"sort"
 	"testing"
+	"time"
 )
 
 type GroupEntry struct {
@@ -1194,3 +1195,83 @@ func TestIsValidGroup(t *testing.T) {
 		}
 	}
 }
+
+func TestGroupings(t *testing.T) {
+	for _, tc := range HandTestCases {
+		for _, entry := range tc.Entries {
+			groups, leftover, score := (&tc.Solver).Groupings(entry.Hand)
+			if score != entry.Score {
+				t.Error("ERROR Expected:", entry.Score, "got:", score, "\nfor hand\n", entry.Hand, "\nand solver\n", tc.Solver)
+			}
+
+			var seen = make(map[int]bool)
+			for _, group := range groups {
+				if !(&tc.Solver).IsValidGroup(entry.Hand, group) {
+					t.Error("ERROR Invalid group", group, "\nfor hand\n", entry.Hand, "\nand solver\n", tc.Solver)
+				}
+
+				for _, index := range group {
+					seen[index] = true
+				}
+			}
+
+			var leftover_score = 0
+			for _, index := range leftover {
+				seen[index] = true
+				leftover_score += tc.Solver.PointValue[entry.Hand[index].Rank]
+			}
+
+			if len(seen) != len(entry.Hand) || leftover_score != score {
+				t.Error("ERROR Expected every card to be placed once with leftover worth", score, "got:", groups, leftover, "\nfor hand\n", entry.Hand)
+			}
+		}
+	}
+
+	// Extending a group laid down by another player.
+	hand := []Card{
+		Card{1, ClubsSuit, FourRank},
+		Card{2, ClubsSuit, FiveRank},
+		Card{3, ClubsSuit, SixRank},
+		Card{4, ClubsSuit, SevenRank},
+		Card{5, HeartsSuit, KingRank},
+	}
+	groups, leftover, score := defaultSolver.GroupingsUsing(hand, [][]int{[]int{0, 1, 2}})
+	if score != 13 || len(groups) != 1 || len(groups[0]) != 4 || len(leftover) != 1 {
+		t.Error("ERROR Expected to extend laid down run; got:", groups, leftover, score)
+	}
+}
+
+func TestGroupingsLargeHand(t *testing.T) {
+	// With wild cards and runs of any suit, a thirteen card hand can be
+	// grouped a great many ways; this should still be quick enough to run
+	// under the game lock.
+	var solver = defaultSolver
+	solver.WildCards = []CardRank{JokerRank, TwoRank}
+	solver.MostlyWildGroups = true
+	solver.RunsWrap = true
+
+	var suits = []CardSuit{SpadesSuit, HeartsSuit, ClubsSuit, DiamondsSuit}
+	var hand []Card
+	for index := 0; index < 11; index++ {
+		hand = append(hand, Card{index, suits[index%4], AceRank + CardRank((index*3)%13)})
+	}
+	hand = append(hand, Card{11, NoneSuit, JokerRank}, Card{12, NoneSuit, JokerRank})
+
+	var start = time.Now()
+	groups, leftover, score := solver.Groupings(hand)
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Error("ERROR Expected grouping to be bounded; took:", elapsed)
+	}
+
+	var placed = len(leftover)
+	for _, group := range groups {
+		if !solver.IsValidGroup(hand, group) {
+			t.Error("ERROR Invalid group", group, "\nfor hand\n", hand)
+		}
+		placed += len(group)
+	}
+
+	if placed != len(hand) || score != 0 {
+		t.Error("ERROR Expected every card to be grouped; got:", groups, leftover, score)
+	}
+}
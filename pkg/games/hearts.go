@@ -58,8 +58,15 @@ type HeartsConfig struct {
 	NoTrickBonus      bool `json:"no_trick_bonus" config:"type:bool,default:false" label:"true:Taking no tricks reduces your score by 5,false:No bonus for taking no tricks"`                              // Whether taking no tricks grants a -5 point bonus.
 	HundredToHalf     bool `json:"hundred_to_half" config:"type:bool,default:false" label:"true:Exactly hitting the ending amount halves your score,false:No prize for hitting the ending amount exactly"` // Whether hitting exactly WinAmount points reduces your score to half.
 
+	Takebacks int `json:"takebacks" config:"type:enum,default:0,options:0:No takebacks;1:Other players approve takebacks;2:Game owner approves takebacks" label:"Taking back a card played in the current trick"` // See UndoApproval.
+
 	// Common game configuration options
 	Countdown bool `json:"countdown" config:"type:bool,default:true" label:"true:Show a 3... 2... 1... countdown before beginning,false:Start the game instantly"` // Whether to wait and send countdown messages.
+
+	// Chess clock style limits on how long each player may take.
+	TurnClock   int `json:"turn_clock" config:"type:int,min:0,default:0,max:600" label:"Seconds per turn (0 for no limit)"`
+	GameClock   int `json:"game_clock" config:"type:int,min:0,default:0,max:120" label:"Minutes per player for the whole game (0 for no limit)"`
+	ClockPolicy int `json:"clock_policy" config:"type:enum,default:0,options:0:Play automatically;2:Forfeit the game" label:"When a player runs out of time"` // See ClockPolicy.
 }
 
 func (cfg HeartsConfig) Validate() error {
@@ -89,6 +96,7 @@ type HeartsRoundPlayer struct {
 type HeartsRound struct {
 	Dealer        int                 `json:"dealer"`
 	Deck          []*Card             `json:"deck"`
+	Seed          *ShuffleSeed        `json:"seed"`
 	Crib          []Card              `json:"crib"`
 	Players       []HeartsRoundPlayer `json:"players"`
 	Tricks        []HeartsTrick       `json:"tricks"`
@@ -104,6 +112,14 @@ const (
 	HoldPassDirectionHearts   = iota // 3
 )
 
+// A card played in the current trick. Leading the first trick of a round also
+// clears the cards passed to each player and resets everyone's tricks taken.
+type HeartsTakeback struct {
+	TrickTakeback
+	Incoming [][]Card `json:"incoming,omitempty"`
+	Tricks   []int    `json:"tricks,omitempty"`
+}
+
 type HeartsState struct {
 	Turn   int `json:"turn"`
 	Leader int `json:"leader"`
@@ -119,6 +135,10 @@ type HeartsState struct {
 
 	PreviousTricks [][]Card       `json:"previous_tricks"` // Contents of previous tricks in the current round; sent to clients.
 	RoundHistory   []*HeartsRound `json:"round_history"`   // Contents of previous rounds for analysis.
+	Seed           *ShuffleSeed   `json:"seed,omitempty"`  // Secret each round's shuffle seed derives from; when unset, every round gets a fresh seed.
+
+	Actions   int              `json:"actions"`             // Number of cards played so far, numbering each for takebacks.
+	Takebacks []HeartsTakeback `json:"takebacks,omitempty"` // Cards played in the current trick, which can still be taken back.
 
 	Config HeartsConfig `json:"config"`
 
@@ -164,11 +184,126 @@ func (hs *HeartsState) IsFinished() bool {
 	return hs.Finished
 }
 
+func (hs *HeartsState) SetShuffleSeed(seed *ShuffleSeed) error {
+	if hs.Started {
+		return errors.New("unable to set the shuffle seed after the game has started")
+	}
+
+	hs.Seed = seed
+	return nil
+}
+
+func (hs *HeartsState) RevealedSeeds() []*ShuffleSeed {
+	var seeds = make([]*ShuffleSeed, 0, len(hs.RoundHistory))
+	for _, round := range hs.RoundHistory {
+		seeds = append(seeds, round.Seed)
+	}
+
+	return revealedRoundSeeds(seeds, hs.Dealt && !hs.Finished)
+}
+
 func (hs *HeartsState) ResetStatus() {
 	hs.Started = false
 	hs.Finished = false
 }
 
+func (hs *HeartsState) UndoApproval() UndoApproval {
+	return UndoApproval(hs.Config.Takebacks)
+}
+
+func (hs *HeartsState) UndoableActions() []UndoAction {
+	var ret = make([]UndoAction, 0, len(hs.Takebacks))
+	for _, takeback := range hs.Takebacks {
+		ret = append(ret, takeback.UndoAction)
+	}
+
+	return ret
+}
+
+func (hs *HeartsState) Undo(action int) error {
+	var first = -1
+	for index, takeback := range hs.Takebacks {
+		if takeback.Action == action {
+			first = index
+			break
+		}
+	}
+
+	if first == -1 {
+		return errors.New("no such card played in the current trick to take back")
+	}
+
+	// Take back the cards played after it first. Actions keep counting up, so
+	// a takeback is never mistaken for the action taken after it.
+	history := hs.RoundHistory[len(hs.RoundHistory)-1]
+	for len(hs.Takebacks) > first {
+		takeback := hs.Takebacks[len(hs.Takebacks)-1]
+		hs.Takebacks = hs.Takebacks[:len(hs.Takebacks)-1]
+
+		this_trick := &history.Tricks[len(history.Tricks)-1]
+		if takeback.takeBack(&hs.Players[takeback.Player].Hand, &hs.Played, &this_trick.Played) {
+			history.Tricks = history.Tricks[:len(history.Tricks)-1]
+			for player_index, incoming := range takeback.Incoming {
+				hs.Players[player_index].Incoming = incoming
+			}
+			for player_index, tricks := range takeback.Tricks {
+				hs.Players[player_index].Tricks = tricks
+			}
+		}
+
+		hs.Turn = takeback.Turn
+		hs.HeartsBroken = takeback.Broken
+	}
+
+	return nil
+}
+
+func (hs *HeartsState) TurnClocks() TurnClocks {
+	return newTurnClocks(hs.Config.TurnClock, hs.Config.GameClock, hs.Config.ClockPolicy)
+}
+
+func (hs *HeartsState) ClockedPlayer() int {
+	// Everyone passes cards at once, so only playing cards is clocked.
+	if !hs.Started || hs.Finished || !hs.Dealt || !hs.Passed {
+		return -1
+	}
+
+	return hs.Turn
+}
+
+func (hs *HeartsState) Timeout(player int, policy ClockPolicy) error {
+	if player != hs.ClockedPlayer() {
+		return errors.New("not your turn")
+	}
+
+	if policy == ForfeitOnTimeout {
+		return hs.forfeit(player)
+	}
+
+	return playLowestCard(hs.Players[player].Hand, true, func(card int) error {
+		return hs.PlayCard(player, card)
+	})
+}
+
+// End the game early, with the lowest score of the other players winning.
+func (hs *HeartsState) forfeit(player int) error {
+	var winner = -1
+	for index, indexed_player := range hs.Players {
+		if index != player && (winner == -1 || indexed_player.Score < hs.Players[winner].Score) {
+			winner = index
+		}
+	}
+
+	hs.Finished = true
+	hs.Dealt = true
+	hs.Passed = true
+	hs.Winner = winner
+	hs.Turn = -1
+	hs.Dealer = -1
+	hs.Takebacks = nil
+	return errors.New(HeartsGameOver)
+}
+
 func (hs *HeartsState) Start(players int) error {
 	var err error
 
@@ -285,10 +420,12 @@ func (hs *HeartsState) StartRound() error {
 	}
 
 	// Shuffling the deck before assigning cards to players.
+	hs.Deck.Reseed(hs.Seed, len(hs.RoundHistory))
 	hs.Deck.Shuffle()
 
 	// Save the initial deck.
 	history.Deck = CopyDeck(hs.Deck.Cards)
+	history.Seed = hs.Deck.Seed.Copy()
 
 	// Clear out all round-specific status before each round.
 	for index := range hs.Players {
@@ -530,7 +667,69 @@ func (hs *HeartsState) PassCards(player int, cards []int) error {
 	return nil
 }
 
+// Whether the hand only holds cards which can't normally be played on the
+// first trick: hearts and the Queen of Spades.
+func onlyFirstTrickPoints(hand []Card) bool {
+	for _, card := range hand {
+		if card.Suit != HeartsSuit && (card.Suit != SpadesSuit || card.Rank != QueenRank) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Whether the next card played leads the first trick of the round.
+func (hs *HeartsState) leadingFirstTrick() bool {
+	if hs.Turn != hs.Leader || len(hs.RoundHistory) == 0 {
+		return false
+	}
+
+	history := hs.RoundHistory[len(hs.RoundHistory)-1]
+	trick_index := len(history.Tricks)
+	return trick_index == 0 || (trick_index == 1 && len(history.Tricks[0].Played) == 0)
+}
+
 func (hs *HeartsState) PlayCard(player int, card int) error {
+	// Remember what playing this card changes, so it can be taken back until
+	// the trick is over.
+	var takeback HeartsTakeback
+	takeback.Player = player
+	takeback.Action = hs.Actions + 1
+	takeback.Turn = hs.Turn
+	takeback.Broken = hs.HeartsBroken
+	if player >= 0 && player < len(hs.Players) {
+		if index, found := hs.Players[player].FindCard(card); found {
+			takeback.Card = hs.Players[player].Hand[index]
+			takeback.Index = index
+		}
+	}
+
+	if hs.Turn == hs.Leader {
+		takeback.Played = hs.Played
+		if hs.leadingFirstTrick() {
+			for _, indexed_player := range hs.Players {
+				takeback.Incoming = append(takeback.Incoming, indexed_player.Incoming)
+				takeback.Tricks = append(takeback.Tricks, indexed_player.Tricks)
+			}
+		}
+	}
+
+	err := hs.playCard(player, card)
+	if err != nil && !isRoundSignal(err) {
+		return err
+	}
+
+	hs.Actions = takeback.Action
+	hs.Takebacks = append(hs.Takebacks, takeback)
+	if hs.Turn == hs.Leader || hs.Finished {
+		hs.Takebacks = nil
+	}
+
+	return err
+}
+
+func (hs *HeartsState) playCard(player int, card int) error {
 	if !hs.Started {
 		return errors.New("game hasn't started yet")
 	}
@@ -573,7 +772,7 @@ func (hs *HeartsState) PlayCard(player int, card int) error {
 	if hs.Turn == hs.Leader {
 		// Create our new trick in the history.
 		trick_index := len(history.Tricks)
-		first_trick = trick_index == 0 || (trick_index == 1 && len(history.Tricks[0].Played) == 0)
+		first_trick = hs.leadingFirstTrick()
 
 		// If this is the very first round, check whether or not we chose the
 		// right card. It will either be the Two or Three of Clubs.
@@ -628,7 +827,9 @@ func (hs *HeartsState) PlayCard(player int, card int) error {
 		}
 
 		if len(hs.RoundHistory[len(hs.RoundHistory)-1].Tricks) == 1 {
-			if !hs.Config.FirstTrickHearts {
+			// Points can still be played on the first trick when the player
+			// has nothing else to play.
+			if !hs.Config.FirstTrickHearts && !onlyFirstTrickPoints(hs.Players[player].Hand) {
 				if played.Suit == HeartsSuit {
 					return errors.New("can't play hearts on the first trick")
 				}
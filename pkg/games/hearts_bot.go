@@ -0,0 +1,379 @@
+package games
+
+import (
+	"errors"
+	"sort"
+)
+
+// HeartsBot is a computer opponent for Hearts. It follows a handful of
+// well-known heuristics: pass dangerous cards, duck under the winning card
+// when it can, and dump points when it is void in the lead suit. It reads
+// the configured scoring variants to decide which cards are dangerous.
+type HeartsBot struct{}
+
+func (heartsEngine) NewBot() GameBot {
+	return HeartsBot{}
+}
+
+func (hb HeartsBot) NextMove(game *GameData, player *PlayerData) (interface{}, error) {
+	var state *HeartsState = game.State.(*HeartsState)
+	if state == nil || !state.Started || state.Finished {
+		return nil, nil
+	}
+
+	var index = player.Index
+	if index < 0 || index >= len(state.Players) {
+		return nil, nil
+	}
+
+	if !state.Dealt {
+		if state.Dealer != index {
+			return nil, nil
+		}
+
+		return botHeader(game, player, "deal"), nil
+	}
+
+	if !state.Passed {
+		if state.Players[index].Passed {
+			return nil, nil
+		}
+
+		var msg HeartsPassMsg
+		msg.MessageHeader = botHeader(game, player, "pass")
+		msg.ToPass = hb.choosePass(state, index)
+		return msg, nil
+	}
+
+	if state.Turn != index {
+		return nil, nil
+	}
+
+	card, err := hb.choosePlay(state, index)
+	if err != nil {
+		return nil, err
+	}
+
+	var msg HeartsPlayMsg
+	msg.MessageHeader = botHeader(game, player, "play")
+	msg.CardID = card.ID
+	return msg, nil
+}
+
+// Number of points taking this card is worth under the game's configuration.
+// Negative values are cards worth winning.
+func heartsCardPoints(config HeartsConfig, card Card) int {
+	if card.Suit == HeartsSuit {
+		if card.Rank == AceRank && config.AceOfHearts {
+			return 5
+		}
+
+		return 1
+	}
+
+	if card.Suit == SpadesSuit && card.Rank == QueenRank {
+		if config.BlackWidowForFive {
+			return 5
+		}
+
+		return 13
+	}
+
+	if card.Suit == DiamondsSuit && card.Rank == JackRank && config.JackOfDiamonds {
+		return -11
+	}
+
+	return 0
+}
+
+// Ace is high in Hearts; jokers never win.
+func heartsRankValue(card Card) int {
+	if card.Rank == AceRank {
+		return int(KingRank) + 1
+	}
+
+	if card.Rank == JokerRank {
+		return 0
+	}
+
+	return int(card.Rank)
+}
+
+// Whether the current trick is the first of the round, mirroring the checks
+// in PlayCard.
+func (hs *HeartsState) isFirstTrick() bool {
+	history := hs.RoundHistory[len(hs.RoundHistory)-1]
+	if hs.Turn == hs.Leader {
+		trick_index := len(history.Tricks)
+		return trick_index == 0 || (trick_index == 1 && len(history.Tricks[0].Played) == 0)
+	}
+
+	return len(history.Tricks) == 1
+}
+
+// Compute the set of cards the given player could legally play right now.
+func (hs *HeartsState) LegalPlays(player int) []Card {
+	var hand = hs.Players[player].Hand
+	var ret []Card
+
+	if hs.Turn == hs.Leader {
+		if hs.isFirstTrick() {
+			var target = TwoRank
+			if hs.Config.NumPlayers == 6 && !hs.Config.WithCrib {
+				target = ThreeRank
+			}
+
+			for _, card := range hand {
+				if card.Suit == ClubsSuit && card.Rank == target {
+					return []Card{card}
+				}
+			}
+
+			return nil
+		}
+
+		var only_hearts = true
+		for _, card := range hand {
+			if card.Suit != HeartsSuit && card.Rank != JokerRank {
+				only_hearts = false
+			}
+		}
+
+		for _, card := range hand {
+			if card.Suit == HeartsSuit && !hs.HeartsBroken && hs.Config.MustBreakHearts && !only_hearts {
+				continue
+			}
+
+			ret = append(ret, card)
+		}
+
+		return ret
+	}
+
+	var lead_suit = hs.Played[0].Suit
+	for _, card := range hand {
+		if card.Suit == lead_suit {
+			ret = append(ret, card)
+		}
+	}
+
+	if len(ret) == 0 {
+		ret = CopyHand(hand)
+	}
+
+	if hs.isFirstTrick() && !hs.Config.FirstTrickHearts && !onlyFirstTrickPoints(ret) {
+		var filtered []Card
+		for _, card := range ret {
+			if card.Suit == HeartsSuit || (card.Suit == SpadesSuit && card.Rank == QueenRank) {
+				continue
+			}
+
+			filtered = append(filtered, card)
+		}
+
+		ret = filtered
+	}
+
+	return ret
+}
+
+// Pick the cards to pass: the highest-risk cards in the hand.
+func (hb HeartsBot) choosePass(state *HeartsState, player int) []int {
+	var hand = CopyHand(state.Players[player].Hand)
+	var suit_count = make(map[CardSuit]int)
+	var low_spades = 0
+	for _, card := range hand {
+		suit_count[card.Suit]++
+		if card.Suit == SpadesSuit && heartsRankValue(card) < int(QueenRank) {
+			low_spades++
+		}
+	}
+
+	var danger = func(card Card) int {
+		var value = heartsRankValue(card)
+		var points = heartsCardPoints(state.Config, card)
+
+		if points < 0 {
+			// Worth keeping to win later.
+			return -100
+		}
+
+		if card.Suit == SpadesSuit && heartsRankValue(card) >= int(QueenRank) {
+			// High spades are only dangerous without enough cover.
+			if low_spades >= 4 {
+				return value
+			}
+
+			return 50 + points + value
+		}
+
+		if card.Suit == HeartsSuit {
+			return 2*value + points
+		}
+
+		if card.Suit == ClubsSuit && card.Rank == TenRank && state.Config.TenOfClubs {
+			return 40
+		}
+
+		// Prefer passing from short suits, so we can void them and sluff.
+		return value + 2*(13-suit_count[card.Suit])/3
+	}
+
+	sort.SliceStable(hand, func(i, j int) bool {
+		return danger(hand[i]) > danger(hand[j])
+	})
+
+	var ret []int
+	for i := 0; i < state.Config.NumberToPass && i < len(hand); i++ {
+		ret = append(ret, hand[i].ID)
+	}
+
+	return ret
+}
+
+func (hb HeartsBot) choosePlay(state *HeartsState, player int) (Card, error) {
+	var legal = state.LegalPlays(player)
+	if len(legal) == 0 {
+		return Card{}, errors.New("no legal plays available")
+	}
+
+	if len(legal) == 1 {
+		return legal[0], nil
+	}
+
+	// Lowest first; ties are broken arbitrarily but stably.
+	sort.SliceStable(legal, func(i, j int) bool {
+		return heartsRankValue(legal[i]) < heartsRankValue(legal[j])
+	})
+
+	if state.Turn == state.Leader {
+		return hb.chooseLead(state, player, legal), nil
+	}
+
+	var lead_suit = state.Played[0].Suit
+	var winning = state.Played[0]
+	var points = 0
+	for _, card := range state.Played {
+		points += heartsCardPoints(state.Config, card)
+		if card.Suit == lead_suit && heartsRankValue(card) > heartsRankValue(winning) {
+			winning = card
+		}
+	}
+
+	var last_to_play = len(state.Played) == len(state.Players)-1
+
+	if !hb.hasSuit(legal, lead_suit) {
+		// Void in the lead suit: dump the most dangerous card we have.
+		return hb.chooseSluff(state, legal), nil
+	}
+
+	// Following suit. If the trick is worth taking (or harmless when we're
+	// the last to play), win it with our highest safe card; this clears
+	// high cards out of our hand while it is cheap to do so.
+	var wants_trick = points < 0 || (last_to_play && points == 0)
+	if wants_trick {
+		for index := len(legal) - 1; index >= 0; index-- {
+			card := legal[index]
+			if heartsCardPoints(state.Config, card) > 0 {
+				continue
+			}
+
+			if heartsRankValue(card) > heartsRankValue(winning) {
+				return card, nil
+			}
+		}
+	}
+
+	// Otherwise duck: play the highest card that still loses.
+	for index := len(legal) - 1; index >= 0; index-- {
+		if heartsRankValue(legal[index]) < heartsRankValue(winning) {
+			return legal[index], nil
+		}
+	}
+
+	// Can't duck. If we're last, we take the trick anyways, so get rid of our
+	// highest safe card. Otherwise, play low and hope someone overtakes.
+	if last_to_play {
+		for index := len(legal) - 1; index >= 0; index-- {
+			if heartsCardPoints(state.Config, legal[index]) <= 0 {
+				return legal[index], nil
+			}
+		}
+	}
+
+	return legal[0], nil
+}
+
+func (hb HeartsBot) hasSuit(cards []Card, suit CardSuit) bool {
+	for _, card := range cards {
+		if card.Suit == suit {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (hb HeartsBot) chooseLead(state *HeartsState, player int, legal []Card) Card {
+	var hand = state.Players[player].Hand
+	var have_high_spade = false
+	for _, card := range hand {
+		if card.Suit == SpadesSuit && heartsRankValue(card) >= int(QueenRank) {
+			have_high_spade = true
+		}
+	}
+
+	var best = legal[0]
+	var best_score = 1 << 30
+	for _, card := range legal {
+		var score = heartsRankValue(card)
+		if card.Suit == HeartsSuit {
+			score += 10
+		}
+
+		if card.Suit == SpadesSuit && have_high_spade {
+			// Don't lead spades while holding the Queen or something that
+			// would catch it.
+			score += 20
+		}
+
+		if heartsCardPoints(state.Config, card) != 0 {
+			score += 30
+		}
+
+		if score < best_score {
+			best = card
+			best_score = score
+		}
+	}
+
+	return best
+}
+
+func (hb HeartsBot) chooseSluff(state *HeartsState, legal []Card) Card {
+	var best = legal[0]
+	var best_score = -1 << 30
+	for _, card := range legal {
+		var points = heartsCardPoints(state.Config, card)
+		var score = heartsRankValue(card)
+
+		if points < 0 {
+			// Never give away something worth winning.
+			score = -100
+		} else if points > 0 {
+			score += 10 * points
+		} else if card.Suit == SpadesSuit && heartsRankValue(card) > int(QueenRank) {
+			// Dangerous when the Queen is still out.
+			score += 40
+		} else if card.Suit == ClubsSuit && card.Rank == TenRank && state.Config.TenOfClubs {
+			score += 40
+		}
+
+		if score > best_score {
+			best = card
+			best_score = score
+		}
+	}
+
+	return best
+}
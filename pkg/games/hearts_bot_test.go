@@ -0,0 +1,102 @@
+package games
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// Create a game owned by a spectating owner with the specified number of
+// bots playing it.
+func newBotGame(t *testing.T, c *Controller, mode string, config interface{}, bots int) *GameData {
+	var owner uint64 = 1
+
+	var gamemode = GameModeFromString(mode)
+	var cfg = gamemode.EmptyConfig()
+	data, err := json.Marshal(config)
+	if err != nil {
+		t.Fatal("Unable to marshal config:", err)
+	}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		t.Fatal("Unable to unmarshal config:", err)
+	}
+
+	if err := c.addGame(mode, 1, owner, cfg); err != nil {
+		t.Fatal("Unable to add game:", err)
+	}
+
+	var game = c.ToGame[1]
+	game.ToPlayer[owner] = &PlayerData{UID: owner, Index: -1, Admitted: true, OutboundID: 1}
+
+	for i := 0; i < bots; i++ {
+		var msg GameAddBot
+		msg.MessageHeader = MessageHeader{Mode: mode, ID: 1, Player: owner, MessageType: "add-bot"}
+		data, _ := json.Marshal(msg)
+		if err := c.handleAddBot(data, game, game.ToPlayer[owner]); err != nil {
+			t.Fatal("Unable to add bot:", err)
+		}
+	}
+
+	return game
+}
+
+// Run the bots until the game finishes.
+func playBotGame(t *testing.T, c *Controller, game *GameData) {
+	for round := 0; round < 200 && !game.State.IsFinished(); round++ {
+		c.runBots(game)
+	}
+
+	if !game.State.IsFinished() {
+		t.Fatal("Expected bots to finish the game")
+	}
+}
+
+func TestHeartsBots(t *testing.T) {
+	for _, config := range []HeartsConfig{
+		{NumPlayers: 4, NumberToPass: 3, MustBreakHearts: true, WinAmount: 100, ShootTheSun: true},
+		{NumPlayers: 3, NumberToPass: 3, HoldRound: true, WithCrib: true, WinAmount: 50, JackOfDiamonds: true, TenOfClubs: true},
+		{NumPlayers: 6, NumberToPass: 2, FirstTrickHearts: true, BlackWidowBreaks: true, WinAmount: 50, BlackWidowForFive: true},
+	} {
+		var c Controller
+		c.Init()
+
+		game := newBotGame(t, &c, "hearts", config, config.NumPlayers)
+		if err := game.State.(*HeartsState).Init(config); err != nil {
+			t.Fatal("Unable to initialize hearts:", err)
+		}
+
+		if err := (heartsEngine{}).Start(&c, game); err != nil {
+			t.Fatal("Unable to start hearts:", err)
+		}
+
+		playBotGame(t, &c, game)
+	}
+}
+
+func TestHeartsFirstTrickOnlyPoints(t *testing.T) {
+	var c Controller
+	c.Init()
+
+	var _, state = newClockedHeartsGame(t, &c, HeartsConfig{NumPlayers: 4, NumberToPass: 3, WinAmount: 100})
+	var leader = state.Turn
+	for _, card := range state.Players[leader].Hand {
+		if card.Suit == ClubsSuit && card.Rank == TwoRank {
+			if err := state.PlayCard(leader, card.ID); err != nil {
+				t.Fatal("Unable to lead the two of clubs:", err)
+			}
+		}
+	}
+
+	// Someone without any clubs who holds nothing but points still has to
+	// be able to play.
+	var player = state.Turn
+	state.Players[player].Hand = []Card{{100, HeartsSuit, FiveRank}, {101, SpadesSuit, QueenRank}}
+
+	var legal = state.LegalPlays(player)
+	if len(legal) != 2 {
+		t.Fatal("Expected every card to be playable:", legal)
+	}
+
+	if err := state.PlayCard(player, 100); err != nil {
+		t.Fatal("Unable to play a heart on the first trick:", err)
+	}
+}
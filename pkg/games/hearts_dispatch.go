@@ -12,6 +12,8 @@ import (
 // 1. Deal
 // 2. PassCards
 // 3. PlayCard
+// 4. UndoRequest / UndoResponse (taking back the last card played)
+// 5. Timeout (sent by the server when a player runs out of time)
 
 type HeartsPassMsg struct {
 	MessageHeader
@@ -106,8 +108,8 @@ func (c *Controller) dispatchHearts(message []byte, header MessageHeader, game *
 		}
 
 		err = state.StartRound()
-		send_synopsis = err != nil
-		send_state = err != nil
+		send_synopsis = err == nil
+		send_state = err == nil
 	case "pass":
 		var data HeartsPassMsg
 		if err = json.Unmarshal(message, &data); err != nil {
@@ -126,6 +128,25 @@ func (c *Controller) dispatchHearts(message []byte, header MessageHeader, game *
 		err = state.PlayCard(player.Index, data.CardID)
 		send_synopsis = true
 		send_state = true
+	case "undo-request":
+		var undone bool
+		undone, err = c.handleUndoRequest(game, player)
+		send_synopsis = undone
+		send_state = undone
+	case "undo-response":
+		var undone bool
+		undone, err = c.handleUndoResponse(message, game, player)
+		send_synopsis = undone
+		send_state = undone
+	case "timeout":
+		var data GameTimeout
+		if err = json.Unmarshal(message, &data); err != nil {
+			return err
+		}
+
+		err = state.Timeout(player.Index, data.Policy)
+		send_synopsis = true
+		send_state = true
 	case "peek":
 		if player.Index != -1 && !state.Finished {
 			return errors.New("can only peek once game is complete")
@@ -205,13 +226,7 @@ func (c *Controller) doHeartsStart(game *GameData, state *HeartsState) error {
 	}
 
 	// Assign indices to players before sending notifications.
-	var player_index int = 0
-	for _, indexed_player := range game.ToPlayer {
-		if indexed_player.Admitted && indexed_player.Playing {
-			indexed_player.Index = player_index
-			player_index++
-		}
-	}
+	seatPlayers(game)
 
 	// Send out initial state data to individuals who are playing. Also notify
 	// all players that the game has started.
@@ -242,3 +257,48 @@ func (c *Controller) doHeartsStart(game *GameData, state *HeartsState) error {
 
 	return nil
 }
+
+// heartsEngine registers Hearts with the controller; see GameEngine.
+type heartsEngine struct{}
+
+func init() {
+	MustRegisterGameEngine(heartsEngine{})
+}
+
+func (heartsEngine) Mode() GameMode {
+	return HeartsGame
+}
+
+func (heartsEngine) Name() string {
+	return "hearts"
+}
+
+func (heartsEngine) Title() string {
+	return "Hearts (Card Game)"
+}
+
+func (heartsEngine) Description() string {
+	return "In Hearts, players pass cards and avoid taking tricks with Hearts or the Queen of Spades. Be careful though: let someone get all the points and they'll shoot the moon!"
+}
+
+func (heartsEngine) EmptyConfig() figgy.Figgurable {
+	return &HeartsConfig{}
+}
+
+func (heartsEngine) NewState() ConfigurableState {
+	return &HeartsState{}
+}
+
+func (heartsEngine) Init(config figgy.Figgurable) (ConfigurableState, error) {
+	var asserted *HeartsConfig = config.(*HeartsConfig)
+	var state = &HeartsState{}
+	return state, state.Init(*asserted)
+}
+
+func (heartsEngine) Dispatch(c *Controller, message []byte, header MessageHeader, game *GameData, player *PlayerData, sid uint64) error {
+	return c.dispatchHearts(message, header, game, player, sid)
+}
+
+func (heartsEngine) Start(c *Controller, game *GameData) error {
+	return c.doHeartsStart(game, game.State.(*HeartsState))
+}
@@ -26,8 +26,9 @@ type HeartsGameState struct {
 	HeartsBroken  bool                `json:"hearts_broken"`
 	History       [][]Card            `json:"history"`
 
-	Crib   []Card       `json:"crib"`
-	Config HeartsConfig `json:"config"`
+	Crib       []Card       `json:"crib"`
+	Commitment string       `json:"commitment"` // To the current round's shuffle seed; see ShuffleSeed.
+	Config     HeartsConfig `json:"config"`
 
 	Started  bool `json:"started"`
 	Dealt    bool `json:"dealt"`
@@ -84,6 +85,7 @@ func (hsn *HeartsStateNotification) LoadData(data *GameData, game *HeartsState,
 		}
 	}
 
+	hsn.Commitment = game.Deck.Commitment()
 	hsn.Config = game.Config
 
 	hsn.Started = game.Started
@@ -0,0 +1,613 @@
+package games
+
+import (
+	"errors"
+	"log"
+	"strconv"
+
+	"git.cipherboy.com/WillowPatchGames/wpg/pkg/middleware/figgy"
+)
+
+const OhHellGameOver string = "game is over"
+const OhHellNextRound string = "begin next round"
+
+// Oh Hell round schedules.
+const (
+	OhHellUpAndDown int = iota // 0
+	OhHellDownOnly  int = iota // 1
+)
+
+// Oh Hell scoring methods.
+const (
+	OhHellClassicScoring int = iota // 0
+	OhHellWizardScoring  int = iota // 1
+)
+
+type OhHellPlayer struct {
+	Hand []Card `json:"hand"`
+
+	Bid        int `json:"bid"` // -1 until this player has bid.
+	Tricks     int `json:"tricks"`
+	RoundScore int `json:"round_score"`
+	Score      int `json:"score"`
+}
+
+func (op *OhHellPlayer) Init() {
+	op.Hand = make([]Card, 0)
+	op.Bid = -1
+}
+
+func (op *OhHellPlayer) FindCard(cardID int) (int, bool) {
+	return FindCard(op.Hand, cardID)
+}
+
+func (op *OhHellPlayer) RemoveCard(cardID int) bool {
+	var ret bool
+	_, op.Hand, ret = RemoveCard(op.Hand, cardID)
+	return ret
+}
+
+type OhHellConfig struct {
+	NumPlayers int `json:"num_players" config:"type:int,min:3,default:4,max:7" label:"Number of players"` // Best with four or five.
+
+	Schedule    int  `json:"schedule" config:"type:enum,default:0,options:0:Up and down (one card up to the most and back);1:Down only (the most cards down to one)" label:"Round schedule"` // How the hand size changes each round.
+	MaxHandSize int  `json:"max_hand_size" config:"type:int,min:1,default:10,max:20" label:"Most cards in a hand"`                                                                           // Capped by what the deck allows.
+	TrumpReveal bool `json:"trump_reveal" config:"type:bool,default:true" label:"true:Turn up a card after dealing to decide trump,false:Play without trump"`                                // Whether trump is decided by turning up the next card.
+	Wizards     bool `json:"wizards" config:"type:bool,default:false" label:"true:Add four Wizards and four Jesters,false:Play with a standard deck"`                                        // Wizards always win; Jesters always lose.
+	HookRule    bool `json:"hook_rule" config:"type:bool,default:true" label:"true:Dealer can't make the bids add up to the number of tricks (hook),false:Dealer can bid anything"`          // Ensure someone misses their bid each round.
+
+	// Scoring
+	Scoring int `json:"scoring" config:"type:enum,default:0,options:0:10 points plus 1 per trick for exact bids;1:20 points plus 10 per trick for exact bids and lose 10 per trick off" label:"Scoring method"`
+
+	// Common game configuration options
+	Countdown bool `json:"countdown" config:"type:bool,default:true" label:"true:Show a 3... 2... 1... countdown before beginning,false:Start the game instantly"` // Whether to wait and send countdown messages.
+
+	// Chess clock style limits on how long each player may take.
+	TurnClock   int `json:"turn_clock" config:"type:int,min:0,default:0,max:600" label:"Seconds per turn (0 for no limit)"`
+	GameClock   int `json:"game_clock" config:"type:int,min:0,default:0,max:120" label:"Minutes per player for the whole game (0 for no limit)"`
+	ClockPolicy int `json:"clock_policy" config:"type:enum,default:0,options:0:Play automatically;2:Forfeit the game" label:"When a player runs out of time"` // See ClockPolicy.
+}
+
+func (cfg OhHellConfig) Validate() error {
+	return nil
+}
+
+type OhHellTrick struct {
+	Leader int    `json:"leader"`
+	Played []Card `json:"played"`
+	Winner int    `json:"winner"`
+}
+
+type OhHellRoundPlayer struct {
+	Hand []Card `json:"hand"`
+
+	Bid        int `json:"bid"`
+	Tricks     int `json:"tricks"`
+	RoundScore int `json:"round_score"`
+	Score      int `json:"score"`
+}
+
+type OhHellRound struct {
+	Dealer   int          `json:"dealer"`
+	Deck     []*Card      `json:"deck"`
+	Seed     *ShuffleSeed `json:"seed"`
+	HandSize int          `json:"hand_size"`
+	TurnedUp *Card        `json:"turned_up"`
+	Trump    CardSuit     `json:"trump"`
+
+	Players []OhHellRoundPlayer `json:"players"`
+	Tricks  []OhHellTrick       `json:"tricks"`
+}
+
+type OhHellState struct {
+	Turn   int `json:"turn"`
+	Leader int `json:"leader"`
+	Dealer int `json:"dealer"`
+
+	Deck     Deck           `json:"deck"`
+	Players  []OhHellPlayer `json:"players"`  // Left of dealer is found by incrementing one.
+	Schedule []int          `json:"schedule"` // Hand size of each round.
+	Round    int            `json:"round"`    // Index into Schedule.
+	TurnedUp *Card          `json:"turned_up"`
+	Trump    CardSuit       `json:"trump"` // NoneSuit when playing without trump.
+
+	Played         []Card         `json:"played"`          // Currently played cards in this trick, starting with the leader.
+	PreviousTricks [][]Card       `json:"previous_tricks"` // Contents of previous tricks in the current round; sent to clients.
+	RoundHistory   []*OhHellRound `json:"round_history"`   // Contents of previous rounds for analysis.
+	Seed           *ShuffleSeed   `json:"seed,omitempty"`  // Secret each round's shuffle seed derives from; when unset, every round gets a fresh seed.
+
+	Config OhHellConfig `json:"config"`
+
+	Started  bool  `json:"started"`
+	Dealt    bool  `json:"dealt"`
+	Bid      bool  `json:"bid"`
+	Finished bool  `json:"finished"`
+	Winners  []int `json:"winners"`
+}
+
+func (ohs *OhHellState) Init(cfg OhHellConfig) error {
+	var err error = figgy.Validate(cfg)
+	if err != nil {
+		log.Println("Error with OhHellConfig", err)
+		return err
+	}
+
+	ohs.Config = cfg
+	ohs.Turn = -1
+	ohs.Dealer = -1
+	ohs.Started = false
+	ohs.Finished = false
+	ohs.Winners = make([]int, 0)
+
+	return nil
+}
+
+func (ohs *OhHellState) GetConfiguration() figgy.Figgurable {
+	return ohs.Config
+}
+
+func (ohs *OhHellState) ReInit() error {
+	// No-op for now. Nothing needs to be re-initialized after reloading
+	// from JSON serialization.
+	return nil
+}
+
+func (ohs *OhHellState) IsStarted() bool {
+	return ohs.Started
+}
+
+func (ohs *OhHellState) IsFinished() bool {
+	return ohs.Finished
+}
+
+func (ohs *OhHellState) SetShuffleSeed(seed *ShuffleSeed) error {
+	if ohs.Started {
+		return errors.New("unable to set the shuffle seed after the game has started")
+	}
+
+	ohs.Seed = seed
+	return nil
+}
+
+func (ohs *OhHellState) RevealedSeeds() []*ShuffleSeed {
+	var seeds = make([]*ShuffleSeed, 0, len(ohs.RoundHistory))
+	for _, round := range ohs.RoundHistory {
+		seeds = append(seeds, round.Seed)
+	}
+
+	return revealedRoundSeeds(seeds, ohs.Dealt && !ohs.Finished)
+}
+
+func (ohs *OhHellState) ResetStatus() {
+	ohs.Started = false
+	ohs.Finished = false
+}
+
+func (ohs *OhHellState) buildDeck() {
+	ohs.Deck.Init()
+	ohs.Deck.AddStandard52Deck()
+	if ohs.Config.Wizards {
+		ohs.Deck.AddJokers(4, true)
+		ohs.Deck.AddJokers(4, false)
+	}
+}
+
+// Hand sizes for each round. The largest hand is limited both by the config
+// and by the deck, leaving a card to turn up for trump when needed.
+func (ohs *OhHellState) buildSchedule() {
+	ohs.buildDeck()
+
+	var available = len(ohs.Deck.Cards)
+	if ohs.Config.TrumpReveal {
+		available -= 1
+	}
+
+	var most = available / len(ohs.Players)
+	if most > ohs.Config.MaxHandSize {
+		most = ohs.Config.MaxHandSize
+	}
+
+	ohs.Schedule = make([]int, 0)
+	if ohs.Config.Schedule == OhHellUpAndDown {
+		for size := 1; size < most; size++ {
+			ohs.Schedule = append(ohs.Schedule, size)
+		}
+	}
+
+	for size := most; size >= 1; size-- {
+		ohs.Schedule = append(ohs.Schedule, size)
+	}
+}
+
+func OhHellIsWizard(card Card) bool {
+	return card.Rank == JokerRank && card.Suit == FancySuit
+}
+
+func OhHellIsJester(card Card) bool {
+	return card.Rank == JokerRank && card.Suit != FancySuit
+}
+
+func (ohs *OhHellState) TurnClocks() TurnClocks {
+	return newTurnClocks(ohs.Config.TurnClock, ohs.Config.GameClock, ohs.Config.ClockPolicy)
+}
+
+func (ohs *OhHellState) ClockedPlayer() int {
+	// Only playing cards is clocked, not bidding.
+	if !ohs.Started || ohs.Finished || !ohs.Dealt || !ohs.Bid {
+		return -1
+	}
+
+	return ohs.Turn
+}
+
+func (ohs *OhHellState) Timeout(player int, policy ClockPolicy) error {
+	if player != ohs.ClockedPlayer() {
+		return errors.New("not your turn")
+	}
+
+	if policy == ForfeitOnTimeout {
+		return ohs.forfeit(player)
+	}
+
+	return playLowestCard(ohs.Players[player].Hand, true, func(card int) error {
+		return ohs.PlayCard(player, card)
+	})
+}
+
+// End the game early, with the highest score of the other players winning.
+func (ohs *OhHellState) forfeit(player int) error {
+	var max_score = 0
+	var found = false
+	for index, indexed_player := range ohs.Players {
+		if index != player && (!found || indexed_player.Score > max_score) {
+			max_score = indexed_player.Score
+			found = true
+		}
+	}
+
+	ohs.Winners = make([]int, 0)
+	for index, indexed_player := range ohs.Players {
+		if index != player && indexed_player.Score == max_score {
+			ohs.Winners = append(ohs.Winners, index)
+		}
+	}
+
+	ohs.Finished = true
+	ohs.Turn = -1
+	ohs.Dealer = -1
+	return errors.New(OhHellGameOver)
+}
+
+func (ohs *OhHellState) Start(players int) error {
+	var err error
+
+	if ohs.Started {
+		log.Println("Error! Double start occurred...", err)
+		return errors.New("double start occurred")
+	}
+
+	ohs.Config.NumPlayers = players
+	err = figgy.Validate(ohs.Config)
+	if err != nil {
+		log.Println("Err with OhHellConfig after starting: ", err)
+		return err
+	}
+
+	// Create all of the players.
+	ohs.Players = make([]OhHellPlayer, ohs.Config.NumPlayers)
+	for index := range ohs.Players {
+		ohs.Players[index].Init()
+	}
+
+	ohs.buildSchedule()
+	ohs.Round = 0
+
+	// Force us to call StartRound() next.
+	ohs.Dealt = false
+	ohs.Dealer = 0
+
+	// Start the round: shuffle the cards and deal them out.
+	err = ohs.StartRound()
+	if err != nil {
+		log.Println("Error starting round: ", err)
+		return err
+	}
+
+	ohs.Started = true
+	return nil
+}
+
+func (ohs *OhHellState) StartRound() error {
+	if ohs.Dealt {
+		return errors.New("unable to call StartRound while we've already dealt")
+	}
+
+	var hand_size = ohs.Schedule[ohs.Round]
+
+	// Start building history of moves.
+	ohs.RoundHistory = append(ohs.RoundHistory, &OhHellRound{})
+	history := ohs.RoundHistory[len(ohs.RoundHistory)-1]
+	history.Dealer = ohs.Dealer
+	history.HandSize = hand_size
+	history.Players = make([]OhHellRoundPlayer, len(ohs.Players))
+	history.Tricks = make([]OhHellTrick, 0)
+
+	// Start with a clean deck and shuffle it.
+	ohs.buildDeck()
+	ohs.Deck.Reseed(ohs.Seed, len(ohs.RoundHistory))
+	ohs.Deck.Shuffle()
+
+	// Save the initial deck.
+	history.Deck = CopyDeck(ohs.Deck.Cards)
+	history.Seed = ohs.Deck.Seed.Copy()
+
+	// Clear out all round-specific status before each round.
+	for index := range ohs.Players {
+		ohs.Players[index].Hand = make([]Card, 0)
+		ohs.Players[index].Bid = -1
+		ohs.Players[index].Tricks = 0
+		ohs.Players[index].RoundScore = 0
+	}
+
+	// Deal out the cards for this round, starting left of the dealer.
+	for round := 0; round < hand_size; round++ {
+		for player_offset := 1; player_offset <= len(ohs.Players); player_offset++ {
+			player_index := (ohs.Dealer + player_offset) % len(ohs.Players)
+			ohs.Players[player_index].Hand = append(ohs.Players[player_index].Hand, *ohs.Deck.Draw())
+		}
+	}
+
+	for index, indexed_player := range ohs.Players {
+		history.Players[index].Hand = CopyHand(indexed_player.Hand)
+	}
+
+	// Turn up the next card for trump. A Wizard or Jester turned up means
+	// there is no trump this round.
+	ohs.TurnedUp = nil
+	ohs.Trump = NoneSuit
+	if ohs.Config.TrumpReveal {
+		ohs.TurnedUp = ohs.Deck.Draw()
+		if ohs.TurnedUp.Rank != JokerRank {
+			ohs.Trump = ohs.TurnedUp.Suit
+		}
+
+		history.TurnedUp = ohs.TurnedUp.Copy()
+	}
+	history.Trump = ohs.Trump
+
+	ohs.Played = make([]Card, 0)
+	ohs.PreviousTricks = make([][]Card, 0)
+
+	// Bidding (and play) starts left of the dealer.
+	ohs.Turn = (ohs.Dealer + 1) % len(ohs.Players)
+	ohs.Leader = ohs.Turn
+	ohs.Dealt = true
+	ohs.Bid = false
+
+	return nil
+}
+
+func (ohs *OhHellState) PlaceBid(player int, bid int) error {
+	if err := (bidTurn{ohs.Started, ohs.Finished, ohs.Dealt, ohs.Bid, ohs.Turn, len(ohs.Players)}).check(player); err != nil {
+		return err
+	}
+
+	var hand_size = ohs.Schedule[ohs.Round]
+	if bid < 0 || bid > hand_size {
+		return errors.New("bid must be between zero and the number of cards in hand")
+	}
+
+	// The hook: the dealer bids last and can't make the total bid equal the
+	// number of tricks, so that somebody has to miss.
+	if player == ohs.Dealer && ohs.Config.HookRule {
+		var total = bid
+		for index, indexed_player := range ohs.Players {
+			if index != player {
+				total += indexed_player.Bid
+			}
+		}
+
+		if total == hand_size {
+			return errors.New("dealer can't bid so the bids add up to the number of tricks")
+		}
+	}
+
+	history := ohs.RoundHistory[len(ohs.RoundHistory)-1]
+
+	// Record the bid.
+	ohs.Players[player].Bid = bid
+	history.Players[player].Bid = bid
+
+	// Dealer bids last.
+	if ohs.Turn == ohs.Dealer {
+		ohs.Bid = true
+	}
+
+	ohs.Turn = (ohs.Turn + 1) % len(ohs.Players)
+	return nil
+}
+
+// The suit others must follow in this trick: the first card other than a
+// Jester. Leading a Wizard means there is nothing to follow.
+func (ohs *OhHellState) leadSuit() CardSuit {
+	for _, card := range ohs.Played {
+		if OhHellIsWizard(card) {
+			return NoneSuit
+		}
+
+		if !OhHellIsJester(card) {
+			return card.Suit
+		}
+	}
+
+	return NoneSuit
+}
+
+func (ohs *OhHellState) PlayCard(player int, cardID int) error {
+	if !ohs.Started {
+		return errors.New("game hasn't started yet")
+	}
+
+	if ohs.Finished {
+		return errors.New("game has already finished")
+	}
+
+	if player < 0 || player >= len(ohs.Players) {
+		return errors.New("not a valid player identifier: " + strconv.Itoa(player))
+	}
+
+	if ohs.Turn != player {
+		return errors.New("not your turn")
+	}
+
+	if !ohs.Dealt {
+		return errors.New("unable to play a card before dealing cards")
+	}
+
+	if !ohs.Bid {
+		return errors.New("unable to play before bidding")
+	}
+
+	index, found := ohs.Players[player].FindCard(cardID)
+	if !found {
+		return errors.New("unable to play card not in hand")
+	}
+
+	var this_trick *OhHellTrick = nil
+	played := ohs.Players[player].Hand[index]
+	history := ohs.RoundHistory[len(ohs.RoundHistory)-1]
+
+	if ohs.Turn == ohs.Leader {
+		ohs.Played = make([]Card, 0)
+
+		trick_index := len(history.Tricks)
+		history.Tricks = append(history.Tricks, OhHellTrick{Leader: ohs.Leader, Winner: -1})
+		this_trick = &history.Tricks[trick_index]
+	} else {
+		this_trick = &history.Tricks[len(history.Tricks)-1]
+
+		// Wizards and Jesters may always be played; otherwise, follow the lead
+		// suit if we can.
+		lead_suit := ohs.leadSuit()
+		if played.Rank != JokerRank && lead_suit != NoneSuit && played.Suit != lead_suit {
+			for _, card := range ohs.Players[player].Hand {
+				if card.Rank != JokerRank && card.Suit == lead_suit {
+					return errors.New("must follow the lead suit")
+				}
+			}
+		}
+	}
+
+	ohs.Players[player].RemoveCard(cardID)
+	ohs.Played = append(ohs.Played, played)
+	this_trick.Played = append(this_trick.Played, played)
+
+	ohs.Turn = (ohs.Turn + 1) % len(ohs.Players)
+	if ohs.Turn == ohs.Leader {
+		// Got back to the player who started this trick. Determine a winner and
+		// exit.
+		return ohs.determineTrickWinner()
+	}
+
+	return nil
+}
+
+// The offset of the winning card in the trick: the first Wizard, else the
+// highest trump, else the highest card of the lead suit (set by the first card
+// other than a Jester). If everyone plays a Jester, the first one wins.
+func (ohs *OhHellState) trickWinner(played []Card) int {
+	var winner_offset = 0
+	var winning_card = played[0]
+
+	for offset := 1; offset < len(played); offset++ {
+		this_card := played[offset]
+		if OhHellIsWizard(winning_card) || OhHellIsJester(this_card) {
+			continue
+		}
+
+		if OhHellIsWizard(this_card) || OhHellIsJester(winning_card) || beatsInTrick(this_card, winning_card, ohs.Trump) {
+			winner_offset = offset
+			winning_card = this_card
+		}
+	}
+
+	return winner_offset
+}
+
+func (ohs *OhHellState) determineTrickWinner() error {
+	history := ohs.RoundHistory[len(ohs.RoundHistory)-1]
+	this_trick := &history.Tricks[len(history.Tricks)-1]
+
+	winner_offset := ohs.trickWinner(ohs.Played)
+
+	absolute_winner := (ohs.Leader + winner_offset) % len(ohs.Players)
+	ohs.Players[absolute_winner].Tricks += 1
+	ohs.Leader = absolute_winner
+	ohs.Turn = absolute_winner
+	ohs.PreviousTricks = append(ohs.PreviousTricks, ohs.Played)
+	this_trick.Winner = absolute_winner
+
+	if len(ohs.Players[0].Hand) == 0 {
+		// Can't play again in this round. Tabulate the round score and maybe try
+		// to play another round.
+		return ohs.tabulateRoundScore()
+	}
+
+	return nil
+}
+
+func (ohs *OhHellState) tabulateRoundScore() error {
+	history := ohs.RoundHistory[len(ohs.RoundHistory)-1]
+
+	// Points only go to players who take exactly as many tricks as they bid.
+	for index := range ohs.Players {
+		var player = &ohs.Players[index]
+		var off = player.Tricks - player.Bid
+		if off < 0 {
+			off = -off
+		}
+
+		if off == 0 && ohs.Config.Scoring == OhHellWizardScoring {
+			player.RoundScore = 20 + 10*player.Tricks
+		} else if off == 0 {
+			player.RoundScore = 10 + player.Tricks
+		} else if ohs.Config.Scoring == OhHellWizardScoring {
+			player.RoundScore = -10 * off
+		} else {
+			player.RoundScore = 0
+		}
+
+		player.Score += player.RoundScore
+
+		history.Players[index].Tricks = player.Tricks
+		history.Players[index].RoundScore = player.RoundScore
+		history.Players[index].Score = player.Score
+	}
+
+	ohs.Round += 1
+	if ohs.Round >= len(ohs.Schedule) {
+		var max_score = ohs.Players[0].Score
+		for _, indexed_player := range ohs.Players {
+			if indexed_player.Score > max_score {
+				max_score = indexed_player.Score
+			}
+		}
+
+		ohs.Winners = make([]int, 0)
+		for index, indexed_player := range ohs.Players {
+			if indexed_player.Score == max_score {
+				ohs.Winners = append(ohs.Winners, index)
+			}
+		}
+
+		ohs.Finished = true
+		ohs.Turn = -1
+		ohs.Dealer = -1
+		return errors.New(OhHellGameOver)
+	}
+
+	ohs.Dealt = false
+	ohs.Turn = -1
+	ohs.Dealer = (ohs.Dealer + 1) % len(ohs.Players)
+	return errors.New(OhHellNextRound)
+}
@@ -0,0 +1,210 @@
+package games
+
+import (
+	"strings"
+)
+
+type OhHellPlayerState struct {
+	Hand []Card `json:"hand"`
+
+	Bid        int `json:"bid"`
+	Tricks     int `json:"tricks"`
+	RoundScore int `json:"round_score"`
+	Score      int `json:"score"`
+}
+
+type OhHellGameState struct {
+	Turn   uint64 `json:"turn"`
+	Leader uint64 `json:"leader"`
+	Dealer uint64 `json:"dealer"`
+
+	HandSize int      `json:"hand_size"`
+	Round    int      `json:"round"`
+	Rounds   int      `json:"rounds"`
+	TurnedUp *Card    `json:"turned_up,omitempty"`
+	Trump    CardSuit `json:"trump"`
+
+	Played    []Card   `json:"played"`
+	WhoPlayed []uint64 `json:"who_played"`
+	History   [][]Card `json:"history"`
+
+	Commitment string `json:"commitment"` // To the current round's shuffle seed; see ShuffleSeed.
+
+	Config OhHellConfig `json:"config"`
+
+	Started  bool `json:"started"`
+	Dealt    bool `json:"dealt"`
+	AllBid   bool `json:"all_bid"`
+	Finished bool `json:"finished"`
+}
+
+func (ogs *OhHellGameState) loadGame(data *GameData, game *OhHellState) {
+	ogs.Turn, _ = data.ToUserID(game.Turn)
+	ogs.Leader, _ = data.ToUserID(game.Leader)
+	ogs.Dealer, _ = data.ToUserID(game.Dealer)
+
+	if game.Round < len(game.Schedule) {
+		ogs.HandSize = game.Schedule[game.Round]
+	}
+	ogs.Round = game.Round
+	ogs.Rounds = len(game.Schedule)
+	ogs.TurnedUp = game.TurnedUp
+	ogs.Trump = game.Trump
+
+	ogs.Played = game.Played
+	if ogs.Played == nil {
+		ogs.Played = make([]Card, 0)
+	}
+
+	ogs.WhoPlayed = make([]uint64, 0)
+	for offset := 0; offset < len(game.Players); offset++ {
+		player_index := (game.Leader + offset) % len(game.Players)
+		player_uid, _ := data.ToUserID(player_index)
+		ogs.WhoPlayed = append(ogs.WhoPlayed, player_uid)
+	}
+
+	if len(game.PreviousTricks) >= 1 {
+		ogs.History = make([][]Card, 1)
+		ogs.History[0] = game.PreviousTricks[len(game.PreviousTricks)-1]
+	}
+
+	ogs.Commitment = game.Deck.Commitment()
+	ogs.Config = game.Config
+
+	ogs.Started = game.Started
+	ogs.Dealt = game.Dealt
+	ogs.AllBid = game.Bid
+	ogs.Finished = game.Finished
+}
+
+type OhHellStateNotification struct {
+	MessageHeader
+	OhHellPlayerState
+	OhHellGameState
+}
+
+func (osn *OhHellStateNotification) LoadData(data *GameData, game *OhHellState, player *PlayerData) {
+	osn.LoadHeader(data, player)
+	osn.MessageType = "state"
+
+	osn.Hand = game.Players[player.Index].Hand
+	osn.Bid = game.Players[player.Index].Bid
+	osn.Tricks = game.Players[player.Index].Tricks
+	osn.RoundScore = game.Players[player.Index].RoundScore
+	osn.Score = game.Players[player.Index].Score
+
+	osn.loadGame(data, game)
+}
+
+type OhHellPlayerSynopsis struct {
+	UID         uint64 `json:"user"`
+	Playing     bool   `json:"playing"`
+	PlayerIndex int    `json:"player_index"`
+
+	IsTurn   bool `json:"is_turn"`
+	IsLeader bool `json:"is_leader"`
+	IsDealer bool `json:"is_dealer"`
+
+	Bid        int `json:"bid"`
+	Tricks     int `json:"tricks"`
+	RoundScore int `json:"round_score"`
+	Score      int `json:"score"`
+}
+
+type OhHellSynopsisNotification struct {
+	MessageHeader
+
+	Players []OhHellPlayerSynopsis `json:"players"`
+
+	Trump         CardSuit `json:"trump"`
+	SuitIndicator string   `json:"suit"`
+}
+
+func (osn *OhHellSynopsisNotification) LoadData(data *GameData, state *OhHellState, player *PlayerData) {
+	osn.LoadHeader(data, player)
+	osn.MessageType = "synopsis"
+
+	for _, indexed_player := range data.ToPlayer {
+		var synopsis OhHellPlayerSynopsis
+		synopsis.UID = indexed_player.UID
+		synopsis.Playing = indexed_player.Playing
+		synopsis.PlayerIndex = indexed_player.Index
+		synopsis.Bid = -1
+
+		if indexed_player.Index >= 0 && indexed_player.Index < len(state.Players) {
+			synopsis.IsTurn = indexed_player.Index == state.Turn
+			synopsis.IsLeader = indexed_player.Index == state.Leader
+			synopsis.IsDealer = indexed_player.Index == state.Dealer
+
+			synopsis.Bid = state.Players[indexed_player.Index].Bid
+			synopsis.Tricks = state.Players[indexed_player.Index].Tricks
+			synopsis.RoundScore = state.Players[indexed_player.Index].RoundScore
+			synopsis.Score = state.Players[indexed_player.Index].Score
+		}
+
+		osn.Players = append(osn.Players, synopsis)
+	}
+
+	osn.Trump = state.Trump
+
+	if !state.Dealt {
+		osn.SuitIndicator = "dealing"
+	} else if !state.Bid {
+		osn.SuitIndicator = "bidding"
+	} else if state.leadSuit() == NoneSuit {
+		osn.SuitIndicator = "waiting"
+	} else {
+		osn.SuitIndicator = state.leadSuit().String()
+		osn.SuitIndicator = strings.TrimSuffix(osn.SuitIndicator, "Suit")
+	}
+}
+
+type OhHellPeekNotification struct {
+	MessageHeader
+
+	PlayerMapping []uint64 `json:"player_mapping"`
+
+	// Info for Ended Games (Everyone)
+	RoundHistory []*OhHellRound `json:"round_history"`
+
+	// Info for Active Games (Spectators)
+	OhHellGameState
+
+	Winners []uint64 `json:"winners"`
+}
+
+func (opn *OhHellPeekNotification) LoadData(data *GameData, game *OhHellState, player *PlayerData) {
+	opn.LoadHeader(data, player)
+	opn.MessageType = "game-state"
+
+	for index := range game.Players {
+		player_uid, _ := data.ToUserID(index)
+		opn.PlayerMapping = append(opn.PlayerMapping, player_uid)
+	}
+
+	opn.loadGame(data, game)
+
+	if !game.Finished {
+		// Allow spectators to see previous rounds before the game has ended.
+		if len(game.RoundHistory) > 0 {
+			opn.RoundHistory = game.RoundHistory[:len(game.RoundHistory)-1]
+		}
+	} else {
+		opn.RoundHistory = game.RoundHistory
+	}
+
+	opn.Winners, _ = data.ToUserIDs(game.Winners)
+}
+
+type OhHellFinishedNotification struct {
+	MessageHeader
+
+	Winners []uint64 `json:"winners"`
+}
+
+func (ofn *OhHellFinishedNotification) LoadData(data *GameData, state *OhHellState, player *PlayerData) {
+	ofn.LoadHeader(data, player)
+	ofn.MessageType = "finished"
+
+	ofn.Winners, _ = data.ToUserIDs(state.Winners)
+}
@@ -0,0 +1,108 @@
+package games
+
+import (
+	"testing"
+)
+
+func TestOhHellTrickWinner(t *testing.T) {
+	var wizard = Card{0, FancySuit, JokerRank}
+	var jester = Card{0, NoneSuit, JokerRank}
+
+	for _, test := range []struct {
+		trump  CardSuit
+		played []Card
+		winner int
+	}{
+		{HeartsSuit, []Card{{0, ClubsSuit, KingRank}, {0, ClubsSuit, AceRank}, {0, SpadesSuit, AceRank}}, 1},
+		{HeartsSuit, []Card{{0, ClubsSuit, KingRank}, {0, HeartsSuit, TwoRank}, {0, ClubsSuit, AceRank}}, 1},
+		{NoneSuit, []Card{{0, ClubsSuit, TwoRank}, {0, HeartsSuit, AceRank}, {0, ClubsSuit, ThreeRank}}, 2},
+		{HeartsSuit, []Card{{0, HeartsSuit, AceRank}, wizard, wizard}, 1},
+		{HeartsSuit, []Card{jester, {0, ClubsSuit, TwoRank}, {0, ClubsSuit, FiveRank}}, 2},
+		{HeartsSuit, []Card{jester, jester, jester}, 0},
+		{HeartsSuit, []Card{jester, {0, DiamondsSuit, TwoRank}, {0, ClubsSuit, AceRank}}, 1},
+	} {
+		var state = OhHellState{Trump: test.trump}
+		if winner := state.trickWinner(test.played); winner != test.winner {
+			t.Fatal("Expected", test.played, "with trump", test.trump, "to be won by", test.winner, "but got", winner)
+		}
+	}
+}
+
+func TestOhHellGame(t *testing.T) {
+	for _, config := range []OhHellConfig{
+		{NumPlayers: 4, Schedule: OhHellUpAndDown, MaxHandSize: 7, TrumpReveal: true, HookRule: true},
+		{NumPlayers: 5, Schedule: OhHellDownOnly, MaxHandSize: 20, Wizards: true, HookRule: true, Scoring: OhHellWizardScoring},
+		{NumPlayers: 3, Schedule: OhHellDownOnly, MaxHandSize: 5},
+	} {
+		var state OhHellState
+		if err := state.Init(config); err != nil {
+			t.Fatal("Unable to initialize game:", err)
+		}
+
+		if err := state.Start(config.NumPlayers); err != nil {
+			t.Fatal("Unable to start game:", err)
+		}
+
+		var expected = config.MaxHandSize
+		if config.Wizards {
+			expected = 12
+		}
+		if config.Schedule == OhHellUpAndDown {
+			expected = 2*expected - 1
+		}
+		if len(state.Schedule) != expected {
+			t.Fatal("Unexpected schedule:", state.Schedule)
+		}
+
+		for round := 0; round < 100 && !state.Finished; round++ {
+			if !state.Dealt {
+				if err := state.StartRound(); err != nil {
+					t.Fatal("Unable to deal:", err)
+				}
+			}
+
+			var hand_size = state.Schedule[state.Round]
+			if len(state.Players[0].Hand) != hand_size {
+				t.Fatal("Expected", hand_size, "cards in hand")
+			}
+
+			// Everyone bids one, so the dealer is hooked whenever the others
+			// leave exactly one trick.
+			var total = 0
+			for !state.Bid {
+				var bid = 1
+				if state.Turn == state.Dealer && config.HookRule && total+1 == hand_size {
+					if err := state.PlaceBid(state.Turn, bid); err == nil {
+						t.Fatal("Expected the dealer to be hooked")
+					}
+
+					bid = 0
+				}
+
+				if err := state.PlaceBid(state.Turn, bid); err != nil {
+					t.Fatal("Unable to bid:", err)
+				}
+				total += bid
+			}
+
+			var err error
+			for err == nil {
+				var played = false
+				for _, card := range state.Players[state.Turn].Hand {
+					if err = state.PlayCard(state.Turn, card.ID); err == nil || err.Error() == OhHellNextRound || err.Error() == OhHellGameOver {
+						played = true
+						break
+					}
+				}
+
+				if !played {
+					t.Fatal("Unable to find a legal card to play:", err)
+				}
+			}
+		}
+
+		if !state.Finished || len(state.Winners) == 0 || state.Round != len(state.Schedule) {
+			t.Fatal("Expected game to finish after every round")
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package games
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"strconv"
+	"time"
+)
+
+const maxPauseReason = 256
+
+// A game its owner has paused, like when someone has to step away mid-hand.
+// This is part of GameData so that a paused game stays paused across restarts.
+type GamePause struct {
+	Reason string    `json:"reason,omitempty"`
+	Since  time.Time `json:"since"`
+}
+
+// Game messages which are still handled while a game is paused; every other
+// action waits until the owner resumes the game.
+var pauseAllowedMessages = map[string]bool{
+	"join": true,
+	"peek": true,
+}
+
+// Implemented by engines of games played against a deadline, so the time a
+// game spends paused isn't counted against its players.
+type PausableEngine interface {
+	// Push back the game's deadlines by how long it was paused and reschedule
+	// any timers for them.
+	Resume(c *Controller, game *GameData, paused time.Duration)
+
+	// Schedule the timers for the game's deadlines again after it was loaded
+	// from the database.
+	Reschedule(c *Controller, game *GameData)
+}
+
+type GamePauseRequest struct {
+	MessageHeader
+	Reason string `json:"reason"`
+}
+
+type ControllerNotifyPause struct {
+	MessageHeader
+	Paused bool      `json:"paused"`
+	Reason string    `json:"reason,omitempty"`
+	Since  time.Time `json:"since"`
+}
+
+func (cnp *ControllerNotifyPause) LoadFromController(data *GameData, player *PlayerData) {
+	cnp.LoadHeader(data, player)
+	cnp.MessageType = "notify-pause"
+
+	if data.Paused != nil {
+		cnp.Paused = true
+		cnp.Reason = data.Paused.Reason
+		cnp.Since = data.Paused.Since
+	}
+}
+
+func (c *Controller) notifyPause(game *GameData) {
+	for _, indexed_player := range game.ToPlayer {
+		if !indexed_player.Admitted {
+			continue
+		}
+
+		var notification ControllerNotifyPause
+		notification.LoadFromController(game, indexed_player)
+		c.undispatch(game, indexed_player, notification.MessageID, 0, notification)
+	}
+}
+
+func (c *Controller) handlePause(message []byte, game *GameData, player *PlayerData) error {
+	// !!NO LOCK!! This should already be held elsewhere, like Dispatch.
+
+	var data GamePauseRequest
+	if err := json.Unmarshal(message, &data); err != nil {
+		return err
+	}
+
+	if player.UID != game.Owner {
+		return errors.New("unable to pause game that you're not the owner of")
+	}
+
+	if game.State.IsFinished() {
+		return errors.New("unable to pause game that has already finished")
+	}
+
+	if game.Paused != nil {
+		return errors.New("game is already paused")
+	}
+
+	if len(data.Reason) > maxPauseReason {
+		return errors.New("reason for pausing must be at most " + strconv.Itoa(maxPauseReason) + " characters")
+	}
+
+	game.Paused = &GamePause{data.Reason, time.Now()}
+	log.Println("Paused game", game.GID, "because:", data.Reason)
+
+	c.notifyPause(game)
+	return nil
+}
+
+func (c *Controller) handleResume(game *GameData, player *PlayerData) error {
+	// !!NO LOCK!! This should already be held elsewhere, like Dispatch.
+
+	if player.UID != game.Owner {
+		return errors.New("unable to resume game that you're not the owner of")
+	}
+
+	if game.Paused == nil {
+		return errors.New("game isn't paused")
+	}
+
+	var paused = time.Since(game.Paused.Since)
+	game.Paused = nil
+
+	// Give back the time spent paused to whoever's clock was running.
+	if game.Clock != nil && game.Clock.Player >= 0 {
+		game.Clock.Started = game.Clock.Started.Add(paused)
+		game.Clock.Deadline = game.Clock.Deadline.Add(paused)
+		c.scheduleClockTimeout(game)
+	}
+
+	if engine, ok := LookupGameEngine(game.Mode); ok {
+		if pausable, ok := engine.(PausableEngine); ok {
+			pausable.Resume(c, game, paused)
+		}
+	}
+
+	c.notifyPause(game)
+
+	// Pick the countdown back up; countbacks received while paused have
+	// already been recorded.
+	if !game.State.IsStarted() && game.CountdownTimer != nil {
+		return c.handleCountdown(game)
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package games
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPauseResume(t *testing.T) {
+	var c Controller
+	c.Init()
+
+	var game, state = newClockedHeartsGame(t, &c, HeartsConfig{NumPlayers: 4, NumberToPass: 3, WinAmount: 100, TurnClock: 600})
+	c.updateClock(game, nil)
+
+	var send = func(uid uint64, messageType string, body string) error {
+		var message = `{"game_mode":"hearts","game_id":1,"player_id":` + strconv.FormatUint(uid, 10) + `,"message_type":"` + messageType + `"` + body + `}`
+		header, err := parseMessageHeader([]byte(message))
+		if err != nil {
+			t.Fatal("Unable to parse message:", err)
+		}
+
+		return c.dispatch([]byte(message), header, game, game.ToPlayer[uid], 0)
+	}
+
+	if err := send(2, "pause", ``); err == nil || game.Paused != nil {
+		t.Fatal("Expected only the owner to be able to pause the game")
+	}
+
+	if err := send(game.Owner, "pause", `,"reason":"doorbell"`); err != nil || game.Paused == nil || game.Paused.Reason != "doorbell" {
+		t.Fatal("Unable to pause game:", err, game.Paused)
+	}
+
+	var notified = false
+	for _, message := range game.ToPlayer[3].OutboundMsgs {
+		notified = notified || (strings.Contains(message.Message, `"notify-pause"`) && strings.Contains(message.Message, `"reason":"doorbell"`))
+	}
+
+	if !notified {
+		t.Fatal("Expected players to be told the game was paused")
+	}
+
+	// Game actions wait until the game is resumed; joining still works.
+	var player = state.Turn
+	var card = state.Players[player].Hand[0].ID
+	if err := send(uint64(player+1), "play", `,"card_id":`+strconv.Itoa(card)); err == nil || !strings.Contains(err.Error(), "paused") || len(state.Played) != 0 {
+		t.Fatal("Expected moves to be rejected while paused:", err)
+	}
+
+	if err := send(uint64(player+1), "join", ``); err != nil {
+		t.Fatal("Unable to join paused game:", err)
+	}
+
+	// The turn clock is frozen.
+	var deadline = game.Clock.Deadline
+	game.Clock.Deadline = time.Now()
+	c.handleClockTimeout(game.GID, game.Clock.Turn)
+	if len(state.Played) != 0 {
+		t.Fatal("Expected the clock to be stopped while paused:", state.Played)
+	}
+	game.Clock.Deadline = deadline
+
+	// The pause is saved along with the game.
+	encoded, err := json.Marshal(game)
+	if err != nil || !strings.Contains(string(encoded), `"reason":"doorbell"`) {
+		t.Fatal("Expected the pause to be persisted:", err)
+	}
+
+	var restored GameData
+	restored.State = &HeartsState{}
+	if err := json.Unmarshal(encoded, &restored); err != nil || restored.Paused == nil || restored.Paused.Reason != "doorbell" {
+		t.Fatal("Expected the pause to survive reloading:", err, restored.Paused)
+	}
+
+	if err := send(2, "resume", ``); err == nil || game.Paused == nil {
+		t.Fatal("Expected only the owner to be able to resume the game")
+	}
+
+	// Resuming gives back the time spent paused.
+	game.Paused.Since = game.Paused.Since.Add(-time.Minute)
+	if err := send(game.Owner, "resume", ``); err != nil || game.Paused != nil {
+		t.Fatal("Unable to resume game:", err)
+	}
+
+	if !game.Clock.Deadline.After(deadline.Add(59 * time.Second)) {
+		t.Fatal("Expected the deadline to be pushed back:", deadline, game.Clock.Deadline)
+	}
+
+	if err := send(game.Owner, "resume", ``); err == nil {
+		t.Fatal("Expected resuming a running game to fail")
+	}
+
+	playAnyHeartsCard(t, state, player)
+	if len(state.Played) != 1 {
+		t.Fatal("Expected moves to be allowed once resumed:", state.Played)
+	}
+}
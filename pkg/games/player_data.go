@@ -47,6 +47,20 @@ type PlayerData struct {
 	// in 8Js, given the specified card selected by the player). This is a
 	// list of UIDs of other PlayerData units.
 	BoundPlayers []uint64 `json:"bound_players"`
+
+	// Last board feedback sent to this player, in games which give it, so it's
+	// only sent again when it changes. This is only for the controller; it
+	// isn't part of the game state.
+	LastFeedback string `json:"-"`
+
+	// Whether or not this player is a computer opponent. Bots have no
+	// websocket connection; their moves are decided by the game engine's
+	// GameBot after every dispatched message.
+	Bot bool `json:"bot,omitempty"`
+
+	// How well this bot plays; one of BotEasy, BotMedium or BotHard. Not all
+	// bots honor this.
+	BotDifficulty string `json:"bot_difficulty,omitempty"`
 }
 
 func (p *PlayerData) IsBound(uid uint64) bool {
@@ -0,0 +1,133 @@
+package games
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"git.cipherboy.com/WillowPatchGames/wpg/pkg/middleware/figgy"
+)
+
+// GameEngine describes everything the controller needs to know about a given
+// game mode. Each game registers an engine (usually from an init() function)
+// and the controller looks up the engine by GameMode rather than switching
+// over every known mode. This lets games live outside of this package
+// without modifying the controller.
+type GameEngine interface {
+	// Persisted identifier of this game mode. This is stored in the database
+	// alongside the game state, so it must be stable.
+	Mode() GameMode
+
+	// Name of this game mode, as sent in the game_mode field of every message
+	// and stored as the style of the game in the database.
+	Name() string
+
+	// Human-readable title and description, shown when creating a game.
+	Title() string
+	Description() string
+
+	// Create an empty configuration object for this game mode. This is
+	// populated by figgy when parsing a request or loading from the database.
+	EmptyConfig() figgy.Figgurable
+
+	// Create an empty state object for this game mode, suitable for
+	// deserializing a persisted game into.
+	NewState() ConfigurableState
+
+	// Create and initialize a new state object from the given configuration.
+	Init(config figgy.Figgurable) (ConfigurableState, error)
+
+	// Handle a game-specific message. Common messages (join, admit, ready,
+	// countback, ...) are handled by the controller before this is called.
+	Dispatch(c *Controller, message []byte, header MessageHeader, game *GameData, player *PlayerData, sid uint64) error
+
+	// Start the game once the countdown has completed.
+	Start(c *Controller, game *GameData) error
+}
+
+var gameEnginesLock sync.RWMutex
+var gameEngines = make(map[GameMode]GameEngine)
+
+// Register a new game engine. Fails when the mode or name conflicts with an
+// already registered engine.
+func RegisterGameEngine(engine GameEngine) error {
+	if engine == nil {
+		return errors.New("refusing to register nil game engine")
+	}
+
+	if engine.Mode() < 0 {
+		return errors.New("game engine mode must be non-negative: " + strconv.Itoa(int(engine.Mode())))
+	}
+
+	if engine.Name() == "" {
+		return errors.New("game engine must have a name")
+	}
+
+	gameEnginesLock.Lock()
+	defer gameEnginesLock.Unlock()
+
+	if existing, present := gameEngines[engine.Mode()]; present {
+		return errors.New("game mode " + strconv.Itoa(int(engine.Mode())) + " is already registered to " + existing.Name())
+	}
+
+	for _, existing := range gameEngines {
+		if strings.EqualFold(existing.Name(), engine.Name()) {
+			return errors.New("game engine with name " + engine.Name() + " is already registered")
+		}
+	}
+
+	gameEngines[engine.Mode()] = engine
+	return nil
+}
+
+// Like RegisterGameEngine, but panics on failure. Useful from init().
+func MustRegisterGameEngine(engine GameEngine) {
+	if err := RegisterGameEngine(engine); err != nil {
+		panic("unable to register game engine: " + err.Error())
+	}
+}
+
+// Find the engine for the given game mode, if any.
+func LookupGameEngine(mode GameMode) (GameEngine, bool) {
+	gameEnginesLock.RLock()
+	defer gameEnginesLock.RUnlock()
+
+	engine, ok := gameEngines[mode]
+	return engine, ok
+}
+
+// All registered game engines, ordered by game mode.
+func GameEngines() []GameEngine {
+	gameEnginesLock.RLock()
+	defer gameEnginesLock.RUnlock()
+
+	var ret = make([]GameEngine, 0, len(gameEngines))
+	for _, engine := range gameEngines {
+		ret = append(ret, engine)
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Mode() < ret[j].Mode()
+	})
+
+	return ret
+}
+
+// Send a notification to the given player. This is the exported variant of
+// undispatch, for use by game engines outside of this package. The caller is
+// expected to already hold the game's lock (i.e., be inside Dispatch).
+func (c *Controller) Notify(game *GameData, player *PlayerData, message_id int, reply_to int, obj interface{}) {
+	c.undispatch(game, player, message_id, reply_to, obj)
+}
+
+// Begin (or continue) the countdown for the given game. Game engines outside
+// of this package call this when handling their start message; once
+// everyone has counted back, the engine's Start hook is called.
+func (c *Controller) StartCountdown(game *GameData) error {
+	game.Countdown = 0
+	game.CountdownTimer = nil
+
+	return c.handleCountdown(game)
+}
@@ -0,0 +1,35 @@
+package games
+
+import (
+	"testing"
+)
+
+func TestBuiltinEngines(t *testing.T) {
+	for _, mode := range []GameMode{RushGame, SpadesGame, ThreeThirteenGame, EightJacksGame, HeartsGame, GinGame, EuchreGame, CribbageGame, BridgeGame, OhHellGame, CrazyEightsGame, WordSearchGame} {
+		if !mode.IsValid() {
+			t.Fatal("Expected builtin game mode to be registered:", int(mode))
+		}
+
+		if GameModeFromString(mode.String()) != mode {
+			t.Fatal("Expected game mode to round trip through its name:", mode.String())
+		}
+
+		if mode.NewState() == nil || mode.EmptyConfig() == nil {
+			t.Fatal("Expected non-nil state and config for game mode:", mode.String())
+		}
+	}
+
+	if GameModeFromString("not a game") != -1 {
+		t.Fatal("Expected unknown game mode to be -1")
+	}
+
+	if GameMode(-1).IsValid() {
+		t.Fatal("Expected -1 to be an invalid game mode")
+	}
+}
+
+func TestDuplicateEngine(t *testing.T) {
+	if err := RegisterGameEngine(heartsEngine{}); err == nil {
+		t.Fatal("Expected duplicate registration of hearts to fail")
+	}
+}
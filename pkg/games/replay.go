@@ -0,0 +1,409 @@
+package games
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+	"time"
+
+	"git.cipherboy.com/WillowPatchGames/wpg/internal/database"
+	"git.cipherboy.com/WillowPatchGames/wpg/pkg/middleware/figgy"
+)
+
+// Inbound messages which don't change the game, so aren't counted as moves
+// when replaying it. Whatever the server sent in reply stays with the move
+// before them.
+var replayIgnoredMessages = map[string]bool{
+	"join":      true,
+	"keepalive": true,
+	"countback": true,
+	"peek":      true,
+	"pause":     true,
+	"resume":    true,
+}
+
+// Messages logged before GameMessage recorded which way they went all look
+// inbound. Those the server sent can be told apart by their type; for types
+// sent both ways, by a field only the server's notification has.
+var replayOutboundMessages = map[string]string{
+	"admitted":            "",
+	"bid":                 "bidder",
+	"checked":             "",
+	"clock":               "",
+	"countdown":           "",
+	"draw":                "drawer",
+	"error":               "",
+	"feedback":            "",
+	"finished":            "",
+	"game-state":          "",
+	"hint":                "hints",
+	"notify-bind":         "",
+	"notify-bound":        "",
+	"notify-countback":    "",
+	"notify-join":         "",
+	"notify-pause":        "",
+	"notify-undo":         "",
+	"notify-undo-request": "",
+	"notify-users":        "",
+	"round-finished":      "",
+	"started":             "",
+	"state":               "",
+	"synopsis":            "",
+	"word":                "valid",
+}
+
+// Whether a message logged before directions were recorded was sent by the
+// server.
+func replayLegacyOutbound(message string, messageType string) bool {
+	field, ok := replayOutboundMessages[messageType]
+	if !ok {
+		return false
+	}
+
+	if field == "" {
+		return true
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(message), &fields); err != nil {
+		return false
+	}
+
+	_, ok = fields[field]
+	return ok
+}
+
+type ReplayMessage struct {
+	Player    uint64          `json:"player"` // Who sent the move, or who the notification was sent to.
+	Timestamp time.Time       `json:"timestamp"`
+	Message   json.RawMessage `json:"message,omitempty"` // Left out of other players' moves until the game is over.
+}
+
+type ReplayMove struct {
+	Move int `json:"move"` // Counting from one; move zero holds everything sent before the first move.
+	ReplayMessage
+
+	// Why the game rejected this move, if it did.
+	Error string `json:"error,omitempty"`
+
+	// Notifications the game sent in response to this move, up until the
+	// next one.
+	Notifications []ReplayMessage `json:"notifications"`
+
+	// The whole state of the game after this move. Left out until the game is
+	// over.
+	State json.RawMessage `json:"state,omitempty"`
+}
+
+// A Replay steps through a game, move by move. The game is dealt again from
+// its configuration and shuffle secret, and every move logged while it was
+// played is run through the game engine in order. Until the game is over,
+// the viewer only sees their own moves and notifications, so they can't
+// learn anyone else's hand; afterwards, everything is revealed, along with
+// the state of the game after each move.
+type Replay struct {
+	GameID   uint64      `json:"game_id"`
+	Mode     string      `json:"game_mode"`
+	Config   interface{} `json:"config"`
+	Finished bool        `json:"finished"`
+	Viewer   uint64      `json:"viewer"`
+
+	Moves int `json:"moves"` // Number of moves in the whole game.
+	Move  int `json:"move"`  // The move this replay was sought to.
+
+	History []ReplayMove `json:"history"`
+
+	// The last notification of each type each visible player had received as
+	// of Move: their view of the game at that point.
+	State []ReplayMessage `json:"state"`
+}
+
+// Rebuild the given game as seen by viewer, through the given move. When
+// move is zero or past the end of the game, the whole game is replayed.
+// Players who joined are admitted as they were in players.
+func NewReplay(gamedb *database.Game, players []database.GamePlayer, messages []database.GameMessage, viewer uint64, move int) (*Replay, error) {
+	if move < 0 {
+		return nil, errors.New("can't seek to a negative move: " + strconv.Itoa(move))
+	}
+
+	var mode = GameModeFromString(gamedb.Style)
+	engine, ok := LookupGameEngine(mode)
+	if !ok {
+		return nil, errors.New("unknown game mode: " + gamedb.Style)
+	}
+
+	// Without the secret, the game can't be dealt the same way again.
+	if !gamedb.ShuffleSecret.Valid {
+		return nil, errors.New("unable to replay a game created before its shuffle secret was recorded")
+	}
+
+	var ret = new(Replay)
+	ret.GameID = gamedb.ID
+	ret.Mode = engine.Name()
+	ret.Finished = gamedb.Lifecycle == "finished"
+	ret.Viewer = viewer
+
+	if gamedb.Config.Valid {
+		var config figgy.Figgurable = engine.EmptyConfig()
+		if err := figgy.Parse(config, []byte(gamedb.Config.String)); err != nil {
+			return nil, err
+		}
+		ret.Config = config
+	}
+
+	// Start over from a fresh game with the same configuration and secret.
+	var c Controller
+	c.Init()
+	c.replay = true
+
+	var fresh = *gamedb
+	fresh.Lifecycle = "pending"
+	fresh.State = sql.NullString{}
+	if err := c.LoadGame(&fresh); err != nil {
+		return nil, err
+	}
+
+	var game = c.ToGame[gamedb.ID]
+
+	// Word games check words against those of the room they were played in.
+	if state, ok := game.State.(WordGameState); ok && gamedb.State.Valid {
+		var played GameData
+		played.State = mode.NewState()
+		if err := json.Unmarshal([]byte(gamedb.State.String), &played); err != nil {
+			return nil, err
+		}
+
+		if words, ok := played.State.(WordGameState); ok {
+			state.SetRoomWords(words.Words().RoomWords)
+		}
+	}
+
+	var admitted = make(map[uint64]bool)
+	for _, player := range players {
+		if player.UserID.Valid {
+			admitted[uint64(player.UserID.Int64)] = player.Admitted && !player.Banned
+		}
+	}
+
+	// Messages are logged in order, so those from before directions were
+	// recorded come before the first one marked outbound.
+	var first_outbound uint64 = 0
+	for _, message := range messages {
+		if message.Outbound && (first_outbound == 0 || message.ID < first_outbound) {
+			first_outbound = message.ID
+		}
+	}
+
+	type replayEntry struct {
+		database.GameMessage
+		header MessageHeader
+	}
+
+	// Only the moves themselves are needed; the replay sends its own
+	// notifications.
+	var ordered = make([]replayEntry, 0, len(messages))
+	for _, message := range messages {
+		header, err := parseMessageHeader([]byte(message.Message))
+		if err != nil {
+			return nil, err
+		}
+
+		if header.MessageType == "keepalive" {
+			continue
+		}
+
+		if !message.Outbound && (first_outbound == 0 || message.ID < first_outbound) {
+			message.Outbound = replayLegacyOutbound(message.Message, header.MessageType)
+		}
+
+		if message.Outbound {
+			continue
+		}
+
+		if !replayIgnoredMessages[header.MessageType] {
+			ret.Moves += 1
+		}
+
+		ordered = append(ordered, replayEntry{message, header})
+	}
+
+	// Each player's messages are saved together, so put the moves back in
+	// the order they were made.
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if !ordered[i].Timestamp.Equal(ordered[j].Timestamp) {
+			return ordered[i].Timestamp.Before(ordered[j].Timestamp)
+		}
+
+		return ordered[i].ID < ordered[j].ID
+	})
+
+	ret.Move = ret.Moves
+	if move > 0 && move < ret.Moves {
+		ret.Move = move
+	}
+
+	// Hand everything the game sent since the last call to the latest move,
+	// along with the state it left the game in.
+	ret.History = []ReplayMove{{Move: 0}}
+	var record = func(timestamp time.Time) error {
+		var last = &ret.History[len(ret.History)-1]
+
+		var uids = make([]uint64, 0, len(game.ToPlayer))
+		for uid := range game.ToPlayer {
+			uids = append(uids, uid)
+		}
+		sort.Slice(uids, func(i, j int) bool { return uids[i] < uids[j] })
+
+		for _, uid := range uids {
+			var indexed_player = game.ToPlayer[uid]
+			for _, sent := range indexed_player.OutboundMsgs {
+				if ret.Finished || sent.UserID == viewer {
+					last.Notifications = append(last.Notifications, ReplayMessage{sent.UserID, timestamp, json.RawMessage(sent.Message)})
+				}
+			}
+
+			indexed_player.InboundMsgs = nil
+			indexed_player.OutboundMsgs = nil
+		}
+
+		if ret.Finished {
+			snapshot, err := replaySnapshot(game.State)
+			if err != nil {
+				return err
+			}
+
+			last.State = snapshot
+		}
+
+		return nil
+	}
+
+	var pausable, timed = engine.(PausableEngine)
+	var previous time.Time
+	var advance = func(timestamp time.Time) error {
+		// Let the time between moves pass, so that the game's deadlines run
+		// out when they did. Time spent paused doesn't count.
+		if timed && !previous.IsZero() && game.Paused == nil && timestamp.After(previous) {
+			pausable.Resume(&c, game, -timestamp.Sub(previous))
+		}
+
+		previous = timestamp
+		return record(timestamp)
+	}
+
+	var moves = 0
+	for _, message := range ordered {
+		var header = message.header
+		var counted = !replayIgnoredMessages[header.MessageType]
+		if counted && moves == ret.Move {
+			break
+		}
+
+		if err := advance(message.Timestamp); err != nil {
+			return nil, err
+		}
+
+		player, ok := game.ToPlayer[message.UserID]
+		var err error
+		if !ok && IsBotUID(message.UserID) {
+			err = errors.New("unknown computer player")
+		} else {
+			if !ok {
+				player = newPlayerData(game, message.UserID, admitted[message.UserID])
+				game.ToPlayer[message.UserID] = player
+				if err := c.notifyAdmin(game, message.UserID); err != nil {
+					return nil, err
+				}
+			}
+
+			err = c.dispatch([]byte(message.Message), header, game, player, 0)
+		}
+
+		if counted {
+			moves += 1
+
+			var entry = ReplayMove{Move: moves}
+			entry.Player = message.UserID
+			entry.Timestamp = message.Timestamp
+			if ret.Finished || message.UserID == viewer {
+				entry.Message = json.RawMessage(message.Message)
+				if err != nil && !isRoundSignal(err) {
+					entry.Error = err.Error()
+				}
+			}
+
+			ret.History = append(ret.History, entry)
+		}
+
+		if err := record(message.Timestamp); err != nil {
+			return nil, err
+		}
+	}
+
+	// Games played against the clock can run out of time after the last
+	// move; catch up with when the game was last saved.
+	if ret.Move == ret.Moves && ret.Finished && gamedb.UpdatedAt.After(previous) && !game.State.IsFinished() {
+		if err := advance(gamedb.UpdatedAt); err != nil {
+			return nil, err
+		}
+	}
+
+	ret.loadState()
+	return ret, nil
+}
+
+// Snapshot the state of the game, leaving out the secret its deals derive
+// from, since other tables might have been dealt from it too.
+func replaySnapshot(state ConfigurableState) (json.RawMessage, error) {
+	encoded, err := json.Marshal(state)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(encoded, &fields); err != nil {
+		return nil, err
+	}
+
+	delete(fields, "seed")
+	return json.Marshal(fields)
+}
+
+func (r *Replay) loadState() {
+	type stateKey struct {
+		player      uint64
+		messageType string
+	}
+
+	var latest = make(map[stateKey]ReplayMessage)
+	for _, move := range r.History {
+		for _, notification := range move.Notifications {
+			header, err := parseMessageHeader(notification.Message)
+			if err != nil {
+				continue
+			}
+
+			latest[stateKey{notification.Player, header.MessageType}] = notification
+		}
+	}
+
+	var keys = make([]stateKey, 0, len(latest))
+	for key := range latest {
+		keys = append(keys, key)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].player != keys[j].player {
+			return keys[i].player < keys[j].player
+		}
+
+		return keys[i].messageType < keys[j].messageType
+	})
+
+	r.State = make([]ReplayMessage, 0, len(keys))
+	for _, key := range keys {
+		r.State = append(r.State, latest[key])
+	}
+}
@@ -0,0 +1,240 @@
+package games
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"git.cipherboy.com/WillowPatchGames/wpg/internal/database"
+)
+
+// Play a game of Hearts between two people and two bots, with the people
+// playing as the bots would, and return everything it logged.
+func playReplayGame(t *testing.T, gamedb *database.Game) (*GameData, []database.GameMessage) {
+	var c Controller
+	c.Init()
+	if err := c.LoadGame(gamedb); err != nil {
+		t.Fatal("Unable to load game:", err)
+	}
+
+	var game = c.ToGame[gamedb.ID]
+	game.ToPlayer[1] = newPlayerData(game, 1, true)
+	game.ToPlayer[2] = newPlayerData(game, 2, true)
+
+	var send = func(uid uint64, message interface{}) error {
+		data, err := json.Marshal(message)
+		if err != nil {
+			t.Fatal("Unable to marshal message:", err)
+		}
+
+		_, err = c.Dispatch(data, gamedb.ID, uid, 0)
+		return err
+	}
+
+	var header = func(uid uint64, messageType string) MessageHeader {
+		return MessageHeader{Mode: "hearts", ID: gamedb.ID, Player: uid, MessageType: messageType}
+	}
+
+	for _, uid := range []uint64{1, 2} {
+		if err := send(uid, header(uid, "join")); err != nil {
+			t.Fatal("Unable to join:", err)
+		}
+	}
+
+	for bot := 0; bot < 2; bot++ {
+		if err := send(1, GameAddBot{header(1, "add-bot"), ""}); err != nil {
+			t.Fatal("Unable to add bot:", err)
+		}
+	}
+
+	if err := send(1, header(1, "start")); err != nil {
+		t.Fatal("Unable to start game:", err)
+	}
+
+	var bot = (heartsEngine{}).NewBot()
+	for turns := 0; turns < 2000 && !game.State.IsFinished(); turns++ {
+		for _, uid := range []uint64{1, 2} {
+			move, err := bot.NextMove(game, game.ToPlayer[uid])
+			if err != nil {
+				t.Fatal("Unable to pick a move:", err)
+			}
+
+			if move == nil {
+				continue
+			}
+
+			if err := send(uid, move); err != nil && !isRoundSignal(err) {
+				t.Fatal("Unable to make move:", err)
+			}
+		}
+	}
+
+	if !game.State.IsFinished() {
+		t.Fatal("Expected the game to finish")
+	}
+
+	var messages []database.GameMessage
+	for _, indexed_player := range game.ToPlayer {
+		for _, message := range append(indexed_player.InboundMsgs, indexed_player.OutboundMsgs...) {
+			message.ID = uint64(len(messages) + 1)
+			messages = append(messages, *message)
+		}
+	}
+
+	return game, messages
+}
+
+func TestReplay(t *testing.T) {
+	var gamedb database.Game
+	gamedb.ID = 7
+	gamedb.OwnerID = 1
+	gamedb.Style = "hearts"
+	gamedb.Lifecycle = "pending"
+	gamedb.Config.Valid = true
+	gamedb.Config.String = `{"num_players":4,"number_to_pass":3,"must_break_hearts":true,"win_amount":50}`
+	database.SetSQLFromString(&gamedb.ShuffleSecret, "replay")
+
+	var players = []database.GamePlayer{{GameID: 7, Admitted: true}, {GameID: 7, Admitted: true}}
+	players[0].UserID.Valid, players[0].UserID.Int64 = true, 1
+	players[1].UserID.Valid, players[1].UserID.Int64 = true, 2
+
+	game, messages := playReplayGame(t, &gamedb)
+	gamedb.Lifecycle = "finished"
+
+	replay, err := NewReplay(&gamedb, players, messages, 2, 0)
+	if err != nil {
+		t.Fatal("Unable to replay game:", err)
+	}
+
+	if replay.Moves < 52 || replay.Move != replay.Moves || len(replay.History) != replay.Moves+1 || replay.Config.(*HeartsConfig).NumPlayers != 4 {
+		t.Fatal("Expected every card played to be a move:", replay.Moves, replay.Move, len(replay.History), replay.Config)
+	}
+
+	for _, move := range replay.History[1:] {
+		if move.Error != "" || move.Message == nil || move.State == nil {
+			t.Fatal("Expected every move to be played again and revealed:", move.Move, string(move.Message), move.Error)
+		}
+
+		if strings.Contains(string(move.State), `"replay"`) {
+			t.Fatal("Expected the shuffle secret to stay out of the snapshots:", move.Move)
+		}
+	}
+
+	// Dealing again from the same secret ends up in the same place.
+	expected, err := replaySnapshot(game.State)
+	if err != nil {
+		t.Fatal("Unable to snapshot game:", err)
+	}
+
+	if string(replay.History[replay.Moves].State) != string(expected) {
+		t.Fatal("Expected the replay to end where the game did:", string(replay.History[replay.Moves].State), string(expected))
+	}
+
+	// Until the game ends, only our own moves and notifications are visible.
+	gamedb.Lifecycle = "playing"
+	replay, err = NewReplay(&gamedb, players, messages, 2, 20)
+	if err != nil || replay.Move != 20 || len(replay.History) != 21 {
+		t.Fatal("Expected to seek to the twentieth move:", err, replay.Move, len(replay.History))
+	}
+
+	var ours = 0
+	for _, move := range replay.History[1:] {
+		if move.State != nil || (move.Player != 2 && move.Message != nil) {
+			t.Fatal("Expected only our own moves to be visible:", move.Move, move.Player, string(move.Message))
+		}
+
+		if move.Player == 2 {
+			ours += 1
+		}
+
+		for _, notification := range move.Notifications {
+			if notification.Player != 2 {
+				t.Fatal("Expected only our own notifications to be visible:", move.Move, string(notification.Message))
+			}
+		}
+	}
+
+	if ours == 0 || len(replay.State) == 0 {
+		t.Fatal("Expected to see our own moves and hand:", ours, replay.State)
+	}
+
+	for _, latest := range replay.State {
+		if latest.Player != 2 {
+			t.Fatal("Expected only our own view of the game:", latest.Player, string(latest.Message))
+		}
+	}
+
+	if _, err := NewReplay(&gamedb, players, messages, 2, -1); err == nil {
+		t.Fatal("Expected seeking to a negative move to fail")
+	}
+
+	gamedb.ShuffleSecret.Valid = false
+	if _, err := NewReplay(&gamedb, players, messages, 2, 0); err == nil {
+		t.Fatal("Expected games without a shuffle secret to be impossible to replay")
+	}
+}
+
+func TestReplayLegacyOutbound(t *testing.T) {
+	// Games logged before directions were recorded tell the server's messages
+	// apart by their types.
+	var message = func(messageType string, body string) string {
+		return `{"game_mode":"hearts","game_id":7,"player_id":` + strconv.Itoa(1) + `,"message_type":"` + messageType + `"` + body + `}`
+	}
+
+	if !replayLegacyOutbound(message("state", `,"hand":[]`), "state") || replayLegacyOutbound(message("play", `,"card_id":3`), "play") {
+		t.Fatal("Expected states to be told apart from moves")
+	}
+
+	if !replayLegacyOutbound(`{"message_type":"draw","drawer":2}`, "draw") || replayLegacyOutbound(`{"message_type":"draw"}`, "draw") {
+		t.Fatal("Expected draws to be told apart by who drew")
+	}
+}
+
+func TestReplayDeadline(t *testing.T) {
+	var gamedb database.Game
+	gamedb.ID = 8
+	gamedb.OwnerID = 1
+	gamedb.Style = "word search"
+	gamedb.Lifecycle = "finished"
+	gamedb.Config.Valid = true
+	gamedb.Config.String = `{"num_players":1,"size":4,"frequency":1,"min_length":3,"time_limit":1,"countdown":true}`
+	database.SetSQLFromString(&gamedb.ShuffleSecret, "deadline")
+
+	var started = time.Now().Add(-time.Hour)
+	var log = func(after time.Duration, messageType string) database.GameMessage {
+		return database.GameMessage{
+			ID:        uint64(after),
+			UserID:    1,
+			GameID:    8,
+			Timestamp: started.Add(after),
+			Message:   `{"game_mode":"word search","game_id":8,"player_id":1,"message_type":"` + messageType + `"}`,
+		}
+	}
+
+	// The game runs out of time between the two peeks.
+	var messages = []database.GameMessage{
+		log(0, "join"),
+		log(time.Second, "start"),
+		log(30*time.Second, "peek"),
+		log(2*time.Minute, "peek"),
+	}
+
+	var finished = func(messages []database.GameMessage) bool {
+		replay, err := NewReplay(&gamedb, nil, messages, 1, 0)
+		if err != nil || replay.Moves != 1 {
+			t.Fatal("Expected starting the game to be the only move:", err, replay)
+		}
+
+		return strings.Contains(string(replay.History[1].State), `"finished":true`)
+	}
+
+	if finished(messages[:3]) {
+		t.Fatal("Expected the game to still be running before its deadline")
+	}
+
+	if !finished(messages) {
+		t.Fatal("Expected the game to end once the log passed its deadline")
+	}
+}
@@ -4,13 +4,15 @@ import (
 	"errors"
 	"log"
 	"strconv"
-
-	"git.cipherboy.com/WillowPatchGames/wpg/internal/utils"
+	"strings"
+	"time"
 
 	"git.cipherboy.com/WillowPatchGames/wpg/pkg/middleware/figgy"
 )
 
 const RushYouWon string = "game is over; you won"
+const RushGameOver string = "game is over"
+const RushNextRound string = "begin next round"
 
 type RushPlayer struct {
 	Board LetterGrid   `json:"board"`
@@ -21,6 +23,23 @@ type RushPlayer struct {
 	// hand or board. When a client reconnects, they'll start with all data in
 	// `board` or `hand` and won't need any of the data here.
 	NewTiles []LetterTile `json:"-"`
+
+	// When this player last asked for a hint, to rate limit them.
+	LastHint time.Time `json:"last_hint"`
+
+	// Words this player could've made from their tiles, computed once the
+	// game is over.
+	PossibleWords []string `json:"possible_words,omitempty"`
+
+	// When playing in teams, each RushPlayer is a team's shared board and
+	// hand, played by this many players.
+	Members int `json:"members"`
+
+	// Number of changes made to the board and hand, so moves based on an
+	// outdated view of a shared board can be caught. Changed maps tile
+	// identifiers to the version in which they last moved.
+	Version int         `json:"version"`
+	Changed map[int]int `json:"changed,omitempty"`
 }
 
 func (rp *RushPlayer) Init() {
@@ -44,11 +63,47 @@ type RushConfig struct {
 	NumTiles   int `json:"num_tiles" config:"type:int,min:10,default:75,max:300" label:"Number of tiles"`  // Between 1 and 200 rounds worth.
 
 	TilesPerPlayer bool      `json:"tiles_per_player" config:"type:bool,default:false" label:"true:Tiles per Player,false:Total number of tiles"`
-	Frequency      Frequency `json:"frequency" config:"type:enum,default:1,options:1:Standard US English Letter Frequencies;2:Bananagrams Tile Frequency;3:Scrabble Tile Frequency" label:"Tile frequency"`
+	Frequency      Frequency `json:"frequency" config:"type:enum,default:1,options:1:Standard US English Letter Frequencies;2:Bananagrams Tile Frequency;3:Scrabble Tile Frequency;4:Custom Letter Distribution" label:"Tile frequency"`
 
 	StartSize      int `json:"start_size" config:"type:int,min:7,default:12,max:25" label:"Player tile start size"`
 	DrawSize       int `json:"draw_size" config:"type:int,min:1,default:1,max:10" label:"Player tile draw size"`
 	DiscardPenalty int `json:"discard_penalty" config:"type:int,min:1,default:3,max:5" label:"Player tile discard penalty"`
+
+	Hints     bool `json:"hints" config:"type:bool,default:false" label:"true:Let players ask for hints,false:Don't give hints"`
+	HintDelay int  `json:"hint_delay" config:"type:int,min:0,default:60,max:600" label:"Seconds between hints"` // Per player.
+
+	Blanks int `json:"blanks" config:"type:int,min:0,default:0,max:20" label:"Number of blank tiles"` // Blanks can be played as any letter.
+
+	Dictionary int `json:"dictionary" config:"type:enum,default:0,options:@dictionaries" label:"Dictionary"` // See dictionaryOptions.
+
+	Bag             bool   `json:"bag" config:"type:bool,default:false" label:"true:Draw from an exact bag of tiles,false:Pick each tile at random"` // Scaled to the number of tiles.
+	CustomFrequency string `json:"custom_frequency" config:"type:string,max:1024" label:"Custom letter distribution"`                                // See ParseFrequencies; used with the custom tile frequency.
+
+	Scoring   int  `json:"scoring" config:"type:enum,default:0,options:0:First to use all their tiles wins;1:Score one point per valid word;2:Score one point per letter in valid words;3:Score the letter values of valid words" label:"Scoring"` // See RushFirstOut and friends.
+	TimeLimit int  `json:"time_limit" config:"type:int,min:0,default:0,max:60" label:"Time limit per round in minutes (0 for none)"`                                                                                                               // Needs a scoring method.
+	Rounds    int  `json:"rounds" config:"type:int,min:0,default:1,max:10" label:"Number of rounds"`                                                                                                                                               // Scores add up across rounds; zero plays one.
+	TeamPlay  bool `json:"team_play" config:"type:bool,default:false" label:"true:Play in teams sharing a board,false:Everyone plays their own board"`
+}
+
+// Relative weights of each letter in the game's tiles.
+func (cfg RushConfig) LetterWeights() (map[string]float64, error) {
+	if cfg.Frequency == CustomFreq {
+		return ParseFrequencies(cfg.CustomFrequency)
+	}
+
+	if cfg.Frequency <= StartFreqRange || cfg.Frequency >= EndFreqRange {
+		return nil, errors.New("unknown tile frequency: " + strconv.Itoa(int(cfg.Frequency)))
+	}
+
+	if cfg.Bag && cfg.Frequency == BananagramsFreq {
+		return bananagramsBag, nil
+	}
+
+	return frequencyMap[cfg.Frequency], nil
+}
+
+func (cfg RushConfig) DictionaryName() string {
+	return dictionaryName(cfg.Dictionary)
 }
 
 func (cfg RushConfig) Validate() error {
@@ -56,6 +111,16 @@ func (cfg RushConfig) Validate() error {
 		return GameConfigError{"frequency range", strconv.Itoa(int(cfg.Frequency)), "between " + strconv.Itoa(int(StartFreqRange)) + " and " + strconv.Itoa(int(EndFreqRange))}
 	}
 
+	if cfg.Frequency == CustomFreq {
+		if _, err := cfg.LetterWeights(); err != nil {
+			return GameConfigError{"custom letter distribution", cfg.CustomFrequency, "letter:weight pairs: " + err.Error()}
+		}
+	}
+
+	if !DictionaryExists(cfg.DictionaryName()) {
+		return GameConfigError{"dictionary", cfg.DictionaryName(), "a dictionary installed on this server"}
+	}
+
 	var totalTiles int = cfg.NumTiles
 	if cfg.TilesPerPlayer {
 		totalTiles *= cfg.NumPlayers
@@ -76,6 +141,22 @@ func (cfg RushConfig) Validate() error {
 		return GameConfigError{"number of tiles", tilesRepr, "must be enough for 1 to 200 rounds of drawing"}
 	}
 
+	if cfg.Blanks*4 > totalTiles {
+		return GameConfigError{"number of blank tiles", strconv.Itoa(cfg.Blanks), "at most a quarter of all tiles"}
+	}
+
+	if cfg.Scoring < RushFirstOut || cfg.Scoring >= rushScoringRange {
+		return GameConfigError{"scoring", strconv.Itoa(cfg.Scoring), "between " + strconv.Itoa(RushFirstOut) + " and " + strconv.Itoa(rushScoringRange-1)}
+	}
+
+	if cfg.Scoring == RushFirstOut && cfg.TimeLimit > 0 {
+		return GameConfigError{"time limit", strconv.Itoa(cfg.TimeLimit), "no time limit unless boards are scored"}
+	}
+
+	if cfg.Scoring == RushFirstOut && cfg.Rounds > 1 {
+		return GameConfigError{"number of rounds", strconv.Itoa(cfg.Rounds), "a single round unless boards are scored"}
+	}
+
 	return nil
 }
 
@@ -87,6 +168,62 @@ type RushState struct {
 	Started  bool         `json:"started"`
 	Finished bool         `json:"finished"`
 	Winner   int          `json:"winner"`
+
+	// Current round (from one) and each finished round's scores, indexed by
+	// player.
+	Round       int     `json:"round"`
+	RoundScores [][]int `json:"round_scores"`
+
+	// When the current round runs out of time; zero without a time limit.
+	Deadline time.Time `json:"deadline"`
+
+	// When playing in teams, the players (by index in the order they were
+	// assigned) on each team. Players holds one entry per team.
+	Teams    [][]int `json:"teams"`
+	Assigned bool    `json:"assigned"`
+
+	// Words added or removed by the room this game is played in.
+	RoomWords RoomWords `json:"room_words"`
+
+	// Secret each round's seed derives from; when unset, every round gets a
+	// fresh seed. Each round's tiles are generated (and shuffled after
+	// discards) from its seed; the last one belongs to the current round and
+	// stays secret until it ends.
+	Seed       *ShuffleSeed   `json:"seed,omitempty"`
+	RoundSeeds []*ShuffleSeed `json:"round_seeds"`
+}
+
+func (rs *RushState) SetShuffleSeed(seed *ShuffleSeed) error {
+	if rs.Started {
+		return errors.New("unable to set the shuffle seed after the game has started")
+	}
+
+	rs.Seed = seed
+	return nil
+}
+
+// Seeds of the rounds which are over, which can be revealed.
+func (rs *RushState) RevealedSeeds() []*ShuffleSeed {
+	return revealedRoundSeeds(rs.RoundSeeds, !rs.Finished)
+}
+
+// Seed of the current round. Rounds started before seeds were kept get a
+// fresh one.
+func (rs *RushState) currentSeed() *ShuffleSeed {
+	if len(rs.RoundSeeds) == 0 {
+		rs.RoundSeeds = append(rs.RoundSeeds, NewShuffleSeed())
+	}
+
+	return rs.RoundSeeds[len(rs.RoundSeeds)-1]
+}
+
+// The words accepted in this game.
+func (rs *RushState) Words() WordList {
+	return WordList{rs.Config.DictionaryName(), rs.RoomWords}
+}
+
+func (rs *RushState) SetRoomWords(words RoomWords) {
+	rs.RoomWords = words
 }
 
 func (rs *RushState) Init(cfg RushConfig) error {
@@ -130,6 +267,64 @@ func (rs *RushState) ResetStatus() {
 	rs.Finished = false
 }
 
+func (rs *RushState) AssignTeams(num_players int, player_assignments [][]int) error {
+	if rs.Started {
+		return errors.New("cannot assign teams after already started")
+	}
+
+	if !rs.Config.TeamPlay {
+		return errors.New("this game isn't played in teams")
+	}
+
+	if len(player_assignments) < 2 {
+		return errors.New("need at least two teams")
+	}
+
+	rs.Config.NumPlayers = num_players
+	if err := figgy.Validate(rs.Config); err != nil {
+		log.Println("Error with RushConfig while assigning teams", err)
+		return err
+	}
+
+	var assigned = make([]bool, num_players)
+	for _, players := range player_assignments {
+		if len(players) == 0 {
+			return errors.New("every team needs at least one player")
+		}
+
+		for _, player := range players {
+			if player < 0 || player >= num_players {
+				return errors.New("not a valid player identifier: " + strconv.Itoa(player))
+			}
+
+			if assigned[player] {
+				return errors.New("player assigned to more than one team: " + strconv.Itoa(player))
+			}
+
+			assigned[player] = true
+		}
+	}
+
+	for player, ok := range assigned {
+		if !ok {
+			return errors.New("player isn't assigned to a team: " + strconv.Itoa(player))
+		}
+	}
+
+	rs.Teams = player_assignments
+	rs.Assigned = true
+	return nil
+}
+
+// Number of players sharing this board and hand.
+func (rs *RushState) members(player int) int {
+	if rs.Players[player].Members > 0 {
+		return rs.Players[player].Members
+	}
+
+	return 1
+}
+
 func (rs *RushState) Start(players int) error {
 	var err error
 
@@ -146,8 +341,34 @@ func (rs *RushState) Start(players int) error {
 		return err
 	}
 
-	// Create the player objects
-	rs.Players = make([]RushPlayer, rs.Config.NumPlayers)
+	// Create the player objects; in teams, one per team.
+	if rs.Config.TeamPlay {
+		if !rs.Assigned {
+			return errors.New("have to assign players to teams before starting")
+		}
+
+		rs.Players = make([]RushPlayer, len(rs.Teams))
+		for team, members := range rs.Teams {
+			rs.Players[team].Members = len(members)
+		}
+	} else {
+		rs.Players = make([]RushPlayer, rs.Config.NumPlayers)
+	}
+
+	rs.Round = 0
+	rs.RoundScores = make([][]int, 0)
+
+	if err := rs.startRound(); err != nil {
+		return err
+	}
+
+	rs.Started = true
+	return nil
+}
+
+// Deal out a fresh set of tiles for the next round.
+func (rs *RushState) startRound() error {
+	var err error
 
 	// First calculate the number of tiles we need in this game.
 	var totalTiles int = rs.Config.NumTiles
@@ -156,26 +377,85 @@ func (rs *RushState) Start(players int) error {
 	}
 
 	// Then generate tiles and have players draw their initial tiles.
-	rs.Tiles = GenerateTiles(totalTiles, false, rs.Config.Frequency)
+	weights, err := rs.Config.LetterWeights()
+	if err != nil {
+		return err
+	}
+
+	var seed = roundSeed(rs.Seed, len(rs.RoundSeeds)+1)
+	rs.RoundSeeds = append(rs.RoundSeeds, seed)
+	rs.Tiles = GenerateSeededTiles(seed, totalTiles, rs.Config.Blanks, weights, rs.Config.Bag)
 	for playerIndex := range rs.Players {
 		rs.Players[playerIndex].Init()
+		rs.Touch(playerIndex)
 
-		err = rs.drawTiles(playerIndex, rs.Config.StartSize)
+		err = rs.drawTiles(playerIndex, rs.Config.StartSize*rs.members(playerIndex))
 		if err != nil {
 			log.Println("Unexpected error from DrawTiles; shouldn't error during RushState.Init()", err)
 			return err
 		}
 	}
 
-	// Increment to the initial draw identifier value of 1 to show players they
-	// need to draw. (Initial DrawID on the client side is 0; this forces them
-	// to load their hands).
-	rs.DrawID = 1
-	rs.Started = true
+	// Increment the draw identifier to show players they need to draw. (The
+	// initial DrawID on the client side is 0; this forces them to load their
+	// hands).
+	rs.DrawID++
+	rs.Round++
+
+	rs.Deadline = time.Time{}
+	if rs.Config.TimeLimit > 0 {
+		rs.Deadline = time.Now().Add(time.Duration(rs.Config.TimeLimit) * time.Minute)
+	}
 
 	return nil
 }
 
+// Score the round which just ended, with player having used all their tiles
+// (or -1 when time ran out), and either start the next round or finish the
+// game.
+func (rs *RushState) endRound(player int) {
+	var scores = make([]int, len(rs.Players))
+	for index := range rs.Players {
+		scores[index] = rs.ScoreBoard(index)
+	}
+	rs.RoundScores = append(rs.RoundScores, scores)
+
+	if rs.Round < rs.Config.Rounds {
+		if err := rs.startRound(); err == nil {
+			return
+		}
+
+		log.Println("Unable to start the next round of Rush; ending the game early")
+	}
+
+	rs.Finished = true
+	rs.Deadline = time.Time{}
+
+	if rs.Config.Scoring == RushFirstOut {
+		rs.Winner = player
+		return
+	}
+
+	// Highest total score wins; between tied players, whoever used all their
+	// tiles wins, and otherwise the earliest player.
+	rs.Winner = -1
+	for index := range rs.Players {
+		if rs.Winner == -1 || rs.TotalScore(index) > rs.TotalScore(rs.Winner) || (index == player && rs.TotalScore(index) == rs.TotalScore(rs.Winner)) {
+			rs.Winner = index
+		}
+	}
+}
+
+// End the current round if it has run out of time, returning whether it did.
+func (rs *RushState) CheckTime() bool {
+	if !rs.Started || rs.Finished || rs.Deadline.IsZero() || time.Now().Before(rs.Deadline) {
+		return false
+	}
+
+	rs.endRound(-1)
+	return true
+}
+
 func (rs *RushState) HasValidWords(player int) error {
 	if !rs.Started {
 		return errors.New("game hasn't started yet")
@@ -185,8 +465,9 @@ func (rs *RushState) HasValidWords(player int) error {
 		return errors.New("not a valid player identifier: " + strconv.Itoa(player))
 	}
 
+	var words = rs.Words()
 	return rs.Players[player].Board.VisitAllWordsOnBoard(func(lg *LetterGrid, start LetterPos, end LetterPos, word string) error {
-		if !IsWord(word) {
+		if !words.IsWord(word) {
 			return errors.New("not a valid word: " + word)
 		}
 
@@ -242,7 +523,9 @@ func (rs *RushState) drawTiles(player int, count int) error {
 	return nil
 }
 
-func (rs *RushState) PlayTile(player int, tileID int, x int, y int) error {
+// Play a tile from the hand onto the board. Blank tiles must be given the
+// letter they stand for; other tiles must not.
+func (rs *RushState) PlayTile(player int, tileID int, x int, y int, letter string) error {
 	if !rs.Started {
 		return errors.New("game hasn't started yet")
 	}
@@ -262,14 +545,35 @@ func (rs *RushState) PlayTile(player int, tileID int, x int, y int) error {
 		return errors.New("tile is not in hand")
 	}
 
+	// Assign a blank's letter to a copy of the tile, so that the hand is left
+	// alone when the play is rejected.
+	var tile = rs.Players[player].Hand[tileIndex]
+	if tile.Blank {
+		if letter == "" {
+			return errors.New("must choose a letter for a blank tile")
+		}
+
+		if err := tile.Assign(letter); err != nil {
+			return err
+		}
+	} else if letter != "" {
+		return errors.New("only blank tiles can be assigned a letter")
+	}
+
 	// Check if destination is occupied; if so, issue a SwapTile request rather
 	// than a PlayTile request.
 	if boardTileID, ok := rs.Players[player].Board.AtPosition[LetterPos{x, y}]; ok {
-		return rs.SwapTile(player, tileID, boardTileID)
+		var original = rs.Players[player].Hand[tileIndex]
+		rs.Players[player].Hand[tileIndex] = tile
+		if err := rs.SwapTile(player, tileID, boardTileID); err != nil {
+			rs.Players[player].Hand[tileIndex] = original
+			return err
+		}
+
+		return nil
 	}
 
 	// Remote tile from hand
-	var tile = rs.Players[player].Hand[tileIndex]
 	rs.Players[player].Hand = append(rs.Players[player].Hand[:tileIndex], rs.Players[player].Hand[tileIndex+1:]...)
 
 	// Add to board
@@ -278,7 +582,9 @@ func (rs *RushState) PlayTile(player int, tileID int, x int, y int) error {
 	return nil
 }
 
-func (rs *RushState) MoveTile(player int, tileID int, x int, y int) error {
+// Move a tile around the board. A blank tile may be assigned a different
+// letter as it is moved.
+func (rs *RushState) MoveTile(player int, tileID int, x int, y int, letter string) error {
 	if !rs.Started {
 		return errors.New("game hasn't started yet")
 	}
@@ -291,10 +597,19 @@ func (rs *RushState) MoveTile(player int, tileID int, x int, y int) error {
 		return errors.New("not a valid player identifier: " + strconv.Itoa(player))
 	}
 
-	if _, ok := rs.Players[player].Board.ToTile[tileID]; !ok {
+	tile, ok := rs.Players[player].Board.ToTile[tileID]
+	if !ok {
 		return errors.New("not a valid tile identifier: " + strconv.Itoa(tileID))
 	}
 
+	if letter != "" && !strings.EqualFold(letter, tile.Value) {
+		if err := tile.Assign(letter); err != nil {
+			return err
+		}
+
+		rs.Players[player].Board.UpdateTile(tile)
+	}
+
 	// Move the tile on the board
 	rs.Players[player].Board.MoveTile(tileID, x, y)
 
@@ -323,8 +638,13 @@ func (rs *RushState) swapTileHandToBoard(player int, handTile int, boardTile int
 	// Grab the tile from the board and place it in the hand
 	var tile = rs.Players[player].Board.ToTile[boardTile]
 	var pos = rs.Players[player].Board.PositionsOf[boardTile]
+	if rs.Players[player].Hand[handTile].Blank && rs.Players[player].Hand[handTile].Value == "" {
+		return errors.New("must choose a letter for a blank tile; play it onto the board instead")
+	}
+
 	rs.Players[player].Board.RemoveTile(boardTile)
 
+	tile.Unassign()
 	rs.Players[player].Hand = append(rs.Players[player].Hand, tile)
 
 	// Grab the tile from the hand and place it on the board
@@ -389,6 +709,7 @@ func (rs *RushState) RecallTile(player int, tileID int) error {
 	}
 
 	rs.Players[player].Board.RemoveTile(tile.ID)
+	tile.Unassign()
 	rs.Players[player].Hand = append(rs.Players[player].Hand, tile)
 
 	return nil
@@ -441,7 +762,7 @@ func (rs *RushState) Discard(player int, tileID int) error {
 	// After putting the tile back in the pool, shuffle it so that another player
 	// has a shot at drawing this tile. Otherwise, just putting it at the end
 	// isn't a good idea as nobody might get it again.
-	utils.SecureRand.Shuffle(len(rs.Tiles), func(i, j int) {
+	rs.currentSeed().Rand().Shuffle(len(rs.Tiles), func(i, j int) {
 		rs.Tiles[i], rs.Tiles[j] = rs.Tiles[j], rs.Tiles[i]
 	})
 
@@ -475,18 +796,116 @@ func (rs *RushState) Draw(player int, lastID int) error {
 
 	var tilesNeeded = rs.Config.DrawSize * rs.Config.NumPlayers
 	if tilesNeeded > len(rs.Tiles) {
-		rs.Finished = true
-		rs.Winner = player
-		return errors.New(RushYouWon)
+		rs.endRound(player)
+		if !rs.Finished {
+			return errors.New(RushNextRound)
+		}
+
+		if rs.Winner == player {
+			return errors.New(RushYouWon)
+		}
+
+		return errors.New(RushGameOver)
 	}
 
 	// Draw for all players
 	rs.DrawID++
 	for playerIndex := range rs.Players {
-		if err := rs.drawTiles(playerIndex, rs.Config.DrawSize); err != nil {
+		if err := rs.drawTiles(playerIndex, rs.Config.DrawSize*rs.members(playerIndex)); err != nil {
 			return err
 		}
 	}
 
 	return nil
 }
+
+// Identifier of the tile at x, y on the player's board, or zero.
+func (rs *RushState) TileAt(player int, x int, y int) int {
+	if player < 0 || player >= len(rs.Players) {
+		return 0
+	}
+
+	return rs.Players[player].Board.AtPosition[LetterPos{x, y}]
+}
+
+// Check that none of the given tiles moved since the version of the board a
+// player last saw. Only shared boards can conflict; a version of zero skips
+// the check.
+func (rs *RushState) CheckConflict(player int, version int, tiles ...int) error {
+	if !rs.Config.TeamPlay || version <= 0 || player < 0 || player >= len(rs.Players) {
+		return nil
+	}
+
+	for _, tile := range tiles {
+		if tile != 0 && rs.Players[player].Changed[tile] > version {
+			return errors.New("a teammate moved that tile first; try again")
+		}
+	}
+
+	return nil
+}
+
+// Record that the given tiles moved.
+func (rs *RushState) Touch(player int, tiles ...int) {
+	if player < 0 || player >= len(rs.Players) {
+		return
+	}
+
+	if rs.Players[player].Changed == nil {
+		rs.Players[player].Changed = make(map[int]int)
+	}
+
+	rs.Players[player].Version++
+	for _, tile := range tiles {
+		if tile != 0 {
+			rs.Players[player].Changed[tile] = rs.Players[player].Version
+		}
+	}
+}
+
+// Number of words suggested in response to a hint request.
+const rushHintCount = 3
+
+// Number of words in the post-game summary of words a player could have made.
+const rushPossibleWordsCount = 10
+
+func (rs *RushState) Hint(player int) ([]RushHint, error) {
+	if !rs.Started {
+		return nil, errors.New("game hasn't started yet")
+	}
+
+	if rs.Finished {
+		return nil, errors.New("game has already finished")
+	}
+
+	if player < 0 || player >= len(rs.Players) {
+		return nil, errors.New("not a valid player identifier: " + strconv.Itoa(player))
+	}
+
+	if !rs.Config.Hints {
+		return nil, errors.New("hints aren't enabled in this game")
+	}
+
+	var since = time.Since(rs.Players[player].LastHint)
+	var delay = time.Duration(rs.Config.HintDelay) * time.Second
+	if since < delay {
+		return nil, errors.New("too soon for another hint; wait " + strconv.Itoa(int((delay-since+time.Second-1)/time.Second)) + " more seconds")
+	}
+
+	rs.Players[player].LastHint = time.Now()
+	return SolveRush(rs.Words(), rs.Players[player].Hand, &rs.Players[player].Board, rushHintCount), nil
+}
+
+// Words the player could have made from all of their tiles. Only available
+// once the game is over.
+func (rs *RushState) PossibleWords(player int) []string {
+	if !rs.Finished || player < 0 || player >= len(rs.Players) {
+		return nil
+	}
+
+	if rs.Players[player].PossibleWords == nil {
+		rs.Players[player].PossibleWords = RushPossibleWords(rs.Words(), &rs.Players[player], rushPossibleWordsCount)
+	}
+
+	return rs.Players[player].PossibleWords
+}
@@ -3,43 +3,63 @@ package games
 import (
 	"encoding/json"
 	"errors"
+	"time"
 
 	"git.cipherboy.com/WillowPatchGames/wpg/pkg/middleware/figgy"
 )
 
+// Assign players to teams, when playing in teams. PlayerMaps gives the user
+// for each player index used in TeamAssignments.
+type RushAssignMsg struct {
+	MessageHeader
+	NumPlayers      int      `json:"num_players"`
+	PlayerMaps      []uint64 `json:"player_map"`
+	TeamAssignments [][]int  `json:"team_assignments"`
+}
+
 type RushDraw struct {
 	MessageHeader
 	DrawID int `json:"draw_id"`
 }
 
+// Moves on a shared board carry the version of the board they were made
+// against; see RushState.CheckConflict.
+
 type RushDiscard struct {
 	MessageHeader
-	TileID int `json:"tile_id"`
+	TileID  int `json:"tile_id"`
+	Version int `json:"version,omitempty"`
 }
 
 type RushRecall struct {
 	MessageHeader
-	TileID int `json:"tile_id"`
+	TileID  int `json:"tile_id"`
+	Version int `json:"version,omitempty"`
 }
 
 type RushSwap struct {
 	MessageHeader
 	FirstID  int `json:"first_id"`
 	SecondID int `json:"second_id"`
+	Version  int `json:"version,omitempty"`
 }
 
 type RushMove struct {
 	MessageHeader
-	TileID int `json:"tile_id"`
-	X      int `json:"x"`
-	Y      int `json:"y"`
+	TileID  int    `json:"tile_id"`
+	X       int    `json:"x"`
+	Y       int    `json:"y"`
+	Letter  string `json:"letter,omitempty"` // Reassigns a blank tile.
+	Version int    `json:"version,omitempty"`
 }
 
 type RushPlay struct {
 	MessageHeader
-	TileID int `json:"tile_id"`
-	X      int `json:"x"`
-	Y      int `json:"y"`
+	TileID  int    `json:"tile_id"`
+	X       int    `json:"x"`
+	Y       int    `json:"y"`
+	Letter  string `json:"letter,omitempty"` // Required for blank tiles.
+	Version int    `json:"version,omitempty"`
 }
 
 func (c *Controller) dispatchRush(message []byte, header MessageHeader, game *GameData, player *PlayerData, sid uint64) error {
@@ -49,10 +69,68 @@ func (c *Controller) dispatchRush(message []byte, header MessageHeader, game *Ga
 		panic("internal state is nil; this shouldn't happen when the game is started")
 	}
 
+	// End the round if time ran out since we last heard from anyone. The clock
+	// is stopped while the game is paused.
+	if game.Paused == nil {
+		state.CheckTime()
+	}
+
 	var was_finished = state.Finished
+	var was_round = state.Round
 	var send_synopsis = false
+	var send_feedback = false
+
+	// Tiles moved by this message, which teammates sharing the board need to
+	// hear about.
+	var touched []int
 
 	switch header.MessageType {
+	case "assign":
+		if player.UID != game.Owner {
+			return errors.New("unable to assign players to game that you're not the owner of")
+		}
+
+		if game.CountdownTimer != nil {
+			return errors.New("unable to assign players while the game is starting")
+		}
+
+		var data RushAssignMsg
+		if err = json.Unmarshal(message, &data); err != nil {
+			return err
+		}
+
+		if data.NumPlayers != len(data.PlayerMaps) {
+			return errors.New("incorrect number of players compared to player map")
+		}
+
+		for _, playerID := range data.PlayerMaps {
+			if _, ok := game.ToPlayer[playerID]; !ok {
+				return errors.New("unknown player")
+			}
+		}
+
+		if err = state.AssignTeams(data.NumPlayers, data.TeamAssignments); err != nil {
+			return err
+		}
+
+		// Everyone on a team plays from their team's board, so their index is
+		// the team's. Reset everyone first in case an earlier assignment was
+		// cancelled.
+		for _, indexed_player := range game.ToPlayer {
+			indexed_player.Index = -1
+		}
+
+		for team, members := range data.TeamAssignments {
+			for _, member := range members {
+				game.ToPlayer[data.PlayerMaps[member]].Index = team
+			}
+		}
+
+		var response MessageHeader
+		response.LoadHeader(game, player)
+		response.ReplyTo = header.MessageID
+
+		c.undispatch(game, player, response.MessageID, response.ReplyTo, response)
 	case "start":
 		if player.UID != game.Owner {
 			return errors.New("unable to start game that you're not the owner of")
@@ -69,6 +147,10 @@ func (c *Controller) dispatchRush(message []byte, header MessageHeader, game *Ga
 			}
 		}
 
+		if state.Config.TeamPlay && (players == 0 || players != state.Config.NumPlayers || !state.Assigned) {
+			return errors.New("must finish configuring assignments for this game")
+		}
+
 		state.Config.NumPlayers = players
 		if err = figgy.Validate(state.Config); err != nil {
 			return err
@@ -103,6 +185,9 @@ func (c *Controller) dispatchRush(message []byte, header MessageHeader, game *Ga
 
 				c.undispatch(game, player, response.MessageID, 0, response)
 
+				// Resend board feedback to the newly connected client.
+				c.sendRushFeedback(game, state, player, true)
+
 				send_synopsis = true
 			}
 		} else if state.Finished {
@@ -121,8 +206,16 @@ func (c *Controller) dispatchRush(message []byte, header MessageHeader, game *Ga
 			return errors.New("unknown tile identifier")
 		}
 
-		err = state.PlayTile(player.Index, data.TileID, data.X, data.Y)
+		var target = state.TileAt(player.Index, data.X, data.Y)
+		if err = state.CheckConflict(player.Index, data.Version, data.TileID, target); err != nil {
+			c.sendRushTeamState(game, state, player.Index)
+			return err
+		}
+
+		err = state.PlayTile(player.Index, data.TileID, data.X, data.Y, data.Letter)
+		touched = []int{data.TileID, target}
 		send_synopsis = err == nil
+		send_feedback = err == nil
 	case "move":
 		var data RushMove
 		if err = json.Unmarshal(message, &data); err != nil {
@@ -133,8 +226,16 @@ func (c *Controller) dispatchRush(message []byte, header MessageHeader, game *Ga
 			return errors.New("unknown tile identifier")
 		}
 
-		err = state.MoveTile(player.Index, data.TileID, data.X, data.Y)
+		var target = state.TileAt(player.Index, data.X, data.Y)
+		if err = state.CheckConflict(player.Index, data.Version, data.TileID, target); err != nil {
+			c.sendRushTeamState(game, state, player.Index)
+			return err
+		}
+
+		err = state.MoveTile(player.Index, data.TileID, data.X, data.Y, data.Letter)
+		touched = []int{data.TileID, target}
 		send_synopsis = err == nil
+		send_feedback = err == nil
 	case "swap":
 		var data RushSwap
 		if err = json.Unmarshal(message, &data); err != nil {
@@ -145,8 +246,15 @@ func (c *Controller) dispatchRush(message []byte, header MessageHeader, game *Ga
 			return errors.New("unknown tile identifier")
 		}
 
+		if err = state.CheckConflict(player.Index, data.Version, data.FirstID, data.SecondID); err != nil {
+			c.sendRushTeamState(game, state, player.Index)
+			return err
+		}
+
 		err = state.SwapTile(player.Index, data.FirstID, data.SecondID)
+		touched = []int{data.FirstID, data.SecondID}
 		send_synopsis = err == nil
+		send_feedback = err == nil
 	case "recall":
 		var data RushRecall
 		if err = json.Unmarshal(message, &data); err != nil {
@@ -157,8 +265,15 @@ func (c *Controller) dispatchRush(message []byte, header MessageHeader, game *Ga
 			return errors.New("unknown tile identifier")
 		}
 
+		if err = state.CheckConflict(player.Index, data.Version, data.TileID); err != nil {
+			c.sendRushTeamState(game, state, player.Index)
+			return err
+		}
+
 		err = state.RecallTile(player.Index, data.TileID)
+		touched = []int{data.TileID}
 		send_synopsis = err == nil
+		send_feedback = err == nil
 	case "discard":
 		var data RushDiscard
 		if err = json.Unmarshal(message, &data); err != nil {
@@ -169,9 +284,15 @@ func (c *Controller) dispatchRush(message []byte, header MessageHeader, game *Ga
 			return errors.New("unknown tile identifier")
 		}
 
+		if err = state.CheckConflict(player.Index, data.Version, data.TileID); err != nil {
+			c.sendRushTeamState(game, state, player.Index)
+			return err
+		}
+
 		if err = state.Discard(player.Index, data.TileID); err != nil && !state.Finished {
 			return err
 		}
+		touched = []int{data.TileID}
 
 		// No error, send a state message back to the player.
 		var response RushStateNotification
@@ -182,6 +303,7 @@ func (c *Controller) dispatchRush(message []byte, header MessageHeader, game *Ga
 		c.undispatch(game, player, response.MessageID, response.ReplyTo, response)
 
 		send_synopsis = true
+		send_feedback = true
 	case "draw":
 		var data RushDraw
 		if err = json.Unmarshal(message, &data); err != nil {
@@ -192,7 +314,7 @@ func (c *Controller) dispatchRush(message []byte, header MessageHeader, game *Ga
 			return errors.New("unknown draw identifier")
 		}
 
-		if err = state.Draw(player.Index, data.DrawID); err != nil && !state.Finished {
+		if err = state.Draw(player.Index, data.DrawID); err != nil && !state.Finished && state.Round == was_round {
 			return err
 		}
 
@@ -221,10 +343,10 @@ func (c *Controller) dispatchRush(message []byte, header MessageHeader, game *Ga
 			send_synopsis = true
 		}
 
-		// If we don't clear err now (when state is Finished), we end up sending
+		// If we don't clear err now (when the round ended), we end up sending
 		// the user back an error message rather than letting them see the message
 		// that they won.
-		if state.Finished && err.Error() == RushYouWon {
+		if err != nil && isRoundSignal(err) && (state.Finished || state.Round != was_round) {
 			err = nil
 		}
 	case "check":
@@ -242,6 +364,16 @@ func (c *Controller) dispatchRush(message []byte, header MessageHeader, game *Ga
 			// error response in the event err was non-nil.
 			err = nil
 		}
+	case "hint":
+		var hints []RushHint
+		if hints, err = state.Hint(player.Index); err != nil {
+			return err
+		}
+
+		var response RushHintNotification
+		response.LoadFromController(game, player, hints)
+		response.ReplyTo = header.MessageID
+		c.undispatch(game, player, response.MessageID, response.ReplyTo, response)
 	case "peek":
 		if !state.Started {
 			return errors.New("unable to peek at game which hasn't started yet")
@@ -269,16 +401,25 @@ func (c *Controller) dispatchRush(message []byte, header MessageHeader, game *Ga
 		return errors.New("unknown message_type issued to rush game: " + header.MessageType)
 	}
 
-	// If this game ended during this dispatch call, notify everyone.
-	if !was_finished && state.Finished {
-		// Notify everyone that the game ended and that this active player won.
-		for _, indexed_player := range game.ToPlayer {
-			var finished RushFinishedNotification
-			finished.LoadData(game, state, indexed_player)
-			c.undispatch(game, indexed_player, finished.MessageID, 0, finished)
+	// If this round or the game ended during this dispatch call, notify
+	// everyone.
+	if c.notifyRushRoundEnd(game, state, was_finished, was_round) {
+		send_synopsis = true
+	}
+
+	// Record the change and show it to everyone sharing the board.
+	if err == nil && len(touched) > 0 {
+		state.Touch(player.Index, touched...)
+		if state.Config.TeamPlay && !state.Finished {
+			c.sendRushTeamState(game, state, player.Index)
 		}
 	}
 
+	// Let the player know about mistakes on their board as they make them.
+	if send_feedback && !state.Finished {
+		c.sendRushFeedback(game, state, player, false)
+	}
+
 	// If someone changed something, notify everyone.
 	if send_synopsis {
 		for _, indexed_player := range game.ToPlayer {
@@ -291,6 +432,148 @@ func (c *Controller) dispatchRush(message []byte, header MessageHeader, game *Ga
 	return err
 }
 
+// Send the current board and hand to everyone playing from the given index.
+// Only used in team games, where the board is shared.
+func (c *Controller) sendRushTeamState(game *GameData, state *RushState, index int) {
+	if !state.Config.TeamPlay || index < 0 || index >= len(state.Players) {
+		return
+	}
+
+	for _, indexed_player := range game.ToPlayer {
+		if indexed_player.Index != index {
+			continue
+		}
+
+		var response RushStateNotification
+		response.LoadFromGame(state, index)
+		response.LoadFromController(game, indexed_player)
+		c.undispatch(game, indexed_player, response.MessageID, 0, response)
+	}
+}
+
+// Send a player (and anyone sharing their board) feedback about invalid words
+// and islands on their board, if it changed since we last told each of them
+// (or when forced to, in which case only this player hears). A board without
+// any problems doesn't need feedback until it's had some.
+func (c *Controller) sendRushFeedback(game *GameData, state *RushState, player *PlayerData, force bool) {
+	if player.Index < 0 || player.Index >= len(state.Players) {
+		return
+	}
+
+	var feedback RushFeedbackNotification
+	feedback.LoadFromGame(state, player.Index)
+
+	var problems = len(feedback.Unwords) > 0 || len(feedback.Islands) > 0
+	encoded, err := json.Marshal([]interface{}{feedback.Unwords, feedback.Islands})
+	if err != nil {
+		return
+	}
+
+	for _, indexed_player := range game.ToPlayer {
+		if indexed_player != player && (force || indexed_player.Index != player.Index) {
+			continue
+		}
+
+		var last = indexed_player.LastFeedback
+		if !force && (string(encoded) == last || (last == "" && !problems)) {
+			continue
+		}
+		indexed_player.LastFeedback = string(encoded)
+
+		var message = feedback
+		message.LoadFromController(game, indexed_player)
+		c.undispatch(game, indexed_player, message.MessageID, 0, message)
+	}
+}
+
+// Tell everyone about the end of a round or of the game, if either happened
+// since was_finished and was_round were observed. Returns whether anything
+// was sent.
+func (c *Controller) notifyRushRoundEnd(game *GameData, state *RushState, was_finished bool, was_round int) bool {
+	if !was_finished && state.Finished {
+		// Notify everyone that the game ended and who won.
+		for _, indexed_player := range game.ToPlayer {
+			var finished RushFinishedNotification
+			finished.LoadData(game, state, indexed_player)
+			c.undispatch(game, indexed_player, finished.MessageID, 0, finished)
+		}
+
+		return true
+	}
+
+	if state.Finished || state.Round == was_round {
+		return false
+	}
+
+	// A new round began: send the results so far and everyone's new tiles.
+	for _, indexed_player := range game.ToPlayer {
+		var round RushRoundNotification
+		round.LoadData(game, state, indexed_player)
+		c.undispatch(game, indexed_player, round.MessageID, 0, round)
+
+		if indexed_player.Index >= 0 {
+			var response RushStateNotification
+			response.LoadFromGame(state, indexed_player.Index)
+			response.LoadFromController(game, indexed_player)
+			c.undispatch(game, indexed_player, response.MessageID, 0, response)
+		}
+	}
+
+	c.scheduleRushTimeout(game, state)
+	return true
+}
+
+// End the current round once it runs out of time.
+func (c *Controller) scheduleRushTimeout(game *GameData, state *RushState) {
+	if state.Deadline.IsZero() {
+		return
+	}
+
+	var gid = game.GID
+	var round = state.Round
+	if c.replay {
+		// Replays catch up with the deadline as the log's moves pass it.
+		if !time.Now().Before(state.Deadline) {
+			c.handleRushTimeout(gid, round)
+		}
+		return
+	}
+
+	time.AfterFunc(time.Until(state.Deadline), func() {
+		c.handleRushTimeout(gid, round)
+	})
+}
+
+func (c *Controller) handleRushTimeout(gid uint64, round int) {
+	c.lock.Lock()
+	game, ok := c.ToGame[gid]
+	if !ok {
+		c.lock.Unlock()
+		return
+	}
+
+	game.lock.Lock()
+	defer game.lock.Unlock()
+	c.lock.Unlock()
+
+	state, ok := game.State.(*RushState)
+	if !ok || state.Round != round || game.Paused != nil {
+		return
+	}
+
+	var was_finished = state.Finished
+	if !state.CheckTime() {
+		return
+	}
+
+	c.notifyRushRoundEnd(game, state, was_finished, round)
+	for _, indexed_player := range game.ToPlayer {
+		var synopsis RushSynopsisNotification
+		synopsis.LoadData(game, state, indexed_player)
+		c.undispatch(game, indexed_player, synopsis.MessageID, 0, synopsis)
+	}
+}
+
 func (c *Controller) doRushStart(game *GameData, state *RushState) error {
 	// First count the number of people playing.
 	var players int = 0
@@ -305,13 +588,10 @@ func (c *Controller) doRushStart(game *GameData, state *RushState) error {
 		return err
 	}
 
-	// Assign indices to players before sending notifications.
-	var player_index int = 0
-	for _, indexed_player := range game.ToPlayer {
-		if indexed_player.Admitted && indexed_player.Playing {
-			indexed_player.Index = player_index
-			player_index++
-		}
+	// Assign indices to players before sending notifications. In teams, the
+	// assign message already gave everyone their team's index.
+	if !state.Config.TeamPlay {
+		seatPlayers(game)
 	}
 
 	// Then assign indices to players who are playing and send out their
@@ -342,5 +622,65 @@ func (c *Controller) doRushStart(game *GameData, state *RushState) error {
 		c.undispatch(game, indexed_player, synopsis.MessageID, 0, synopsis)
 	}
 
+	c.scheduleRushTimeout(game, state)
 	return nil
 }
+
+// rushEngine registers Rush with the controller; see GameEngine.
+type rushEngine struct{}
+
+func init() {
+	MustRegisterGameEngine(rushEngine{})
+}
+
+func (rushEngine) Mode() GameMode {
+	return RushGame
+}
+
+func (rushEngine) Name() string {
+	return "rush"
+}
+
+func (rushEngine) Title() string {
+	return "Rush (Fast-Paced Word Game)"
+}
+
+func (rushEngine) Description() string {
+	return "In Rush, when one player draws a tile, all players must draw tiles and catch up – first to finish their board when there are no more tiles left wins!"
+}
+
+func (rushEngine) EmptyConfig() figgy.Figgurable {
+	return &RushConfig{}
+}
+
+func (rushEngine) NewState() ConfigurableState {
+	return &RushState{}
+}
+
+func (rushEngine) Init(config figgy.Figgurable) (ConfigurableState, error) {
+	var asserted *RushConfig = config.(*RushConfig)
+	var state = &RushState{}
+	return state, state.Init(*asserted)
+}
+
+func (rushEngine) Dispatch(c *Controller, message []byte, header MessageHeader, game *GameData, player *PlayerData, sid uint64) error {
+	return c.dispatchRush(message, header, game, player, sid)
+}
+
+func (rushEngine) Start(c *Controller, game *GameData) error {
+	return c.doRushStart(game, game.State.(*RushState))
+}
+
+func (rushEngine) Resume(c *Controller, game *GameData, paused time.Duration) {
+	var state = game.State.(*RushState)
+	if state.Deadline.IsZero() {
+		return
+	}
+
+	state.Deadline = state.Deadline.Add(paused)
+	c.scheduleRushTimeout(game, state)
+}
+
+func (rushEngine) Reschedule(c *Controller, game *GameData) {
+	c.scheduleRushTimeout(game, game.State.(*RushState))
+}
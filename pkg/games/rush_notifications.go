@@ -1,9 +1,15 @@
 package games
 
+import (
+	"sort"
+	"time"
+)
+
 type RushPlayerState struct {
 	Board   LetterGrid   `json:"board,omitempty"`
 	Hand    []LetterTile `json:"hand,omitempty"`
 	Unwords []string     `json:"unwords,omitempty"`
+	Version int          `json:"version,omitempty"` // Of a shared board and hand.
 }
 
 type RushGameState struct {
@@ -11,6 +17,29 @@ type RushGameState struct {
 	Config   RushConfig `json:"config"`
 	Started  bool       `json:"started"`
 	Finished bool       `json:"finished"`
+	Round    int        `json:"round"`
+
+	// When the round runs out of time, in milliseconds since the epoch.
+	Deadline int64 `json:"deadline,omitempty"`
+
+	// Commitment to the current round's seed; see ShuffleSeed.
+	Commitment string `json:"commitment,omitempty"`
+}
+
+func (rgs *RushGameState) LoadFromGame(game *RushState) {
+	rgs.DrawID = game.DrawID
+	rgs.Config = game.Config
+	rgs.Started = game.Started
+	rgs.Finished = game.Finished
+	rgs.Round = game.Round
+
+	if !game.Deadline.IsZero() {
+		rgs.Deadline = game.Deadline.UnixNano() / int64(time.Millisecond)
+	}
+
+	if len(game.RoundSeeds) > 0 {
+		rgs.Commitment = game.RoundSeeds[len(game.RoundSeeds)-1].Commitment
+	}
 }
 
 type RushStateNotification struct {
@@ -24,12 +53,10 @@ type RushStateNotification struct {
 func (rsn *RushStateNotification) LoadFromGame(game *RushState, player int) {
 	rsn.Board = game.Players[player].Board
 	rsn.Hand = game.Players[player].Hand
-	rsn.Unwords = game.Players[player].Board.FindUnwords()
+	rsn.Unwords = game.Players[player].Board.FindUnwords(game.Words())
+	rsn.Version = game.Players[player].Version
 
-	rsn.DrawID = game.DrawID
-	rsn.Config = game.Config
-	rsn.Started = game.Started
-	rsn.Finished = game.Finished
+	rsn.RushGameState.LoadFromGame(game)
 
 	if len(game.Players[player].NewTiles) > 0 {
 		rsn.Added = new(RushPlayerState)
@@ -97,7 +124,7 @@ type RushCheckNotification struct {
 }
 
 func (rcn *RushCheckNotification) LoadFromGame(game *RushState, player int) {
-	rcn.Unwords = game.Players[player].Board.FindUnwords()
+	rcn.Unwords = game.Players[player].Board.FindUnwords(game.Words())
 }
 
 func (rcn *RushCheckNotification) LoadFromController(data *GameData, player *PlayerData, err error) {
@@ -109,10 +136,132 @@ func (rcn *RushCheckNotification) LoadFromController(data *GameData, player *Pla
 	}
 }
 
+type RushHintNotification struct {
+	MessageHeader
+
+	Hints []RushHint `json:"hints"`
+}
+
+func (rhn *RushHintNotification) LoadFromController(data *GameData, player *PlayerData, hints []RushHint) {
+	rhn.LoadHeader(data, player)
+	rhn.MessageType = "hint"
+
+	rhn.Hints = hints
+	if rhn.Hints == nil {
+		rhn.Hints = make([]RushHint, 0)
+	}
+}
+
+// Live feedback on a player's board while they build it: words which aren't
+// valid and groups of tiles (by identifier) which aren't connected to the
+// rest of the board.
+type RushFeedbackNotification struct {
+	MessageHeader
+
+	Unwords []string `json:"unwords"`
+	Islands [][]int  `json:"islands"`
+}
+
+func (rfn *RushFeedbackNotification) LoadFromGame(game *RushState, player int) {
+	rfn.Unwords = game.Players[player].Board.FindUnwords(game.Words())
+	if rfn.Unwords == nil {
+		rfn.Unwords = make([]string, 0)
+	}
+	sort.Strings(rfn.Unwords)
+
+	rfn.Islands = make([][]int, 0)
+	var islands = game.Players[player].Board.Islands()
+	if len(islands) > 1 {
+		for _, island := range islands[1:] {
+			sort.Ints(island)
+			rfn.Islands = append(rfn.Islands, island)
+		}
+	}
+}
+
+func (rfn *RushFeedbackNotification) LoadFromController(data *GameData, player *PlayerData) {
+	rfn.LoadHeader(data, player)
+	rfn.MessageType = "feedback"
+}
+
+// One row of the results table, ordered by rank.
+type RushResult struct {
+	User        uint64   `json:"user"`
+	Team        []uint64 `json:"team,omitempty"` // Everyone on User's team.
+	Rank        int      `json:"rank"`           // From one; tied players share a rank.
+	Score       int      `json:"score"`
+	RoundScores []int    `json:"round_scores"`
+}
+
+// Users playing from the given index, when playing in teams.
+func rushTeamMembers(data *GameData, state *RushState, index int) []uint64 {
+	if !state.Config.TeamPlay || index < 0 {
+		return nil
+	}
+
+	var members []uint64
+	for _, indexed_player := range data.ToPlayer {
+		if indexed_player.Index == index {
+			members = append(members, indexed_player.UID)
+		}
+	}
+
+	sort.Slice(members, func(i, j int) bool { return members[i] < members[j] })
+	return members
+}
+
+func rushResults(data *GameData, state *RushState) []RushResult {
+	var results = make([]RushResult, 0, len(state.Players))
+
+	order, ranks := state.Rankings()
+	for index, player := range order {
+		var result RushResult
+		result.User, _ = data.ToUserID(player)
+		result.Team = rushTeamMembers(data, state, player)
+		result.Rank = ranks[index]
+		result.Score = state.TotalScore(player)
+
+		result.RoundScores = make([]int, len(state.RoundScores))
+		for round, scores := range state.RoundScores {
+			if player < len(scores) {
+				result.RoundScores[round] = scores[player]
+			}
+		}
+
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// Sent when a round finishes and the next one begins.
+type RushRoundNotification struct {
+	MessageHeader
+
+	Round   int            `json:"round"` // The round which just finished.
+	Results []RushResult   `json:"results"`
+	Seeds   []*ShuffleSeed `json:"seeds"` // Of every round which is over.
+}
+
+func (rrn *RushRoundNotification) LoadData(data *GameData, state *RushState, player *PlayerData) {
+	rrn.LoadHeader(data, player)
+	rrn.MessageType = "round-finished"
+
+	rrn.Round = len(state.RoundScores)
+	rrn.Results = rushResults(data, state)
+	rrn.Seeds = state.RevealedSeeds()
+}
+
 type RushFinishedNotification struct {
 	MessageHeader
 
-	Winner uint64 `json:"winner"`
+	Winner      uint64         `json:"winner"`
+	WinningTeam []uint64       `json:"winning_team,omitempty"`
+	Results     []RushResult   `json:"results"`
+	Seeds       []*ShuffleSeed `json:"seeds"`
+
+	// Words this player could have made from their tiles.
+	PossibleWords []string `json:"possible_words,omitempty"`
 }
 
 func (rwn *RushFinishedNotification) LoadData(data *GameData, state *RushState, player *PlayerData) {
@@ -120,6 +269,10 @@ func (rwn *RushFinishedNotification) LoadData(data *GameData, state *RushState,
 	rwn.MessageType = "finished"
 
 	rwn.Winner, _ = data.ToUserID(state.Winner)
+	rwn.WinningTeam = rushTeamMembers(data, state, state.Winner)
+	rwn.Results = rushResults(data, state)
+	rwn.Seeds = state.RevealedSeeds()
+	rwn.PossibleWords = state.PossibleWords(player.Index)
 }
 
 type RushGameStateNotification struct {
@@ -129,25 +282,25 @@ type RushGameStateNotification struct {
 	Players   []RushPlayerState `json:"player_data"`
 	PlayerMap map[int]uint64    `json:"player_map"`
 	Winner    uint64            `json:"winner,omitempty"`
+	Results   []RushResult      `json:"results,omitempty"`
 
 	// Used to map winner from internal state index to external UID
 	winner int
+	state  *RushState
 }
 
 func (rgsn *RushGameStateNotification) LoadFromGame(game *RushState) {
-	rgsn.DrawID = game.DrawID
-	rgsn.Config = game.Config
-	rgsn.Started = game.Started
-	rgsn.Finished = game.Finished
+	rgsn.RushGameState.LoadFromGame(game)
 
 	rgsn.Players = make([]RushPlayerState, game.Config.NumPlayers)
 	for index, player := range game.Players {
 		rgsn.Players[index].Board = player.Board
 		rgsn.Players[index].Hand = player.Hand
-		rgsn.Players[index].Unwords = player.Board.FindUnwords()
+		rgsn.Players[index].Unwords = player.Board.FindUnwords(game.Words())
 	}
 
 	rgsn.winner = game.Winner
+	rgsn.state = game
 }
 
 func (rgsn *RushGameStateNotification) LoadFromController(data *GameData, player *PlayerData) {
@@ -164,4 +317,8 @@ func (rgsn *RushGameStateNotification) LoadFromController(data *GameData, player
 			}
 		}
 	}
+
+	if rgsn.state != nil && len(rgsn.state.RoundScores) > 0 {
+		rgsn.Results = rushResults(data, rgsn.state)
+	}
 }
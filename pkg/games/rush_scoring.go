@@ -0,0 +1,128 @@
+package games
+
+import (
+	"sort"
+)
+
+// Ways of scoring a game of Rush; see RushConfig.Scoring.
+const (
+	RushFirstOut     int = iota // 0 -- the first player to use all their tiles wins.
+	RushWordCount    int = iota // 1 -- one point per valid word.
+	RushWordLength   int = iota // 2 -- one point per letter of each valid word.
+	RushLetterValues int = iota // 3 -- Scrabble letter values of each valid word.
+	rushScoringRange int = iota
+)
+
+// Scrabble letter values, used with RushLetterValues scoring. Blank tiles are
+// worth nothing and letters outside this table are worth a single point.
+var rushLetterValues = map[string]int{
+	"A": 1, "B": 3, "C": 3, "D": 2, "E": 1, "F": 4, "G": 2, "H": 4, "I": 1,
+	"J": 8, "K": 5, "L": 1, "M": 3, "N": 1, "O": 1, "P": 3, "Q": 10, "R": 1,
+	"S": 1, "T": 1, "U": 1, "V": 4, "W": 4, "X": 8, "Y": 4, "Z": 10,
+}
+
+func rushTileValue(tile LetterTile) int {
+	if tile.Blank {
+		return 0
+	}
+
+	if value, ok := rushLetterValues[tile.Value]; ok {
+		return value
+	}
+
+	return 1
+}
+
+// Score a single word on a board, running from start to end.
+func rushWordScore(scoring int, lg *LetterGrid, start LetterPos, end LetterPos) int {
+	switch scoring {
+	case RushWordCount:
+		return 1
+	case RushWordLength, RushLetterValues:
+		var score = 0
+		for pos := start; pos.X <= end.X && pos.Y <= end.Y; {
+			if scoring == RushWordLength {
+				score += 1
+			} else {
+				score += rushTileValue(lg.ToTile[lg.AtPosition[pos]])
+			}
+
+			if start.X == end.X {
+				pos.Y++
+			} else {
+				pos.X++
+			}
+		}
+		return score
+	}
+
+	return 0
+}
+
+// Score a player's board. Only valid words count. Without a scoring method,
+// the score is the number of tiles the player got onto their board.
+func (rs *RushState) ScoreBoard(player int) int {
+	if player < 0 || player >= len(rs.Players) {
+		return 0
+	}
+
+	var board = &rs.Players[player].Board
+	if rs.Config.Scoring == RushFirstOut {
+		return len(board.Tiles)
+	}
+
+	var words = rs.Words()
+	var score = 0
+	_ = board.VisitAllWordsOnBoard(func(lg *LetterGrid, start LetterPos, end LetterPos, word string) error {
+		if words.IsWord(word) {
+			score += rushWordScore(rs.Config.Scoring, lg, start, end)
+		}
+
+		return nil
+	})
+
+	return score
+}
+
+// Total score of a player over all rounds played so far.
+func (rs *RushState) TotalScore(player int) int {
+	var total = 0
+	for _, round := range rs.RoundScores {
+		if player >= 0 && player < len(round) {
+			total += round[player]
+		}
+	}
+
+	return total
+}
+
+// Rank players by their total score, best first. Tied players share a rank.
+// Without a scoring method, the winner always ranks first.
+func (rs *RushState) Rankings() (order []int, ranks []int) {
+	order = make([]int, len(rs.Players))
+	for index := range order {
+		order[index] = index
+	}
+
+	var better = func(left int, right int) bool {
+		if rs.Config.Scoring == RushFirstOut && rs.Winner >= 0 && (left == rs.Winner) != (right == rs.Winner) {
+			return left == rs.Winner
+		}
+
+		return rs.TotalScore(left) > rs.TotalScore(right)
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		return better(order[i], order[j])
+	})
+
+	ranks = make([]int, len(order))
+	for index, player := range order {
+		ranks[index] = index + 1
+		if index > 0 && !better(order[index-1], player) {
+			ranks[index] = ranks[index-1]
+		}
+	}
+
+	return order, ranks
+}
@@ -0,0 +1,371 @@
+package games
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/dghubble/trie"
+)
+
+// A word a player could play, found by the Rush solver. Words formed only
+// from tiles in the hand have no position; words attached to the board cross
+// an existing tile and start at the given position, reading either left to
+// right or top to bottom.
+type RushHint struct {
+	Word     string    `json:"word"`
+	Attached bool      `json:"attached"`
+	Start    LetterPos `json:"start"`
+	Vertical bool      `json:"vertical"`
+}
+
+// Count the tiles by their letter. Blank tiles are counted under the empty
+// string, whatever letter they were assigned.
+func rushLetterCounts(tiles []LetterTile) map[string]int {
+	var ret = make(map[string]int)
+	for _, tile := range tiles {
+		if tile.Blank {
+			ret[""]++
+		} else {
+			ret[strings.ToUpper(tile.Value)]++
+		}
+	}
+
+	return ret
+}
+
+// Letters which are missing from counts to spell word, after using up any
+// blanks; returns early once more than limit letters are missing.
+func rushMissingLetters(word string, counts map[string]int, limit int) []string {
+	var used = make(map[string]int)
+	var blanks = counts[""]
+	var ret []string
+	for _, letter := range word {
+		var value = string(letter)
+		used[value]++
+		if used[value] > counts[value] {
+			if blanks > 0 {
+				blanks--
+				continue
+			}
+
+			ret = append(ret, value)
+			if len(ret) > limit {
+				return ret
+			}
+		}
+	}
+
+	return ret
+}
+
+// A dictionary word along with how many of each letter it uses, so it can be
+// checked against a hand without allocating.
+type rushIndexedWord struct {
+	word    string
+	length  int       // In letters rather than bytes.
+	letters [26]uint8 // How many of each of A through Z the word uses.
+	simple  bool      // Whether the word only uses A through Z; otherwise letters is incomplete.
+}
+
+func newRushIndexedWord(word string) rushIndexedWord {
+	var ret = rushIndexedWord{word: word, simple: true}
+	for _, letter := range word {
+		ret.length++
+		if letter >= 'A' && letter <= 'Z' && ret.letters[letter-'A'] < 255 {
+			ret.letters[letter-'A']++
+		} else {
+			ret.simple = false
+		}
+	}
+
+	return ret
+}
+
+// Whether left comes before right in the order hints are given in (see
+// sortRushHints).
+func rushWordBefore(left *rushIndexedWord, right *rushIndexedWord) bool {
+	if len(left.word) != len(right.word) {
+		return len(left.word) > len(right.word)
+	}
+
+	return left.word < right.word
+}
+
+// Like rushMissingLetters, given the array form of counts from
+// rushLetterArray as well.
+func (word *rushIndexedWord) missing(counts map[string]int, letters *[26]int, limit int) []string {
+	if !word.simple {
+		return rushMissingLetters(word.word, counts, limit)
+	}
+
+	var blanks = counts[""]
+	var ret []string
+	for index, wanted := range word.letters {
+		for short := int(wanted) - letters[index]; short > 0; short-- {
+			if blanks > 0 {
+				blanks--
+				continue
+			}
+
+			ret = append(ret, string(rune('A'+index)))
+			if len(ret) > limit {
+				return ret
+			}
+		}
+	}
+
+	return ret
+}
+
+// The counts of A through Z from rushLetterCounts.
+func rushLetterArray(counts map[string]int) [26]int {
+	var ret [26]int
+	for letter, count := range counts {
+		if len(letter) == 1 && letter[0] >= 'A' && letter[0] <= 'Z' {
+			ret[letter[0]-'A'] = count
+		}
+	}
+
+	return ret
+}
+
+// Every word of a dictionary, in the order hints are given in. Hints are
+// requested while holding the game lock, so rather than walking the whole
+// dictionary for each one, this is built once per dictionary and searched in
+// order until enough words are found.
+type rushWordIndex struct {
+	dict  *trie.RuneTrie
+	words []rushIndexedWord
+}
+
+var rushIndexLock sync.Mutex
+var rushIndexes = make(map[string]*rushWordIndex)
+
+func loadRushWordIndex(name string) (*rushWordIndex, error) {
+	dict, err := loadDictionary(name)
+	if err != nil {
+		return nil, err
+	}
+
+	rushIndexLock.Lock()
+	defer rushIndexLock.Unlock()
+
+	// Reloading a dictionary replaces it; rebuild the index to match.
+	if index, ok := rushIndexes[name]; ok && index.dict == dict {
+		return index, nil
+	}
+
+	var index = &rushWordIndex{dict: dict}
+	_ = dict.Walk(func(key string, value interface{}) error {
+		index.words = append(index.words, newRushIndexedWord(key))
+		return nil
+	})
+
+	sort.Slice(index.words, func(i, j int) bool {
+		return rushWordBefore(&index.words[i], &index.words[j])
+	})
+
+	rushIndexes[name] = index
+	return index, nil
+}
+
+// Visit the words of the list in the order hints are given in, until visit
+// returns false.
+func (wl WordList) walkHintOrder(visit func(word *rushIndexedWord) bool) error {
+	index, err := loadRushWordIndex(wl.name())
+	if err != nil {
+		return err
+	}
+
+	// Words the room adds are merged in with the dictionary's.
+	var denied = wl.deniedSet()
+	var extra []rushIndexedWord
+	for _, word := range wl.Allowed {
+		word = strings.ToUpper(strings.TrimSpace(word))
+		if word != "" && index.dict.Get(word) == nil && !denied[word] {
+			extra = append(extra, newRushIndexedWord(word))
+		}
+	}
+
+	sort.Slice(extra, func(i, j int) bool {
+		return rushWordBefore(&extra[i], &extra[j])
+	})
+
+	var next = 0
+	for offset := range index.words {
+		var word = &index.words[offset]
+		for ; next < len(extra) && rushWordBefore(&extra[next], word); next++ {
+			if !visit(&extra[next]) {
+				return nil
+			}
+		}
+
+		if denied[word.word] {
+			continue
+		}
+
+		if !visit(word) {
+			return nil
+		}
+	}
+
+	for ; next < len(extra); next++ {
+		if !visit(&extra[next]) {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// Whether word can be placed so that its index'th letter lands on the
+// existing tile at pos. Every other letter must go on an empty square, and
+// none of them may touch another tile, so the only word this forms is word
+// itself.
+func rushCanAttach(board *LetterGrid, word []rune, index int, pos LetterPos, vertical bool) bool {
+	var step = LetterPos{1, 0}
+	var side = LetterPos{0, 1}
+	if vertical {
+		step, side = side, step
+	}
+
+	var at = func(offset int) LetterPos {
+		return LetterPos{pos.X + step.X*(offset-index), pos.Y + step.Y*(offset-index)}
+	}
+
+	var occupied = func(where LetterPos) bool {
+		_, ok := board.AtPosition[where]
+		return ok
+	}
+
+	if occupied(at(-1)) || occupied(at(len(word))) {
+		return false
+	}
+
+	for offset := range word {
+		if offset == index {
+			continue
+		}
+
+		var where = at(offset)
+		if occupied(where) {
+			return false
+		}
+
+		if occupied(LetterPos{where.X + side.X, where.Y + side.Y}) || occupied(LetterPos{where.X - side.X, where.Y - side.Y}) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Find words the player could make with the tiles in their hand, either on
+// their own or by crossing a single tile already on their board. Longer words
+// come first; at most limit hints are returned.
+func SolveRush(words WordList, hand []LetterTile, board *LetterGrid, limit int) []RushHint {
+	board.ReInit()
+
+	var counts = rushLetterCounts(hand)
+	var letters = rushLetterArray(counts)
+	var ret []RushHint
+
+	// Words are visited in the order hints are given in, and each word makes
+	// at most one hint, so the search can stop once it has enough.
+	_ = words.walkHintOrder(func(indexed *rushIndexedWord) bool {
+		if limit >= 0 && len(ret) >= limit {
+			return false
+		}
+
+		// At most one letter can come from the board.
+		if indexed.length < 2 || indexed.length > len(hand)+1 {
+			return true
+		}
+
+		var word = indexed.word
+		var missing = indexed.missing(counts, &letters, 1)
+		if len(missing) == 0 {
+			ret = append(ret, RushHint{Word: word})
+			return true
+		}
+
+		if len(missing) > 1 {
+			return true
+		}
+
+		var runes = []rune(word)
+
+		// Exactly one letter short; see if a board tile can supply it.
+		for _, tile := range board.Tiles {
+			if strings.ToUpper(tile.Value) != missing[0] {
+				continue
+			}
+
+			var pos = board.PositionsOf[tile.ID]
+			for index, letter := range runes {
+				if string(letter) != missing[0] {
+					continue
+				}
+
+				for _, vertical := range []bool{false, true} {
+					if rushCanAttach(board, runes, index, pos, vertical) {
+						var start = LetterPos{pos.X - index, pos.Y}
+						if vertical {
+							start = LetterPos{pos.X, pos.Y - index}
+						}
+
+						ret = append(ret, RushHint{word, true, start, vertical})
+						return true
+					}
+				}
+			}
+		}
+
+		return true
+	})
+
+	sortRushHints(ret)
+	if limit >= 0 && len(ret) > limit {
+		ret = ret[:limit]
+	}
+
+	return ret
+}
+
+// Find the longest words which could be made from all of a player's tiles,
+// both in their hand and on their board.
+func RushPossibleWords(words WordList, player *RushPlayer, limit int) []string {
+	var tiles = append(append([]LetterTile{}, player.Hand...), player.Board.Tiles...)
+	var counts = rushLetterCounts(tiles)
+	var letters = rushLetterArray(counts)
+	var ret = make([]string, 0, limit)
+
+	_ = words.walkHintOrder(func(word *rushIndexedWord) bool {
+		if len(ret) >= limit {
+			return false
+		}
+
+		if word.length >= 2 && word.length <= len(tiles) && len(word.missing(counts, &letters, 0)) == 0 {
+			ret = append(ret, word.word)
+		}
+
+		return true
+	})
+
+	return ret
+}
+
+func sortRushHints(hints []RushHint) {
+	sort.SliceStable(hints, func(i, j int) bool {
+		if len(hints[i].Word) != len(hints[j].Word) {
+			return len(hints[i].Word) > len(hints[j].Word)
+		}
+
+		if hints[i].Word != hints[j].Word {
+			return hints[i].Word < hints[j].Word
+		}
+
+		return !hints[i].Attached && hints[j].Attached
+	})
+}
@@ -0,0 +1,91 @@
+package games
+
+import (
+	"testing"
+)
+
+func TestSolveRush(t *testing.T) {
+	var board LetterGrid
+	board.Init()
+	board.AddTile(LetterTile{ID: 1, Value: "C"}, 0, 0)
+	board.AddTile(LetterTile{ID: 2, Value: "A"}, 1, 0)
+	board.AddTile(LetterTile{ID: 3, Value: "T"}, 2, 0)
+
+	var hand = []LetterTile{{ID: 4, Value: "E"}, {ID: 5, Value: "D"}, {ID: 6, Value: "O"}, {ID: 7, Value: "G"}, {ID: 8, Value: "T"}}
+
+	var found = make(map[string]RushHint)
+	for _, hint := range SolveRush(WordList{}, hand, &board, -1) {
+		found[hint.Word] = hint
+	}
+
+	for _, word := range []string{"DOG", "GOD", "TO", "DO", "GO"} {
+		if hint, ok := found[word]; !ok || hint.Attached {
+			t.Fatal("Expected to make", word, "from the hand alone:", found)
+		}
+	}
+
+	if hint, ok := found["EAT"]; !ok || !hint.Attached || !hint.Vertical || hint.Start != (LetterPos{1, -1}) {
+		t.Fatal("Expected to attach EAT down through the A:", hint, ok)
+	}
+
+	if hint, ok := found["TEA"]; !ok || !hint.Attached || !hint.Vertical || hint.Start != (LetterPos{1, -2}) {
+		t.Fatal("Expected to attach TEA down through the A:", hint, ok)
+	}
+
+	// CAT can't be attached anywhere: it'd need a second C.
+	if _, ok := found["CAT"]; ok {
+		t.Fatal("Didn't expect to find CAT:", found)
+	}
+
+	var limited = SolveRush(WordList{}, hand, &board, 2)
+	if len(limited) != 2 || len(limited[0].Word) < len(limited[1].Word) {
+		t.Fatal("Expected the two longest hints:", limited)
+	}
+}
+
+func TestRushHints(t *testing.T) {
+	var state RushState
+	if err := state.Init(RushConfig{NumPlayers: 2, NumTiles: 75, Frequency: 1, StartSize: 12, DrawSize: 1, DiscardPenalty: 3, Hints: true, HintDelay: 60}); err != nil {
+		t.Fatal("Unable to initialize rush:", err)
+	}
+
+	if err := state.Start(2); err != nil {
+		t.Fatal("Unable to start rush:", err)
+	}
+
+	if _, err := state.Hint(0); err != nil {
+		t.Fatal("Unable to get a hint:", err)
+	}
+
+	if _, err := state.Hint(0); err == nil {
+		t.Fatal("Expected the second hint to be rate limited")
+	}
+
+	if _, err := state.Hint(1); err != nil {
+		t.Fatal("Expected hints to be rate limited per player:", err)
+	}
+
+	state.Players[1].Hand = []LetterTile{{ID: 1000, Value: "D"}, {ID: 1001, Value: "O"}, {ID: 1002, Value: "G"}, {ID: 1003, Value: "S"}}
+	if words := state.PossibleWords(1); words != nil {
+		t.Fatal("Expected no summary before the game ends:", words)
+	}
+
+	state.Finished = true
+	var words = state.PossibleWords(1)
+	if len(words) == 0 || words[0] != "DOGS" {
+		t.Fatal("Expected DOGS to be the best word:", words)
+	}
+}
+
+func TestSolveRushRoomWords(t *testing.T) {
+	var board LetterGrid
+	board.Init()
+
+	var hand = []LetterTile{{ID: 1, Value: "D"}, {ID: 2, Value: "O"}, {ID: 3, Value: "G"}, {ID: 4, Value: "Z"}}
+	var words = WordList{RoomWords: RoomWords{Allowed: []string{"zgod"}, Denied: []string{"dog"}}}
+
+	var hints = SolveRush(words, hand, &board, 2)
+	if len(hints) != 2 || hints[0].Word != "ZGOD" || hints[1].Word != "GOD" {
+		t.Fatal("Expected the room's words to be merged in order:", hints)
+	}
+}
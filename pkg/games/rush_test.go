@@ -1,8 +1,10 @@
 package games
 
 import (
+	"encoding/json"
 	"strings"
 	"testing"
+	"time"
 )
 
 func hasLetterInHand(game *RushState, player int, letter string) int {
@@ -48,19 +50,19 @@ func validateTileIDs(t *testing.T, game *RushState) {
 	}
 
 	if !haveC {
-		game.Tiles = append(game.Tiles, LetterTile{len(game.Tiles) + 1, "", "C", 0})
+		game.Tiles = append(game.Tiles, LetterTile{len(game.Tiles) + 1, "", "C", 0, false})
 	}
 
 	if !haveA {
-		game.Tiles = append(game.Tiles, LetterTile{len(game.Tiles) + 1, "", "A", 0})
+		game.Tiles = append(game.Tiles, LetterTile{len(game.Tiles) + 1, "", "A", 0, false})
 	}
 
 	if !haveT {
-		game.Tiles = append(game.Tiles, LetterTile{len(game.Tiles) + 1, "", "T", 0})
+		game.Tiles = append(game.Tiles, LetterTile{len(game.Tiles) + 1, "", "T", 0, false})
 	}
 
 	if !haveZ {
-		game.Tiles = append(game.Tiles, LetterTile{len(game.Tiles) + 1, "", "Z", 0})
+		game.Tiles = append(game.Tiles, LetterTile{len(game.Tiles) + 1, "", "Z", 0, false})
 	}
 }
 
@@ -108,7 +110,7 @@ func TestRushGame(t *testing.T) {
 	// We should now have all tiles in our hand. Play them on the board.
 	{
 		tileID := hasLetterInHand(&game, player, "C")
-		if err := game.PlayTile(player, tileID, 0, 0); err != nil {
+		if err := game.PlayTile(player, tileID, 0, 0, ""); err != nil {
 			t.Fatal("Unable to play C tile on board", err, tileID)
 		}
 
@@ -118,7 +120,7 @@ func TestRushGame(t *testing.T) {
 		}
 
 		tileID = hasLetterInHand(&game, player, "T")
-		if err := game.PlayTile(player, tileID, 0, 2); err != nil {
+		if err := game.PlayTile(player, tileID, 0, 2, ""); err != nil {
 			t.Fatal("Unable to play T tile on board", err, tileID)
 		}
 
@@ -129,7 +131,7 @@ func TestRushGame(t *testing.T) {
 		}
 
 		tileID = hasLetterInHand(&game, player, "A")
-		if err := game.PlayTile(player, tileID, 0, 1); err != nil {
+		if err := game.PlayTile(player, tileID, 0, 1, ""); err != nil {
 			t.Fatal("Unable to play A tile on board:", err, tileID)
 		}
 
@@ -142,7 +144,7 @@ func TestRushGame(t *testing.T) {
 	// Now play the Z and make sure the board isn't valid still.
 	{
 		tileID := hasLetterInHand(&game, player, "Z")
-		if err := game.PlayTile(player, tileID, 0, 3); err != nil {
+		if err := game.PlayTile(player, tileID, 0, 3, ""); err != nil {
 			t.Fatal("Unable to play Z tile on board:", err, tileID)
 		}
 
@@ -175,3 +177,363 @@ func TestRushGame(t *testing.T) {
 		}
 	}
 }
+
+func TestRushFeedback(t *testing.T) {
+	var c Controller
+	c.Init()
+
+	var config = RushConfig{NumPlayers: 2, NumTiles: 75, Frequency: 1, StartSize: 12, DrawSize: 1, DiscardPenalty: 3}
+	if err := c.addGame("rush", 1, 1, &config); err != nil {
+		t.Fatal("Unable to add game:", err)
+	}
+
+	var game = c.ToGame[1]
+	var notifications = make(chan interface{}, 64)
+	game.ToPlayer[1] = &PlayerData{UID: 1, Index: 0, Admitted: true, Playing: true, Notifications: map[uint64]chan interface{}{1: notifications}}
+	game.ToPlayer[2] = &PlayerData{UID: 2, Index: 1, Admitted: true, Playing: true}
+
+	var state = game.State.(*RushState)
+	if err := state.Start(2); err != nil {
+		t.Fatal("Unable to start game:", err)
+	}
+	state.Players[0].Hand = []LetterTile{{ID: 1001, Value: "C"}, {ID: 1002, Value: "A"}, {ID: 1003, Value: "T"}, {ID: 1004, Value: "Z"}}
+
+	var send = func(message interface{}) (*RushFeedbackNotification, *ControllerNotifyWord) {
+		data, err := json.Marshal(message)
+		if err != nil {
+			t.Fatal("Unable to marshal message:", err)
+		}
+
+		if _, err := c.Dispatch(data, 1, 1, 1); err != nil {
+			t.Fatal("Unexpected error dispatching", string(data), err)
+		}
+
+		var feedback *RushFeedbackNotification
+		var word *ControllerNotifyWord
+		for len(notifications) > 0 {
+			switch notification := (<-notifications).(type) {
+			case RushFeedbackNotification:
+				feedback = &notification
+			case ControllerNotifyWord:
+				word = &notification
+			}
+		}
+
+		return feedback, word
+	}
+
+	var play = func(tileID int, x int, y int) *RushFeedbackNotification {
+		feedback, _ := send(RushPlay{MessageHeader{Mode: "rush", ID: 1, Player: 1, MessageType: "play"}, tileID, x, y, "", 0})
+		return feedback
+	}
+
+	if feedback := play(1001, 0, 0); feedback != nil {
+		t.Fatal("Didn't expect feedback for a single valid tile:", feedback)
+	}
+
+	if feedback := play(1002, 1, 0); feedback == nil || len(feedback.Unwords) != 1 || feedback.Unwords[0] != "CA" {
+		t.Fatal("Expected CA to be flagged as invalid:", feedback)
+	}
+
+	if feedback := play(1003, 2, 0); feedback == nil || len(feedback.Unwords) != 0 || len(feedback.Islands) != 0 {
+		t.Fatal("Expected CAT to clear the feedback:", feedback)
+	}
+
+	if feedback := play(1004, 5, 5); feedback == nil || len(feedback.Islands) != 1 || len(feedback.Islands[0]) != 1 || feedback.Islands[0][0] != 1004 {
+		t.Fatal("Expected Z to be flagged as an island:", feedback)
+	}
+
+	// Moving the island elsewhere doesn't change the feedback, so nothing is
+	// sent.
+	if feedback, _ := send(RushMove{MessageHeader{Mode: "rush", ID: 1, Player: 1, MessageType: "move"}, 1004, 6, 6, "", 0}); feedback != nil {
+		t.Fatal("Didn't expect repeated feedback:", feedback)
+	}
+
+	for word, valid := range map[string]bool{"cat": true, "catz": false} {
+		_, response := send(GameIsWord{MessageHeader{Mode: "rush", ID: 1, Player: 1, MessageType: "word"}, word})
+		if response == nil || response.Word != word || response.Valid != valid || response.MessageType != "word" {
+			t.Fatal("Unexpected response to word check for", word, response)
+		}
+	}
+}
+
+func TestRushBlanks(t *testing.T) {
+	var state RushState
+	if err := state.Init(RushConfig{NumPlayers: 2, NumTiles: 75, Frequency: 1, StartSize: 12, DrawSize: 1, DiscardPenalty: 3, Blanks: 4}); err != nil {
+		t.Fatal("Unable to initialize rush:", err)
+	}
+
+	if err := state.Start(2); err != nil {
+		t.Fatal("Unable to start rush:", err)
+	}
+
+	var blanks = 0
+	for _, tiles := range [][]LetterTile{state.Tiles, state.Players[0].Hand, state.Players[1].Hand} {
+		for _, tile := range tiles {
+			if tile.Blank {
+				blanks++
+				if tile.Value != "" || tile.Display != blankTileDisplay {
+					t.Fatal("Expected unassigned blank tile:", tile)
+				}
+			}
+		}
+	}
+
+	if blanks != 4 {
+		t.Fatal("Expected four blank tiles but got", blanks)
+	}
+
+	state.Players[0].Hand = []LetterTile{{ID: 1001, Value: "C"}, {ID: 1002, Blank: true, Display: blankTileDisplay}, {ID: 1003, Value: "T"}}
+	state.Players[0].Board.Init()
+
+	if err := state.PlayTile(0, 1002, 1, 0, ""); err == nil {
+		t.Fatal("Expected to need a letter for the blank tile")
+	}
+
+	if err := state.PlayTile(0, 1001, 0, 0, "C"); err == nil {
+		t.Fatal("Expected only blank tiles to take a letter")
+	}
+
+	if err := state.PlayTile(0, 1002, 1, 0, "7"); err == nil || state.Players[0].Hand[1].Value != "" || state.Players[0].Hand[1].Display != blankTileDisplay {
+		t.Fatal("Expected a rejected letter to leave the blank alone:", err, state.Players[0].Hand[1])
+	}
+
+	for _, play := range []struct {
+		id     int
+		x      int
+		letter string
+	}{{1001, 0, ""}, {1002, 1, "a"}, {1003, 2, ""}} {
+		if err := state.PlayTile(0, play.id, play.x, 0, play.letter); err != nil {
+			t.Fatal("Unable to play tile:", err)
+		}
+	}
+
+	var blank = state.Players[0].Board.ToTile[1002]
+	if blank.Value != "A" || blank.Display != "a" {
+		t.Fatal("Expected the blank to be assigned an A:", blank)
+	}
+
+	if err := state.IsValidBoard(0); err != nil {
+		t.Fatal("Expected CAT to be valid with a blank:", err)
+	}
+
+	// Reassigning the blank changes the words on the board.
+	if err := state.MoveTile(0, 1002, 1, 0, "x"); err != nil {
+		t.Fatal("Unable to reassign blank:", err)
+	}
+
+	if err := state.HasValidWords(0); err == nil {
+		t.Fatal("Expected CXT to be invalid")
+	}
+
+	if err := state.RecallTile(0, 1002); err != nil {
+		t.Fatal("Unable to recall blank:", err)
+	}
+
+	if index, ok := state.Players[0].FindTile(1002); !ok || state.Players[0].Hand[index].Value != "" || state.Players[0].Hand[index].Display != blankTileDisplay {
+		t.Fatal("Expected the recalled blank to lose its letter")
+	}
+
+	// The solver uses blanks as any letter.
+	var board LetterGrid
+	board.Init()
+	var found = false
+	for _, hint := range SolveRush(WordList{}, []LetterTile{{ID: 1, Value: "O"}, {ID: 2, Blank: true}, {ID: 3, Value: "G"}}, &board, -1) {
+		found = found || hint.Word == "DOG"
+	}
+
+	if !found {
+		t.Fatal("Expected to find DOG using the blank")
+	}
+}
+
+func TestRushScoring(t *testing.T) {
+	var config = RushConfig{NumPlayers: 2, NumTiles: 75, Frequency: 1, StartSize: 12, DrawSize: 1, DiscardPenalty: 3, Scoring: RushFirstOut, TimeLimit: 5, Rounds: 1}
+	if err := config.Validate(); err == nil {
+		t.Fatal("Expected a time limit to need a scoring method")
+	}
+
+	config.TimeLimit = 0
+	config.Rounds = 2
+	if err := config.Validate(); err == nil {
+		t.Fatal("Expected multiple rounds to need a scoring method")
+	}
+
+	config.Scoring = RushLetterValues
+	config.TimeLimit = 5
+
+	var state RushState
+	if err := state.Init(config); err != nil {
+		t.Fatal("Unable to initialize rush:", err)
+	}
+
+	if err := state.Start(2); err != nil {
+		t.Fatal("Unable to start rush:", err)
+	}
+
+	if state.Round != 1 || state.Deadline.IsZero() {
+		t.Fatal("Expected the first round to have a deadline:", state.Round, state.Deadline)
+	}
+
+	var place = func(player int, x int, y int, letters string) {
+		for index, letter := range letters {
+			var id = 1000 + 100*player + len(state.Players[player].Board.Tiles)
+			state.Players[player].Board.AddTile(LetterTile{ID: id, Value: string(letter), Display: string(letter)}, x+index, y)
+		}
+	}
+
+	// CAT and AT score 3+1+1 and 1+1; DOG scores 2+1+2 and ZQ isn't a word.
+	state.Players[0].Board.Init()
+	place(0, 0, 0, "CAT")
+	state.Players[0].Board.AddTile(LetterTile{ID: 1099, Value: "T", Display: "T"}, 1, 1)
+	state.Players[1].Board.Init()
+	place(1, 0, 0, "DOG")
+	place(1, 0, 5, "ZQ")
+
+	if score := state.ScoreBoard(0); score != 7 {
+		t.Fatal("Expected player 0 to score 7 but got", score)
+	}
+
+	state.Config.Scoring = RushWordCount
+	if score := state.ScoreBoard(0); score != 2 {
+		t.Fatal("Expected player 0 to have 2 words but got", score)
+	}
+
+	state.Config.Scoring = RushWordLength
+	if score := state.ScoreBoard(1); score != 3 {
+		t.Fatal("Expected player 1 to have 3 letters but got", score)
+	}
+	state.Config.Scoring = RushLetterValues
+
+	// Player 1 going out ends the first round; scores carry into the second.
+	state.endRound(1)
+	if state.Finished || state.Round != 2 || len(state.Players[0].Board.Tiles) != 0 || len(state.Players[0].Hand) != 12 {
+		t.Fatal("Expected a fresh second round:", state.Finished, state.Round)
+	}
+
+	if len(state.RoundScores) != 1 || state.RoundScores[0][0] != 7 || state.RoundScores[0][1] != 5 {
+		t.Fatal("Unexpected round scores:", state.RoundScores)
+	}
+
+	// Running out of time ends the match.
+	place(1, 0, 0, "AT")
+	state.Deadline = time.Now().Add(-time.Second)
+	if !state.CheckTime() || !state.Finished {
+		t.Fatal("Expected the match to end when time ran out")
+	}
+
+	if state.Winner != 0 || state.TotalScore(0) != 7 || state.TotalScore(1) != 7 {
+		t.Fatal("Expected the earliest tied player to win:", state.Winner, state.RoundScores)
+	}
+
+	var game = GameData{ToPlayer: map[uint64]*PlayerData{
+		10: {UID: 10, Index: 0},
+		11: {UID: 11, Index: 1},
+	}}
+	var results = rushResults(&game, &state)
+	if len(results) != 2 || results[0].Rank != 1 || results[1].Rank != 1 || results[0].Score != 7 || len(results[1].RoundScores) != 2 {
+		t.Fatal("Unexpected results:", results)
+	}
+}
+
+func TestRushTeams(t *testing.T) {
+	var c Controller
+	c.Init()
+
+	var config = RushConfig{NumPlayers: 4, NumTiles: 100, Frequency: 1, StartSize: 10, DrawSize: 1, DiscardPenalty: 3, TeamPlay: true}
+	if err := c.addGame("rush", 1, 1, &config); err != nil {
+		t.Fatal("Unable to add game:", err)
+	}
+
+	var game = c.ToGame[1]
+	var notifications = make(map[uint64]chan interface{})
+	for uid := uint64(1); uid <= 4; uid++ {
+		notifications[uid] = make(chan interface{}, 64)
+		game.ToPlayer[uid] = &PlayerData{UID: uid, Index: -1, Admitted: true, Playing: true, Notifications: map[uint64]chan interface{}{uid: notifications[uid]}}
+	}
+
+	var send = func(uid uint64, message interface{}) error {
+		data, err := json.Marshal(message)
+		if err != nil {
+			t.Fatal("Unable to marshal message:", err)
+		}
+
+		_, err = c.Dispatch(data, 1, uid, uid)
+		return err
+	}
+
+	var lastState = func(uid uint64) *RushStateNotification {
+		var ret *RushStateNotification
+		for len(notifications[uid]) > 0 {
+			if notification, ok := (<-notifications[uid]).(RushStateNotification); ok {
+				ret = &notification
+			}
+		}
+		return ret
+	}
+
+	var header = func(uid uint64, messageType string) MessageHeader {
+		return MessageHeader{Mode: "rush", ID: 1, Player: uid, MessageType: messageType}
+	}
+
+	if err := send(1, RushAssignMsg{header(1, "assign"), 4, []uint64{1, 2, 3, 4}, [][]int{{0, 1}, {2}}}); err == nil {
+		t.Fatal("Expected every player to need a team")
+	}
+
+	if err := send(1, RushAssignMsg{header(1, "assign"), 4, []uint64{1, 2, 3, 4}, [][]int{{0, 2}, {1, 3}}}); err != nil {
+		t.Fatal("Unable to assign teams:", err)
+	}
+
+	if game.ToPlayer[1].Index != 0 || game.ToPlayer[3].Index != 0 || game.ToPlayer[2].Index != 1 || game.ToPlayer[4].Index != 1 {
+		t.Fatal("Expected players to take their team's index")
+	}
+
+	var state = game.State.(*RushState)
+	if err := c.doRushStart(game, state); err != nil {
+		t.Fatal("Unable to start game:", err)
+	}
+
+	if len(state.Players) != 2 || len(state.Players[0].Hand) != 20 || game.ToPlayer[3].Index != 0 {
+		t.Fatal("Expected two teams with pooled hands:", len(state.Players))
+	}
+
+	// A move by one teammate is sent to the other.
+	var version = lastState(3).Version
+	lastState(2)
+	var tile = state.Players[0].Hand[0].ID
+	if err := send(1, RushPlay{header(1, "play"), tile, 0, 0, "", version}); err != nil {
+		t.Fatal("Unable to play tile:", err)
+	}
+
+	if update := lastState(3); update == nil || len(update.Board.Tiles) != 1 || update.Version <= version {
+		t.Fatal("Expected teammate to see the played tile:", update)
+	}
+
+	if update := lastState(2); update != nil {
+		t.Fatal("Didn't expect the other team to see the board:", update)
+	}
+
+	// The teammate moving the same tile from an outdated board conflicts.
+	if err := send(3, RushMove{header(3, "move"), tile, 2, 2, "", version}); err == nil {
+		t.Fatal("Expected a conflicting move to fail")
+	}
+
+	if err := send(3, RushMove{header(3, "move"), tile, 2, 2, "", state.Players[0].Version}); err != nil {
+		t.Fatal("Unable to move tile with the current board:", err)
+	}
+
+	// Conflicts are still caught after the game is saved and loaded again.
+	encoded, err := json.Marshal(state)
+	if err != nil {
+		t.Fatal("Unable to serialize game:", err)
+	}
+
+	var restored RushState
+	if err := json.Unmarshal(encoded, &restored); err != nil {
+		t.Fatal("Unable to deserialize game:", err)
+	}
+
+	if err := restored.CheckConflict(0, version, tile); err == nil {
+		t.Fatal("Expected a conflicting move to fail after reloading")
+	}
+}
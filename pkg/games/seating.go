@@ -0,0 +1,134 @@
+package games
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+)
+
+// Seat everyone who is playing in a random order, for games where the owner
+// doesn't assign seats. Games dealt from a shuffle secret draw the order from
+// it as well, so a replay seats everyone the same way.
+func seatPlayers(game *GameData) {
+	var seated []*PlayerData
+	for _, indexed_player := range game.ToPlayer {
+		if indexed_player.Admitted && indexed_player.Playing {
+			seated = append(seated, indexed_player)
+		}
+	}
+
+	sort.Slice(seated, func(i, j int) bool {
+		return seated[i].UID < seated[j].UID
+	})
+
+	var seed = game.Seating
+	if seed == nil {
+		seed = NewShuffleSeed()
+	}
+
+	seed.Rand().Shuffle(len(seated), func(i, j int) {
+		seated[i], seated[j] = seated[j], seated[i]
+	})
+
+	for index, indexed_player := range seated {
+		indexed_player.Index = index
+	}
+}
+
+// Work out who sits where from an assign message, for games where partners
+// sit across the table from each other, like Euchre and Bridge. The message
+// is the same one the lobby sends for Spades. Players sit in the order of
+// PlayerMaps, unless the owner put them into two teams of two, in which case
+// teammates are seated across from each other. Returns the user in each
+// seat.
+func partnershipSeating(data SpadesAssignMsg) ([]uint64, error) {
+	if data.NumPlayers != len(data.PlayerMaps) {
+		return nil, errors.New("incorrect number of players compared to player map")
+	}
+
+	// The lobby puts everyone without a team on a team of their own.
+	var partnered = false
+	for _, team := range data.TeamAssignments {
+		if len(team) > 1 {
+			partnered = true
+		}
+	}
+
+	if !partnered {
+		return append([]uint64{}, data.PlayerMaps...), nil
+	}
+
+	if data.NumPlayers != 4 || len(data.TeamAssignments) != 2 || len(data.TeamAssignments[0]) != 2 || len(data.TeamAssignments[1]) != 2 {
+		return nil, errors.New("partners must be assigned as two teams of two players")
+	}
+
+	var seats = make([]uint64, 4)
+	var seated = make(map[int]bool)
+	for team, members := range data.TeamAssignments {
+		for member, player := range members {
+			if player < 0 || player >= len(data.PlayerMaps) {
+				return nil, errors.New("not a valid player identifier: " + strconv.Itoa(player))
+			}
+
+			if seated[player] {
+				return nil, errors.New("player assigned to more than one seat: " + strconv.Itoa(player))
+			}
+
+			seated[player] = true
+			seats[team+2*member] = data.PlayerMaps[player]
+		}
+	}
+
+	return seats, nil
+}
+
+// Handle an assign message for a game with partnerships: seat the players as
+// the owner picked. assign is given the number of players seated, to check it
+// against the game's config before anyone's seat changes.
+func (c *Controller) assignPartnershipSeats(message []byte, header MessageHeader, game *GameData, player *PlayerData, assign func(num_players int) error) error {
+	if player.UID != game.Owner {
+		return errors.New("unable to assign players to game that you're not the owner of")
+	}
+
+	if game.CountdownTimer != nil {
+		return errors.New("unable to assign players while the game is starting")
+	}
+
+	var data SpadesAssignMsg
+	if err := json.Unmarshal(message, &data); err != nil {
+		return err
+	}
+
+	seats, err := partnershipSeating(data)
+	if err != nil {
+		return err
+	}
+
+	for _, uid := range seats {
+		if seated, ok := game.ToPlayer[uid]; !ok || !seated.Playing {
+			return errors.New("unknown player")
+		}
+	}
+
+	if err := assign(len(seats)); err != nil {
+		return err
+	}
+
+	// Set unused players to -1 before setting used players, in case a previous
+	// configuration was set but cancelled.
+	for _, indexed_player := range game.ToPlayer {
+		indexed_player.Index = -1
+	}
+
+	for seat, uid := range seats {
+		game.ToPlayer[uid].Index = seat
+	}
+
+	var response MessageHeader
+	response.LoadHeader(game, player)
+	response.ReplyTo = header.MessageID
+
+	c.undispatch(game, player, response.MessageID, response.ReplyTo, response)
+	return nil
+}
@@ -0,0 +1,190 @@
+package games
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"strconv"
+
+	math_rand "math/rand"
+
+	"git.cipherboy.com/WillowPatchGames/wpg/internal/utils"
+)
+
+// A ShuffleSeed makes shuffles verifiable. Every shuffle draws from a stream
+// derived from the secret, so the same secret always gives the same deal.
+// While the secret is in use, only its commitment (the hex SHA-256 hash of
+// the secret) is published; once the round is over, the secret is revealed
+// so anyone can check it against the commitment and recompute the deal.
+//
+// The Nth shuffle (from one) draws 64-bit big-endian numbers from the first
+// eight bytes of SHA-256(secret || N || counter), with N and counter as
+// 64-bit big-endian integers and counter running up from zero, through
+// math/rand's Shuffle.
+type ShuffleSeed struct {
+	Secret     string `json:"secret"`
+	Commitment string `json:"commitment"`
+	Shuffles   int    `json:"shuffles"` // Number of shuffles drawn from this seed so far.
+}
+
+// Create a seed with a fresh random secret.
+func NewShuffleSeed() *ShuffleSeed {
+	return ShuffleSeedFromSecret(utils.RandomToken())
+}
+
+// Create a seed with a known secret, as for reproducible deals in tests and
+// tournaments.
+func ShuffleSeedFromSecret(secret string) *ShuffleSeed {
+	var hash = sha256.Sum256([]byte(secret))
+	return &ShuffleSeed{
+		Secret:     secret,
+		Commitment: hex.EncodeToString(hash[:]),
+	}
+}
+
+// Check that the secret matches the commitment published for it.
+func (ss *ShuffleSeed) Verify() error {
+	if ss == nil {
+		return errors.New("no shuffle seed to verify")
+	}
+
+	if ShuffleSeedFromSecret(ss.Secret).Commitment != ss.Commitment {
+		return errors.New("shuffle secret doesn't match its commitment")
+	}
+
+	return nil
+}
+
+// Derive the seed for a single round (counting from one) from this one.
+// Revealing a round's seed doesn't reveal this seed, or the seed of any
+// other round.
+func (ss *ShuffleSeed) Derive(round int) *ShuffleSeed {
+	var mac = hmac.New(sha256.New, []byte(ss.Secret))
+	mac.Write([]byte(strconv.Itoa(round)))
+	return ShuffleSeedFromSecret(hex.EncodeToString(mac.Sum(nil)))
+}
+
+// Copy the seed, as to record it in a round's history.
+func (ss *ShuffleSeed) Copy() *ShuffleSeed {
+	if ss == nil {
+		return nil
+	}
+
+	var ret = *ss
+	return &ret
+}
+
+// The source of randomness for the next shuffle drawn from this seed.
+func (ss *ShuffleSeed) Rand() *math_rand.Rand {
+	ss.Shuffles += 1
+	return math_rand.New(&seedSource{secret: []byte(ss.Secret), stream: uint64(ss.Shuffles)}) // #nosec G404
+}
+
+// Implemented by the states of games whose deals come from a ShuffleSeed.
+type SeededState interface {
+	// Use the given secret for every deal of the game. The server picks it
+	// when the game is created, or the owner does, as for tournaments which
+	// want every table to play the same hands. Only possible before the game
+	// starts.
+	SetShuffleSeed(seed *ShuffleSeed) error
+
+	// The seeds of every round which is over, for players to check their deals
+	// against.
+	RevealedSeeds() []*ShuffleSeed
+}
+
+// Owners who picked the shuffle secret know every deal in advance, so they
+// can't play in games dealt from it.
+func checkPickedSeed(game *GameData) error {
+	if !game.PickedSeed {
+		return nil
+	}
+
+	if owner, ok := game.ToPlayer[game.Owner]; ok && owner.Admitted && owner.Playing {
+		return errors.New("the owner picked this game's shuffle secret, so they can only watch; make them a spectator before starting")
+	}
+
+	return nil
+}
+
+// The seeds of the rounds which are over. When a round is being played, the
+// last seed is its own and stays secret.
+func revealedRoundSeeds(seeds []*ShuffleSeed, playing bool) []*ShuffleSeed {
+	if playing && len(seeds) > 0 {
+		seeds = seeds[:len(seeds)-1]
+	}
+
+	var ret = make([]*ShuffleSeed, 0, len(seeds))
+	for _, seed := range seeds {
+		if seed != nil {
+			ret = append(ret, seed)
+		}
+	}
+
+	return ret
+}
+
+type ControllerNotifySeeds struct {
+	MessageHeader
+	Seeds []*ShuffleSeed `json:"seeds"`
+}
+
+func (cns *ControllerNotifySeeds) LoadFromController(data *GameData, player *PlayerData, state SeededState) {
+	cns.LoadHeader(data, player)
+	cns.MessageType = "notify-seeds"
+	cns.Seeds = state.RevealedSeeds()
+}
+
+func (c *Controller) handleSeeds(header MessageHeader, game *GameData, player *PlayerData) error {
+	// !!NO LOCK!! This should already be held elsewhere, like Dispatch.
+
+	if !player.Admitted {
+		return errors.New("unable to see the seeds of a game you're not admitted to")
+	}
+
+	state, ok := game.State.(SeededState)
+	if !ok {
+		return errors.New("game doesn't have shuffle seeds: " + game.Mode.String())
+	}
+
+	var notification ControllerNotifySeeds
+	notification.LoadFromController(game, player, state)
+	notification.ReplyTo = header.MessageID
+	c.undispatch(game, player, notification.MessageID, notification.ReplyTo, notification)
+	return nil
+}
+
+// Either the given seed's round seed or, without a seed, a fresh random one.
+func roundSeed(seed *ShuffleSeed, round int) *ShuffleSeed {
+	if seed == nil {
+		return NewShuffleSeed()
+	}
+
+	return seed.Derive(round)
+}
+
+type seedSource struct {
+	secret  []byte
+	stream  uint64
+	counter uint64
+}
+
+func (s *seedSource) Seed(int64) {}
+
+func (s *seedSource) Int63() int64 {
+	return int64(s.Uint64() >> 1)
+}
+
+func (s *seedSource) Uint64() uint64 {
+	var block = make([]byte, 16)
+	binary.BigEndian.PutUint64(block[0:8], s.stream)
+	binary.BigEndian.PutUint64(block[8:16], s.counter)
+	s.counter += 1
+
+	var hash = sha256.New()
+	hash.Write(s.secret)
+	hash.Write(block)
+	return binary.BigEndian.Uint64(hash.Sum(nil)[0:8])
+}
@@ -0,0 +1,303 @@
+package games
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"git.cipherboy.com/WillowPatchGames/wpg/internal/database"
+)
+
+func TestShuffleSeed(t *testing.T) {
+	var seed = ShuffleSeedFromSecret("tournament")
+	if err := seed.Verify(); err != nil {
+		t.Fatal("Expected seed to match its commitment:", err)
+	}
+
+	var tampered = seed.Copy()
+	tampered.Secret = "rigged"
+	if err := tampered.Verify(); err == nil {
+		t.Fatal("Expected a different secret to fail verification")
+	}
+
+	if NewShuffleSeed().Secret == NewShuffleSeed().Secret {
+		t.Fatal("Expected fresh seeds to differ")
+	}
+
+	var first = seed.Derive(1)
+	if first.Secret == seed.Secret || first.Secret != ShuffleSeedFromSecret("tournament").Derive(1).Secret || first.Secret == seed.Derive(2).Secret {
+		t.Fatal("Expected derived seeds to be distinct and reproducible")
+	}
+
+	// The same seed always gives the same shuffles, in order.
+	var decks [2]Deck
+	for index := range decks {
+		decks[index].Init()
+		decks[index].AddStandard52Deck()
+		decks[index].Seed = ShuffleSeedFromSecret("tournament")
+		decks[index].Shuffle()
+	}
+
+	for index := range decks[0].Cards {
+		if *decks[0].Cards[index] != *decks[1].Cards[index] {
+			t.Fatal("Expected identical shuffles from the same seed:", decks[0].Cards[index], decks[1].Cards[index])
+		}
+	}
+
+	var before = CopyDeck(decks[0].Cards)
+	decks[0].Shuffle()
+	var same = true
+	for index := range before {
+		same = same && *before[index] == *decks[0].Cards[index]
+	}
+
+	if same || decks[0].Seed.Shuffles != 2 {
+		t.Fatal("Expected the next shuffle to draw a new order:", decks[0].Seed.Shuffles)
+	}
+
+	// Tiles can be recomputed from their seed, too.
+	var tiles = GenerateSeededTiles(ShuffleSeedFromSecret("tiles"), 20, 2, frequencyMap[StandardFreq], false)
+	var again = GenerateSeededTiles(ShuffleSeedFromSecret("tiles"), 20, 2, frequencyMap[StandardFreq], false)
+	for index := range tiles {
+		if tiles[index] != again[index] {
+			t.Fatal("Expected identical tiles from the same seed:", tiles[index], again[index])
+		}
+	}
+}
+
+func TestShuffleSeedAudit(t *testing.T) {
+	var state EuchreState
+	if err := state.Init(EuchreConfig{NumPlayers: 4, WinAmount: 10}); err != nil {
+		t.Fatal("Unable to initialize game:", err)
+	}
+
+	state.Seed = ShuffleSeedFromSecret("tournament")
+	if err := state.Start(4); err != nil {
+		t.Fatal("Unable to start game:", err)
+	}
+
+	var history = state.RoundHistory[0]
+	if history.Seed == nil || history.Seed.Commitment != state.Deck.Commitment() || history.Seed.Secret != state.Seed.Derive(1).Secret {
+		t.Fatal("Expected the round's seed to be recorded and published:", history.Seed)
+	}
+
+	// Anyone given the revealed secret can check it against the commitment
+	// published at deal time and recompute the deal.
+	if err := history.Seed.Verify(); err != nil {
+		t.Fatal("Expected the revealed seed to verify:", err)
+	}
+
+	var deck Deck
+	deck.Init()
+	deck.AddEuchre24Deck()
+	deck.Seed = ShuffleSeedFromSecret(history.Seed.Secret)
+	deck.Shuffle()
+
+	if len(deck.Cards) != len(history.Deck) {
+		t.Fatal("Expected the recomputed deck to match in size:", len(deck.Cards), len(history.Deck))
+	}
+
+	for index := range deck.Cards {
+		if *deck.Cards[index] != *history.Deck[index] {
+			t.Fatal("Expected the recomputed deal to match:", index, deck.Cards[index], history.Deck[index])
+		}
+	}
+}
+
+func TestShuffleSecret(t *testing.T) {
+	var gamedb database.Game
+	gamedb.ID = 1
+	gamedb.OwnerID = 1
+	gamedb.Style = "euchre"
+	gamedb.Lifecycle = "pending"
+	gamedb.Config.Valid = true
+	gamedb.Config.String = `{"num_players":4,"win_amount":10}`
+	database.SetSQLFromString(&gamedb.ShuffleSecret, "tournament")
+
+	// Every game created with the same secret is dealt the same hands.
+	var states []*EuchreState
+	var c Controller
+	for table := 0; table < 2; table++ {
+		c.Init()
+		if err := c.LoadGame(&gamedb); err != nil {
+			t.Fatal("Unable to load game:", err)
+		}
+
+		var state = c.ToGame[1].State.(*EuchreState)
+		if err := state.Start(4); err != nil {
+			t.Fatal("Unable to start game:", err)
+		}
+
+		states = append(states, state)
+	}
+
+	for player := range states[0].Players {
+		for index, card := range states[0].Players[player].Hand {
+			if card != states[1].Players[player].Hand[index] {
+				t.Fatal("Expected both tables to be dealt the same hands:", player, states[0].Players[player].Hand, states[1].Players[player].Hand)
+			}
+		}
+	}
+
+	var state = states[1]
+	if err := state.SetShuffleSeed(NewShuffleSeed()); err == nil {
+		t.Fatal("Expected to be unable to change the seed after starting")
+	}
+
+	if seeds := state.RevealedSeeds(); len(seeds) != 0 {
+		t.Fatal("Expected the seed of the round being played to stay secret:", seeds)
+	}
+
+	// Once the round is over, its seed is revealed, but never the secret.
+	for state.BidRound == 1 {
+		if err := state.Pass(state.Turn); err != nil {
+			t.Fatal("Unable to pass:", err)
+		}
+	}
+
+	var trump = ClubsSuit
+	if trump == state.TurnedUp.Suit {
+		trump = HeartsSuit
+	}
+
+	if err := state.Call(state.Turn, trump, false); err != nil {
+		t.Fatal("Unable to call trump:", err)
+	}
+
+	var err error
+	for err == nil {
+		err = playEuchreCard(t, state)
+	}
+
+	var game = c.ToGame[1]
+	game.ToPlayer[1] = &PlayerData{UID: 1, Index: 0, Admitted: true, Playing: true, Notifications: make(map[uint64]chan interface{})}
+	game.ToPlayer[2] = &PlayerData{UID: 2, Index: -1, Notifications: make(map[uint64]chan interface{})}
+
+	var send = func(uid uint64) error {
+		_, err := c.Dispatch([]byte(`{"game_mode":"euchre","game_id":1,"player_id":`+strconv.FormatUint(uid, 10)+`,"message_id":5,"message_type":"seeds"}`), 1, uid, uid)
+		return err
+	}
+
+	if err := send(2); err == nil {
+		t.Fatal("Expected players who weren't admitted to be unable to see the seeds")
+	}
+
+	if err := send(1); err != nil {
+		t.Fatal("Unable to ask for the seeds:", err)
+	}
+
+	var round = ShuffleSeedFromSecret("tournament").Derive(1)
+	var notified = false
+	for _, message := range game.ToPlayer[1].OutboundMsgs {
+		notified = notified || (strings.Contains(message.Message, `"notify-seeds"`) && strings.Contains(message.Message, `"reply_to":5`) && strings.Contains(message.Message, round.Secret))
+		if strings.Contains(message.Message, `"tournament"`) {
+			t.Fatal("Expected the secret itself never to be revealed:", message.Message)
+		}
+	}
+
+	if !notified {
+		t.Fatal("Expected the finished round's seed to be sent:", game.ToPlayer[1].OutboundMsgs)
+	}
+}
+
+func TestSeededDeals(t *testing.T) {
+	var seed = ShuffleSeedFromSecret("tournament")
+
+	var crazy []*CrazyEightsState
+	var jacks []*EightJacksState
+	for table := 0; table < 2; table++ {
+		var ces = new(CrazyEightsState)
+		if err := ces.Init(CrazyEightsConfig{NumPlayers: 4, HandSize: 5, WildEights: true}); err != nil {
+			t.Fatal("Unable to initialize crazy eights:", err)
+		}
+
+		if err := ces.SetShuffleSeed(seed.Copy()); err != nil {
+			t.Fatal("Unable to set the crazy eights seed:", err)
+		}
+
+		if err := ces.Start(4); err != nil {
+			t.Fatal("Unable to start crazy eights:", err)
+		}
+
+		var ejs = new(EightJacksState)
+		if err := ejs.Init(EightJacksConfig{NumPlayers: 2, RunLength: 4, WinLimit: 2, BoardWidth: 10, BoardHeight: 10, WildCorners: true, BoardLayout: 4, HandSize: 7}); err != nil {
+			t.Fatal("Unable to initialize eight jacks:", err)
+		}
+
+		if err := ejs.SetShuffleSeed(seed.Copy()); err != nil {
+			t.Fatal("Unable to set the eight jacks seed:", err)
+		}
+
+		if err := ejs.AssignTeams(0, 2, [][]int{{0}, {1}}); err != nil {
+			t.Fatal("Unable to assign teams:", err)
+		}
+
+		if err := ejs.Start(); err != nil {
+			t.Fatal("Unable to start eight jacks:", err)
+		}
+
+		crazy = append(crazy, ces)
+		jacks = append(jacks, ejs)
+	}
+
+	for player := range crazy[0].Players {
+		for index, card := range crazy[0].Players[player].Hand {
+			if card != crazy[1].Players[player].Hand[index] {
+				t.Fatal("Expected both crazy eights tables to be dealt the same hands:", crazy[0].Players[player].Hand, crazy[1].Players[player].Hand)
+			}
+		}
+	}
+
+	for player := range jacks[0].Players {
+		for index, card := range jacks[0].Players[player].Hand {
+			if card != jacks[1].Players[player].Hand[index] {
+				t.Fatal("Expected both eight jacks tables to be dealt the same hands:", jacks[0].Players[player].Hand, jacks[1].Players[player].Hand)
+			}
+		}
+	}
+
+	if err := crazy[0].SetShuffleSeed(NewShuffleSeed()); err == nil {
+		t.Fatal("Expected to be unable to change the crazy eights seed after starting")
+	}
+
+	if seeds := jacks[0].RevealedSeeds(); len(seeds) != 0 {
+		t.Fatal("Expected the eight jacks seed to stay secret until the game is over:", seeds)
+	}
+}
+
+func TestPickedShuffleSecret(t *testing.T) {
+	var gamedb database.Game
+	gamedb.ID = 1
+	gamedb.OwnerID = 1
+	gamedb.Style = "crazy eights"
+	gamedb.Lifecycle = "pending"
+	gamedb.Config.Valid = true
+	gamedb.Config.String = `{"num_players":2,"hand_size":5}`
+	gamedb.PickedShuffleSecret = true
+	database.SetSQLFromString(&gamedb.ShuffleSecret, "tournament")
+
+	var c Controller
+	c.Init()
+	if err := c.LoadGame(&gamedb); err != nil {
+		t.Fatal("Unable to load game:", err)
+	}
+
+	var game = c.ToGame[1]
+	if !game.PickedSeed {
+		t.Fatal("Expected the game to know its owner picked the secret")
+	}
+
+	game.ToPlayer[1] = &PlayerData{UID: 1, Index: -1, Admitted: true, Playing: true, Notifications: make(map[uint64]chan interface{})}
+	game.ToPlayer[2] = &PlayerData{UID: 2, Index: -1, Admitted: true, Playing: true, Notifications: make(map[uint64]chan interface{})}
+	game.ToPlayer[3] = &PlayerData{UID: 3, Index: -1, Admitted: true, Playing: true, Notifications: make(map[uint64]chan interface{})}
+
+	// The owner knows every deal, so they can't take part.
+	if err := checkPickedSeed(game); err == nil {
+		t.Fatal("Expected the owner to be unable to play with a secret they picked")
+	}
+
+	game.ToPlayer[1].Playing = false
+	if err := checkPickedSeed(game); err != nil {
+		t.Fatal("Expected the owner to be able to watch:", err)
+	}
+}
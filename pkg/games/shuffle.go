@@ -1,9 +1,5 @@
 package games
 
-import (
-	"git.cipherboy.com/WillowPatchGames/wpg/internal/utils"
-)
-
 func (d *Deck) AddStandard52Deck() {
 	for _, suit := range StandardCardSuits {
 		for _, rank := range StandardCardRanks {
@@ -13,6 +9,16 @@ func (d *Deck) AddStandard52Deck() {
 	}
 }
 
+// The nines through aces of each suit, as used in Euchre.
+func (d *Deck) AddEuchre24Deck() {
+	for _, suit := range StandardCardSuits {
+		for _, rank := range []CardRank{NineRank, TenRank, JackRank, QueenRank, KingRank, AceRank} {
+			var card Card = Card{0, suit, rank}
+			d.Cards = append(d.Cards, &card)
+		}
+	}
+}
+
 func (d *Deck) AddJokers(count int, marked bool) {
 	for i := 0; i < count; i++ {
 		var suit CardSuit = NoneSuit
@@ -50,13 +56,35 @@ func (d *Deck) RemoveCard(rank CardRank, suit CardSuit) bool {
 	return found
 }
 
+// Replace the deck's seed with the one for the given round: derived from
+// seed when it is set, and fresh otherwise. Games dealing several rounds from
+// one deck reseed it each round, so that revealing one round's seed doesn't
+// reveal the next deal.
+func (d *Deck) Reseed(seed *ShuffleSeed, round int) {
+	d.Seed = roundSeed(seed, round)
+}
+
+// Publicly verifiable commitment to the deck's seed; see ShuffleSeed.
+func (d *Deck) Commitment() string {
+	if d.Seed == nil {
+		return ""
+	}
+
+	return d.Seed.Commitment
+}
+
 func (d *Deck) Shuffle() {
+	if d.Seed == nil {
+		d.Seed = NewShuffleSeed()
+	}
+	var source = d.Seed.Rand()
+
 	// By shuffling, then assigning IDs, and then shuffling IDs, we get random
 	// identifier -> card assignments, and random order of IDs in a deck.
 	missing_ids := d.Cards[0].ID == 0
 
 	if missing_ids {
-		utils.SecureRand.Shuffle(len(d.Cards), func(i, j int) {
+		source.Shuffle(len(d.Cards), func(i, j int) {
 			d.Cards[i], d.Cards[j] = d.Cards[j], d.Cards[i]
 		})
 
@@ -65,7 +93,7 @@ func (d *Deck) Shuffle() {
 		}
 	}
 
-	utils.SecureRand.Shuffle(len(d.Cards), func(i, j int) {
+	source.Shuffle(len(d.Cards), func(i, j int) {
 		d.Cards[i], d.Cards[j] = d.Cards[j], d.Cards[i]
 	})
 }
@@ -92,8 +92,15 @@ type SpadesConfig struct {
 	MoonOrBoston    bool `json:"perfect_round" config:"type:bool,default:false" label:"true:Score half of winning amount for a perfect round (Moon or Boston),false:Score no additional points for a perfect round"` // Score half of the win amount for a perfect round (taking all tricks tricks).
 	NilScore        int  `json:"nil_score" config:"type:enum,default:100,options:50:50 Points;75:75 Points;100:100 Points;125:125 Points;150:150 Points;200:200 Points" label:"Single nil score"`                    // n in {50, 75. 100, 125, 150, 200}.
 
+	Takebacks int `json:"takebacks" config:"type:enum,default:0,options:0:No takebacks;1:Other players approve takebacks;2:Game owner approves takebacks" label:"Taking back a card played in the current trick"` // See UndoApproval.
+
 	// Common game configuration options
 	Countdown bool `json:"countdown" config:"type:bool,default:true" label:"true:Show a 3... 2... 1... countdown before beginning,false:Start the game instantly"` // Whether to wait and send countdown messages.
+
+	// Chess clock style limits on how long each player may take.
+	TurnClock   int `json:"turn_clock" config:"type:int,min:0,default:0,max:600" label:"Seconds per turn (0 for no limit)"`
+	GameClock   int `json:"game_clock" config:"type:int,min:0,default:0,max:120" label:"Minutes per player for the whole game (0 for no limit)"`
+	ClockPolicy int `json:"clock_policy" config:"type:enum,default:0,options:0:Play automatically;2:Forfeit the game" label:"When a player runs out of time"` // See ClockPolicy.
 }
 
 func (cfg SpadesConfig) Validate() error {
@@ -124,8 +131,9 @@ type SpadesRoundPlayer struct {
 }
 
 type SpadesRound struct {
-	Dealer int     `json:"dealer"`
-	Deck   []*Card `json:"deck"`
+	Dealer int          `json:"dealer"`
+	Deck   []*Card      `json:"deck"`
+	Seed   *ShuffleSeed `json:"seed"`
 
 	Players []SpadesRoundPlayer `json:"players"`
 	Tricks  []SpadesTrick       `json:"tricks"`
@@ -143,6 +151,10 @@ type SpadesState struct {
 	SpadesBroken   bool           `json:"spades_broken"`   // Whether or not spades have been broken.
 	PreviousTricks [][]Card       `json:"previous_tricks"` // Contents of previous tricks in the current round; sent to clients.
 	RoundHistory   []*SpadesRound `json:"round_history"`   // Contents of previous rounds for analysis.
+	Seed           *ShuffleSeed   `json:"seed,omitempty"`  // Secret each round's shuffle seed derives from; when unset, every round gets a fresh seed.
+
+	Actions   int             `json:"actions"`             // Number of cards played so far, numbering each for takebacks.
+	Takebacks []TrickTakeback `json:"takebacks,omitempty"` // Cards played in the current trick, which can still be taken back.
 
 	Config SpadesConfig `json:"config"`
 
@@ -194,11 +206,126 @@ func (ss *SpadesState) IsFinished() bool {
 	return ss.Finished
 }
 
+func (ss *SpadesState) SetShuffleSeed(seed *ShuffleSeed) error {
+	if ss.Started {
+		return errors.New("unable to set the shuffle seed after the game has started")
+	}
+
+	ss.Seed = seed
+	return nil
+}
+
+func (ss *SpadesState) RevealedSeeds() []*ShuffleSeed {
+	var seeds = make([]*ShuffleSeed, 0, len(ss.RoundHistory))
+	for _, round := range ss.RoundHistory {
+		seeds = append(seeds, round.Seed)
+	}
+
+	return revealedRoundSeeds(seeds, ss.Dealt && !ss.Finished)
+}
+
 func (ss *SpadesState) ResetStatus() {
 	ss.Started = false
 	ss.Finished = false
 }
 
+func (ss *SpadesState) UndoApproval() UndoApproval {
+	return UndoApproval(ss.Config.Takebacks)
+}
+
+func (ss *SpadesState) UndoableActions() []UndoAction {
+	var ret = make([]UndoAction, 0, len(ss.Takebacks))
+	for _, takeback := range ss.Takebacks {
+		ret = append(ret, takeback.UndoAction)
+	}
+
+	return ret
+}
+
+func (ss *SpadesState) Undo(action int) error {
+	var first = -1
+	for index, takeback := range ss.Takebacks {
+		if takeback.Action == action {
+			first = index
+			break
+		}
+	}
+
+	if first == -1 {
+		return errors.New("no such card played in the current trick to take back")
+	}
+
+	// Take back the cards played after it first. Actions keep counting up, so
+	// a takeback is never mistaken for the action taken after it.
+	history := ss.RoundHistory[len(ss.RoundHistory)-1]
+	for len(ss.Takebacks) > first {
+		takeback := ss.Takebacks[len(ss.Takebacks)-1]
+		ss.Takebacks = ss.Takebacks[:len(ss.Takebacks)-1]
+
+		this_trick := &history.Tricks[len(history.Tricks)-1]
+		if takeback.takeBack(&ss.Players[takeback.Player].Hand, &ss.Played, &this_trick.Played) {
+			history.Tricks = history.Tricks[:len(history.Tricks)-1]
+		}
+
+		ss.Turn = takeback.Turn
+		ss.SpadesBroken = takeback.Broken
+	}
+
+	return nil
+}
+
+func (ss *SpadesState) TurnClocks() TurnClocks {
+	return newTurnClocks(ss.Config.TurnClock, ss.Config.GameClock, ss.Config.ClockPolicy)
+}
+
+func (ss *SpadesState) ClockedPlayer() int {
+	// Only playing cards is clocked, not splitting or bidding.
+	if !ss.Started || ss.Finished || !ss.Dealt || !ss.Split || !ss.Bid {
+		return -1
+	}
+
+	return ss.Turn
+}
+
+func (ss *SpadesState) Timeout(player int, policy ClockPolicy) error {
+	if player != ss.ClockedPlayer() {
+		return errors.New("not your turn")
+	}
+
+	if policy == ForfeitOnTimeout {
+		return ss.forfeit(player)
+	}
+
+	return playLowestCard(ss.Players[player].Hand, true, func(card int) error {
+		return ss.PlayCard(player, card)
+	})
+}
+
+// End the game early, with the highest scoring of the other teams winning.
+func (ss *SpadesState) forfeit(player int) error {
+	var winner = -1
+	for index, indexed_player := range ss.Players {
+		if indexed_player.Team != ss.Players[player].Team && (winner == -1 || indexed_player.Score > ss.Players[winner].Score) {
+			winner = index
+		}
+	}
+
+	ss.Winners = make([]int, 0)
+	for index, indexed_player := range ss.Players {
+		if winner != -1 && indexed_player.Team == ss.Players[winner].Team {
+			ss.Winners = append(ss.Winners, index)
+		}
+	}
+
+	ss.Finished = true
+	ss.Dealt = true
+	ss.Bid = true
+	ss.Turn = -1
+	ss.Dealer = -1
+	ss.Takebacks = nil
+	return errors.New(SpadesGameOver)
+}
+
 func (ss *SpadesState) Start() error {
 	var err error
 
@@ -358,10 +485,12 @@ func (ss *SpadesState) StartRound() error {
 	}
 
 	// Shuffling the deck before assigning cards to players.
+	ss.Deck.Reseed(ss.Seed, len(ss.RoundHistory))
 	ss.Deck.Shuffle()
 
 	// Save the initial deck.
 	history.Deck = CopyDeck(ss.Deck.Cards)
+	history.Seed = ss.Deck.Seed.Copy()
 
 	// Clear out all round-specific status before each round.
 	for index := range ss.Players {
@@ -671,6 +800,39 @@ func (ss *SpadesState) PlaceBid(player int, bid SpadesBid) error {
 }
 
 func (ss *SpadesState) PlayCard(player int, card int) error {
+	// Remember what playing this card changes, so it can be taken back until
+	// the trick is over.
+	var takeback TrickTakeback
+	takeback.Player = player
+	takeback.Action = ss.Actions + 1
+	takeback.Turn = ss.Turn
+	takeback.Broken = ss.SpadesBroken
+	if player >= 0 && player < len(ss.Players) {
+		if index, found := ss.Players[player].FindCard(card); found {
+			takeback.Card = ss.Players[player].Hand[index]
+			takeback.Index = index
+		}
+	}
+
+	if ss.Turn == ss.Leader {
+		takeback.Played = ss.Played
+	}
+
+	err := ss.playCard(player, card)
+	if err != nil && !isRoundSignal(err) {
+		return err
+	}
+
+	ss.Actions = takeback.Action
+	ss.Takebacks = append(ss.Takebacks, takeback)
+	if ss.Turn == ss.Leader || ss.Finished {
+		ss.Takebacks = nil
+	}
+
+	return err
+}
+
+func (ss *SpadesState) playCard(player int, card int) error {
 	if !ss.Started {
 		return errors.New("game hasn't started yet")
 	}
@@ -776,30 +938,20 @@ func (ss *SpadesState) PlayCard(player int, card int) error {
 	return nil
 }
 
-func (ss *SpadesState) determineTrickWinner() error {
+// Given the cards played in a trick (in order, starting with the leader),
+// determine the offset of the winning card.
+func (ss *SpadesState) trickWinner(played []Card) int {
 	var winner_offset = 0
-	var winning_card = ss.Played[0]
-
-	history := ss.RoundHistory[len(ss.RoundHistory)-1]
-	this_trick := &history.Tricks[len(history.Tricks)-1]
-
-	// Always have at least two players, so an offset of one is always valid.
-	for offset := 1; offset < ss.Config.NumPlayers; offset++ {
-		this_card := ss.Played[offset]
-		if winning_card.Suit == this_card.Suit && this_card.Suit != NoneSuit && this_card.Suit != FancySuit {
-			// Highest card of the lead suit wins. Or, when playing with six players,
-			// choose the winner in case of tie according to config. Also, check for
-			// aces, cuz they have integer value 1, but beat 2 and above... :)
-			is_higher := this_card.Rank > winning_card.Rank && winning_card.Rank != AceRank
-			won_due_to_tie := this_card.Rank == winning_card.Rank && !ss.Config.FirstWins
-			is_ace_win := this_card.Rank == AceRank && winning_card.Rank != AceRank && winning_card.Rank != JokerRank
-			if is_higher || won_due_to_tie || is_ace_win {
-				winner_offset = offset
-				winning_card = this_card
-			}
-		} else if this_card.Suit == SpadesSuit {
-			if winning_card.Suit != SpadesSuit && winning_card.Rank != JokerRank {
-				// Someone is winning with trump over here!
+	var winning_card = played[0]
+
+	for offset := 1; offset < len(played); offset++ {
+		this_card := played[offset]
+		if this_card.Rank != JokerRank && winning_card.Rank != JokerRank {
+			// Highest card of the lead suit wins, unless someone trumps it with a
+			// spade. Or, when playing with six players, choose the winner in case
+			// of tie according to config.
+			won_due_to_tie := this_card.Suit == winning_card.Suit && this_card.Rank == winning_card.Rank && !ss.Config.FirstWins
+			if beatsInTrick(this_card, winning_card, SpadesSuit) || won_due_to_tie {
 				winner_offset = offset
 				winning_card = this_card
 			}
@@ -815,6 +967,16 @@ func (ss *SpadesState) determineTrickWinner() error {
 		}
 	}
 
+	return winner_offset
+}
+
+func (ss *SpadesState) determineTrickWinner() error {
+	history := ss.RoundHistory[len(ss.RoundHistory)-1]
+	this_trick := &history.Tricks[len(history.Tricks)-1]
+
+	// Always have at least two players, so an offset of one is always valid.
+	winner_offset := ss.trickWinner(ss.Played)
+
 	absolute_winner := (ss.Leader + winner_offset) % ss.Config.NumPlayers
 	ss.Players[absolute_winner].Tricks += 1
 	ss.Leader = absolute_winner
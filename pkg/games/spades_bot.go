@@ -0,0 +1,476 @@
+package games
+
+import (
+	"errors"
+	"sort"
+)
+
+// SpadesBot is a computer opponent for Spades. It estimates its bid by
+// counting likely winners, considers nil (and blind nil when far behind),
+// and during play tries to make its team's contract without collecting
+// overtakes, covering a partner who bid nil.
+type SpadesBot struct{}
+
+func (spadesEngine) NewBot() GameBot {
+	return SpadesBot{}
+}
+
+func (sb SpadesBot) NextMove(game *GameData, player *PlayerData) (interface{}, error) {
+	var state *SpadesState = game.State.(*SpadesState)
+	if state == nil || !state.Started || state.Finished {
+		return nil, nil
+	}
+
+	var index = player.Index
+	if index < 0 || index >= len(state.Players) {
+		return nil, nil
+	}
+
+	var us = &state.Players[index]
+
+	if !state.Dealt {
+		if state.Dealer != index {
+			return nil, nil
+		}
+
+		return botHeader(game, player, "deal"), nil
+	}
+
+	if !state.Split {
+		// Two player only: pick up the top card and decide whether to keep
+		// it. PeekTop isn't turn based; both players split concurrently.
+		if us.Drawn == nil {
+			if len(us.DrawPile) < 2 {
+				return nil, nil
+			}
+
+			return botHeader(game, player, "deal"), nil
+		}
+
+		var msg SpadesDecideMsg
+		msg.MessageHeader = botHeader(game, player, "decide")
+		msg.Keep = sb.cardStrength(*us.Drawn) >= 10
+		return msg, nil
+	}
+
+	if state.Turn != index {
+		return nil, nil
+	}
+
+	if !state.Bid {
+		if !us.Peeked {
+			if sb.shouldBidBlindNil(state, index) {
+				var msg SpadesBidMsg
+				msg.MessageHeader = botHeader(game, player, "bid")
+				msg.Bid = int(BlindNilBidSpades)
+				return msg, nil
+			}
+
+			return botHeader(game, player, "look"), nil
+		}
+
+		var msg SpadesBidMsg
+		msg.MessageHeader = botHeader(game, player, "bid")
+		msg.Bid = int(sb.chooseBid(state, index))
+		return msg, nil
+	}
+
+	card, err := sb.choosePlay(state, index)
+	if err != nil {
+		return nil, err
+	}
+
+	var msg SpadesPlayMsg
+	msg.MessageHeader = botHeader(game, player, "play")
+	msg.CardID = card.ID
+	return msg, nil
+}
+
+func spadesEffectivelySpade(card Card) bool {
+	return card.Suit == SpadesSuit || card.Rank == JokerRank
+}
+
+// Rough ordering of how likely a card is to take a trick: aces high, spades
+// above everything else, jokers above spades.
+func (sb SpadesBot) cardStrength(card Card) int {
+	if card.Rank == JokerRank {
+		if card.Suit == FancySuit {
+			return 22
+		}
+
+		return 21
+	}
+
+	var value = int(card.Rank)
+	if card.Rank == AceRank {
+		value = int(KingRank) + 1
+	}
+
+	if card.Suit == SpadesSuit {
+		value += 6
+	}
+
+	return value
+}
+
+// Find our partner, if any.
+func (ss *SpadesState) partnerOf(player int) (int, bool) {
+	for other := range ss.Players {
+		if other != player && ss.Players[other].Team == ss.Players[player].Team {
+			return other, true
+		}
+	}
+
+	return -1, false
+}
+
+func spadesIsNil(bid SpadesBid) bool {
+	return bid >= NilBidSpades && bid <= TripleNilBidSpades
+}
+
+// Largest bid allowed with the current number of players.
+func (ss *SpadesState) maxBid() SpadesBid {
+	switch ss.Config.NumPlayers {
+	case 2, 4:
+		return ThirteenBidSpades
+	case 5:
+		return TenBidSpades
+	case 3, 6:
+		if !ss.Config.AddJokers {
+			return SeventeenBidSpades
+		}
+
+		return EighteenBidSpades
+	}
+
+	return ThirteenBidSpades
+}
+
+// Only bid blind nil when we're well behind; it's a gamble.
+func (sb SpadesBot) shouldBidBlindNil(state *SpadesState, player int) bool {
+	if !state.Config.BlindBidding || !state.Config.WithDoubleNil || !state.Config.WithNil {
+		return false
+	}
+
+	if partner, ok := state.partnerOf(player); ok && spadesIsNil(state.Players[partner].Bid) {
+		return false
+	}
+
+	var best_other = -1 << 30
+	for other := range state.Players {
+		if state.Players[other].Team != state.Players[player].Team && state.Players[other].Score > best_other {
+			best_other = state.Players[other].Score
+		}
+	}
+
+	return best_other-state.Players[player].Score >= state.Config.WinAmount/2
+}
+
+// Estimate the number of tricks this hand will take.
+func (sb SpadesBot) estimateTricks(state *SpadesState, hand []Card) float64 {
+	var by_suit = make(map[CardSuit][]Card)
+	for _, card := range hand {
+		if spadesEffectivelySpade(card) {
+			by_suit[SpadesSuit] = append(by_suit[SpadesSuit], card)
+		} else {
+			by_suit[card.Suit] = append(by_suit[card.Suit], card)
+		}
+	}
+
+	var estimate = 0.0
+	for suit, cards := range by_suit {
+		sort.Slice(cards, func(i, j int) bool {
+			return sb.cardStrength(cards[i]) > sb.cardStrength(cards[j])
+		})
+
+		if suit == SpadesSuit {
+			// High spades are (nearly) sure things; long spades take the
+			// last few tricks.
+			for position, card := range cards {
+				var strength = sb.cardStrength(card) - 6
+				if strength >= int(KingRank)-position {
+					estimate += 1
+				} else if position >= 3 {
+					estimate += 0.75
+				}
+			}
+			continue
+		}
+
+		for position, card := range cards {
+			var strength = sb.cardStrength(card)
+			if strength == int(KingRank)+1 && position == 0 {
+				estimate += 1
+			} else if strength == int(KingRank) && position <= 1 && len(cards) >= 2 {
+				estimate += 0.75
+			} else if strength == int(QueenRank) && position <= 2 && len(cards) >= 3 && len(cards) <= 5 {
+				estimate += 0.25
+			}
+		}
+	}
+
+	// Short side suits let us trump in, if we have spades to spare.
+	var spades = len(by_suit[SpadesSuit])
+	for _, suit := range StandardCardSuits {
+		if suit == SpadesSuit || spades <= 2 {
+			continue
+		}
+
+		if len(by_suit[suit]) == 0 {
+			estimate += 1
+		} else if len(by_suit[suit]) == 1 {
+			estimate += 0.5
+		}
+	}
+
+	// The estimates above assume four players; with more players, each card
+	// faces more competition.
+	if state.Config.NumPlayers > 4 {
+		estimate = estimate * 4 / float64(state.Config.NumPlayers)
+	}
+
+	return estimate
+}
+
+func (sb SpadesBot) chooseBid(state *SpadesState, player int) SpadesBid {
+	var hand = state.Players[player].Hand
+	var estimate = sb.estimateTricks(state, hand)
+
+	partner, have_partner := state.partnerOf(player)
+	var partner_nil = have_partner && spadesIsNil(state.Players[partner].Bid)
+
+	// Consider nil when we have almost no winners and no high spades.
+	if state.Config.WithNil && !partner_nil && estimate < 1 {
+		var dangerous = false
+		for _, card := range hand {
+			if spadesEffectivelySpade(card) && sb.cardStrength(card)-6 >= int(TenRank) {
+				dangerous = true
+			}
+
+			if sb.cardStrength(card) == int(KingRank)+1 {
+				dangerous = true
+			}
+		}
+
+		if !dangerous {
+			return NilBidSpades
+		}
+	}
+
+	var bid = int(estimate + 0.5)
+	if partner_nil {
+		// We need to cover for our partner; they'll be throwing away their
+		// winners, so we'll take a bit more.
+		bid += 1
+	}
+
+	// Don't overbid the table: if the bids placed so far already account for
+	// most of the tricks, shave ours down.
+	var total = 0
+	for other := range state.Players {
+		if other == player || state.Players[other].Bid == NotBidSpades || spadesIsNil(state.Players[other].Bid) {
+			continue
+		}
+
+		if have_partner && other == partner {
+			continue
+		}
+
+		total += int(state.Players[other].Bid)
+	}
+
+	if total+bid > len(hand)+1 && bid > 1 {
+		bid = len(hand) + 1 - total
+	}
+
+	if bid < int(OneBidSpades) {
+		bid = int(OneBidSpades)
+	}
+
+	if SpadesBid(bid) > state.maxBid() {
+		bid = int(state.maxBid())
+	}
+
+	return SpadesBid(bid)
+}
+
+// Compute the set of cards the given player could legally play right now.
+func (ss *SpadesState) LegalPlays(player int) []Card {
+	var hand = ss.Players[player].Hand
+	var ret []Card
+
+	if ss.Turn == ss.Leader {
+		var only_spades = true
+		for _, card := range hand {
+			if !spadesEffectivelySpade(card) {
+				only_spades = false
+			}
+		}
+
+		for _, card := range hand {
+			if spadesEffectivelySpade(card) && !ss.SpadesBroken && ss.Config.MustBreakSpades && !only_spades {
+				continue
+			}
+
+			ret = append(ret, card)
+		}
+
+		return ret
+	}
+
+	var lead = ss.Played[0]
+	for _, card := range hand {
+		if spadesEffectivelySpade(lead) && spadesEffectivelySpade(card) {
+			ret = append(ret, card)
+		} else if !spadesEffectivelySpade(lead) && !spadesEffectivelySpade(card) && card.Suit == lead.Suit {
+			ret = append(ret, card)
+		}
+	}
+
+	if len(ret) == 0 {
+		ret = CopyHand(hand)
+	}
+
+	return ret
+}
+
+// Whether playing this card would currently win the trick.
+func (sb SpadesBot) wouldWin(state *SpadesState, card Card) bool {
+	var played = append(CopyHand(state.Played), card)
+	return state.trickWinner(played) == len(played)-1
+}
+
+// Number of tricks our side still needs to make its bid. Nil bidders on our
+// team don't contribute to the count.
+func (sb SpadesBot) tricksNeeded(state *SpadesState, player int) int {
+	var needed = 0
+	var taken = 0
+	for other := range state.Players {
+		if state.Players[other].Team != state.Players[player].Team {
+			continue
+		}
+
+		if spadesIsNil(state.Players[other].Bid) {
+			continue
+		}
+
+		needed += int(state.Players[other].Bid)
+		taken += state.Players[other].Tricks
+	}
+
+	return needed - taken
+}
+
+func (sb SpadesBot) choosePlay(state *SpadesState, player int) (Card, error) {
+	var legal = state.LegalPlays(player)
+	if len(legal) == 0 {
+		return Card{}, errors.New("no legal plays available")
+	}
+
+	// Weakest first.
+	sort.SliceStable(legal, func(i, j int) bool {
+		return sb.cardStrength(legal[i]) < sb.cardStrength(legal[j])
+	})
+
+	if len(legal) == 1 {
+		return legal[0], nil
+	}
+
+	var us = state.Players[player]
+	partner, have_partner := state.partnerOf(player)
+
+	// We bid nil: avoid taking anything.
+	if spadesIsNil(us.Bid) {
+		return sb.duck(state, legal), nil
+	}
+
+	var leading = state.Turn == state.Leader
+	var partner_nil = have_partner && spadesIsNil(state.Players[partner].Bid)
+	var needed = sb.tricksNeeded(state, player)
+
+	// Whether our partner is currently winning this trick.
+	var partner_winning = false
+	if !leading && have_partner {
+		var winner = (state.Leader + state.trickWinner(state.Played)) % len(state.Players)
+		partner_winning = winner == partner
+	}
+
+	if leading {
+		if needed > 0 || partner_nil {
+			// Lead our strongest non-spade winner; otherwise probe low.
+			for index := len(legal) - 1; index >= 0; index-- {
+				card := legal[index]
+				if !spadesEffectivelySpade(card) && sb.cardStrength(card) == int(KingRank)+1 {
+					return card, nil
+				}
+			}
+		}
+
+		// Lead low from a side suit when possible, saving spades.
+		for _, card := range legal {
+			if !spadesEffectivelySpade(card) {
+				return card, nil
+			}
+		}
+
+		return legal[0], nil
+	}
+
+	if partner_nil {
+		// Protect our partner: if they're winning, overtake them; if an
+		// opponent is winning, leave it alone unless our partner still has
+		// to play after us.
+		if partner_winning {
+			for _, card := range legal {
+				if sb.wouldWin(state, card) {
+					return card, nil
+				}
+			}
+		}
+
+		if len(state.Played) < len(state.Players)-1 {
+			// Partner may play after us; play high to make it easy for them
+			// to duck.
+			return legal[len(legal)-1], nil
+		}
+	}
+
+	if needed > 0 && !partner_winning {
+		// Win as cheaply as possible.
+		for _, card := range legal {
+			if sb.wouldWin(state, card) {
+				return card, nil
+			}
+		}
+
+		return legal[0], nil
+	}
+
+	if needed > 0 && partner_winning {
+		// Don't overtake our partner.
+		return sb.duck(state, legal), nil
+	}
+
+	// We've made our bid; avoid overtakes when they're counted.
+	if state.Config.Overtakes {
+		return sb.duck(state, legal), nil
+	}
+
+	return legal[0], nil
+}
+
+// Play the strongest card which doesn't win the trick; if every card wins,
+// play the weakest. Expects legal to be sorted weakest first.
+func (sb SpadesBot) duck(state *SpadesState, legal []Card) Card {
+	if state.Turn == state.Leader {
+		return legal[0]
+	}
+
+	for index := len(legal) - 1; index >= 0; index-- {
+		if !sb.wouldWin(state, legal[index]) {
+			return legal[index]
+		}
+	}
+
+	return legal[0]
+}
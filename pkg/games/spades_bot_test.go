@@ -0,0 +1,47 @@
+package games
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestSpadesBots(t *testing.T) {
+	for _, test := range []struct {
+		config SpadesConfig
+		teams  [][]int
+	}{
+		{SpadesConfig{NumPlayers: 4, Overtakes: true, OvertakeLimit: 10, MustBreakSpades: true, WithNil: true, BlindBidding: true, WithDoubleNil: true, WinAmount: 250, OvertakePenalty: 100, TrickMultiplier: 10, NilScore: 100}, [][]int{{0, 2}, {1, 3}}},
+		{SpadesConfig{NumPlayers: 3, OvertakeLimit: 10, AddJokers: true, WithNil: true, WinAmount: 150, OvertakePenalty: 50, TrickMultiplier: 5, NilScore: 50}, [][]int{{0}, {1}, {2}}},
+		{SpadesConfig{NumPlayers: 2, Overtakes: true, OvertakeLimit: 5, MustBreakSpades: true, WithNil: true, WinAmount: 150, OvertakePenalty: 50, TrickMultiplier: 10, NilScore: 100}, [][]int{{0}, {1}}},
+	} {
+		var c Controller
+		c.Init()
+
+		game := newBotGame(t, &c, "spades", test.config, test.config.NumPlayers)
+		state := game.State.(*SpadesState)
+		if err := state.Init(test.config); err != nil {
+			t.Fatal("Unable to initialize spades:", err)
+		}
+
+		if err := state.AssignTeams(0, test.config.NumPlayers, test.teams); err != nil {
+			t.Fatal("Unable to assign teams:", err)
+		}
+
+		var uids []uint64
+		for uid, player := range game.ToPlayer {
+			if player.Bot {
+				uids = append(uids, uid)
+			}
+		}
+		sort.Slice(uids, func(i, j int) bool { return uids[i] < uids[j] })
+		for index, uid := range uids {
+			game.ToPlayer[uid].Index = index
+		}
+
+		if err := (spadesEngine{}).Start(&c, game); err != nil {
+			t.Fatal("Unable to start spades:", err)
+		}
+
+		playBotGame(t, &c, game)
+	}
+}
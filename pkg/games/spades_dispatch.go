@@ -20,6 +20,8 @@ import (
 //    )
 // 2. Bid
 // 3. Play Card
+// 4. Undo Request / Undo Response (taking back the last card played)
+// 5. Timeout (sent by the server when a player runs out of time)
 
 type SpadesAssignMsg struct {
 	MessageHeader
@@ -166,7 +168,7 @@ func (c *Controller) dispatchSpades(message []byte, header MessageHeader, game *
 			send_synopsis = true
 		}
 	case "deal":
-		if state.Config.NumPlayers == 2 {
+		if state.Config.NumPlayers == 2 && state.Dealt {
 			err = state.PeekTop(player.Index)
 		} else {
 			if player.Index != state.Dealer {
@@ -216,6 +218,25 @@ func (c *Controller) dispatchSpades(message []byte, header MessageHeader, game *
 		err = state.PlayCard(player.Index, data.CardID)
 		send_synopsis = true
 		send_state = true
+	case "undo-request":
+		var undone bool
+		undone, err = c.handleUndoRequest(game, player)
+		send_synopsis = undone
+		send_state = undone
+	case "undo-response":
+		var undone bool
+		undone, err = c.handleUndoResponse(message, game, player)
+		send_synopsis = undone
+		send_state = undone
+	case "timeout":
+		var data GameTimeout
+		if err = json.Unmarshal(message, &data); err != nil {
+			return err
+		}
+
+		err = state.Timeout(player.Index, data.Policy)
+		send_synopsis = true
+		send_state = true
 	case "peek":
 		if player.Index != -1 && !state.Finished {
 			return errors.New("can only peek once game is complete")
@@ -327,3 +348,48 @@ func (c *Controller) doSpadesStart(game *GameData, state *SpadesState) error {
 
 	return nil
 }
+
+// spadesEngine registers Spades with the controller; see GameEngine.
+type spadesEngine struct{}
+
+func init() {
+	MustRegisterGameEngine(spadesEngine{})
+}
+
+func (spadesEngine) Mode() GameMode {
+	return SpadesGame
+}
+
+func (spadesEngine) Name() string {
+	return "spades"
+}
+
+func (spadesEngine) Title() string {
+	return "Spades (Card Game)"
+}
+
+func (spadesEngine) Description() string {
+	return "In Spades, players bid how many tricks they will take. If they make their bid, they get more points. First to a set amount wins!"
+}
+
+func (spadesEngine) EmptyConfig() figgy.Figgurable {
+	return &SpadesConfig{}
+}
+
+func (spadesEngine) NewState() ConfigurableState {
+	return &SpadesState{}
+}
+
+func (spadesEngine) Init(config figgy.Figgurable) (ConfigurableState, error) {
+	var asserted *SpadesConfig = config.(*SpadesConfig)
+	var state = &SpadesState{}
+	return state, state.Init(*asserted)
+}
+
+func (spadesEngine) Dispatch(c *Controller, message []byte, header MessageHeader, game *GameData, player *PlayerData, sid uint64) error {
+	return c.dispatchSpades(message, header, game, player, sid)
+}
+
+func (spadesEngine) Start(c *Controller, game *GameData) error {
+	return c.doSpadesStart(game, game.State.(*SpadesState))
+}
@@ -26,6 +26,8 @@ type SpadesGameState struct {
 	SpadesBroken bool     `json:"spades_broken"`
 	History      [][]Card `json:"history"`
 
+	Commitment string `json:"commitment"` // To the current round's shuffle seed; see ShuffleSeed.
+
 	Config SpadesConfig `json:"config"`
 
 	Started  bool `json:"started"`
@@ -77,6 +79,7 @@ func (ssn *SpadesStateNotification) LoadData(data *GameData, game *SpadesState,
 
 	ssn.SpadesBroken = game.SpadesBroken
 
+	ssn.Commitment = game.Deck.Commitment()
 	ssn.Config = game.Config
 
 	ssn.Started = game.Started
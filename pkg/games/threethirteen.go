@@ -63,6 +63,11 @@ type ThreeThirteenConfig struct {
 
 	// Common game configuration options
 	Countdown bool `json:"countdown" config:"type:bool,default:true" label:"true:Show a 3... 2... 1... countdown before beginning,false:Start the game instantly"` // Whether to wait and send countdown messages.
+
+	// Chess clock style limits on how long each player may take.
+	TurnClock   int `json:"turn_clock" config:"type:int,min:0,default:0,max:600" label:"Seconds per turn (0 for no limit)"`
+	GameClock   int `json:"game_clock" config:"type:int,min:0,default:0,max:120" label:"Minutes per player for the whole game (0 for no limit)"`
+	ClockPolicy int `json:"clock_policy" config:"type:enum,default:0,options:0:Play automatically;1:Skip their turn;2:Forfeit the game" label:"When a player runs out of time"` // See ClockPolicy.
 }
 
 func (cfg ThreeThirteenConfig) Validate() error {
@@ -101,7 +106,8 @@ type ThreeThirteenRound struct {
 	Turns   []ThreeThirteenTurn        `json:"plays"`
 	Discard []*Card                    `json:"discard"`
 
-	Deck []*Card `json:"deck"`
+	Deck []*Card      `json:"deck"`
+	Seed *ShuffleSeed `json:"seed"`
 }
 
 type ThreeThirteenState struct {
@@ -113,6 +119,7 @@ type ThreeThirteenState struct {
 	Players      []ThreeThirteenPlayer `json:"players"` // Left of dealer is found by incrementing one.
 	Round        int                   `json:"round"`
 	RoundHistory []*ThreeThirteenRound `json:"round_history"`
+	Seed         *ShuffleSeed          `json:"seed,omitempty"` // Secret each round's shuffle seed derives from; when unset, every round gets a fresh seed.
 
 	Config ThreeThirteenConfig `json:"config"`
 
@@ -166,11 +173,97 @@ func (tts *ThreeThirteenState) IsFinished() bool {
 	return tts.Finished
 }
 
+func (tts *ThreeThirteenState) SetShuffleSeed(seed *ShuffleSeed) error {
+	if tts.Started {
+		return errors.New("unable to set the shuffle seed after the game has started")
+	}
+
+	tts.Seed = seed
+	return nil
+}
+
+func (tts *ThreeThirteenState) RevealedSeeds() []*ShuffleSeed {
+	var seeds = make([]*ShuffleSeed, 0, len(tts.RoundHistory))
+	for _, round := range tts.RoundHistory {
+		seeds = append(seeds, round.Seed)
+	}
+
+	return revealedRoundSeeds(seeds, tts.Dealt && !tts.Finished)
+}
+
 func (tts *ThreeThirteenState) ResetStatus() {
 	tts.Started = false
 	tts.Finished = false
 }
 
+func (tts *ThreeThirteenState) TurnClocks() TurnClocks {
+	return newTurnClocks(tts.Config.TurnClock, tts.Config.GameClock, tts.Config.ClockPolicy)
+}
+
+func (tts *ThreeThirteenState) ClockedPlayer() int {
+	// Once someone goes out, everyone scores at once.
+	if !tts.Started || tts.Finished || !tts.Dealt || tts.LaidDown != -1 {
+		return -1
+	}
+
+	return tts.Turn
+}
+
+func (tts *ThreeThirteenState) Timeout(player int, policy ClockPolicy) error {
+	if player != tts.ClockedPlayer() {
+		return errors.New("not your turn")
+	}
+
+	if policy == SkipOnTimeout && tts.Players[player].Drawn == nil {
+		tts.Turn = (tts.Turn + 1) % len(tts.Players)
+		return nil
+	}
+
+	if policy == ForfeitOnTimeout {
+		tts.Winner = tts.forfeitWinner(player)
+		tts.Dealer = -1
+		tts.Turn = -1
+		tts.Finished = true
+		return errors.New(ThreeThirteenGameOver)
+	}
+
+	// Draw from the deck and discard the drawn card. A player who already
+	// picked up the discard can't put it back, so they discard from their hand.
+	if tts.Players[player].Drawn == nil {
+		if err := tts.TakeCard(player, false); err != nil {
+			return err
+		}
+	}
+
+	if !tts.Players[player].PickedUpDiscard {
+		return tts.DiscardCard(player, tts.Players[player].Drawn.ID, false)
+	}
+
+	return playLowestCard(tts.Players[player].Hand, false, func(card int) error {
+		return tts.DiscardCard(player, card, false)
+	})
+}
+
+// Whoever else has the best score wins when a player forfeits.
+func (tts *ThreeThirteenState) forfeitWinner(player int) int {
+	var winner = -1
+	for index, indexed_player := range tts.Players {
+		if index == player {
+			continue
+		}
+
+		if winner == -1 {
+			winner = index
+		} else if tts.Config.GolfScoring && indexed_player.Score < tts.Players[winner].Score {
+			winner = index
+		} else if !tts.Config.GolfScoring && indexed_player.Score > tts.Players[winner].Score {
+			winner = index
+		}
+	}
+
+	return winner
+}
+
 func (tts *ThreeThirteenState) Start(players int) error {
 	var err error
 
@@ -263,10 +356,12 @@ func (tts *ThreeThirteenState) StartRound() error {
 	}
 
 	// Shuffling the deck.
+	tts.Deck.Reseed(tts.Seed, len(tts.RoundHistory))
 	tts.Deck.Shuffle()
 
 	// Save the initial deck.
 	history.Deck = CopyDeck(tts.Deck.Cards)
+	history.Seed = tts.Deck.Seed.Copy()
 
 	// Clear out all round-specific status before each round.
 	for index := range tts.Players {
@@ -471,8 +566,9 @@ func (tts *ThreeThirteenState) DiscardCard(player int, cardID int, laidDown bool
 	}
 
 	// If we aren't going out and we're running out of cards, stop the game.
-	// Assign the current player as the leader.
-	if !laidDown && len(tts.Deck.Cards) <= len(tts.Players) {
+	// Assign the current player as the leader. Once someone has gone out,
+	// the remaining discards don't restart this.
+	if !laidDown && tts.LaidDown == -1 && len(tts.Deck.Cards) <= len(tts.Players) {
 		laidDown = true
 	}
 
@@ -0,0 +1,86 @@
+package games
+
+// ThreeThirteenBot is a computer opponent for Three Thirteen. It plays like
+// GinBot: the GinSolver decides whether the discard is worth taking, which
+// card to throw away and how to group the hand when the round ends. Its
+// difficulty sets how often it blunders a draw or discard.
+type ThreeThirteenBot struct{}
+
+func (threeThirteenEngine) NewBot() GameBot {
+	return ThreeThirteenBot{}
+}
+
+func (ttb ThreeThirteenBot) NextMove(game *GameData, player *PlayerData) (interface{}, error) {
+	var state *ThreeThirteenState = game.State.(*ThreeThirteenState)
+	if state == nil || !state.Started || state.Finished {
+		return nil, nil
+	}
+
+	var index = player.Index
+	if index < 0 || index >= len(state.Players) {
+		return nil, nil
+	}
+
+	if !state.Dealt {
+		if state.Dealer != index {
+			return nil, nil
+		}
+
+		return botHeader(game, player, "deal"), nil
+	}
+
+	var solver = state.GinSolver()
+	var us = &state.Players[index]
+
+	if state.LaidDown != -1 {
+		// Once someone has gone out, everyone else discards their last draw
+		// (if they got one) and scores, in any order.
+		if us.RoundScore != -1 {
+			return nil, nil
+		}
+
+		if us.Drawn != nil {
+			var msg ThreeThirteenDiscardMsg
+			msg.MessageHeader = botHeader(game, player, "discard")
+			msg.CardID, _ = ginBotChooseDiscard(&solver, us.Hand, *us.Drawn, false, botBlunders(player))
+			return msg, nil
+		}
+
+		groups, leftover, _ := solver.Groupings(us.Hand)
+
+		var msg ThreeThirteenScoreByGroupsMsg
+		msg.MessageHeader = botHeader(game, player, "score_by_groups")
+		msg.Groups = make([][]int, 0, len(groups))
+		for _, group := range groups {
+			msg.Groups = append(msg.Groups, ginBotCardIDs(us.Hand, group))
+		}
+		msg.Leftover = ginBotCardIDs(us.Hand, leftover)
+		return msg, nil
+	}
+
+	if state.Turn != index {
+		return nil, nil
+	}
+
+	if us.Drawn == nil {
+		var msg ThreeThirteenTakeMsg
+		msg.MessageHeader = botHeader(game, player, "take")
+		if len(state.Discard) > 0 {
+			msg.FromDiscard = ginBotWantsDiscard(&solver, us.Hand, *state.Discard[len(state.Discard)-1])
+			if len(state.Deck.Cards) == 0 {
+				msg.FromDiscard = true
+			} else if botBlunders(player) {
+				msg.FromDiscard = !msg.FromDiscard
+			}
+		}
+
+		return msg, nil
+	}
+
+	var score int
+	var msg ThreeThirteenDiscardMsg
+	msg.MessageHeader = botHeader(game, player, "discard")
+	msg.CardID, score = ginBotChooseDiscard(&solver, us.Hand, *us.Drawn, us.PickedUpDiscard, botBlunders(player))
+	msg.LayingDown = score <= state.Config.LayingDownLimit
+	return msg, nil
+}
@@ -13,6 +13,7 @@ import (
 // 2. Take
 // 3. Discard
 // 4. Score
+// 5. Timeout (sent by the server when a player runs out of time)
 
 type ThreeThirteenTakeMsg struct {
 	MessageHeader
@@ -176,6 +177,15 @@ func (c *Controller) dispatchThreeThirteen(message []byte, header MessageHeader,
 
 		err = state.Order(player.Index, data.Order)
 		send_state = true
+	case "timeout":
+		var data GameTimeout
+		if err = json.Unmarshal(message, &data); err != nil {
+			return err
+		}
+
+		err = state.Timeout(player.Index, data.Policy)
+		send_synopsis = true
+		send_state = true
 	case "peek":
 		if player.Index != -1 && !state.Finished {
 			return errors.New("can only peek once game is complete")
@@ -255,13 +265,7 @@ func (c *Controller) doThreeThirteenStart(game *GameData, state *ThreeThirteenSt
 	}
 
 	// Assign indices to players before sending notifications.
-	var player_index int = 0
-	for _, indexed_player := range game.ToPlayer {
-		if indexed_player.Admitted && indexed_player.Playing {
-			indexed_player.Index = player_index
-			player_index++
-		}
-	}
+	seatPlayers(game)
 
 	// Send out initial state data to individuals who are playing. Also notify
 	// all players that the game has started.
@@ -292,3 +296,48 @@ func (c *Controller) doThreeThirteenStart(game *GameData, state *ThreeThirteenSt
 
 	return nil
 }
+
+// threeThirteenEngine registers ThreeThirteen with the controller; see GameEngine.
+type threeThirteenEngine struct{}
+
+func init() {
+	MustRegisterGameEngine(threeThirteenEngine{})
+}
+
+func (threeThirteenEngine) Mode() GameMode {
+	return ThreeThirteenGame
+}
+
+func (threeThirteenEngine) Name() string {
+	return "three thirteen"
+}
+
+func (threeThirteenEngine) Title() string {
+	return "Three Thirteen (Card Game)"
+}
+
+func (threeThirteenEngine) Description() string {
+	return "In Three Thirteen, players compete against each other to score the least points each round. Each round, a new wild card pops up... try not to discard it!"
+}
+
+func (threeThirteenEngine) EmptyConfig() figgy.Figgurable {
+	return &ThreeThirteenConfig{}
+}
+
+func (threeThirteenEngine) NewState() ConfigurableState {
+	return &ThreeThirteenState{}
+}
+
+func (threeThirteenEngine) Init(config figgy.Figgurable) (ConfigurableState, error) {
+	var asserted *ThreeThirteenConfig = config.(*ThreeThirteenConfig)
+	var state = &ThreeThirteenState{}
+	return state, state.Init(*asserted)
+}
+
+func (threeThirteenEngine) Dispatch(c *Controller, message []byte, header MessageHeader, game *GameData, player *PlayerData, sid uint64) error {
+	return c.dispatchThreeThirteen(message, header, game, player, sid)
+}
+
+func (threeThirteenEngine) Start(c *Controller, game *GameData) error {
+	return c.doThreeThirteenStart(game, game.State.(*ThreeThirteenState))
+}
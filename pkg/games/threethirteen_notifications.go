@@ -23,6 +23,8 @@ type ThreeThirteenGameState struct {
 	Round    int    `json:"round"`
 	DrawDeck int    `json:"draw_deck"`
 
+	Commitment string `json:"commitment"` // To the current round's shuffle seed; see ShuffleSeed.
+
 	Config ThreeThirteenConfig `json:"config"`
 
 	Started  bool `json:"started"`
@@ -69,6 +71,7 @@ func (ttsn *ThreeThirteenStateNotification) LoadData(data *GameData, game *Three
 
 	ttsn.Round = game.Round
 
+	ttsn.Commitment = game.Deck.Commitment()
 	ttsn.Config = game.Config
 
 	ttsn.Started = game.Started
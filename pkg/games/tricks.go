@@ -0,0 +1,56 @@
+package games
+
+import (
+	"errors"
+	"strconv"
+)
+
+// Whether card takes a trick from the card currently winning it, in games
+// where aces are high: by following its suit with a higher card, or by
+// trumping it. Pass NoneSuit when nothing is trump.
+func beatsInTrick(card Card, winning Card, trump CardSuit) bool {
+	if card.Suit == winning.Suit {
+		return card.Rank.AceHigh() > winning.Rank.AceHigh()
+	}
+
+	return card.Suit == trump && trump != NoneSuit
+}
+
+// Where a trick-taking game stands when a player tries to bid: bids are
+// placed in turn, once the cards are dealt and until everyone has bid.
+type bidTurn struct {
+	Started  bool
+	Finished bool
+	Dealt    bool
+	Bid      bool
+	Turn     int
+	Players  int
+}
+
+func (bt bidTurn) check(player int) error {
+	if !bt.Started {
+		return errors.New("game hasn't started yet")
+	}
+
+	if bt.Finished {
+		return errors.New("game has already finished")
+	}
+
+	if player < 0 || player >= bt.Players {
+		return errors.New("not a valid player identifier: " + strconv.Itoa(player))
+	}
+
+	if bt.Turn != player {
+		return errors.New("not your turn")
+	}
+
+	if !bt.Dealt {
+		return errors.New("unable to place bid before dealing cards")
+	}
+
+	if bt.Bid {
+		return errors.New("already bid; can't bid again")
+	}
+
+	return nil
+}
@@ -0,0 +1,25 @@
+package games
+
+import "testing"
+
+func TestBeatsInTrick(t *testing.T) {
+	var king = Card{Suit: HeartsSuit, Rank: KingRank}
+	var ace = Card{Suit: HeartsSuit, Rank: AceRank}
+	var two = Card{Suit: SpadesSuit, Rank: TwoRank}
+
+	if !beatsInTrick(ace, king, NoneSuit) || beatsInTrick(king, ace, NoneSuit) {
+		t.Fatal("Expected aces to rank above kings")
+	}
+
+	if !beatsInTrick(two, ace, SpadesSuit) || beatsInTrick(ace, two, SpadesSuit) {
+		t.Fatal("Expected a low trump to beat an ace of another suit")
+	}
+
+	if beatsInTrick(two, king, NoneSuit) {
+		t.Fatal("Expected not following suit to lose without trump")
+	}
+
+	if (bidTurn{true, false, true, false, 1, 4}).check(1) != nil || (bidTurn{true, false, true, false, 1, 4}).check(2) == nil || (bidTurn{true, false, true, true, 1, 4}).check(1) == nil {
+		t.Fatal("Expected only the player whose turn it is to bid, until everyone has")
+	}
+}
@@ -0,0 +1,255 @@
+package games
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+)
+
+// Who has to agree before a player can take back their last action.
+type UndoApproval int
+
+const (
+	NoUndo             UndoApproval = iota // 0
+	PlayersApproveUndo UndoApproval = iota // 1
+	OwnerApprovesUndo  UndoApproval = iota // 2
+)
+
+// Implemented by states of games which let a player take back their last
+// action, as long as the trick it was taken in is still going.
+type UndoableState interface {
+	UndoApproval() UndoApproval
+
+	// The actions which can still be taken back, oldest first.
+	UndoableActions() []UndoAction
+
+	// Take back the given action, along with every action taken after it.
+	Undo(action int) error
+}
+
+// An action a player took, numbered so that a takeback is never mistaken for
+// the action taken after it.
+type UndoAction struct {
+	Player int `json:"player"`
+	Action int `json:"action"`
+}
+
+// A card played in the current trick, along with what playing it changed, so
+// that it can be taken back.
+type TrickTakeback struct {
+	UndoAction
+	Card   Card   `json:"card"`
+	Index  int    `json:"index"`            // Where the card was in the player's hand.
+	Turn   int    `json:"turn"`             // Whose turn it was before the card was played.
+	Broken bool   `json:"broken"`           // Whether the suit which has to be broken had been.
+	Played []Card `json:"played,omitempty"` // The cards left on the table, when the card led the trick.
+}
+
+// Take the card back off the table and put it back in the player's hand.
+// Returns whether the card led the trick, in which case the cards which were
+// on the table before are put back.
+func (tt TrickTakeback) takeBack(hand *[]Card, table *[]Card, trick *[]Card) bool {
+	*hand = InsertCard(*hand, tt.Index, tt.Card)
+	*table = (*table)[:len(*table)-1]
+	*trick = (*trick)[:len(*trick)-1]
+	if len(*trick) > 0 {
+		return false
+	}
+
+	*table = tt.Played
+	return true
+}
+
+// The player's latest action which can be taken back. Only bots may have
+// acted since: their responses are taken back along with it.
+func undoableAction(game *GameData, state UndoableState, player *PlayerData) (int, bool) {
+	var actions = state.UndoableActions()
+	for index := len(actions) - 1; index >= 0; index-- {
+		if actions[index].Player == player.Index {
+			return actions[index].Action, true
+		}
+
+		uid, ok := game.ToUserID(actions[index].Player)
+		if !ok || !game.ToPlayer[uid].Bot {
+			break
+		}
+	}
+
+	return 0, false
+}
+
+// A request to take back an action, waiting on approval.
+type UndoRequest struct {
+	Requester uint64   `json:"requester"`
+	Action    int      `json:"action"`
+	Approvers []uint64 `json:"approvers"` // Who has yet to approve it.
+}
+
+type GameUndoResponse struct {
+	MessageHeader
+	Approve bool `json:"approve"`
+}
+
+type ControllerNotifyUndoRequest struct {
+	MessageHeader
+	Requester uint64 `json:"requester"`
+}
+
+func (cnur *ControllerNotifyUndoRequest) LoadFromController(data *GameData, player *PlayerData, requester uint64) {
+	cnur.LoadHeader(data, player)
+	cnur.MessageType = "notify-undo-request"
+
+	cnur.Requester = requester
+}
+
+// Sent to everyone once a request to take back an action is decided; these
+// double as the audit trail of takebacks in the message log.
+type ControllerNotifyUndo struct {
+	MessageHeader
+	Requester uint64 `json:"requester"`
+	Responder uint64 `json:"responder"`
+	Action    int    `json:"action"`
+	Approved  bool   `json:"approved"`
+}
+
+func (cnu *ControllerNotifyUndo) LoadFromController(data *GameData, player *PlayerData, request *UndoRequest, responder uint64, approved bool) {
+	cnu.LoadHeader(data, player)
+	cnu.MessageType = "notify-undo"
+
+	cnu.Requester = request.Requester
+	cnu.Responder = responder
+	cnu.Action = request.Action
+	cnu.Approved = approved
+}
+
+// Ask to take back the player's last action. Returns whether the action was
+// taken back right away, which happens when nobody else needs to approve it.
+func (c *Controller) handleUndoRequest(game *GameData, player *PlayerData) (bool, error) {
+	// !!NO LOCK!! This should already be held elsewhere, like Dispatch.
+
+	state, ok := game.State.(UndoableState)
+	if !ok {
+		return false, errors.New("this game doesn't allow taking back actions")
+	}
+
+	var approval = state.UndoApproval()
+	if approval == NoUndo {
+		return false, errors.New("this game was configured without takebacks")
+	}
+
+	action, ok := undoableAction(game, state, player)
+	if !ok || !player.Playing {
+		return false, errors.New("you have no action in the current trick to take back")
+	}
+
+	var request = &UndoRequest{Requester: player.UID, Action: action}
+	if approval == OwnerApprovesUndo {
+		if player.UID != game.Owner {
+			request.Approvers = append(request.Approvers, game.Owner)
+		}
+	} else {
+		// Bots never object to a takeback.
+		for _, indexed_player := range game.ToPlayer {
+			if indexed_player.Admitted && indexed_player.Playing && !indexed_player.Bot && indexed_player.UID != player.UID {
+				request.Approvers = append(request.Approvers, indexed_player.UID)
+			}
+		}
+	}
+
+	if len(request.Approvers) == 0 {
+		return true, c.finishUndo(game, state, request, player.UID, true)
+	}
+
+	game.Undo = request
+	for _, uid := range request.Approvers {
+		if approver, present := game.ToPlayer[uid]; present {
+			var notification ControllerNotifyUndoRequest
+			notification.LoadFromController(game, approver, player.UID)
+			c.undispatch(game, approver, notification.MessageID, 0, notification)
+		}
+	}
+
+	return false, nil
+}
+
+// Approve or reject the pending request to take back an action. Returns
+// whether the action was taken back.
+func (c *Controller) handleUndoResponse(message []byte, game *GameData, player *PlayerData) (bool, error) {
+	// !!NO LOCK!! This should already be held elsewhere, like Dispatch.
+
+	var data GameUndoResponse
+	if err := json.Unmarshal(message, &data); err != nil {
+		return false, err
+	}
+
+	var request = game.Undo
+	if request == nil {
+		return false, errors.New("no takeback is waiting on approval")
+	}
+
+	var remaining []uint64
+	var found = false
+	for _, uid := range request.Approvers {
+		if uid == player.UID {
+			found = true
+		} else {
+			remaining = append(remaining, uid)
+		}
+	}
+
+	if !found {
+		return false, errors.New("you aren't asked to approve this takeback")
+	}
+
+	state, ok := game.State.(UndoableState)
+	if !ok {
+		return false, errors.New("this game doesn't allow taking back actions")
+	}
+
+	// Someone may have played on since the request was made.
+	requester, present := game.ToPlayer[request.Requester]
+	if !present {
+		game.Undo = nil
+		return false, errors.New("the player who asked for the takeback has left")
+	}
+
+	if action, ok := undoableAction(game, state, requester); !ok || action != request.Action {
+		game.Undo = nil
+		return false, errors.New("the action to take back is no longer in the current trick")
+	}
+
+	if !data.Approve {
+		return false, c.finishUndo(game, state, request, player.UID, false)
+	}
+
+	request.Approvers = remaining
+	if len(remaining) > 0 {
+		return false, nil
+	}
+
+	return true, c.finishUndo(game, state, request, player.UID, true)
+}
+
+func (c *Controller) finishUndo(game *GameData, state UndoableState, request *UndoRequest, responder uint64, approved bool) error {
+	game.Undo = nil
+
+	if approved {
+		if err := state.Undo(request.Action); err != nil {
+			return err
+		}
+
+		log.Println("Took back action", request.Action, "by", request.Requester, "in game", game.GID, "approved by", responder)
+	}
+
+	for _, indexed_player := range game.ToPlayer {
+		if !indexed_player.Admitted {
+			continue
+		}
+
+		var notification ControllerNotifyUndo
+		notification.LoadFromController(game, indexed_player, request, responder, approved)
+		c.undispatch(game, indexed_player, notification.MessageID, 0, notification)
+	}
+
+	return nil
+}
@@ -0,0 +1,196 @@
+package games
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Play the first card the player is allowed to, returning its identifier.
+func playAnyHeartsCard(t *testing.T, state *HeartsState, player int) int {
+	for _, card := range state.Players[player].Hand {
+		if err := state.PlayCard(player, card.ID); err == nil || isRoundSignal(err) {
+			return card.ID
+		}
+	}
+
+	t.Fatal("Unable to find a card to play for", player, state.Players[player].Hand)
+	return 0
+}
+
+func TestHeartsTakeback(t *testing.T) {
+	var c Controller
+	c.Init()
+
+	var state HeartsState
+	if err := state.Init(HeartsConfig{NumPlayers: 4, NumberToPass: 3, WinAmount: 100, Takebacks: int(PlayersApproveUndo)}); err != nil {
+		t.Fatal("Unable to initialize game:", err)
+	}
+
+	if err := state.Start(4); err != nil {
+		t.Fatal("Unable to start game:", err)
+	}
+
+	for player := range state.Players {
+		var hand = state.Players[player].Hand
+		if err := state.PassCards(player, []int{hand[0].ID, hand[1].ID, hand[2].ID}); err != nil {
+			t.Fatal("Unable to pass cards:", err)
+		}
+	}
+
+	var game = &GameData{GID: 1, Mode: HeartsGame, Owner: 1, State: &state, ToPlayer: make(map[uint64]*PlayerData)}
+	for index := 0; index < 4; index++ {
+		var uid = uint64(index + 1)
+		game.ToPlayer[uid] = &PlayerData{UID: uid, Index: index, Admitted: true, Playing: true, Notifications: make(map[uint64]chan interface{})}
+	}
+
+	var send = func(uid uint64, messageType string, approve bool) error {
+		var msg GameUndoResponse
+		msg.MessageHeader = MessageHeader{Mode: "hearts", ID: 1, Player: uid, MessageType: messageType}
+		msg.Approve = approve
+		data, _ := json.Marshal(msg)
+		return c.dispatch(data, msg.MessageHeader, game, game.ToPlayer[uid], 0)
+	}
+
+	var leader = state.Turn
+	playAnyHeartsCard(t, &state, leader)
+	var second = state.Turn
+	var card = playAnyHeartsCard(t, &state, second)
+	var leaderUID = uint64(leader + 1)
+	var secondUID = uint64(second + 1)
+
+	if err := send(leaderUID, "undo-request", false); err == nil {
+		t.Fatal("Expected a takeback of someone else's card to fail")
+	}
+
+	// Any other player can reject the takeback.
+	if err := send(secondUID, "undo-request", false); err != nil || game.Undo == nil || len(game.Undo.Approvers) != 3 {
+		t.Fatal("Expected the takeback to wait on the other players:", err, game.Undo)
+	}
+
+	if err := send(secondUID, "undo-response", true); err == nil {
+		t.Fatal("Expected the requester to be unable to approve their own takeback")
+	}
+
+	if err := send(leaderUID, "undo-response", false); err != nil || game.Undo != nil || state.Turn == second {
+		t.Fatal("Expected the takeback to be rejected:", err, game.Undo)
+	}
+
+	// Once everyone approves, the card goes back in the player's hand.
+	if err := send(secondUID, "undo-request", false); err != nil {
+		t.Fatal("Unable to request takeback:", err)
+	}
+
+	for uid := range game.ToPlayer {
+		if uid == secondUID {
+			continue
+		}
+
+		if err := send(uid, "undo-response", true); err != nil {
+			t.Fatal("Unable to approve takeback:", err)
+		}
+	}
+
+	if _, found := state.Players[second].FindCard(card); !found || state.Turn != second || len(state.Played) != 1 || len(state.Takebacks) != 1 || game.Undo != nil {
+		t.Fatal("Expected the card to be taken back:", state.Turn, state.Played)
+	}
+
+	var audited = false
+	for _, message := range game.ToPlayer[leaderUID].OutboundMsgs {
+		audited = audited || (strings.Contains(message.Message, `"notify-undo"`) && strings.Contains(message.Message, `"approved":true`))
+	}
+
+	if !audited {
+		t.Fatal("Expected the takeback to be recorded in the message log")
+	}
+
+	// With owner approval, only the owner is asked.
+	state.Config.Takebacks = int(OwnerApprovesUndo)
+	playAnyHeartsCard(t, &state, second)
+	if secondUID != game.Owner {
+		if err := send(secondUID, "undo-request", false); err != nil || len(game.Undo.Approvers) != 1 || game.Undo.Approvers[0] != game.Owner {
+			t.Fatal("Expected only the owner to be asked:", err, game.Undo)
+		}
+
+		if err := send(game.Owner, "undo-response", true); err != nil || state.Turn != second {
+			t.Fatal("Expected the owner to approve the takeback:", err)
+		}
+
+		playAnyHeartsCard(t, &state, second)
+	}
+
+	// Cards can't be taken back once the trick is over.
+	for len(state.PreviousTricks) == 0 {
+		playAnyHeartsCard(t, &state, state.Turn)
+	}
+
+	if err := send(uint64(state.Leader+1), "undo-request", false); err == nil || len(state.Takebacks) != 0 {
+		t.Fatal("Expected cards from a finished trick to stay played")
+	}
+
+	state.Config.Takebacks = int(NoUndo)
+	playAnyHeartsCard(t, &state, state.Turn)
+	if err := send(uint64(state.Leader+1), "undo-request", false); err == nil {
+		t.Fatal("Expected takebacks to be refused when disabled")
+	}
+}
+
+func TestHeartsTakebackBotResponses(t *testing.T) {
+	var c Controller
+	c.Init()
+
+	var state HeartsState
+	if err := state.Init(HeartsConfig{NumPlayers: 4, NumberToPass: 3, WinAmount: 100, Takebacks: int(PlayersApproveUndo)}); err != nil {
+		t.Fatal("Unable to initialize game:", err)
+	}
+
+	if err := state.Start(4); err != nil {
+		t.Fatal("Unable to start game:", err)
+	}
+
+	for player := range state.Players {
+		var hand = state.Players[player].Hand
+		if err := state.PassCards(player, []int{hand[0].ID, hand[1].ID, hand[2].ID}); err != nil {
+			t.Fatal("Unable to pass cards:", err)
+		}
+	}
+
+	// Only whoever leads the first trick is a person; the rest are bots.
+	var leader = state.Turn
+	var game = &GameData{GID: 1, Mode: HeartsGame, Owner: 1, State: &state, ToPlayer: make(map[uint64]*PlayerData)}
+	for index := 0; index < 4; index++ {
+		var uid = uint64(index + 1)
+		game.ToPlayer[uid] = &PlayerData{UID: uid, Index: index, Admitted: true, Playing: true, Bot: index != leader, Notifications: make(map[uint64]chan interface{})}
+	}
+
+	var hand = CopyHand(state.Players[leader].Hand)
+	var incoming = CopyHand(state.Players[leader].Incoming)
+
+	// The bots respond before the person can ask for their card back.
+	playAnyHeartsCard(t, &state, leader)
+	for state.Turn != leader && len(state.Played) < 3 {
+		playAnyHeartsCard(t, &state, state.Turn)
+	}
+
+	var request = GameUndoResponse{MessageHeader: MessageHeader{Mode: "hearts", ID: 1, Player: uint64(leader + 1), MessageType: "undo-request"}}
+	data, _ := json.Marshal(request)
+	if err := c.dispatch(data, request.MessageHeader, game, game.ToPlayer[uint64(leader+1)], 0); err != nil {
+		t.Fatal("Expected the takeback to rewind the bots' responses too:", err)
+	}
+
+	var history = state.RoundHistory[len(state.RoundHistory)-1]
+	if state.Turn != leader || len(state.Played) != 0 || len(history.Tricks) != 0 || len(state.Takebacks) != 0 || game.Undo != nil {
+		t.Fatal("Expected every card in the trick to be taken back:", state.Turn, state.Played, history.Tricks, state.Takebacks)
+	}
+
+	if !reflect.DeepEqual(state.Players[leader].Hand, hand) || !reflect.DeepEqual(state.Players[leader].Incoming, incoming) {
+		t.Fatal("Expected the hand to be as it was before the card was played:", state.Players[leader].Hand, hand)
+	}
+
+	for player := range state.Players {
+		if len(state.Players[player].Hand) != len(hand) {
+			t.Fatal("Expected the bots' cards to go back in their hands:", player, state.Players[player].Hand)
+		}
+	}
+}
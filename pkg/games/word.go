@@ -2,6 +2,10 @@ package games
 
 import (
 	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"unicode"
 )
 
 type LetterTile struct {
@@ -9,6 +13,38 @@ type LetterTile struct {
 	Display string `json:"display,omitempty"`
 	Value   string `json:"value"`
 	Score   int    `json:"score,omitempty"`
+
+	// Blank tiles can stand in for any letter. Their Value is the letter the
+	// player assigned when placing them on the board, and is empty otherwise.
+	Blank bool `json:"blank,omitempty"`
+}
+
+// Display marker for a blank tile which hasn't been assigned a letter.
+const blankTileDisplay = "?"
+
+// Assign a letter to a blank tile. Assigned blanks are displayed in lower
+// case to tell them apart from regular tiles.
+func (lt *LetterTile) Assign(letter string) error {
+	if !lt.Blank {
+		return errors.New("only blank tiles can be assigned a letter")
+	}
+
+	var runes = []rune(letter)
+	if len(runes) != 1 || !unicode.IsLetter(runes[0]) {
+		return errors.New("blank tiles must be assigned a single letter: " + letter)
+	}
+
+	lt.Value = strings.ToUpper(letter)
+	lt.Display = strings.ToLower(letter)
+	return nil
+}
+
+// Clear the letter assigned to a blank tile, as when it returns to the hand.
+func (lt *LetterTile) Unassign() {
+	if lt.Blank {
+		lt.Value = ""
+		lt.Display = blankTileDisplay
+	}
 }
 
 type LetterPos struct {
@@ -75,6 +111,24 @@ func (lg *LetterGrid) AddTile(tile LetterTile, x int, y int) {
 	lg.AtPosition[pos] = tile.ID
 }
 
+// Replace the tile with the given identifier, such as after assigning a new
+// letter to a blank, keeping its position.
+func (lg *LetterGrid) UpdateTile(tile LetterTile) {
+	lg.ReInit()
+
+	if _, ok := lg.ToTile[tile.ID]; !ok {
+		return
+	}
+
+	for index := range lg.Tiles {
+		if lg.Tiles[index].ID == tile.ID {
+			lg.Tiles[index] = tile
+		}
+	}
+
+	lg.ToTile[tile.ID] = tile
+}
+
 func (lg *LetterGrid) MoveTile(tileID int, x int, y int) {
 	lg.ReInit()
 
@@ -200,11 +254,11 @@ func (lg *LetterGrid) VisitAllWordsOnBoard(visitor func(lg *LetterGrid, start Le
 	return nil
 }
 
-func (lg *LetterGrid) FindUnwords() []string {
+func (lg *LetterGrid) FindUnwords(words WordList) []string {
 	var ret []string
 
 	err := lg.VisitAllWordsOnBoard(func(lg *LetterGrid, start LetterPos, end LetterPos, word string) error {
-		if !IsWord(word) {
+		if !words.IsWord(word) {
 			ret = append(ret, word)
 		}
 
@@ -217,6 +271,47 @@ func (lg *LetterGrid) FindUnwords() []string {
 	return ret
 }
 
+// Find the groups of tiles which are connected to each other, as lists of
+// tile identifiers. The largest group comes first; any others are islands
+// which aren't connected to it.
+func (lg *LetterGrid) Islands() [][]int {
+	lg.ReInit()
+
+	var visited map[LetterPos]bool = make(map[LetterPos]bool)
+	var ret [][]int
+
+	for _, tile := range lg.Tiles {
+		var start = lg.PositionsOf[tile.ID]
+		if visited[start] {
+			continue
+		}
+
+		var island []int
+		var queue []LetterPos = []LetterPos{start}
+		visited[start] = true
+		for len(queue) > 0 {
+			var current = queue[0]
+			queue = queue[1:]
+			island = append(island, lg.AtPosition[current])
+
+			for _, neighbor := range []LetterPos{{current.X - 1, current.Y}, {current.X + 1, current.Y}, {current.X, current.Y - 1}, {current.X, current.Y + 1}} {
+				if _, ok := lg.AtPosition[neighbor]; ok && !visited[neighbor] {
+					visited[neighbor] = true
+					queue = append(queue, neighbor)
+				}
+			}
+		}
+
+		ret = append(ret, island)
+	}
+
+	sort.SliceStable(ret, func(i, j int) bool {
+		return len(ret[i]) > len(ret[j])
+	})
+
+	return ret
+}
+
 func (lg *LetterGrid) IsAllConnected() bool {
 	lg.ReInit()
 
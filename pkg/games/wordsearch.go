@@ -0,0 +1,434 @@
+package games
+
+import (
+	"errors"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"git.cipherboy.com/WillowPatchGames/wpg/pkg/middleware/figgy"
+)
+
+type WordSearchPlayer struct {
+	Words []string `json:"words"` // Accepted words, upper case, in the order they were found.
+	Done  bool     `json:"done"`  // Whether this player stopped searching before time ran out.
+
+	// Computed once the game is over: words another player also found, which
+	// don't score, and the total of everything else.
+	Cancelled []string `json:"cancelled,omitempty"`
+	Score     int      `json:"score"`
+}
+
+func (wsp *WordSearchPlayer) Init() {
+	wsp.Words = make([]string, 0)
+	wsp.Done = false
+	wsp.Cancelled = nil
+	wsp.Score = 0
+}
+
+func (wsp *WordSearchPlayer) HasWord(word string) bool {
+	for _, found := range wsp.Words {
+		if found == word {
+			return true
+		}
+	}
+
+	return false
+}
+
+type WordSearchConfig struct {
+	NumPlayers int `json:"num_players" config:"type:int,min:1,default:4,max:16" label:"Number of players"`
+
+	Size      int       `json:"size" config:"type:int,min:3,default:4,max:6" label:"Grid size (tiles per side)"`
+	Frequency Frequency `json:"frequency" config:"type:enum,default:1,options:1:Standard US English Letter Frequencies;2:Bananagrams Tile Frequency;3:Scrabble Tile Frequency" label:"Tile frequency"`
+
+	MinLength  int `json:"min_length" config:"type:int,min:3,default:3,max:5" label:"Minimum word length"`
+	TimeLimit  int `json:"time_limit" config:"type:int,min:1,default:3,max:10" label:"Time limit in minutes"`
+	Dictionary int `json:"dictionary" config:"type:enum,default:0,options:@dictionaries" label:"Dictionary"` // See dictionaryOptions.
+
+	// Common game configuration options
+	Countdown bool `json:"countdown" config:"type:bool,default:true" label:"true:Show a 3... 2... 1... countdown before beginning,false:Start the game instantly"` // Whether to wait and send countdown messages.
+}
+
+func (cfg WordSearchConfig) DictionaryName() string {
+	return dictionaryName(cfg.Dictionary)
+}
+
+func (cfg WordSearchConfig) Validate() error {
+	if cfg.Frequency < StandardFreq || cfg.Frequency > ScrabbleFreq {
+		return GameConfigError{"frequency range", strconv.Itoa(int(cfg.Frequency)), "between " + strconv.Itoa(int(StandardFreq)) + " and " + strconv.Itoa(int(ScrabbleFreq))}
+	}
+
+	if !DictionaryExists(cfg.DictionaryName()) {
+		return GameConfigError{"dictionary", cfg.DictionaryName(), "a dictionary installed on this server"}
+	}
+
+	return nil
+}
+
+// Points for a word of the given length: longer words are worth more.
+func WordSearchScore(word string) int {
+	var length = len([]rune(word))
+	switch {
+	case length < 3:
+		return 0
+	case length <= 4:
+		return 1
+	case length == 5:
+		return 2
+	case length == 6:
+		return 3
+	case length == 7:
+		return 5
+	}
+
+	return 11
+}
+
+type WordSearchState struct {
+	Grid    LetterGrid         `json:"grid"`
+	Players []WordSearchPlayer `json:"players"`
+	Config  WordSearchConfig   `json:"config"`
+
+	// When the game runs out of time.
+	Deadline time.Time `json:"deadline"`
+
+	// Every word which could be traced on the grid, computed once the game is
+	// over.
+	AllWords []string `json:"all_words,omitempty"`
+
+	Started  bool  `json:"started"`
+	Finished bool  `json:"finished"`
+	Winners  []int `json:"winners"`
+
+	// Words added or removed by the room this game is played in.
+	RoomWords RoomWords `json:"room_words"`
+
+	// Seed the grid is generated from; a fresh one unless set before starting.
+	// Kept secret until the game is over.
+	Seed *ShuffleSeed `json:"seed,omitempty"`
+}
+
+// The words accepted in this game.
+func (wss *WordSearchState) Words() WordList {
+	return WordList{wss.Config.DictionaryName(), wss.RoomWords}
+}
+
+func (wss *WordSearchState) SetRoomWords(words RoomWords) {
+	wss.RoomWords = words
+}
+
+// The grid is generated from the seed's first round, so that revealing it
+// doesn't give away the secret, which might be used for other games too.
+func (wss *WordSearchState) SetShuffleSeed(seed *ShuffleSeed) error {
+	if wss.Started {
+		return errors.New("unable to set the shuffle seed after the game has started")
+	}
+
+	wss.Seed = seed.Derive(1)
+	return nil
+}
+
+// The grid's seed, once the game is over.
+func (wss *WordSearchState) RevealedSeeds() []*ShuffleSeed {
+	if !wss.Finished || wss.Seed == nil {
+		return []*ShuffleSeed{}
+	}
+
+	return []*ShuffleSeed{wss.Seed}
+}
+
+func (wss *WordSearchState) Init(cfg WordSearchConfig) error {
+	var err error = figgy.Validate(cfg)
+	if err != nil {
+		log.Println("Error with WordSearchConfig", err)
+		return err
+	}
+
+	wss.Config = cfg
+	wss.Started = false
+	wss.Finished = false
+
+	return nil
+}
+
+func (wss *WordSearchState) GetConfiguration() figgy.Figgurable {
+	return wss.Config
+}
+
+func (wss *WordSearchState) ReInit() error {
+	wss.Grid.ReInit()
+	return nil
+}
+
+func (wss *WordSearchState) IsStarted() bool {
+	return wss.Started
+}
+
+func (wss *WordSearchState) IsFinished() bool {
+	return wss.Finished
+}
+
+func (wss *WordSearchState) ResetStatus() {
+	wss.Started = false
+	wss.Finished = false
+}
+
+func (wss *WordSearchState) Start(players int) error {
+	var err error
+
+	if wss.Started {
+		log.Println("Error! Double start occurred...", err)
+		return errors.New("double start occurred")
+	}
+
+	wss.Config.NumPlayers = players
+	err = figgy.Validate(wss.Config)
+	if err != nil {
+		log.Println("Err with WordSearchConfig after starting: ", err)
+		return err
+	}
+
+	wss.Players = make([]WordSearchPlayer, wss.Config.NumPlayers)
+	for index := range wss.Players {
+		wss.Players[index].Init()
+	}
+
+	if wss.Seed == nil {
+		wss.Seed = NewShuffleSeed()
+	}
+
+	// Lay the tiles out row by row.
+	var size = wss.Config.Size
+	var tiles = GenerateSeededTiles(wss.Seed, size*size, 0, frequencyMap[wss.Config.Frequency], false)
+	wss.Grid.Init()
+	for index, tile := range tiles {
+		wss.Grid.AddTile(tile, index%size, index/size)
+	}
+
+	wss.AllWords = nil
+	wss.Winners = nil
+	wss.Deadline = time.Now().Add(time.Duration(wss.Config.TimeLimit) * time.Minute)
+	wss.Started = true
+	return nil
+}
+
+// Whether two positions on the grid touch, including diagonally.
+func wordSearchAdjacent(first LetterPos, second LetterPos) bool {
+	var dx = first.X - second.X
+	var dy = first.Y - second.Y
+	return (dx != 0 || dy != 0) && dx >= -1 && dx <= 1 && dy >= -1 && dy <= 1
+}
+
+// Spell out the word traced through the given tiles, checking that each tile
+// touches the one before it and that no tile is used twice.
+func (wss *WordSearchState) TraceWord(path []int) (string, error) {
+	wss.Grid.ReInit()
+
+	var word string
+	var used = make(map[int]bool)
+	var last LetterPos
+	for index, tileID := range path {
+		tile, ok := wss.Grid.ToTile[tileID]
+		if !ok {
+			return "", errors.New("unknown tile identifier: " + strconv.Itoa(tileID))
+		}
+
+		if used[tileID] {
+			return "", errors.New("can't use the same tile twice in a word")
+		}
+
+		var pos = wss.Grid.PositionsOf[tileID]
+		if index > 0 && !wordSearchAdjacent(last, pos) {
+			return "", errors.New("each tile in a word must touch the one before it")
+		}
+
+		word += tile.Value
+		used[tileID] = true
+		last = pos
+	}
+
+	return word, nil
+}
+
+func (wss *WordSearchState) checkPlayer(player int) error {
+	if !wss.Started {
+		return errors.New("game hasn't started yet")
+	}
+
+	if wss.Finished {
+		return errors.New("game has already finished")
+	}
+
+	if player < 0 || player >= len(wss.Players) {
+		return errors.New("not a valid player identifier: " + strconv.Itoa(player))
+	}
+
+	if wss.Players[player].Done {
+		return errors.New("you've already finished searching")
+	}
+
+	return nil
+}
+
+// Submit a word traced through the given tiles. An empty word takes whatever
+// the path spells.
+func (wss *WordSearchState) Submit(player int, word string, path []int) error {
+	if err := wss.checkPlayer(player); err != nil {
+		return err
+	}
+
+	traced, err := wss.TraceWord(path)
+	if err != nil {
+		return err
+	}
+
+	word = strings.ToUpper(strings.TrimSpace(word))
+	if word == "" {
+		word = traced
+	}
+
+	if word != traced {
+		return errors.New("word doesn't match the tiles traced: " + word + " vs " + traced)
+	}
+
+	if len([]rune(word)) < wss.Config.MinLength {
+		return errors.New("words need at least " + strconv.Itoa(wss.Config.MinLength) + " letters")
+	}
+
+	if wss.Players[player].HasWord(word) {
+		return errors.New("already found " + word)
+	}
+
+	if !wss.Words().IsWord(word) {
+		return errors.New("not a word: " + word)
+	}
+
+	wss.Players[player].Words = append(wss.Players[player].Words, word)
+	return nil
+}
+
+// Stop searching before time runs out. Once everyone is done, the game ends.
+func (wss *WordSearchState) MarkDone(player int) error {
+	if err := wss.checkPlayer(player); err != nil {
+		return err
+	}
+
+	wss.Players[player].Done = true
+	for _, indexed_player := range wss.Players {
+		if !indexed_player.Done {
+			return nil
+		}
+	}
+
+	wss.finish()
+	return nil
+}
+
+// End the game if it has run out of time, returning whether it did.
+func (wss *WordSearchState) CheckTime() bool {
+	if !wss.Started || wss.Finished || wss.Deadline.IsZero() || time.Now().Before(wss.Deadline) {
+		return false
+	}
+
+	wss.finish()
+	return true
+}
+
+// Cancel words found by more than one player, score the rest and reveal
+// everything which could have been found.
+func (wss *WordSearchState) finish() {
+	var finders = make(map[string]int)
+	for _, indexed_player := range wss.Players {
+		for _, word := range indexed_player.Words {
+			finders[word] += 1
+		}
+	}
+
+	var best = -1
+	for index := range wss.Players {
+		var indexed_player = &wss.Players[index]
+		indexed_player.Cancelled = nil
+		indexed_player.Score = 0
+		for _, word := range indexed_player.Words {
+			if finders[word] > 1 {
+				indexed_player.Cancelled = append(indexed_player.Cancelled, word)
+			} else {
+				indexed_player.Score += WordSearchScore(word)
+			}
+		}
+
+		if indexed_player.Score > best {
+			best = indexed_player.Score
+			wss.Winners = []int{index}
+		} else if indexed_player.Score == best {
+			wss.Winners = append(wss.Winners, index)
+		}
+	}
+
+	wss.AllWords = wss.FindAllWords()
+	wss.Deadline = time.Time{}
+	wss.Finished = true
+}
+
+// Whether the rest of word can be traced starting from the tile at pos,
+// without reusing any visited tile.
+func (wss *WordSearchState) canTrace(word string, pos LetterPos, visited map[LetterPos]bool) bool {
+	var tile = wss.Grid.ToTile[wss.Grid.AtPosition[pos]]
+	if tile.Value == "" || !strings.HasPrefix(word, tile.Value) {
+		return false
+	}
+
+	var rest = word[len(tile.Value):]
+	if rest == "" {
+		return true
+	}
+
+	visited[pos] = true
+	defer delete(visited, pos)
+
+	for dx := -1; dx <= 1; dx++ {
+		for dy := -1; dy <= 1; dy++ {
+			var neighbor = LetterPos{pos.X + dx, pos.Y + dy}
+			if _, ok := wss.Grid.AtPosition[neighbor]; !ok || visited[neighbor] {
+				continue
+			}
+
+			if wss.canTrace(rest, neighbor, visited) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Every word in the game's word list, at least the minimum length, which
+// can be traced on the grid, sorted.
+func (wss *WordSearchState) FindAllWords() []string {
+	wss.Grid.ReInit()
+
+	var ret = make([]string, 0)
+	err := wss.Words().Walk(func(word string) error {
+		if len([]rune(word)) < wss.Config.MinLength {
+			return nil
+		}
+
+		for pos := range wss.Grid.AtPosition {
+			if wss.canTrace(word, pos, make(map[LetterPos]bool)) {
+				ret = append(ret, word)
+				break
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		log.Println("Unable to list the words on a word search grid", err)
+	}
+
+	sort.Strings(ret)
+	return ret
+}
@@ -0,0 +1,345 @@
+package games
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"git.cipherboy.com/WillowPatchGames/wpg/pkg/middleware/figgy"
+)
+
+// Word Search message types:
+//
+// 1. Submit
+// 2. Done
+
+type WordSearchSubmitMsg struct {
+	MessageHeader
+	Word string `json:"word"`
+	Path []int  `json:"path"` // Tile identifiers, in the order traced.
+}
+
+func (c *Controller) dispatchWordSearch(message []byte, header MessageHeader, game *GameData, player *PlayerData, sid uint64) error {
+	var err error
+	var state *WordSearchState = game.State.(*WordSearchState)
+	if state == nil {
+		panic("internal state is nil; this shouldn't happen when the game is started")
+	}
+
+	// End the game if time ran out since we last heard from anyone. The clock
+	// is stopped while the game is paused.
+	if game.Paused == nil {
+		state.CheckTime()
+	}
+
+	var was_finished = state.Finished
+	var send_synopsis = false
+
+	switch header.MessageType {
+	case "start":
+		if player.UID != game.Owner {
+			return errors.New("unable to start game that you're not the owner of")
+		}
+
+		var players int = 0
+		for _, player := range game.ToPlayer {
+			if player.Playing {
+				// When we click the start button again, say, after a user has come
+				// back to being active, Countback will be higher than 0, because we've
+				// already attempted to set this.
+				player.Countback = 0
+				players += 1
+			}
+		}
+
+		state.Config.NumPlayers = players
+		if err = figgy.Validate(state.Config); err != nil {
+			return err
+		}
+
+		if state.Config.Countdown {
+			game.Countdown = 0
+			game.CountdownTimer = nil
+
+			return c.handleCountdown(game)
+		} else {
+			return c.doWordSearchStart(game, state)
+		}
+	case "cancel":
+		if player.UID != game.Owner {
+			return errors.New("unable to cancel game that you're not the owner of")
+		}
+
+		if !state.Config.Countdown {
+			return errors.New("unable to cancel game that doesn't use a countdown")
+		}
+
+		if state.Started || state.Finished {
+			return errors.New("unable to cancel game that is already started")
+		}
+
+		game.Countdown = 0
+		game.CountdownTimer = nil
+	case "join":
+		if state.Started && !state.Finished {
+			var started ControllerNotifyStarted
+			started.LoadFromController(game, player)
+			started.ReplyTo = header.MessageID
+			c.undispatch(game, player, started.MessageID, started.ReplyTo, started)
+
+			if player.Playing && player.Index >= 0 {
+				var response WordSearchStateNotification
+				response.LoadData(game, state, player)
+				c.undispatch(game, player, response.MessageID, 0, response)
+
+				send_synopsis = true
+			}
+		} else if state.Finished {
+			var finished WordSearchFinishedNotification
+			finished.LoadData(game, state, player)
+			finished.ReplyTo = header.MessageID
+			c.undispatch(game, player, finished.MessageID, finished.ReplyTo, finished)
+			send_synopsis = true
+		}
+	case "submit":
+		var data WordSearchSubmitMsg
+		if err = json.Unmarshal(message, &data); err != nil {
+			return err
+		}
+
+		if err = state.Submit(player.Index, data.Word, data.Path); err != nil {
+			return err
+		}
+
+		// Words found are private until the game ends; only the player who
+		// found this one hears about it.
+		var response WordSearchStateNotification
+		response.LoadData(game, state, player)
+		response.ReplyTo = header.MessageID
+		c.undispatch(game, player, response.MessageID, response.ReplyTo, response)
+
+		send_synopsis = true
+	case "done":
+		err = state.MarkDone(player.Index)
+		send_synopsis = err == nil
+	case "peek":
+		if player.Index != -1 && !state.Finished {
+			return errors.New("can only peek once game is complete")
+		}
+
+		var response WordSearchPeekNotification
+		response.LoadData(game, state, player)
+		response.ReplyTo = header.MessageID
+		c.undispatch(game, player, response.MessageID, header.MessageID, response)
+
+		var synopsis WordSearchSynopsisNotification
+		synopsis.LoadData(game, state, player)
+		c.undispatch(game, player, synopsis.MessageID, 0, synopsis)
+	default:
+		return errors.New("unknown message_type issued to word search game: " + header.MessageType)
+	}
+
+	// If this game ended during this dispatch call, notify everyone.
+	if c.notifyWordSearchEnd(game, state, was_finished) {
+		send_synopsis = true
+	}
+
+	// If someone changed something, notify everyone.
+	if send_synopsis {
+		for _, indexed_player := range game.ToPlayer {
+			var synopsis WordSearchSynopsisNotification
+			synopsis.LoadData(game, state, indexed_player)
+			c.undispatch(game, indexed_player, synopsis.MessageID, 0, synopsis)
+		}
+	}
+
+	return err
+}
+
+// Tell everyone the game ended, who won and which words could have been
+// found, if it ended since was_finished was observed. Returns whether
+// anything was sent.
+func (c *Controller) notifyWordSearchEnd(game *GameData, state *WordSearchState, was_finished bool) bool {
+	if was_finished || !state.Finished {
+		return false
+	}
+
+	for _, indexed_player := range game.ToPlayer {
+		var finished WordSearchFinishedNotification
+		finished.LoadData(game, state, indexed_player)
+		c.undispatch(game, indexed_player, finished.MessageID, 0, finished)
+
+		if !indexed_player.Admitted {
+			continue
+		}
+
+		if indexed_player.Playing && indexed_player.Index >= 0 {
+			var response WordSearchStateNotification
+			response.LoadData(game, state, indexed_player)
+			c.undispatch(game, indexed_player, response.MessageID, 0, response)
+		} else {
+			var response WordSearchPeekNotification
+			response.LoadData(game, state, indexed_player)
+			c.undispatch(game, indexed_player, response.MessageID, 0, response)
+		}
+	}
+
+	return true
+}
+
+// End the game once it runs out of time.
+func (c *Controller) scheduleWordSearchTimeout(game *GameData, state *WordSearchState) {
+	if state.Deadline.IsZero() {
+		return
+	}
+
+	var gid = game.GID
+	if c.replay {
+		// Replays catch up with the deadline as the log's moves pass it.
+		if !time.Now().Before(state.Deadline) {
+			c.handleWordSearchTimeout(gid)
+		}
+		return
+	}
+
+	time.AfterFunc(time.Until(state.Deadline), func() {
+		c.handleWordSearchTimeout(gid)
+	})
+}
+
+func (c *Controller) handleWordSearchTimeout(gid uint64) {
+	c.lock.Lock()
+	game, ok := c.ToGame[gid]
+	if !ok {
+		c.lock.Unlock()
+		return
+	}
+
+	game.lock.Lock()
+	defer game.lock.Unlock()
+	c.lock.Unlock()
+
+	state, ok := game.State.(*WordSearchState)
+	if !ok || game.Paused != nil {
+		return
+	}
+
+	var was_finished = state.Finished
+	if !state.CheckTime() {
+		return
+	}
+
+	c.notifyWordSearchEnd(game, state, was_finished)
+	for _, indexed_player := range game.ToPlayer {
+		var synopsis WordSearchSynopsisNotification
+		synopsis.LoadData(game, state, indexed_player)
+		c.undispatch(game, indexed_player, synopsis.MessageID, 0, synopsis)
+	}
+}
+
+func (c *Controller) doWordSearchStart(game *GameData, state *WordSearchState) error {
+	// First count the number of people playing.
+	var players int = 0
+	for _, player := range game.ToPlayer {
+		if player.Playing {
+			players += 1
+		}
+	}
+
+	// Then start the underlying Word Search game to populate game data.
+	if err := state.Start(players); err != nil {
+		return err
+	}
+
+	// Assign indices to players before sending notifications.
+	seatPlayers(game)
+
+	// Send out initial state data to individuals who are playing. Also notify
+	// all players that the game has started.
+	for _, indexed_player := range game.ToPlayer {
+		if !indexed_player.Admitted {
+			continue
+		}
+
+		// Tell everyone interested that the game has started.
+		var started ControllerNotifyStarted
+		started.LoadFromController(game, indexed_player)
+		c.undispatch(game, indexed_player, started.MessageID, started.ReplyTo, started)
+
+		// Only send state to players who are playing initially. Everyone else
+		// (namely, admitted spectators) should send a peek event before they can
+		// view the grid.
+		if indexed_player.Playing {
+			var response WordSearchStateNotification
+			response.LoadData(game, state, indexed_player)
+			c.undispatch(game, indexed_player, response.MessageID, 0, response)
+		}
+
+		// Give everyone the initial synopsis.
+		var synopsis WordSearchSynopsisNotification
+		synopsis.LoadData(game, state, indexed_player)
+		c.undispatch(game, indexed_player, synopsis.MessageID, 0, synopsis)
+	}
+
+	c.scheduleWordSearchTimeout(game, state)
+	return nil
+}
+
+// wordsearchEngine registers Word Search with the controller; see GameEngine.
+type wordsearchEngine struct{}
+
+func init() {
+	MustRegisterGameEngine(wordsearchEngine{})
+}
+
+func (wordsearchEngine) Mode() GameMode {
+	return WordSearchGame
+}
+
+func (wordsearchEngine) Name() string {
+	return "word search"
+}
+
+func (wordsearchEngine) Title() string {
+	return "Word Search (Timed Word Game)"
+}
+
+func (wordsearchEngine) Description() string {
+	return "In Word Search, everyone hunts for words traced through touching tiles of the same grid before time runs out. Words someone else also found don't count, so look for the long and unusual ones!"
+}
+
+func (wordsearchEngine) EmptyConfig() figgy.Figgurable {
+	return &WordSearchConfig{}
+}
+
+func (wordsearchEngine) NewState() ConfigurableState {
+	return &WordSearchState{}
+}
+
+func (wordsearchEngine) Init(config figgy.Figgurable) (ConfigurableState, error) {
+	var asserted *WordSearchConfig = config.(*WordSearchConfig)
+	var state = &WordSearchState{}
+	return state, state.Init(*asserted)
+}
+
+func (wordsearchEngine) Dispatch(c *Controller, message []byte, header MessageHeader, game *GameData, player *PlayerData, sid uint64) error {
+	return c.dispatchWordSearch(message, header, game, player, sid)
+}
+
+func (wordsearchEngine) Start(c *Controller, game *GameData) error {
+	return c.doWordSearchStart(game, game.State.(*WordSearchState))
+}
+
+func (wordsearchEngine) Resume(c *Controller, game *GameData, paused time.Duration) {
+	var state = game.State.(*WordSearchState)
+	if state.Deadline.IsZero() {
+		return
+	}
+
+	state.Deadline = state.Deadline.Add(paused)
+	c.scheduleWordSearchTimeout(game, state)
+}
+
+func (wordsearchEngine) Reschedule(c *Controller, game *GameData) {
+	c.scheduleWordSearchTimeout(game, game.State.(*WordSearchState))
+}
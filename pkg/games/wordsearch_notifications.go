@@ -0,0 +1,153 @@
+package games
+
+import (
+	"time"
+)
+
+type WordSearchPlayerState struct {
+	Words []string `json:"words"`
+	Done  bool     `json:"done"`
+}
+
+type WordSearchGameState struct {
+	Grid       LetterGrid `json:"grid"`
+	Deadline   time.Time  `json:"deadline"`
+	Commitment string     `json:"commitment"` // To the grid's seed; see ShuffleSeed.
+
+	Config WordSearchConfig `json:"config"`
+
+	Started  bool `json:"started"`
+	Finished bool `json:"finished"`
+}
+
+func (wgs *WordSearchGameState) loadGame(data *GameData, game *WordSearchState) {
+	wgs.Grid = game.Grid
+	wgs.Deadline = game.Deadline
+	if game.Seed != nil {
+		wgs.Commitment = game.Seed.Commitment
+	}
+	wgs.Config = game.Config
+
+	wgs.Started = game.Started
+	wgs.Finished = game.Finished
+}
+
+type WordSearchStateNotification struct {
+	MessageHeader
+	WordSearchPlayerState
+	WordSearchGameState
+}
+
+func (wsn *WordSearchStateNotification) LoadData(data *GameData, game *WordSearchState, player *PlayerData) {
+	wsn.LoadHeader(data, player)
+	wsn.MessageType = "state"
+
+	wsn.Words = game.Players[player.Index].Words
+	wsn.Done = game.Players[player.Index].Done
+
+	wsn.loadGame(data, game)
+}
+
+type WordSearchPlayerSynopsis struct {
+	UID         uint64 `json:"user"`
+	Playing     bool   `json:"playing"`
+	PlayerIndex int    `json:"player_index"`
+
+	NumWords int  `json:"num_words"`
+	Done     bool `json:"done"`
+	Score    int  `json:"score"` // Only known once the game is over.
+}
+
+type WordSearchSynopsisNotification struct {
+	MessageHeader
+
+	Players []WordSearchPlayerSynopsis `json:"players"`
+
+	Deadline time.Time `json:"deadline"`
+}
+
+func (wsn *WordSearchSynopsisNotification) LoadData(data *GameData, state *WordSearchState, player *PlayerData) {
+	wsn.LoadHeader(data, player)
+	wsn.MessageType = "synopsis"
+
+	for _, indexed_player := range data.ToPlayer {
+		var synopsis WordSearchPlayerSynopsis
+		synopsis.UID = indexed_player.UID
+		synopsis.Playing = indexed_player.Playing
+		synopsis.PlayerIndex = indexed_player.Index
+
+		if indexed_player.Index >= 0 && indexed_player.Index < len(state.Players) {
+			synopsis.NumWords = len(state.Players[indexed_player.Index].Words)
+			synopsis.Done = state.Players[indexed_player.Index].Done
+			synopsis.Score = state.Players[indexed_player.Index].Score
+		}
+
+		wsn.Players = append(wsn.Players, synopsis)
+	}
+
+	wsn.Deadline = state.Deadline
+}
+
+type WordSearchPeekNotification struct {
+	MessageHeader
+
+	PlayerMapping []uint64 `json:"player_mapping"`
+
+	// Info for Ended Games (Everyone)
+	Words     [][]string   `json:"words,omitempty"`
+	Cancelled [][]string   `json:"cancelled,omitempty"`
+	Scores    []int        `json:"scores,omitempty"`
+	AllWords  []string     `json:"all_words,omitempty"`
+	Seed      *ShuffleSeed `json:"seed,omitempty"`
+
+	// Info for Active Games (Spectators)
+	WordSearchGameState
+
+	Winners []uint64 `json:"winners"`
+}
+
+func (wpn *WordSearchPeekNotification) LoadData(data *GameData, game *WordSearchState, player *PlayerData) {
+	wpn.LoadHeader(data, player)
+	wpn.MessageType = "game-state"
+
+	for index := range game.Players {
+		player_uid, _ := data.ToUserID(index)
+		wpn.PlayerMapping = append(wpn.PlayerMapping, player_uid)
+	}
+
+	wpn.loadGame(data, game)
+
+	if game.Finished {
+		for _, indexed_player := range game.Players {
+			wpn.Words = append(wpn.Words, indexed_player.Words)
+			wpn.Cancelled = append(wpn.Cancelled, indexed_player.Cancelled)
+			wpn.Scores = append(wpn.Scores, indexed_player.Score)
+		}
+
+		wpn.AllWords = game.AllWords
+		wpn.Seed = game.Seed
+	}
+
+	wpn.Winners, _ = data.ToUserIDs(game.Winners)
+}
+
+type WordSearchFinishedNotification struct {
+	MessageHeader
+
+	Winners  []uint64     `json:"winners"`
+	Scores   []int        `json:"scores"`
+	AllWords []string     `json:"all_words"`
+	Seed     *ShuffleSeed `json:"seed"`
+}
+
+func (wfn *WordSearchFinishedNotification) LoadData(data *GameData, state *WordSearchState, player *PlayerData) {
+	wfn.LoadHeader(data, player)
+	wfn.MessageType = "finished"
+
+	wfn.Winners, _ = data.ToUserIDs(state.Winners)
+	for _, indexed_player := range state.Players {
+		wfn.Scores = append(wfn.Scores, indexed_player.Score)
+	}
+	wfn.AllWords = state.AllWords
+	wfn.Seed = state.Seed
+}
@@ -0,0 +1,179 @@
+package games
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWordSearchScore(t *testing.T) {
+	for word, expected := range map[string]int{"AT": 0, "CAT": 1, "DOGS": 1, "ALPHA": 2, "ALPHAS": 3, "ALPHABE": 5, "ALPHABET": 11} {
+		if score := WordSearchScore(word); score != expected {
+			t.Fatal("Expected", word, "to score", expected, "but got", score)
+		}
+	}
+}
+
+func TestWordSearchGame(t *testing.T) {
+	var state WordSearchState
+	if err := state.Init(WordSearchConfig{NumPlayers: 2, Size: 3, Frequency: StandardFreq, MinLength: 3, TimeLimit: 3}); err != nil {
+		t.Fatal("Unable to initialize game:", err)
+	}
+
+	if err := state.Start(2); err != nil {
+		t.Fatal("Unable to start game:", err)
+	}
+
+	if len(state.Grid.Tiles) != 9 || state.Deadline.IsZero() {
+		t.Fatal("Expected a 3x3 grid with a deadline:", len(state.Grid.Tiles), state.Deadline)
+	}
+
+	// Replace the grid with a known one; tile identifiers run 1 to 9 by row:
+	//
+	//   C A T
+	//   O D E
+	//   G S H
+	state.Grid.Init()
+	for index, letter := range "CATODEGSH" {
+		state.Grid.AddTile(LetterTile{ID: index + 1, Value: string(letter)}, index%3, index/3)
+	}
+
+	if word, err := state.TraceWord([]int{7, 4, 5}); err != nil || word != "GOD" {
+		t.Fatal("Expected to trace GOD:", word, err)
+	}
+
+	if _, err := state.TraceWord([]int{1, 3}); err == nil {
+		t.Fatal("Expected tiles which don't touch to be rejected")
+	}
+
+	if _, err := state.TraceWord([]int{1, 2, 1}); err == nil {
+		t.Fatal("Expected reusing a tile to be rejected")
+	}
+
+	if err := state.Submit(0, "cat", []int{1, 2, 3}); err != nil {
+		t.Fatal("Unable to submit CAT:", err)
+	}
+
+	if err := state.Submit(0, "cat", []int{1, 2, 3}); err == nil {
+		t.Fatal("Expected submitting the same word twice to fail")
+	}
+
+	if err := state.Submit(0, "dog", []int{1, 2, 3}); err == nil {
+		t.Fatal("Expected a word not matching its path to fail")
+	}
+
+	if err := state.Submit(0, "", []int{2, 3}); err == nil {
+		t.Fatal("Expected a word below the minimum length to fail")
+	}
+
+	if err := state.Submit(0, "", []int{3, 2, 1}); err == nil {
+		t.Fatal("Expected a word not in the dictionary to fail")
+	}
+
+	if err := state.Submit(0, "", []int{5, 4, 7}); err != nil || !state.Players[0].HasWord("DOG") {
+		t.Fatal("Unable to submit DOG by its path:", err)
+	}
+
+	for _, path := range [][]int{{1, 2, 3}, {5, 4, 7, 8}, {7, 4, 5}} {
+		if err := state.Submit(1, "", path); err != nil {
+			t.Fatal("Unable to submit word for player 1:", path, err)
+		}
+	}
+
+	if err := state.MarkDone(0); err != nil || state.Finished {
+		t.Fatal("Expected the game to continue until everyone is done:", err)
+	}
+
+	if err := state.Submit(0, "", []int{2, 3, 6}); err == nil {
+		t.Fatal("Expected a player who's done to be unable to submit")
+	}
+
+	if err := state.MarkDone(1); err != nil || !state.Finished {
+		t.Fatal("Expected the game to end once everyone is done:", err)
+	}
+
+	// CAT was found by both players, so only DOG counts for player 0, while
+	// DOGS and GOD count for player 1.
+	if state.Players[0].Score != 1 || state.Players[1].Score != 2 || len(state.Players[0].Cancelled) != 1 || state.Players[1].Cancelled[0] != "CAT" {
+		t.Fatal("Unexpected scores:", state.Players)
+	}
+
+	if len(state.Winners) != 1 || state.Winners[0] != 1 {
+		t.Fatal("Expected player 1 to win:", state.Winners)
+	}
+
+	if strings.Join(state.AllWords, ",") != "ATE,CAT,DOG,DOGS,EAT,GOD,TEA" {
+		t.Fatal("Unexpected words found on the grid:", state.AllWords)
+	}
+
+	if err := state.Submit(1, "", []int{2, 3, 6}); err == nil {
+		t.Fatal("Expected submitting after the game ended to fail")
+	}
+}
+
+func TestWordSearchTimeLimit(t *testing.T) {
+	var state WordSearchState
+	if err := state.Init(WordSearchConfig{NumPlayers: 1, Size: 4, Frequency: ScrabbleFreq, MinLength: 4, TimeLimit: 1}); err != nil {
+		t.Fatal("Unable to initialize game:", err)
+	}
+
+	if err := state.Start(1); err != nil {
+		t.Fatal("Unable to start game:", err)
+	}
+
+	if state.CheckTime() || state.Finished {
+		t.Fatal("Expected the game to continue before the deadline")
+	}
+
+	state.Deadline = time.Now().Add(-time.Second)
+	if !state.CheckTime() || !state.Finished || len(state.Winners) != 1 {
+		t.Fatal("Expected the game to end when time ran out:", state.Winners)
+	}
+
+	for _, word := range state.AllWords {
+		if len(word) < 4 {
+			t.Fatal("Expected only words of at least four letters:", word)
+		}
+	}
+
+	var config = WordSearchConfig{NumPlayers: 1, Size: 4, Frequency: CustomFreq, MinLength: 3, TimeLimit: 3}
+	if err := config.Validate(); err == nil {
+		t.Fatal("Expected a custom tile frequency to be rejected")
+	}
+}
+
+func TestWordSearchReschedule(t *testing.T) {
+	var c Controller
+	c.Init()
+
+	var state WordSearchState
+	if err := state.Init(WordSearchConfig{NumPlayers: 1, Size: 4, Frequency: ScrabbleFreq, MinLength: 4, TimeLimit: 1}); err != nil {
+		t.Fatal("Unable to initialize game:", err)
+	}
+
+	if err := state.Start(1); err != nil {
+		t.Fatal("Unable to start game:", err)
+	}
+
+	var game = &GameData{GID: 1, Mode: WordSearchGame, Owner: 1, State: &state, ToPlayer: make(map[uint64]*PlayerData)}
+	game.ToPlayer[1] = &PlayerData{UID: 1, Index: 0, Admitted: true, Playing: true, Notifications: make(map[uint64]chan interface{})}
+	c.ToGame[game.GID] = game
+
+	// Rescheduling a game whose time ran out while it was unloaded ends it
+	// right away.
+	state.Deadline = time.Now()
+	wordsearchEngine{}.Reschedule(&c, game)
+	for attempt := 0; attempt < 100; attempt++ {
+		game.lock.Lock()
+		var finished = state.Finished
+		game.lock.Unlock()
+
+		if finished {
+			return
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatal("Expected the rescheduled deadline to end the game")
+}
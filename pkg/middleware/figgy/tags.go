@@ -12,6 +12,7 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
 )
 
 type configurable interface {
@@ -120,11 +121,94 @@ func (bct *boolConfigTag) GetType() string {
 	return bct.Type
 }
 
+type stringConfigTag struct {
+	Type    string `json:"type"`
+	Field   string `json:"-"`
+	Default string `json:"default"`
+	Max     int    `json:"max"` // Maximum length of the value.
+}
+
+func (sct *stringConfigTag) LoadValue(field reflect.Value, values map[string]interface{}) error {
+	if values == nil {
+		return errors.New("passed nil value map")
+	}
+
+	var value string = sct.Default
+
+	if wire_value, ok := values[sct.Field]; ok {
+		if string_value, ok := wire_value.(string); ok {
+			value = string_value
+		} else {
+			return errors.New("unable to parse value for " + sct.Field + " as string: " + reflect.TypeOf(wire_value).String())
+		}
+	}
+
+	if err := sct.validateValue(value); err != nil {
+		return err
+	}
+
+	field.SetString(value)
+
+	return nil
+}
+
+func (sct *stringConfigTag) ValidateValue(field reflect.Value) error {
+	return sct.validateValue(field.String())
+}
+
+func (sct *stringConfigTag) validateValue(value string) error {
+	if len(value) > sct.Max {
+		return fmt.Errorf("invalid value for parameter: %s has length %d; allowed at most %d", sct.Field, len(value), sct.Max)
+	}
+
+	return nil
+}
+
+func (sct *stringConfigTag) GetType() string {
+	return sct.Type
+}
+
 type selectOption struct {
 	Label string `json:"label"`
 	Value string `json:"value"`
 }
 
+// Enum options registered by name, for enums whose options are defined in
+// code rather than spelled out in the struct tag. Tags refer to these as
+// "options:@name".
+var namedOptionsLock sync.RWMutex
+var namedOptions = make(map[string][]selectOption)
+
+// Register the options of a named enum; option values are their index in
+// labels. This should be called from an init function, before any config
+// using these options is parsed.
+func RegisterOptions(name string, labels []string) {
+	var values = make([]int, 0, len(labels))
+	for index := range labels {
+		values = append(values, index)
+	}
+
+	RegisterOptionValues(name, values, labels)
+}
+
+// Register the options of a named enum with the given values, for options
+// which come and go while keeping their values. These may be registered
+// again at any time, replacing the earlier options.
+func RegisterOptionValues(name string, values []int, labels []string) {
+	var options = make([]selectOption, 0, len(labels))
+	for index, label := range labels {
+		options = append(options, selectOption{
+			Value: strconv.Itoa(values[index]),
+			Label: label,
+		})
+	}
+
+	namedOptionsLock.Lock()
+	defer namedOptionsLock.Unlock()
+
+	namedOptions[name] = options
+}
+
 type enumConfigTag struct {
 	Type    string         `json:"type"`
 	Field   string         `json:"-"`
@@ -346,6 +430,28 @@ func parseConfigField(field string, config string) (configurable, error) {
 			Field:   field,
 			Default: b_default,
 		}, nil
+	} else if c_type == "string" {
+		s_default, err := findKeyValueWithDefault(field, split, "default", "")
+		if err != nil {
+			return nil, err
+		}
+
+		s_max, err := findKeyValue(field, split, "max")
+		if err != nil {
+			return nil, err
+		}
+
+		i_max, err := strconv.Atoi(s_max)
+		if err != nil {
+			return nil, err
+		}
+
+		return &stringConfigTag{
+			Type:    c_type,
+			Field:   field,
+			Default: s_default,
+			Max:     i_max,
+		}, nil
 	} else if c_type == "enum" {
 		s_default, err := findKeyValue(field, split, "default")
 		if err != nil {
@@ -357,6 +463,22 @@ func parseConfigField(field string, config string) (configurable, error) {
 			return nil, err
 		}
 
+		if s_options[0] == '@' {
+			namedOptionsLock.RLock()
+			o_options, ok := namedOptions[s_options[1:]]
+			namedOptionsLock.RUnlock()
+			if !ok {
+				return nil, errors.New("unknown enum options for field " + field + ": " + s_options)
+			}
+
+			return &enumConfigTag{
+				Type:    c_type,
+				Field:   field,
+				Default: s_default,
+				Options: o_options,
+			}, nil
+		}
+
 		sa_options := strings.Split(s_options, ";")
 		var o_options = make([]selectOption, 0)
 
@@ -387,7 +509,7 @@ func parseConfigField(field string, config string) (configurable, error) {
 func parseLabelField(field string, config_type string, label string) (labelable, error) {
 	split := strings.Split(label, ",")
 
-	if config_type == "int" || config_type == "enum" {
+	if config_type == "int" || config_type == "enum" || config_type == "string" {
 		return &labelTag{
 			Type:  config_type,
 			Field: field,